@@ -1,7 +1,9 @@
 package database
 
 import (
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/wowsims/mop/sim/core/proto"
@@ -31,3 +33,11 @@ func Load() *proto.UIDatabase {
 
 	return db
 }
+
+// Returns a hash of the database contents, which changes whenever the database is regenerated.
+func Version() string {
+	hash := sha256.New()
+	hash.Write(dbBytes)
+	hash.Write(leftoverBytes)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplCmd = &cobra.Command{
+	Use:   "apl",
+	Short: "tools for working with APL rotations",
+	Long:  "tools for working with APL rotations",
+}
+
+var aplConvertFormat string
+
+var aplConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "convert an APL between the json and text formats",
+	Long:  "convert an APL between the json format used by the UI and the text format, which is easier to read and diff",
+	Run:   aplConvertMain,
+}
+
+func init() {
+	aplConvertCmd.Flags().StringVar(&infile, "infile", "", "location of input file, either an APLRotation in protojson format (.json) or an APL in text format")
+	aplConvertCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	aplConvertCmd.Flags().StringVar(&aplConvertFormat, "format", "", "output format, either 'json' or 'text'. Defaults to the format which the input is not in")
+	aplConvertCmd.MarkFlagRequired("infile")
+
+	aplCmd.AddCommand(aplConvertCmd)
+}
+
+func aplConvertMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input file %q: %v", infile, err)
+	}
+
+	inputIsJson := strings.HasSuffix(infile, ".json")
+	rot, err := readAPL(data, inputIsJson)
+	if err != nil {
+		log.Fatalf("failed to load input file %q: %v", infile, err)
+	}
+
+	format := aplConvertFormat
+	if format == "" {
+		format = "json"
+		if inputIsJson {
+			format = "text"
+		}
+	}
+
+	var output []byte
+	switch format {
+	case "text":
+		output = []byte(apltext.Format(rot))
+	case "json":
+		output, err = formatAPLJson(rot)
+		if err != nil {
+			log.Fatalf("failed to marshal APL: %s", err)
+		}
+	default:
+		log.Fatalf("unknown output format %q, expected 'json' or 'text'", format)
+	}
+
+	if outfile == "" {
+		fmt.Print(string(output))
+	} else {
+		err = os.WriteFile(outfile, output, 0666)
+		if err != nil {
+			log.Fatalf("failed to write output file: %s", err)
+		}
+	}
+}
+
+func readAPL(data []byte, isJson bool) (*proto.APLRotation, error) {
+	if !isJson {
+		return apltext.Parse(string(data))
+	}
+	rot := &proto.APLRotation{}
+	if err := protojson.Unmarshal(data, rot); err != nil {
+		return nil, err
+	}
+	return rot, nil
+}
+
+// Formats the APL like the files in ui/*/*/apls, with one list item per line.
+func formatAPLJson(rot *proto.APLRotation) ([]byte, error) {
+	data, err := protojson.Marshal(rot)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	// Known keys keep the order of the files in ui/*/*/apls, any others follow sorted by name.
+	keys := []string{"type", "simple", "variables", "prepullActions", "priorityList", "actionLists"}
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	var lines []string
+	for _, key := range keys {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			// Not a list, so it goes on a single line.
+			lines = append(lines, fmt.Sprintf("\t%q: %s", key, compactJson(value)))
+			continue
+		}
+		itemLines := make([]string, len(items))
+		for i, item := range items {
+			itemLines[i] = "\t\t" + compactJson(item)
+		}
+		lines = append(lines, fmt.Sprintf("\t%q: [\n%s\n\t]", key, strings.Join(itemLines, ",\n")))
+	}
+
+	return []byte("{\n" + strings.Join(lines, ",\n") + "\n}\n"), nil
+}
+
+func compactJson(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplCompileFormat string
+
+var aplCompileCmd = &cobra.Command{
+	Use:   "compile",
+	Short: "compile a simple rotation into an APL",
+	Long:  "compile the simple rotation of a player into the equivalent APL rotation, which can then be edited",
+	Run:   aplCompileMain,
+}
+
+func init() {
+	aplCompileCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (Player in protojson format, whose rotation has simple rotation options)")
+	aplCompileCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	aplCompileCmd.Flags().StringVar(&aplCompileFormat, "format", "text", "output format, either 'json' or 'text'")
+	aplCompileCmd.MarkFlagRequired("infile")
+
+	aplCmd.AddCommand(aplCompileCmd)
+}
+
+func aplCompileMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	player := &proto.Player{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, player)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.CompileSimpleRotation(&proto.CompileSimpleRotationRequest{Player: player})
+	if result.Error != nil {
+		log.Fatalf("failed to compile simple rotation: %s", result.Error.Message)
+	}
+
+	var output []byte
+	switch aplCompileFormat {
+	case "text":
+		output = []byte(apltext.Format(result.Rotation))
+	case "json":
+		output, err = formatAPLJson(result.Rotation)
+		if err != nil {
+			log.Fatalf("failed to marshal APL: %s", err)
+		}
+	default:
+		log.Fatalf("unknown output format %q, expected 'json' or 'text'", aplCompileFormat)
+	}
+
+	if outfile == "" {
+		fmt.Print(string(output))
+	} else {
+		err = os.WriteFile(outfile, output, 0666)
+		if err != nil {
+			log.Fatalf("failed to write output file: %s", err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "check the APL rotations of a raid for problems",
+	Long:  "print the validations of the APL rotations of all players in a raid sim request, including semantic problems like entries which can never run. Exits with status 1 if there are any",
+	Run:   aplLintMain,
+}
+
+func init() {
+	aplLintCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
+	aplLintCmd.MarkFlagRequired("infile")
+
+	aplCmd.AddCommand(aplLintCmd)
+}
+
+func aplLintMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.RaidSimRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.ComputeStats(&proto.ComputeStatsRequest{Raid: input.Raid, Encounter: input.Encounter})
+	if result.ErrorResult != "" {
+		log.Fatalf("failed to compute stats: %s", result.ErrorResult)
+	}
+
+	numIssues := 0
+	for i, party := range input.Raid.GetParties() {
+		for j, player := range party.Players {
+			if i >= len(result.RaidStats.Parties) || j >= len(result.RaidStats.Parties[i].Players) {
+				continue
+			}
+			label := player.Name
+			if label == "" {
+				label = fmt.Sprintf("party %d player %d", i+1, j+1)
+			}
+			numIssues += printAPLValidations(label, player.Rotation, result.RaidStats.Parties[i].Players[j].RotationStats)
+		}
+	}
+	if numIssues > 0 {
+		os.Exit(1)
+	}
+}
+
+// Prints the validations of the player's rotation, and returns how many there are.
+func printAPLValidations(label string, rotation *proto.APLRotation, stats *proto.APLStats) int {
+	if stats == nil {
+		return 0
+	}
+
+	numIssues := 0
+	printLocation := func(location string, validations []*proto.APLValidation) {
+		for _, validation := range validations {
+			fmt.Printf("%s: %s: %s: %s\n", label, location, validation.LogLevel, validation.Validation)
+			numIssues++
+		}
+	}
+
+	for i, item := range stats.Variables {
+		printLocation(fmt.Sprintf("variable %s", rotation.Variables[i].Name), item.Validations)
+	}
+	for i, item := range stats.PrepullActions {
+		printLocation(fmt.Sprintf("prepull #%d", i+1), item.Validations)
+	}
+	for i, item := range stats.PriorityList {
+		printLocation(fmt.Sprintf("actions #%d", i+1), item.Validations)
+	}
+	for i, list := range stats.ActionLists {
+		name := rotation.ActionLists[i].Name
+		printLocation(fmt.Sprintf("list %s", name), list.Validations)
+		for j, item := range list.Items {
+			printLocation(fmt.Sprintf("list %s #%d", name, j+1), item.Validations)
+		}
+	}
+	for _, uuidValidations := range stats.UuidValidations {
+		printLocation(fmt.Sprintf("value %s", uuidValidations.Uuid.GetValue()), uuidValidations.Validations)
+	}
+	return numIssues
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplTuneCmd = &cobra.Command{
+	Use:   "tune",
+	Short: "tune the constants of APL rotations",
+	Long:  "search for the values of the APL constants marked with a tuning range which maximize raid DPS, and print them as a table",
+	Run:   aplTuneMain,
+}
+
+var (
+	aplTuneIterations     int32
+	aplTuneMaxEvaluations int32
+)
+
+func init() {
+	aplTuneCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (TuneAPLRequest in protojson format)")
+	aplTuneCmd.Flags().StringVar(&outfile, "outfile", "", "location of an output file to write the full TuneAPLResult to, in protojson format")
+	aplTuneCmd.Flags().Int32Var(&aplTuneIterations, "iterations", 0, "iterations for each evaluated set of values, overriding the request")
+	aplTuneCmd.Flags().Int32Var(&aplTuneMaxEvaluations, "max-evaluations", 0, "limit on the number of evaluated sets of values, overriding the request")
+	aplTuneCmd.Flags().BoolVar(&verbose, "verbose", false, "print all evaluated sets of values")
+	aplTuneCmd.MarkFlagRequired("infile")
+
+	aplCmd.AddCommand(aplTuneCmd)
+}
+
+func aplTuneMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.TuneAPLRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+	if aplTuneIterations != 0 {
+		input.Iterations = aplTuneIterations
+	}
+	if aplTuneMaxEvaluations != 0 {
+		input.MaxEvaluations = aplTuneMaxEvaluations
+	}
+
+	result := core.TuneAPL(input)
+	if result.Error != nil {
+		log.Fatalf("failed to tune APL: %s", result.Error.Message)
+	}
+	printAPLTuneResult(result)
+
+	if outfile != "" {
+		output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+		if err != nil {
+			log.Fatalf("failed to marshal final results: %s", err)
+		}
+		if err := os.WriteFile(outfile, output, 0666); err != nil {
+			log.Fatalf("failed to write output file: %s", err)
+		}
+	}
+}
+
+func printAPLTuneResult(result *proto.TuneAPLResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	if verbose {
+		for _, tuned := range result.Consts {
+			fmt.Fprintf(w, "%s\t", tuned.Name)
+		}
+		fmt.Fprintln(w, "DPS\t± Stderr\t")
+		for _, evaluation := range result.Evaluations {
+			for _, value := range evaluation.Values {
+				fmt.Fprintf(w, "%g\t", value)
+			}
+			fmt.Fprintf(w, "%.2f\t%.2f\t\n", evaluation.Dps, evaluation.DpsStderr)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "Constant\tInitial\tTuned\t")
+	for _, tuned := range result.Consts {
+		fmt.Fprintf(w, "%s\t%g\t%s\t\n", tuned.Name, tuned.InitialValue, tuned.Val)
+	}
+	w.Flush()
+
+	fmt.Printf("Initial DPS: %.2f [%.2f, %.2f]\n", result.InitialDps, result.InitialDpsInterval.GetLower(), result.InitialDpsInterval.GetUpper())
+	fmt.Printf("Tuned DPS: %.2f [%.2f, %.2f]\n", result.Dps, result.DpsInterval.GetLower(), result.DpsInterval.GetUpper())
+	significance := "not significant"
+	if result.DpsDeltaSignificant {
+		significance = "significant"
+	}
+	fmt.Printf("Delta: %+.2f [%+.2f, %+.2f], %s\n", result.DpsDelta, result.DpsDeltaInterval.GetLower(), result.DpsDeltaInterval.GetUpper(), significance)
+	fmt.Printf("Evaluations: %d\n", len(result.Evaluations))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wowsims/mop/sim/core"
@@ -21,9 +22,14 @@ func init() {
 	simCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
 	simCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
 	simCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	simCmd.Flags().StringVar(&combatLogFile, "combatlog", "", "location of a file to write the first iteration to, in the format of the game's combat log")
+	simCmd.Flags().StringVar(&cacheDir, "cachedir", "", "directory to cache results of requests with a fixed random seed in, identical requests return the cached result")
 	simCmd.MarkFlagRequired("infile")
 }
 
+var combatLogFile string
+var cacheDir string
+
 func simMain(cmd *cobra.Command, args []string) {
 	data, err := os.ReadFile(infile)
 	if err != nil {
@@ -34,20 +40,40 @@ func simMain(cmd *cobra.Command, args []string) {
 	if err != nil {
 		log.Fatalf("failed to load input json file: %s", err)
 	}
+	if combatLogFile != "" {
+		if input.SimOptions == nil {
+			input.SimOptions = &proto.SimOptions{}
+		}
+		input.SimOptions.DebugFirstIteration = true
+		input.SimOptions.LogEvents = true
+	}
 
-	var output []byte
-	reporter := make(chan *proto.ProgressMetrics, 10)
-	core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
+	var cache *core.SimResultCache
+	if cacheDir != "" {
+		cache, err = core.NewSimResultCache(0, cacheDir)
+		if err != nil {
+			log.Fatalf("failed to open result cache: %s", err)
+		}
+	}
 
+	var output []byte
 	var finalResult *proto.RaidSimResult
-	for v := range reporter {
-		if v.FinalRaidResult != nil {
-			finalResult = v.FinalRaidResult
-			break
-		}
+	if cache != nil {
+		finalResult = cache.Get(input)
+	}
+	if finalResult != nil {
 		if verbose {
-			fmt.Printf("Sim Progress: %d / %d\n", v.CompletedIterations, v.TotalIterations)
+			fmt.Printf("Using cached result %s\n", finalResult.CacheInfo.RequestHash)
 		}
+	} else {
+		finalResult = runSim(input)
+		if cache != nil {
+			cache.Put(input, finalResult)
+		}
+	}
+
+	if combatLogFile != "" && finalResult.Error == nil {
+		writeCombatLog(finalResult)
 	}
 
 	output, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(finalResult)
@@ -67,3 +93,33 @@ func simMain(cmd *cobra.Command, args []string) {
 		}
 	}
 }
+
+func runSim(input *proto.RaidSimRequest) *proto.RaidSimResult {
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
+
+	for v := range reporter {
+		if v.FinalRaidResult != nil {
+			return v.FinalRaidResult
+		}
+		if verbose {
+			fmt.Printf("Sim Progress: %d / %d\n", v.CompletedIterations, v.TotalIterations)
+		}
+	}
+	return nil
+}
+
+func writeCombatLog(result *proto.RaidSimResult) {
+	file, err := os.Create(combatLogFile)
+	if err != nil {
+		log.Fatalf("failed to create combat log file %q: %v", combatLogFile, err)
+	}
+	defer file.Close()
+
+	if err := core.WriteCombatLog(file, result, time.Now()); err != nil {
+		log.Fatalf("failed to write combat log: %s", err)
+	}
+	if verbose {
+		fmt.Printf("Wrote combat log: `%s` successfully.\n", combatLogFile)
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [file] [file]...",
+	Short: "compare the dps of two or more sims",
+	Long:  "run two or more sims (RaidSimRequests in protojson format) with identical seeds, and print the dps of each with its difference to the first sim",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   compareMain,
+}
+
+var (
+	compareSeed             int64
+	compareConfidenceLevel  float64
+	compareFailOnRegression bool
+)
+
+func init() {
+	compareCmd.Flags().Int64Var(&compareSeed, "seed", 0, "random seed used for all sims, defaults to the seed of the first sim or a random one")
+	compareCmd.Flags().Float64Var(&compareConfidenceLevel, "confidence", 0.95, "confidence level of the error bars")
+	compareCmd.Flags().BoolVar(&compareFailOnRegression, "fail-on-regression", false, "exit with status 1 if any sim has significantly lower dps than the first")
+}
+
+func compareMain(cmd *cobra.Command, args []string) {
+	requests := make([]*proto.RaidSimRequest, len(args))
+	for i, file := range args {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatalf("failed to load input json file %q: %v", file, err)
+		}
+		requests[i] = &proto.RaidSimRequest{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, requests[i])); err != nil {
+			log.Fatalf("failed to load input json file %q: %s", file, err)
+		}
+	}
+
+	comparisons, err := core.CompareRaidSims(requests, compareSeed, compareConfidenceLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Sim\tDPS\t± Error\tDelta\t± Error\t\t")
+	regression := false
+	for i, comparison := range comparisons {
+		if i == 0 {
+			fmt.Fprintf(w, "%s\t%.2f\t%.2f\t\t\t\t\n", args[i], comparison.Dps, comparison.DpsError)
+			continue
+		}
+		significance := ""
+		if comparison.DpsDeltaSignificant {
+			significance = "*"
+			regression = regression || comparison.DpsDelta < 0
+		}
+		fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%+.2f\t%.2f\t%s\t\n", args[i], comparison.Dps, comparison.DpsError, comparison.DpsDelta, comparison.DpsDeltaError, significance)
+	}
+	w.Flush()
+	fmt.Printf("Errors are half-widths of %g%% confidence intervals, * marks significant deltas.\n", compareConfidenceLevel*100)
+
+	if compareFailOnRegression && regression {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [settings] [settings]",
+	Short: "print the differences between two settings",
+	Long:  "print the field-level differences between two settings, each given as a wowsims link or a file in protojson format. Exits with status 1 if they differ",
+	Args:  cobra.ExactArgs(2),
+	Run:   diffMain,
+}
+
+var diffFileType string
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFileType, "type", "individual", "message type of settings given as files, one of 'individual' (IndividualSimSettings), 'raid' (RaidSimSettings) or 'request' (RaidSimRequest)")
+}
+
+func diffMain(cmd *cobra.Command, args []string) {
+	var settings [2]goproto.Message
+	for i, arg := range args {
+		var err error
+		settings[i], err = loadSettings(arg)
+		if err != nil {
+			log.Fatalf("failed to load settings %q: %s", arg, err)
+		}
+	}
+	if settings[0].ProtoReflect().Descriptor() != settings[1].ProtoReflect().Descriptor() {
+		log.Fatalf("cannot diff %s with %s", settings[0].ProtoReflect().Descriptor().Name(), settings[1].ProtoReflect().Descriptor().Name())
+	}
+
+	differences := diffProto(settings[0], settings[1])
+	for _, difference := range differences {
+		fmt.Println(difference)
+	}
+	if len(differences) > 0 {
+		os.Exit(1)
+	}
+}
+
+// Loads settings from a wowsims link, or from a file in protojson format.
+func loadSettings(arg string) (goproto.Message, error) {
+	if strings.Contains(arg, "#") {
+		return decodeSettingsLink(arg)
+	}
+
+	var settings goproto.Message
+	switch diffFileType {
+	case "individual":
+		settings = &proto.IndividualSimSettings{}
+	case "raid":
+		settings = &proto.RaidSimSettings{}
+	case "request":
+		settings = &proto.RaidSimRequest{}
+	default:
+		return nil, fmt.Errorf("unknown settings type %q", diffFileType)
+	}
+
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, settings)); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Returns one line per differing field of the two messages, as "path: a -> b".
+func diffProto(a goproto.Message, b goproto.Message) []string {
+	var differences []string
+	diffMessage(&differences, "", a.ProtoReflect(), b.ProtoReflect())
+	return differences
+}
+
+func diffMessage(differences *[]string, path string, a protoreflect.Message, b protoreflect.Message) {
+	fields := a.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !a.Has(field) && !b.Has(field) {
+			continue
+		}
+		fieldPath := field.JSONName()
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		switch {
+		case field.IsList():
+			diffList(differences, fieldPath, field, a.Get(field).List(), b.Get(field).List())
+		case field.IsMap():
+			diffMap(differences, fieldPath, field, a.Get(field).Map(), b.Get(field).Map())
+		default:
+			diffValue(differences, fieldPath, field, a.Get(field), b.Get(field), true, true)
+		}
+	}
+}
+
+func diffList(differences *[]string, path string, field protoreflect.FieldDescriptor, a protoreflect.List, b protoreflect.List) {
+	for i := 0; i < max(a.Len(), b.Len()); i++ {
+		var aValue, bValue protoreflect.Value
+		if i < a.Len() {
+			aValue = a.Get(i)
+		}
+		if i < b.Len() {
+			bValue = b.Get(i)
+		}
+		diffValue(differences, fmt.Sprintf("%s[%d]", path, i), field, aValue, bValue, i < a.Len(), i < b.Len())
+	}
+}
+
+func diffMap(differences *[]string, path string, field protoreflect.FieldDescriptor, a protoreflect.Map, b protoreflect.Map) {
+	var keys []protoreflect.MapKey
+	a.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	b.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		if !a.Has(key) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	for _, key := range keys {
+		diffValue(differences, fmt.Sprintf("%s[%s]", path, key.String()), field.MapValue(), a.Get(key), b.Get(key), a.Has(key), b.Has(key))
+	}
+}
+
+// Compares a single value, which is missing if it is not present in a list or map.
+func diffValue(differences *[]string, path string, field protoreflect.FieldDescriptor, a protoreflect.Value, b protoreflect.Value, aPresent bool, bPresent bool) {
+	if field.Message() != nil && aPresent && bPresent {
+		diffMessage(differences, path, a.Message(), b.Message())
+		return
+	}
+
+	aText, bText := formatValue(field, a, aPresent), formatValue(field, b, bPresent)
+	if aText != bText {
+		*differences = append(*differences, fmt.Sprintf("%s: %s -> %s", path, aText, bText))
+	}
+}
+
+func formatValue(field protoreflect.FieldDescriptor, value protoreflect.Value, present bool) string {
+	switch {
+	case !present:
+		return "<none>"
+	case field.Message() != nil:
+		// Only messages missing on the other side are formatted.
+		data, err := protojson.Marshal(value.Message().Interface())
+		if err != nil {
+			return "<invalid>"
+		}
+		return string(data)
+	case field.Enum() != nil:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return fmt.Sprintf("%d", value.Enum())
+	case field.Kind() == protoreflect.StringKind:
+		return fmt.Sprintf("%q", value.String())
+	case field.Kind() == protoreflect.BytesKind:
+		return fmt.Sprintf("%x", value.Bytes())
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
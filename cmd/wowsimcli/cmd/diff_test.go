@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestDiffProto(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		Raid:      &proto.Raid{Parties: []*proto.Party{{Players: []*proto.Player{{Name: "a", Race: proto.Race_RaceHuman}}}}},
+		Encounter: &proto.Encounter{Duration: 180},
+	}
+	if differences := diffProto(request, goproto.Clone(request)); len(differences) != 0 {
+		t.Fatalf("Expected no differences between identical requests, got %v", differences)
+	}
+
+	changed := goproto.Clone(request).(*proto.RaidSimRequest)
+	changed.Raid.Parties[0].Players[0].Race = proto.Race_RaceOrc
+	if differences := diffProto(request, changed); !slices.Equal(differences, []string{"raid.parties[0].players[0].race: RaceHuman -> RaceOrc"}) {
+		t.Fatalf("Expected the race to differ, got %v", differences)
+	}
+
+	changed = goproto.Clone(request).(*proto.RaidSimRequest)
+	changed.Encounter.Duration = 300
+	changed.Raid.Parties[0].Players = append(changed.Raid.Parties[0].Players, &proto.Player{Name: "b"})
+	// Messages missing on one side are printed as protojson, whose whitespace is not stable.
+	differences := diffProto(request, changed)
+	if len(differences) != 2 || !strings.HasPrefix(differences[0], "raid.parties[0].players[1]: <none> -> {") || differences[1] != "encounter.duration: 180 -> 300" {
+		t.Fatalf("Expected the added player and the duration to differ, got %v", differences)
+	}
+}
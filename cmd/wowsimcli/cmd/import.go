@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/importer"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [export]",
+	Short: "import a character from the in-game addon, Sixty Upgrades or Wowhead",
+	Long:  "import a character from a WowSimsExporter addon export, a Sixty Upgrades export or a Wowhead gear planner link, given as an argument or with --infile, and print the resulting settings",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   importMain,
+}
+
+var (
+	importFormat    string
+	importBase      string
+	importAsRequest bool
+)
+
+func init() {
+	importCmd.Flags().StringVar(&infile, "infile", "", "location of a file containing the export, if not given as an argument")
+	importCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	importCmd.Flags().StringVar(&importFormat, "format", "", fmt.Sprintf("format of the export, one of %q, detected if not given", importer.Formats))
+	importCmd.Flags().StringVar(&importBase, "base", "", "wowsims link or file (IndividualSimSettings in protojson format) with the settings to import into, e.g. the spec options, rotation and encounter")
+	importCmd.Flags().BoolVar(&importAsRequest, "request", false, "print a RaidSimRequest, which can be run with the sim command, instead of IndividualSimSettings")
+}
+
+func importMain(cmd *cobra.Command, args []string) {
+	var export string
+	switch {
+	case len(args) == 1:
+		export = args[0]
+	case infile != "":
+		data, err := os.ReadFile(infile)
+		if err != nil {
+			log.Fatalf("failed to load export file %q: %v", infile, err)
+		}
+		export = string(data)
+	default:
+		log.Fatalf("an export or --infile is required")
+	}
+
+	character, err := importer.Parse(importer.Format(importFormat), export)
+	if err != nil {
+		log.Fatalf("failed to import: %s", err)
+	}
+	for _, warning := range character.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	var base *proto.IndividualSimSettings
+	if importBase != "" {
+		base, err = loadIndividualSimSettings(importBase)
+		if err != nil {
+			log.Fatalf("failed to load base settings: %s", err)
+		}
+	}
+	settings, err := character.ApplyTo(base)
+	if err != nil {
+		log.Fatalf("failed to import: %s", err)
+	}
+
+	var message goproto.Message = settings
+	if importAsRequest {
+		message = individualSimRequest(settings)
+	}
+	output, err := protojson.MarshalOptions{Multiline: true}.Marshal(message)
+	if err != nil {
+		log.Fatalf("failed to marshal settings: %s", err)
+	}
+
+	if outfile == "" {
+		fmt.Println(string(output))
+	} else if err := os.WriteFile(outfile, output, 0666); err != nil {
+		log.Fatalf("failed to write output file: %s", err)
+	}
+}
+
+// Loads IndividualSimSettings from a wowsims link, or a file in protojson format.
+func loadIndividualSimSettings(linkOrFile string) (*proto.IndividualSimSettings, error) {
+	if strings.Contains(linkOrFile, "#") {
+		settings, err := decodeSettingsLink(linkOrFile)
+		if err != nil {
+			return nil, err
+		}
+		individualSettings, ok := settings.(*proto.IndividualSimSettings)
+		if !ok {
+			return nil, fmt.Errorf("expected an individual sim link")
+		}
+		return individualSettings, nil
+	}
+
+	data, err := os.ReadFile(linkOrFile)
+	if err != nil {
+		return nil, err
+	}
+	settings := &proto.IndividualSimSettings{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Builds the request the individual sim UI runs for the settings.
+func individualSimRequest(settings *proto.IndividualSimSettings) *proto.RaidSimRequest {
+	raid := core.SinglePlayerRaidProto(settings.Player, settings.PartyBuffs, settings.RaidBuffs, settings.Debuffs)
+	raid.Tanks = settings.Tanks
+	raid.TargetDummies = settings.TargetDummies
+	raid.IncomingDamage = settings.IncomingDamage
+
+	iterations := settings.GetSettings().GetIterations()
+	if iterations == 0 {
+		iterations = 12500 // The default of the UI.
+	}
+	return &proto.RaidSimRequest{
+		Type:      proto.SimType_SimTypeIndividual,
+		Raid:      raid,
+		Encounter: settings.Encounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: iterations,
+			RandomSeed: settings.GetSettings().GetFixedRngSeed(),
+		},
+	}
+}
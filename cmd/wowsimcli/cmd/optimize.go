@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var optimizeCmd = &cobra.Command{
+	Use:   "optimize",
+	Short: "choose reforges, gems and enchants for a gear set",
+	Long:  "choose reforges, gems and enchants for a gear set, based on stat weights and stat caps",
+	Run:   optimizeMain,
+}
+
+func init() {
+	optimizeCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (OptimizeGearRequest in protojson format)")
+	optimizeCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	optimizeCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	optimizeCmd.MarkFlagRequired("infile")
+}
+
+func optimizeMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.OptimizeGearRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.OptimizeGear(input)
+	if result.Error != nil {
+		log.Fatalf("failed to optimize gear: %s", result.Error.Message)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Score: %0.2f --> %0.2f\n", result.InitialScore, result.Score)
+	}
+
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+	if err != nil {
+		log.Fatalf("failed to marshal final results: %s", err)
+	}
+
+	if outfile == "" {
+		fmt.Print(string(output))
+	} else {
+		err = os.WriteFile(outfile, output, 0666)
+		if err != nil {
+			log.Fatalf("failed to write output file:: %s", err)
+		}
+		if verbose {
+			fmt.Printf("Wrote output file: `%s` successfully.\n", outfile)
+		}
+	}
+}
@@ -18,6 +18,12 @@ func Execute(version string) {
 	rootCmd.AddCommand(simCmd)
 	rootCmd.AddCommand(bulkCmd)
 	rootCmd.AddCommand(decodeLinkCmd)
+	rootCmd.AddCommand(optimizeCmd)
+	rootCmd.AddCommand(aplCmd)
+	rootCmd.AddCommand(statWeightsCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(importCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var statWeightsCmd = &cobra.Command{
+	Use:   "statweights",
+	Short: "calculate stat weights and EP values",
+	Long:  "calculate stat weights and EP values, and print them as a table",
+	Run:   statWeightsMain,
+}
+
+var (
+	statWeightsInfile  string
+	statWeightsOutfile string
+	statWeightsMetric  string
+	statWeightsVerbose bool
+)
+
+func init() {
+	statWeightsCmd.Flags().StringVar(&statWeightsInfile, "infile", "input.json", "location of input file (StatWeightsRequest in protojson format)")
+	statWeightsCmd.Flags().StringVar(&statWeightsOutfile, "outfile", "", "location of an output file to write the full StatWeightsResult to, in protojson format")
+	statWeightsCmd.Flags().StringVar(&statWeightsMetric, "metric", "dps", "metric to print the weights for, one of 'dps', 'hps', 'tps', 'dtps', 'tmi' or 'pdeath'")
+	statWeightsCmd.Flags().BoolVar(&statWeightsVerbose, "verbose", false, "print information during runtime")
+	statWeightsCmd.MarkFlagRequired("infile")
+}
+
+func statWeightsMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(statWeightsInfile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", statWeightsInfile, err)
+	}
+	input := &proto.StatWeightsRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.StatWeightsAsync(input, reporter, "cmd-stat-weights")
+
+	var result *proto.StatWeightsResult
+	for v := range reporter {
+		if v.FinalWeightResult != nil {
+			result = v.FinalWeightResult
+			break
+		}
+		if statWeightsVerbose {
+			fmt.Fprintf(os.Stderr, "Stat Weights Progress: %d / %d sims, %d / %d iterations\n", v.CompletedSims, v.TotalSims, v.CompletedIterations, v.TotalIterations)
+		}
+	}
+	if result == nil {
+		log.Fatalf("failed to calculate stat weights: no result was reported")
+	}
+	if result.Error != nil {
+		log.Fatalf("failed to calculate stat weights: %s", result.Error.Message)
+	}
+
+	values := map[string]*proto.StatWeightValues{
+		"dps":    result.Dps,
+		"hps":    result.Hps,
+		"tps":    result.Tps,
+		"dtps":   result.Dtps,
+		"tmi":    result.Tmi,
+		"pdeath": result.PDeath,
+	}[statWeightsMetric]
+	if values == nil {
+		log.Fatalf("unknown metric %q", statWeightsMetric)
+	}
+	printStatWeights(input, values)
+
+	if statWeightsOutfile != "" {
+		output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+		if err != nil {
+			log.Fatalf("failed to marshal final results: %s", err)
+		}
+		if err := os.WriteFile(statWeightsOutfile, output, 0666); err != nil {
+			log.Fatalf("failed to write output file: %s", err)
+		}
+	}
+}
+
+// Prints a table of the weights and EP values of the weighed stats.
+func printStatWeights(input *proto.StatWeightsRequest, values *proto.StatWeightValues) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Stat\tWeight\t± Stdev\tEP\t± Stdev\t")
+
+	printRow := func(name string, stat stats.UnitStat) {
+		fmt.Fprintf(w, "%s\t%.3f\t%.3f\t%.3f\t%.3f\t\n", name,
+			unitStatValue(values.GetWeights(), stat), unitStatValue(values.GetWeightsStdev(), stat),
+			unitStatValue(values.GetEpValues(), stat), unitStatValue(values.GetEpValuesStdev(), stat))
+	}
+	for _, stat := range input.StatsToWeigh {
+		printRow(stats.Stat(stat).StatName(), stats.UnitStatFromStat(stats.Stat(stat)))
+	}
+	for _, pseudoStat := range input.PseudoStatsToWeigh {
+		printRow(strings.TrimPrefix(pseudoStat.String(), "PseudoStat"), stats.UnitStatFromPseudoStat(pseudoStat))
+	}
+	w.Flush()
+
+	if values.VarianceReduction > 0 {
+		fmt.Printf("Variance reduction from common random numbers: %.1fx\n", values.VarianceReduction)
+	}
+}
+
+func unitStatValue(unitStats *proto.UnitStats, stat stats.UnitStat) float64 {
+	values, idx := unitStats.GetStats(), 0
+	if stat.IsStat() {
+		idx = stat.StatIdx()
+	} else {
+		values, idx = unitStats.GetPseudoStats(), stat.PseudoStatIdx()
+	}
+	if idx < len(values) {
+		return values[idx]
+	}
+	return 0
+}
@@ -27,7 +27,7 @@ func ComputeStats(csr *proto.ComputeStatsRequest) *proto.ComputeStatsResult {
  * Returns stat weights and EP values, with standard deviations, for all stats.
  */
 func StatWeights(request *proto.StatWeightsRequest) *proto.StatWeightsResult {
-	return runStatWeights(request, nil, simsignals.CreateSignals())
+	return runStatWeights(request, nil, simsignals.CreateSignals(), nil)
 }
 
 func StatWeightsAsync(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, requestId string) {
@@ -44,7 +44,7 @@ func StatWeightsAsync(request *proto.StatWeightsRequest, progress chan *proto.Pr
 	}
 	go func() {
 		defer simsignals.UnregisterId(requestId)
-		result := runStatWeights(request, progress, signals)
+		result := runStatWeights(request, progress, signals, nil)
 		progress <- &proto.ProgressMetrics{
 			FinalWeightResult: result,
 		}
@@ -131,6 +131,72 @@ func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.Progres
 	}()
 }
 
+// Like RunRaidSimConcurrentAsync, but runs the sim on the shard runner.
+func RunRaidSimDistributedAsync(runner ShardRunner, request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalRaidResult: &proto.RaidSimResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		runSimDistributed(runner, request, progress, signals)
+	}()
+}
+
+// Like StatWeightsAsync, but runs each of the stat weight sims on the shard runner.
+func StatWeightsDistributedAsync(runner ShardRunner, request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalWeightResult: &proto.StatWeightsResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runStatWeights(request, progress, signals, func(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+			return runSimDistributed(runner, request, progress, signals)
+		})
+		progress <- &proto.ProgressMetrics{
+			FinalWeightResult: result,
+		}
+	}()
+}
+
+// Like RunBulkSimAsync, but runs each combo on the shard runner.
+func RunBulkSimDistributedAsync(runner ShardRunner, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalBulkResult: &proto.BulkSimResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		runBulkSim(signals, &bulkSimRunner{
+			SingleRaidSimRunner: distributedBulkSimRunner(runner),
+			Request:             request,
+			Concurrency:         runner.Capacity(),
+		}, progress)
+	}()
+}
+
 var runningInWasm = false
 
 func SetRunningInWasm() {
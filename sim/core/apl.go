@@ -10,9 +10,14 @@ import (
 
 type APLRotation struct {
 	unit           *Unit
+	config         *proto.APLRotation
 	prepullActions []*APLAction
 	priorityList   []*APLAction
 
+	// Named variables and action lists, which can be referenced from other actions and values.
+	variables   []*APLVariable
+	actionLists map[string]*APLActionList
+
 	// Action currently controlling this rotation (only used for certain actions, such as StrictSequence).
 	controllingActions []APLActionImpl
 
@@ -26,6 +31,9 @@ type APLRotation struct {
 	// Used inside of actions/value to determine whether they will occur during the prepull or regular rotation.
 	parsingPrepull bool
 
+	// Action list whose items are currently being parsed, or nil for the main lists.
+	parsingActionList *APLActionList
+
 	// Used to avoid recursive APL loops.
 	inLoop bool
 
@@ -38,10 +46,30 @@ type APLRotation struct {
 	prepullValidations      [][]*proto.APLValidation
 	priorityListValidations [][]*proto.APLValidation
 	uuidValidations         map[*proto.UUID][]*proto.APLValidation
+	variableValidations     [][]*proto.APLValidation
+	actionListValidations   []*aplActionListValidations
 
 	// Maps indices in filtered sim lists to indices in configs.
 	prepullIdxMap      []int
 	priorityListIdxMap []int
+
+	// Action list invocations which led to the action returned by getNextAction.
+	nextActionCallers []*APLAction
+
+	// Number of iterations and total combat time, for averaging the action profiles.
+	profileIterations int32
+	profileDuration   time.Duration
+
+	// Buffers for restoring the action profiles after peekNextAction.
+	peekActions  []*APLAction
+	peekProfiles []aplActionProfile
+}
+
+type aplActionListValidations struct {
+	list        *APLActionList
+	validations []*proto.APLValidation
+	items       [][]*proto.APLValidation
+	itemIdxMap  []int
 }
 
 func (rot *APLRotation) ValidationMessage(log_level proto.LogLevel, message string, vals ...interface{}) {
@@ -94,9 +122,40 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 
 	rotation := &APLRotation{
 		unit:                    unit,
+		config:                  config,
 		prepullValidations:      make([][]*proto.APLValidation, len(config.PrepullActions)),
 		priorityListValidations: make([][]*proto.APLValidation, len(config.PriorityList)),
 		uuidValidations:         make(map[*proto.UUID][]*proto.APLValidation),
+		variableValidations:     make([][]*proto.APLValidation, len(config.Variables)),
+		actionListValidations:   make([]*aplActionListValidations, len(config.ActionLists)),
+		actionLists:             make(map[string]*APLActionList),
+	}
+
+	// Parse variables first, so that their types are known when parsing values which reference them.
+	for i, variableConfig := range config.Variables {
+		rotation.doAndRecordWarnings(&rotation.variableValidations[i], false, func() {
+			if variable := rotation.newAPLVariable(variableConfig); variable != nil {
+				rotation.variables = append(rotation.variables, variable)
+			}
+		})
+	}
+
+	// Register action list names before parsing any actions, so that list invocations can be checked.
+	for i, listConfig := range config.ActionLists {
+		listValidations := &aplActionListValidations{
+			items: make([][]*proto.APLValidation, len(listConfig.Items)),
+		}
+		rotation.actionListValidations[i] = listValidations
+		rotation.doAndRecordWarnings(&listValidations.validations, false, func() {
+			if listConfig.Name == "" {
+				rotation.ValidationMessage(proto.LogLevel_Warning, "Action list must provide a name")
+			} else if _, ok := rotation.actionLists[listConfig.Name]; ok {
+				rotation.ValidationMessage(proto.LogLevel_Warning, "Duplicate action list name: '%s'", listConfig.Name)
+			} else {
+				listValidations.list = &APLActionList{name: listConfig.Name}
+				rotation.actionLists[listConfig.Name] = listValidations.list
+			}
+		})
 	}
 
 	// Parse prepull actions
@@ -118,6 +177,7 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 								// Warnings for prepull cast failure are detected by running a fake prepull,
 								// so this action.Execute needs to record warnings.
 								rotation.doAndRecordWarnings(&rotation.prepullValidations[prepullIdx], true, func() {
+									action.profile.countPrepull()
 									action.Execute(sim)
 								})
 							})
@@ -141,6 +201,27 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 		})
 	}
 
+	// Parse action lists
+	for i, listConfig := range config.ActionLists {
+		listValidations := rotation.actionListValidations[i]
+		if listValidations.list == nil {
+			continue
+		}
+		rotation.parsingActionList = listValidations.list
+		for j, aplItem := range listConfig.Items {
+			rotation.doAndRecordWarnings(&listValidations.items[j], false, func() {
+				if !aplItem.Hide {
+					action := rotation.newAPLAction(aplItem.Action)
+					if action != nil {
+						listValidations.list.actions = append(listValidations.list.actions, action)
+						listValidations.itemIdxMap = append(listValidations.itemIdxMap, j)
+					}
+				}
+			})
+		}
+		rotation.parsingActionList = nil
+	}
+
 	// Finalize
 	for i, action := range rotation.prepullActions {
 		rotation.doAndRecordWarnings(&rotation.prepullValidations[rotation.prepullIdxMap[i]], true, func() {
@@ -152,6 +233,16 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 			action.Finalize(rotation)
 		})
 	}
+	for _, listValidations := range rotation.actionListValidations {
+		if listValidations.list == nil {
+			continue
+		}
+		for i, action := range listValidations.list.actions {
+			rotation.doAndRecordWarnings(&listValidations.items[listValidations.itemIdxMap[i]], false, func() {
+				action.Finalize(rotation)
+			})
+		}
+	}
 
 	agent := unit.Env.GetAgentFromUnit(unit)
 	if agent != nil {
@@ -219,6 +310,17 @@ func (rot *APLRotation) getStats() *proto.APLStats {
 			action.impl.PostFinalize(rot)
 		})
 	}
+	for _, listValidations := range rot.actionListValidations {
+		if listValidations.list == nil {
+			continue
+		}
+		for i, action := range listValidations.list.actions {
+			rot.doAndRecordWarnings(&listValidations.items[listValidations.itemIdxMap[i]], false, func() {
+				action.impl.PostFinalize(rot)
+			})
+		}
+	}
+	rot.lint()
 
 	uuidValidationsArr := make([]*proto.UUIDValidations, len(rot.uuidValidations))
 	i := 0
@@ -238,6 +340,17 @@ func (rot *APLRotation) getStats() *proto.APLStats {
 			return &proto.APLActionStats{Validations: validations}
 		}),
 		UuidValidations: uuidValidationsArr,
+		Variables: MapSlice(rot.variableValidations, func(validations []*proto.APLValidation) *proto.APLActionStats {
+			return &proto.APLActionStats{Validations: validations}
+		}),
+		ActionLists: MapSlice(rot.actionListValidations, func(listValidations *aplActionListValidations) *proto.APLActionListStats {
+			return &proto.APLActionListStats{
+				Validations: listValidations.validations,
+				Items: MapSlice(listValidations.items, func(validations []*proto.APLValidation) *proto.APLActionStats {
+					return &proto.APLActionStats{Validations: validations}
+				}),
+			}
+		}),
 	}
 }
 
@@ -246,7 +359,14 @@ func (rot *APLRotation) allAPLActions() []*APLAction {
 		return []*APLAction{}
 	}
 
-	return Flatten(MapSlice(rot.priorityList, func(action *APLAction) []*APLAction {
+	actions := rot.priorityList
+	for _, listValidations := range rot.actionListValidations {
+		if listValidations.list != nil {
+			actions = append(actions[:len(actions):len(actions)], listValidations.list.actions...)
+		}
+	}
+
+	return Flatten(MapSlice(actions, func(action *APLAction) []*APLAction {
 		// Check if action is nil before calling GetAllActions
 		if action == nil {
 			return []*APLAction{}
@@ -265,6 +385,9 @@ func (rot *APLRotation) reset(sim *Simulation) {
 	rot.inLoop = false
 	rot.interruptChannelIf = nil
 	rot.allowChannelRecastOnInterrupt = false
+	for _, variable := range rot.variables {
+		variable.reset(sim)
+	}
 	for _, action := range rot.allAPLActions() {
 		action.impl.Reset(sim)
 	}
@@ -299,7 +422,7 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 			panic(fmt.Sprintf("[USER_ERROR] Infinite loop detected, current action:\n%s", nextAction))
 		}
 
-		nextAction.Execute(sim)
+		apl.executeNextAction(sim, nextAction)
 	}
 	apl.inLoop = false
 
@@ -319,18 +442,22 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 	}
 }
 
+// Executes the action returned by getNextAction, counting it for the action lists which led to it.
+func (apl *APLRotation) executeNextAction(sim *Simulation, nextAction *APLAction) {
+	for _, caller := range apl.nextActionCallers {
+		caller.profile.executions++
+	}
+	nextAction.Execute(sim)
+}
+
 func (apl *APLRotation) getNextAction(sim *Simulation) *APLAction {
+	apl.nextActionCallers = apl.nextActionCallers[:0]
 	if len(apl.controllingActions) != 0 {
 		return apl.controllingActions[len(apl.controllingActions)-1].GetNextAction(sim)
 	}
 
-	for _, action := range apl.priorityList {
-		if action.IsReady(sim) {
-			return action
-		}
-	}
-
-	return nil
+	nextAction, _ := apl.getNextActionFromList(sim, apl.priorityList)
+	return nextAction
 }
 
 func (apl *APLRotation) pushControllingAction(ca APLActionImpl) {
@@ -358,7 +485,7 @@ func (apl *APLRotation) shouldInterruptChannel(sim *Simulation) bool {
 	}
 
 	// Allow next action to interrupt the channel, but if the action is the same action then it still needs to continue.
-	nextAction := apl.getNextAction(sim)
+	nextAction := apl.peekNextAction(sim)
 	if nextAction == nil {
 		return false
 	}
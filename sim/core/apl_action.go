@@ -9,6 +9,8 @@ import (
 type APLAction struct {
 	condition APLValue
 	impl      APLActionImpl
+
+	profile aplActionProfile
 }
 
 func (action *APLAction) Finalize(rot *APLRotation) {
@@ -19,10 +21,20 @@ func (action *APLAction) Finalize(rot *APLRotation) {
 }
 
 func (action *APLAction) IsReady(sim *Simulation) bool {
-	return (action.condition == nil || action.condition.GetBool(sim)) && action.impl.IsReady(sim)
+	action.profile.evaluations++
+	if action.condition != nil && !action.condition.GetBool(sim) {
+		return false
+	}
+	action.profile.conditionMet++
+	if !action.impl.IsReady(sim) {
+		return false
+	}
+	action.profile.ready++
+	return true
 }
 
 func (action *APLAction) Execute(sim *Simulation) {
+	action.profile.executions++
 	action.impl.Execute(sim)
 }
 
@@ -165,6 +177,8 @@ func (rot *APLRotation) newAPLActionImpl(config *proto.APLAction) APLActionImpl
 		return rot.newActionWait(config.GetWait())
 	case *proto.APLAction_WaitUntil:
 		return rot.newActionWaitUntil(config.GetWaitUntil())
+	case *proto.APLAction_PoolResource:
+		return rot.newActionPoolResource(config.GetPoolResource())
 	case *proto.APLAction_Schedule:
 		return rot.newActionSchedule(config.GetSchedule())
 
@@ -176,6 +190,14 @@ func (rot *APLRotation) newAPLActionImpl(config *proto.APLAction) APLActionImpl
 	case *proto.APLAction_StrictSequence:
 		return rot.newActionStrictSequence(config.GetStrictSequence())
 
+	// Variables and action lists
+	case *proto.APLAction_SetVariable:
+		return rot.newActionSetVariable(config.GetSetVariable())
+	case *proto.APLAction_CallActionList:
+		return rot.newActionCallActionList(config.GetCallActionList())
+	case *proto.APLAction_RunActionList:
+		return rot.newActionRunActionList(config.GetRunActionList())
+
 	// Misc
 	case *proto.APLAction_ChangeTarget:
 		return rot.newActionChangeTarget(config.GetChangeTarget())
@@ -176,8 +176,8 @@ func (action *APLActionMultidot) IsReady(sim *Simulation) bool {
 			}
 		}
 	} else {
-		for i := int32(0); i < action.maxDots; i++ {
-			target := sim.Encounter.TargetUnits[i]
+		for i := int32(0); i < min(action.maxDots, sim.GetNumTargets()); i++ {
+			target := sim.GetActiveTargetUnit(i)
 			dot := action.spell.Dot(target)
 			if (!dot.IsActive() || dot.RemainingDuration(sim) < maxOverlap) && action.spell.CanCastOrQueue(sim, target) {
 				action.nextTarget = target
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// A named list of actions, which can be invoked from other lists.
+type APLActionList struct {
+	name    string
+	actions []*APLAction
+}
+
+// Returns the first ready action from actions, descending into any called action lists.
+// The second return value is true if no further actions should be considered, which
+// happens when a Run Action List action has been reached.
+func (rot *APLRotation) getNextActionFromList(sim *Simulation, actions []*APLAction) (*APLAction, bool) {
+	for _, action := range actions {
+		switch impl := action.impl.(type) {
+		case *APLActionCallActionList:
+			if !rot.shouldInvokeActionList(sim, action, impl.list) {
+				continue
+			}
+			if nextAction, stop := rot.getNextActionFromList(sim, impl.list.actions); nextAction != nil || stop {
+				rot.addNextActionCaller(action, nextAction)
+				return nextAction, stop
+			}
+		case *APLActionRunActionList:
+			if !rot.shouldInvokeActionList(sim, action, impl.list) {
+				continue
+			}
+			nextAction, _ := rot.getNextActionFromList(sim, impl.list.actions)
+			rot.addNextActionCaller(action, nextAction)
+			return nextAction, true
+		default:
+			if action.IsReady(sim) {
+				return action, false
+			}
+		}
+	}
+	return nil, false
+}
+
+func (rot *APLRotation) shouldInvokeActionList(sim *Simulation, action *APLAction, list *APLActionList) bool {
+	if list == nil {
+		return false
+	}
+	action.profile.evaluations++
+	if action.condition != nil && !action.condition.GetBool(sim) {
+		return false
+	}
+	action.profile.conditionMet++
+	return true
+}
+
+// Records that the action list invocation provided the next action, if any.
+func (rot *APLRotation) addNextActionCaller(action *APLAction, nextAction *APLAction) {
+	if nextAction != nil {
+		action.profile.ready++
+		rot.nextActionCallers = append(rot.nextActionCallers, action)
+	}
+}
+
+// Whether an action in from (or any list invoked by it) invokes the to list.
+func (rot *APLRotation) actionListReaches(from *APLActionList, to *APLActionList, visited map[*APLActionList]bool) bool {
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	for _, action := range Flatten(MapSlice(from.actions, func(action *APLAction) []*APLAction { return action.GetAllActions() })) {
+		var listName string
+		switch impl := action.impl.(type) {
+		case *APLActionCallActionList:
+			listName = impl.listName
+		case *APLActionRunActionList:
+			listName = impl.listName
+		default:
+			continue
+		}
+
+		if invoked := rot.actionLists[listName]; invoked == to || (invoked != nil && rot.actionListReaches(invoked, to, visited)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Shared implementation of Call Action List and Run Action List.
+type aplActionListInvocation struct {
+	defaultAPLActionImpl
+	rot      *APLRotation
+	listName string
+	parent   *APLActionList // List containing this action, or nil for the main lists.
+	list     *APLActionList
+}
+
+func (rot *APLRotation) newActionListInvocation(listName string) aplActionListInvocation {
+	return aplActionListInvocation{
+		rot:      rot,
+		listName: listName,
+		parent:   rot.parsingActionList,
+	}
+}
+func (action *aplActionListInvocation) Finalize(rot *APLRotation) {
+	list, ok := rot.actionLists[action.listName]
+	if !ok {
+		rot.ValidationMessage(proto.LogLevel_Warning, "No action list with name: '%s'", action.listName)
+		return
+	}
+	if action.parent != nil && (list == action.parent || rot.actionListReaches(list, action.parent, make(map[*APLActionList]bool))) {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Action list '%s' recursively invokes itself, ignoring this action", action.parent.name)
+		return
+	}
+	action.list = list
+}
+func (action *aplActionListInvocation) IsReady(sim *Simulation) bool {
+	if action.list == nil {
+		return false
+	}
+	nextAction, _ := action.rot.getNextActionFromList(sim, action.list.actions)
+	return nextAction != nil
+}
+func (action *aplActionListInvocation) Execute(sim *Simulation) {
+	if nextAction, _ := action.rot.getNextActionFromList(sim, action.list.actions); nextAction != nil {
+		nextAction.Execute(sim)
+	}
+}
+
+type APLActionCallActionList struct {
+	aplActionListInvocation
+}
+
+func (rot *APLRotation) newActionCallActionList(config *proto.APLActionCallActionList) APLActionImpl {
+	if config.ListName == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Call Action List must provide an action list name")
+		return nil
+	}
+	return &APLActionCallActionList{
+		aplActionListInvocation: rot.newActionListInvocation(config.ListName),
+	}
+}
+func (action *APLActionCallActionList) String() string {
+	return fmt.Sprintf("Call Action List(%s)", action.listName)
+}
+
+type APLActionRunActionList struct {
+	aplActionListInvocation
+}
+
+func (rot *APLRotation) newActionRunActionList(config *proto.APLActionRunActionList) APLActionImpl {
+	if rot.parsingPrepull {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Run Action List cannot be used as a prepull action")
+		return nil
+	}
+	if config.ListName == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Run Action List must provide an action list name")
+		return nil
+	}
+	return &APLActionRunActionList{
+		aplActionListInvocation: rot.newActionListInvocation(config.ListName),
+	}
+}
+func (action *APLActionRunActionList) String() string {
+	return fmt.Sprintf("Run Action List(%s)", action.listName)
+}
+
+type APLActionSetVariable struct {
+	defaultAPLActionImpl
+	variable *APLVariable
+	value    APLValue
+}
+
+func (rot *APLRotation) newActionSetVariable(config *proto.APLActionSetVariable) APLActionImpl {
+	if config.Name == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Set Variable must provide a variable name")
+		return nil
+	}
+	variable := rot.getAPLVariable(config.Name)
+	if variable == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "No variable with name: '%s'", config.Name)
+		return nil
+	}
+	value := rot.coerceTo(rot.newAPLValue(config.Value), variable.Type())
+	if value == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Set Variable must provide a value")
+		return nil
+	}
+	return &APLActionSetVariable{
+		variable: variable,
+		value:    value,
+	}
+}
+func (action *APLActionSetVariable) GetAPLValues() []APLValue {
+	return []APLValue{action.value}
+}
+
+// Only ready when it would change the variable, so that the rotation moves on
+// to the next action once the variable has been set.
+func (action *APLActionSetVariable) IsReady(sim *Simulation) bool {
+	return action.variable.differsFrom(sim, action.value)
+}
+func (action *APLActionSetVariable) Execute(sim *Simulation) {
+	action.variable.set(sim, action.value)
+}
+func (action *APLActionSetVariable) String() string {
+	return fmt.Sprintf("Set Variable(name = '%s', value = %s)", action.variable.name, action.value)
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+type aplTestAction struct {
+	defaultAPLActionImpl
+	ready bool
+}
+
+func (action *aplTestAction) IsReady(*Simulation) bool { return action.ready }
+func (action *aplTestAction) Execute(*Simulation)      {}
+func (action *aplTestAction) String() string           { return "Test Action" }
+
+func newTestActionList(rot *APLRotation, name string, actions ...*APLAction) *APLActionList {
+	list := &APLActionList{name: name, actions: actions}
+	rot.actionLists[name] = list
+	return list
+}
+
+func newTestListInvocation(rot *APLRotation, parent *APLActionList, listName string, run bool) *APLAction {
+	invocation := aplActionListInvocation{rot: rot, listName: listName, parent: parent}
+	if run {
+		return &APLAction{impl: &APLActionRunActionList{invocation}}
+	}
+	return &APLAction{impl: &APLActionCallActionList{invocation}}
+}
+
+func TestActionListInvocations(t *testing.T) {
+	sim := &Simulation{}
+	rot := &APLRotation{actionLists: make(map[string]*APLActionList)}
+
+	notReady := &APLAction{impl: &aplTestAction{ready: false}}
+	ready := &APLAction{impl: &aplTestAction{ready: true}}
+	fallback := &APLAction{impl: &aplTestAction{ready: true}}
+
+	newTestActionList(rot, "empty", notReady)
+	newTestActionList(rot, "ready", notReady, ready)
+
+	callEmpty := newTestListInvocation(rot, nil, "empty", false)
+	callReady := newTestListInvocation(rot, nil, "ready", false)
+	runEmpty := newTestListInvocation(rot, nil, "empty", true)
+	for _, action := range []*APLAction{callEmpty, callReady, runEmpty} {
+		action.impl.Finalize(rot)
+	}
+
+	if next, _ := rot.getNextActionFromList(sim, []*APLAction{callEmpty, fallback}); next != fallback {
+		t.Fatalf("Expected evaluation to continue after a call to a list without ready actions")
+	}
+	if next, _ := rot.getNextActionFromList(sim, []*APLAction{callReady, fallback}); next != ready {
+		t.Fatalf("Expected the ready action from the called list")
+	}
+	if next, _ := rot.getNextActionFromList(sim, []*APLAction{runEmpty, fallback}); next != nil {
+		t.Fatalf("Expected evaluation to stop after running a list without ready actions")
+	}
+}
+
+func TestActionListRecursion(t *testing.T) {
+	rot := &APLRotation{actionLists: make(map[string]*APLActionList)}
+
+	listA := newTestActionList(rot, "a")
+	listB := newTestActionList(rot, "b")
+	callB := newTestListInvocation(rot, listA, "b", false)
+	callA := newTestListInvocation(rot, listB, "a", false)
+	listA.actions = []*APLAction{callB}
+	listB.actions = []*APLAction{callA}
+
+	rot.doAndRecordWarnings(nil, false, func() {
+		callA.impl.Finalize(rot)
+	})
+	if callA.impl.(*APLActionCallActionList).list != nil {
+		t.Fatalf("Expected recursive action list call to be ignored")
+	}
+}
+
+func TestVariables(t *testing.T) {
+	sim := &Simulation{}
+	rot := &APLRotation{unit: &Unit{}}
+
+	variable := &APLVariable{
+		name:      "counter",
+		initValue: rot.newValueConst(&proto.APLValueConst{Val: "1"}, nil),
+	}
+	rot.variables = []*APLVariable{variable}
+	variable.reset(sim)
+
+	value := &APLValueVariable{variable: variable}
+	if value.Type() != proto.APLValueType_ValueTypeInt || value.GetInt(sim) != 1 {
+		t.Fatalf("Unexpected initial variable value %d", value.GetInt(sim))
+	}
+
+	setVariable := &APLActionSetVariable{
+		variable: variable,
+		value:    rot.coerceTo(rot.newValueConst(&proto.APLValueConst{Val: "3"}, nil), variable.Type()),
+	}
+	if !setVariable.IsReady(sim) {
+		t.Fatalf("Expected Set Variable to be ready when it changes the variable")
+	}
+	setVariable.Execute(sim)
+	if value.GetInt(sim) != 3 {
+		t.Fatalf("Unexpected variable value %d after Set Variable", value.GetInt(sim))
+	}
+	if setVariable.IsReady(sim) {
+		t.Fatalf("Expected Set Variable to not be ready when the variable already has its value")
+	}
+
+	variable.reset(sim)
+	if value.GetInt(sim) != 1 {
+		t.Fatalf("Expected variable to be reset to its initial value, got %d", value.GetInt(sim))
+	}
+}
@@ -96,6 +96,141 @@ func (action *APLActionWaitUntil) String() string {
 	return fmt.Sprintf("WaitUntil(%s)", action.condition)
 }
 
+type APLActionPoolResource struct {
+	defaultAPLActionImpl
+	unit         *Unit
+	resourceType proto.ResourceType
+	amount       APLValue
+	maxWait      APLValue
+
+	// Spell of the next action in the list, when pooling for its cost.
+	nextSpell *Spell
+
+	curWaitTime time.Duration
+	deadline    time.Duration
+}
+
+func (rot *APLRotation) newActionPoolResource(config *proto.APLActionPoolResource) APLActionImpl {
+	unit := rot.unit
+	if !unit.HasResource(config.ResourceType) {
+		rot.ValidationMessage(proto.LogLevel_Warning, "%s does not use %s", unit.Label, resourceTypeLabel(config.ResourceType))
+		return nil
+	}
+	if !canPredictResource(config.ResourceType) {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Cannot pool %s, since it is only generated by abilities", resourceTypeLabel(config.ResourceType))
+		return nil
+	}
+
+	// Both values are optional, so an empty amount pools for the next action and an empty max wait has no limit.
+	amountVal := rot.coerceTo(rot.newAPLValue(config.Amount), proto.APLValueType_ValueTypeFloat)
+	maxWaitVal := rot.coerceTo(rot.newAPLValue(config.MaxWait), proto.APLValueType_ValueTypeDuration)
+
+	return &APLActionPoolResource{
+		unit:         unit,
+		resourceType: config.ResourceType,
+		amount:       amountVal,
+		maxWait:      maxWaitVal,
+	}
+}
+func (action *APLActionPoolResource) GetAPLValues() []APLValue {
+	return FilterSlice([]APLValue{action.amount, action.maxWait}, func(val APLValue) bool { return val != nil })
+}
+func (action *APLActionPoolResource) Finalize(rot *APLRotation) {
+	if action.amount != nil {
+		return
+	}
+
+	lists := [][]*APLAction{rot.priorityList}
+	for _, list := range rot.actionLists {
+		lists = append(lists, list.actions)
+	}
+	for _, actions := range lists {
+		for i, listAction := range actions {
+			if listAction.impl != action || i+1 == len(actions) {
+				continue
+			}
+			if castSpell, ok := actions[i+1].impl.(*APLActionCastSpell); ok {
+				action.nextSpell = castSpell.spell
+			}
+		}
+	}
+
+	if action.nextSpell == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Pool Resource without an amount must be followed by a Cast Spell action")
+	} else if action.nextSpell.ResourceCost(action.resourceType) == 0 {
+		rot.ValidationMessage(proto.LogLevel_Warning, "%s does not cost %s", action.nextSpell.ActionID, resourceTypeLabel(action.resourceType))
+		action.nextSpell = nil
+	}
+}
+func (action *APLActionPoolResource) Reset(*Simulation) {
+	action.curWaitTime = 0
+	action.deadline = 0
+}
+
+func (action *APLActionPoolResource) targetAmount(sim *Simulation) float64 {
+	if action.amount != nil {
+		return action.amount.GetFloat(sim)
+	}
+	return action.nextSpell.ResourceCost(action.resourceType)
+}
+
+// Returns when the resource is pooled, and the next spell can be cast if pooling for it.
+func (action *APLActionPoolResource) readyAt(sim *Simulation) time.Duration {
+	timeToTarget := action.unit.TimeToTargetResource(sim, action.resourceType, action.targetAmount(sim))
+	if timeToTarget >= NeverExpires {
+		return NeverExpires
+	}
+	readyAt := sim.CurrentTime + timeToTarget
+	if action.nextSpell != nil {
+		readyAt = max(readyAt, action.nextSpell.CastTimersReadyAt())
+	}
+	return readyAt
+}
+
+func (action *APLActionPoolResource) IsReady(sim *Simulation) bool {
+	if action.amount == nil && action.nextSpell == nil {
+		return false
+	}
+	if action.unit.CurrentResource(action.resourceType) >= action.targetAmount(sim) {
+		return false
+	}
+	readyAt := action.readyAt(sim)
+	return readyAt < NeverExpires && (action.maxWait == nil || readyAt-sim.CurrentTime <= action.maxWait.GetDuration(sim))
+}
+
+func (action *APLActionPoolResource) Execute(sim *Simulation) {
+	action.unit.Rotation.pushControllingAction(action)
+	action.deadline = NeverExpires
+	if action.maxWait != nil {
+		action.deadline = sim.CurrentTime + action.maxWait.GetDuration(sim)
+	}
+	action.curWaitTime = action.readyAt(sim)
+	action.unit.WaitUntil(sim, action.curWaitTime)
+}
+
+func (action *APLActionPoolResource) GetNextAction(sim *Simulation) *APLAction {
+	if sim.CurrentTime < action.curWaitTime {
+		return nil
+	}
+
+	// Regeneration can change while pooling, e.g. from haste, so wait again if the resource is still short.
+	readyAt := action.readyAt(sim)
+	if readyAt <= sim.CurrentTime || readyAt >= NeverExpires || sim.CurrentTime >= action.deadline {
+		action.unit.Rotation.popControllingAction(action)
+		return action.unit.Rotation.getNextAction(sim)
+	}
+	action.curWaitTime = min(readyAt, action.deadline)
+	action.unit.WaitUntil(sim, action.curWaitTime)
+	return nil
+}
+
+func (action *APLActionPoolResource) String() string {
+	if action.amount == nil {
+		return fmt.Sprintf("Pool Resource(%s, next action)", resourceTypeLabel(action.resourceType))
+	}
+	return fmt.Sprintf("Pool Resource(%s, %s)", resourceTypeLabel(action.resourceType), action.amount)
+}
+
 type APLActionSchedule struct {
 	defaultAPLActionImpl
 	innerAction *APLAction
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Returns a rotation for the fake shaman, with an energy bar instead of its mana.
+func newTestEnergyRotation(currentEnergy float64) (*Simulation, *APLRotation) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	unit.energyBar = energyBar{
+		unit:                  unit,
+		maxEnergy:             100,
+		currentEnergy:         currentEnergy,
+		energyRegenMultiplier: 1,
+		hasteRatingMultiplier: 1,
+	}
+	rot := &APLRotation{
+		unit:            unit,
+		uuidValidations: make(map[*proto.UUID][]*proto.APLValidation),
+		actionLists:     make(map[string]*APLActionList),
+	}
+	return sim, rot
+}
+
+func testConstValue(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
+
+func TestValueResourceTimeToTarget(t *testing.T) {
+	sim, rot := newTestEnergyRotation(40)
+
+	for _, testCase := range []struct {
+		amount   string
+		expected time.Duration
+	}{
+		{"30", 0},
+		{"60", time.Second * 2},
+		{"120", NeverExpires},
+	} {
+		value := rot.newValueResourceTimeToTarget(&proto.APLValueResourceTimeToTarget{
+			ResourceType: proto.ResourceType_ResourceTypeEnergy,
+			TargetAmount: testConstValue(testCase.amount),
+		}, nil)
+		if duration := value.GetDuration(sim); duration != testCase.expected {
+			t.Errorf("Expected %s to %s energy, got %s", testCase.expected, testCase.amount, duration)
+		}
+	}
+
+	uuid := &proto.UUID{Value: "rage"}
+	if value := rot.newValueResourceTimeToTarget(&proto.APLValueResourceTimeToTarget{
+		ResourceType: proto.ResourceType_ResourceTypeRage,
+		TargetAmount: testConstValue("30"),
+	}, uuid); value != nil || len(rot.uuidValidations[uuid]) != 1 {
+		t.Fatalf("Expected a warning for a resource the unit does not use")
+	}
+
+	rot.unit.SetMaxComboPoints(5)
+	uuid = &proto.UUID{Value: "comboPoints"}
+	if value := rot.newValueResourceTimeToTarget(&proto.APLValueResourceTimeToTarget{
+		ResourceType: proto.ResourceType_ResourceTypeComboPoints,
+		TargetAmount: testConstValue("5"),
+	}, uuid); value != nil || len(rot.uuidValidations[uuid]) != 1 {
+		t.Fatalf("Expected a warning for a resource only generated by abilities")
+	}
+}
+
+func TestTimeToTargetRage(t *testing.T) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	unit.rageBar = rageBar{
+		unit:             unit,
+		maxRage:          100,
+		currentRage:      10,
+		currentHitFactor: BaseRageHitFactor,
+	}
+	unit.SetCurrentPowerBar(RageBar)
+
+	// Each 2.0 speed MH swing generates 3.5 rage, and the next one lands in 1s.
+	mh := &unit.AutoAttacks.mh
+	mh.SwingSpeed = 2
+	mh.curSwingDuration = time.Second * 2
+	mh.swingAt = time.Second
+	mh.enabled = true
+
+	for _, testCase := range []struct {
+		amount   float64
+		expected time.Duration
+	}{
+		{10, 0},
+		{13, time.Second},
+		{17, time.Second * 3},
+		{120, NeverExpires},
+	} {
+		if duration := unit.TimeToTargetResource(sim, proto.ResourceType_ResourceTypeRage, testCase.amount); duration != testCase.expected {
+			t.Errorf("Expected %s to %.0f rage, got %s", testCase.expected, testCase.amount, duration)
+		}
+	}
+}
+
+func TestTimeToTargetRunes(t *testing.T) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	unit.runicPowerBar = runicPowerBar{character: sim.Raid.Parties[0].Players[0].GetCharacter()}
+	for i := range unit.runeMeta {
+		unit.runeMeta[i].regenAt = NeverExpires
+		unit.runeMeta[i].revertAt = NeverExpires
+	}
+
+	// Both blood runes are spent, and the left frost rune is a ready death rune.
+	unit.runeStates = isSpents[0] | isSpents[1] | isDeaths[2]
+	unit.runeMeta[0].regenAt = time.Second * 5
+	unit.runeMeta[1].regenAt = time.Second * 8
+
+	for _, testCase := range []struct {
+		resourceType proto.ResourceType
+		amount       float64
+		expected     time.Duration
+	}{
+		{proto.ResourceType_ResourceTypeBloodRune, 1, 0},
+		{proto.ResourceType_ResourceTypeBloodRune, 2, time.Second * 5},
+		{proto.ResourceType_ResourceTypeBloodRune, 3, time.Second * 8},
+		{proto.ResourceType_ResourceTypeUnholyRune, 3, 0},
+		{proto.ResourceType_ResourceTypeDeathRune, 1, 0},
+		{proto.ResourceType_ResourceTypeDeathRune, 2, NeverExpires},
+	} {
+		if duration := unit.TimeToTargetResource(sim, testCase.resourceType, testCase.amount); duration != testCase.expected {
+			t.Errorf("Expected %s to %.0f %s, got %s", testCase.expected, testCase.amount, resourceTypeLabel(testCase.resourceType), duration)
+		}
+	}
+}
+
+func TestActionPoolResource(t *testing.T) {
+	sim, rot := newTestEnergyRotation(40)
+
+	newPool := func(amount string, maxWait string) *APLActionPoolResource {
+		config := &proto.APLActionPoolResource{ResourceType: proto.ResourceType_ResourceTypeEnergy}
+		if amount != "" {
+			config.Amount = testConstValue(amount)
+		}
+		if maxWait != "" {
+			config.MaxWait = testConstValue(maxWait)
+		}
+		return rot.newActionPoolResource(config).(*APLActionPoolResource)
+	}
+
+	if !newPool("60", "").IsReady(sim) {
+		t.Errorf("Expected pooling to 60 energy to be ready")
+	}
+	if !newPool("60", "3s").IsReady(sim) {
+		t.Errorf("Expected pooling to 60 energy within 3s to be ready")
+	}
+	if newPool("60", "1s").IsReady(sim) {
+		t.Errorf("Expected pooling to 60 energy within 1s not to be ready")
+	}
+	if newPool("30", "").IsReady(sim) {
+		t.Errorf("Expected pooling to 30 energy not to be ready, since it is already reached")
+	}
+	if newPool("120", "").IsReady(sim) {
+		t.Errorf("Expected pooling above the maximum energy not to be ready")
+	}
+
+	rot.unit.SetMaxComboPoints(5)
+	if pool := rot.newActionPoolResource(&proto.APLActionPoolResource{ResourceType: proto.ResourceType_ResourceTypeChi}); pool != nil || len(rot.curValidations) != 1 {
+		t.Fatalf("Expected a warning for pooling a resource only generated by abilities")
+	}
+	rot.curValidations = nil
+
+	// Pooling for the next action requires it to cast a spell.
+	pool := newPool("", "")
+	rot.priorityList = []*APLAction{{impl: pool}, {impl: &aplTestAction{ready: true}}}
+	pool.Finalize(rot)
+	if pool.IsReady(sim) || len(rot.curValidations) != 1 {
+		t.Fatalf("Expected a warning for pooling for an action which does not cast a spell")
+	}
+}
@@ -12,6 +12,7 @@ import (
 type UnitReference struct {
 	fixedUnit       *Unit
 	curTargetSource *Unit
+	smartHealSource *Unit
 }
 
 func (ur UnitReference) Get() *Unit {
@@ -19,6 +20,12 @@ func (ur UnitReference) Get() *Unit {
 		return ur.fixedUnit
 	} else if ur.curTargetSource != nil {
 		return ur.curTargetSource.CurrentTarget
+	} else if ur.smartHealSource != nil {
+		// Falls back to the source unit while nobody has lost any health yet.
+		if lowestHealthUnit := ur.smartHealSource.Env.Raid.GetLowestHealthPercentAllyUnit(); lowestHealthUnit != nil {
+			return lowestHealthUnit
+		}
+		return ur.smartHealSource
 	} else {
 		return nil
 	}
@@ -35,6 +42,10 @@ func NewUnitReference(ref *proto.UnitReference, contextUnit *Unit) UnitReference
 		return UnitReference{
 			curTargetSource: contextUnit,
 		}
+	} else if ref.Type == proto.UnitReference_LowestHealthAlly {
+		return UnitReference{
+			smartHealSource: contextUnit,
+		}
 	} else {
 		return UnitReference{
 			fixedUnit: contextUnit.GetUnit(ref),
@@ -0,0 +1,352 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Checks the rotation for semantic problems which do not prevent it from being parsed, like entries
+// which can never run. Problems are recorded as validations of the affected entries.
+func (rot *APLRotation) lint() {
+	if rot.config == nil {
+		return
+	}
+
+	for i, item := range rot.config.PrepullActions {
+		if !item.Hide {
+			rot.doAndRecordWarnings(&rot.prepullValidations[i], true, func() {
+				rot.lintAuraReferences(item.Action)
+			})
+		}
+	}
+
+	rot.lintList(rot.config.PriorityList, rot.priorityList, rot.priorityListIdxMap, rot.priorityListValidations)
+	for i, listValidations := range rot.actionListValidations {
+		if listValidations.list != nil {
+			rot.lintList(rot.config.ActionLists[i].Items, listValidations.list.actions, listValidations.itemIdxMap, listValidations.items)
+		}
+	}
+}
+
+func (rot *APLRotation) lintList(items []*proto.APLListItem, actions []*APLAction, idxMap []int, validations [][]*proto.APLValidation) {
+	for i, item := range items {
+		if !item.Hide {
+			rot.doAndRecordWarnings(&validations[i], false, func() {
+				rot.lintAuraReferences(item.Action)
+			})
+		}
+	}
+
+	// Earlier cast which is always ready, so that the entries after it are never reached.
+	var blocker *Spell
+	for i, action := range actions {
+		rot.doAndRecordWarnings(&validations[idxMap[i]], false, func() {
+			if blocker != nil && (blocker.DefaultCast.GCD == 0 || aplActionUsesGCD(action)) {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Never reached, because Cast Spell(%s) above is always ready", blocker.ActionID)
+				return
+			}
+
+			conditionMet := true
+			if action.condition != nil {
+				var isConst bool
+				var reason string
+				conditionMet, isConst, reason = aplConstBool(action.condition)
+				if !isConst {
+					return
+				}
+				if !conditionMet {
+					rot.ValidationMessage(proto.LogLevel_Warning, "Condition is always false (%s), so this action never runs", reason)
+				}
+			}
+
+			if castSpell, ok := action.impl.(*APLActionCastSpell); ok && conditionMet && spellIsAlwaysReady(castSpell.spell) {
+				blocker = castSpell.spell
+			}
+		})
+	}
+}
+
+// Whether the spell can be cast whenever the GCD allows it, because nothing else limits it.
+func spellIsAlwaysReady(spell *Spell) bool {
+	return spell.CD.Timer == nil && spell.SharedCD.Timer == nil && spell.Cost == nil && spell.MaxCharges == 0 &&
+		spell.ExtraCastCondition == nil && !spell.Flags.Matches(SpellFlagMCD|SpellFlagSwapped) &&
+		(spell.DefaultCast.CastTime == 0 || spell.Flags.Matches(SpellFlagCanCastWhileMoving))
+}
+
+func aplActionUsesGCD(action *APLAction) bool {
+	castSpell, ok := action.impl.(*APLActionCastSpell)
+	return ok && castSpell.spell.DefaultCast.GCD > 0
+}
+
+// Returns the value of a bool APL value if it is the same for the whole sim, along with the reason.
+func aplConstBool(value APLValue) (result bool, isConst bool, reason string) {
+	switch value := value.(type) {
+	case *APLValueConst:
+		return value.GetBool(nil), true, fmt.Sprintf("constant %s", value)
+	case *APLValueCoerced:
+		if _, ok := value.inner.(*APLValueConst); ok {
+			return value.GetBool(nil), true, fmt.Sprintf("constant %s", value.inner)
+		}
+	case *APLValueAuraIsKnown:
+		if value.aura.Get() == nil {
+			return false, true, "the aura is never known"
+		}
+	case *APLValueNot:
+		if result, isConst, reason := aplConstBool(value.val); isConst {
+			return !result, true, reason
+		}
+	case *APLValueAnd:
+		allTrue := true
+		for _, val := range value.vals {
+			result, isConst, reason := aplConstBool(val)
+			if isConst && !result {
+				return false, true, reason
+			}
+			allTrue = allTrue && isConst
+		}
+		if allTrue {
+			return true, true, "all parts are always true"
+		}
+		if reason := aplContradictoryComparisons(value.vals); reason != "" {
+			return false, true, reason
+		}
+	case *APLValueOr:
+		allFalse := true
+		for _, val := range value.vals {
+			result, isConst, reason := aplConstBool(val)
+			if isConst && result {
+				return true, true, reason
+			}
+			allFalse = allFalse && isConst
+		}
+		if allFalse {
+			return false, true, "all parts are always false"
+		}
+	case *APLValueCompare:
+		if _, lhsIsConst := aplConstNumber(value.lhs); lhsIsConst {
+			if _, rhsIsConst := aplConstNumber(value.rhs); rhsIsConst {
+				return value.GetBool(nil), true, fmt.Sprintf("%s compares constants", value)
+			}
+		}
+	}
+	return false, false, ""
+}
+
+// Returns the number of a constant numeric APL value, with durations in seconds.
+func aplConstNumber(value APLValue) (float64, bool) {
+	if coerced, ok := value.(*APLValueCoerced); ok {
+		if _, innerIsConst := coerced.inner.(*APLValueConst); !innerIsConst {
+			return 0, false
+		}
+	} else if _, ok := value.(*APLValueConst); !ok {
+		return 0, false
+	}
+
+	switch value.Type() {
+	case proto.APLValueType_ValueTypeInt:
+		return float64(value.GetInt(nil)), true
+	case proto.APLValueType_ValueTypeFloat:
+		return value.GetFloat(nil), true
+	case proto.APLValueType_ValueTypeDuration:
+		return value.GetDuration(nil).Seconds(), true
+	}
+	return 0, false
+}
+
+// Comparison of a non-constant value against a constant.
+type aplConstComparison struct {
+	compare *APLValueCompare
+	key     string
+	op      proto.APLValueCompare_ComparisonOperator
+	val     float64
+}
+
+func newAPLConstComparison(value APLValue) (aplConstComparison, bool) {
+	compare, ok := value.(*APLValueCompare)
+	if !ok {
+		return aplConstComparison{}, false
+	}
+	if val, isConst := aplConstNumber(compare.rhs); isConst {
+		return aplConstComparison{compare: compare, key: compare.lhs.String(), op: compare.op, val: val}, true
+	}
+	if val, isConst := aplConstNumber(compare.lhs); isConst {
+		flipped := map[proto.APLValueCompare_ComparisonOperator]proto.APLValueCompare_ComparisonOperator{
+			proto.APLValueCompare_OpLt: proto.APLValueCompare_OpGt,
+			proto.APLValueCompare_OpLe: proto.APLValueCompare_OpGe,
+			proto.APLValueCompare_OpGt: proto.APLValueCompare_OpLt,
+			proto.APLValueCompare_OpGe: proto.APLValueCompare_OpLe,
+		}
+		op, isFlipped := flipped[compare.op]
+		if !isFlipped {
+			op = compare.op
+		}
+		return aplConstComparison{compare: compare, key: compare.rhs.String(), op: op, val: val}, true
+	}
+	return aplConstComparison{}, false
+}
+
+func (comparison aplConstComparison) matches(x float64) bool {
+	switch comparison.op {
+	case proto.APLValueCompare_OpEq:
+		return x == comparison.val
+	case proto.APLValueCompare_OpNe:
+		return x != comparison.val
+	case proto.APLValueCompare_OpLt:
+		return x < comparison.val
+	case proto.APLValueCompare_OpLe:
+		return x <= comparison.val
+	case proto.APLValueCompare_OpGt:
+		return x > comparison.val
+	case proto.APLValueCompare_OpGe:
+		return x >= comparison.val
+	}
+	return true
+}
+
+// Returns a description of two comparisons of the same value which can not both be true, e.g.
+// x < 2 and x > 5, or "" if there are none.
+func aplContradictoryComparisons(vals []APLValue) string {
+	var comparisons []aplConstComparison
+	for _, val := range vals {
+		if comparison, ok := newAPLConstComparison(val); ok {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+
+	for i, a := range comparisons {
+		for _, b := range comparisons[i+1:] {
+			if a.key != b.key {
+				continue
+			}
+			// Both sets are unions of intervals bounded by the two constants, so a value in both
+			// exists if and only if one of these candidates is in both.
+			eps := 1e-9 * max(1, math.Abs(a.val), math.Abs(b.val))
+			candidates := []float64{
+				a.val, a.val - eps, a.val + eps,
+				b.val, b.val - eps, b.val + eps,
+				(a.val + b.val) / 2, min(a.val, b.val) - 1, max(a.val, b.val) + 1,
+			}
+			if !slices.ContainsFunc(candidates, func(x float64) bool { return a.matches(x) && b.matches(x) }) {
+				return fmt.Sprintf("%s contradicts %s", a.compare, b.compare)
+			}
+		}
+	}
+	return ""
+}
+
+// Aura of a unit referenced by an APL value.
+type aplAuraKey struct {
+	unit     *Unit
+	actionID ActionID
+}
+
+// Returns the aura referenced by an aura value config, or false if the value does not reference one.
+func (rot *APLRotation) aplValueAura(value *proto.APLValue) (aplAuraKey, bool) {
+	var sourceUnit UnitReference
+	var auraId *proto.ActionID
+	switch value := value.Value.(type) {
+	case *proto.APLValue_AuraIsKnown:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraIsKnown.SourceUnit, proto.UnitReference_Self), value.AuraIsKnown.AuraId
+	case *proto.APLValue_AuraIsActive:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraIsActive.SourceUnit, proto.UnitReference_Self), value.AuraIsActive.AuraId
+	case *proto.APLValue_AuraIsActiveWithReactionTime:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraIsActiveWithReactionTime.SourceUnit, proto.UnitReference_Self), value.AuraIsActiveWithReactionTime.AuraId
+	case *proto.APLValue_AuraIsInactiveWithReactionTime:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraIsInactiveWithReactionTime.SourceUnit, proto.UnitReference_Self), value.AuraIsInactiveWithReactionTime.AuraId
+	case *proto.APLValue_AuraRemainingTime:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraRemainingTime.SourceUnit, proto.UnitReference_Self), value.AuraRemainingTime.AuraId
+	case *proto.APLValue_AuraNumStacks:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraNumStacks.SourceUnit, proto.UnitReference_Self), value.AuraNumStacks.AuraId
+	case *proto.APLValue_AuraShouldRefresh:
+		sourceUnit, auraId = rot.lintSourceUnit(value.AuraShouldRefresh.SourceUnit, proto.UnitReference_CurrentTarget), value.AuraShouldRefresh.AuraId
+	default:
+		return aplAuraKey{}, false
+	}
+	if auraId == nil || sourceUnit.Get() == nil {
+		return aplAuraKey{}, false
+	}
+	return aplAuraKey{unit: sourceUnit.Get(), actionID: ProtoToActionID(auraId)}, true
+}
+
+// Like GetSourceUnit, but without recording validations, since parsing already did.
+func (rot *APLRotation) lintSourceUnit(ref *proto.UnitReference, defaultType proto.UnitReference_Type) UnitReference {
+	if ref == nil || ref.Type == proto.UnitReference_Unknown {
+		ref = &proto.UnitReference{Type: defaultType}
+	}
+	return NewUnitReference(ref, rot.unit)
+}
+
+// Records a validation for each value of the action which references an aura the unit can never
+// gain, unless it is guarded by an Aura Is Known check of the same aura. Such values are dropped
+// during parsing, which usually changes the meaning of the condition containing them.
+func (rot *APLRotation) lintAuraReferences(config *proto.APLAction) {
+	var visitValue func(value *proto.APLValue, guarded map[aplAuraKey]bool)
+	visitValue = func(value *proto.APLValue, guarded map[aplAuraKey]bool) {
+		if and := value.GetAnd(); and != nil {
+			guarded = maps.Clone(guarded)
+			if guarded == nil {
+				guarded = make(map[aplAuraKey]bool)
+			}
+			for _, val := range and.Vals {
+				if val.GetAuraIsKnown() == nil {
+					continue
+				}
+				if aura, ok := rot.aplValueAura(val); ok {
+					guarded[aura] = true
+				}
+			}
+		}
+
+		if value.GetAuraIsKnown() == nil {
+			if aura, ok := rot.aplValueAura(value); ok && !guarded[aura] && aura.unit.GetAuraByID(aura.actionID) == nil {
+				rot.ValidationMessage(proto.LogLevel_Warning, "%s never gains %s, so the part of the condition using it is ignored. Check Aura Is Known first if this is intended", aura.unit.Label, aura.actionID)
+			}
+		}
+
+		visitAPLChildren(value.ProtoReflect(), nil, func(child *proto.APLValue) { visitValue(child, guarded) })
+	}
+
+	var visitAction func(action *proto.APLAction)
+	visitAction = func(action *proto.APLAction) {
+		visitAPLChildren(action.ProtoReflect(), visitAction, func(value *proto.APLValue) { visitValue(value, nil) })
+	}
+	if config != nil {
+		visitAction(config)
+	}
+}
+
+// Invokes visitAction and visitValue for the outermost APL actions and values nested in msg.
+func visitAPLChildren(msg protoreflect.Message, visitAction func(*proto.APLAction), visitValue func(*proto.APLValue)) {
+	visit := func(child protoreflect.Message) {
+		switch child := child.Interface().(type) {
+		case *proto.APLAction:
+			if visitAction != nil {
+				visitAction(child)
+			}
+		case *proto.APLValue:
+			visitValue(child)
+		default:
+			visitAPLChildren(child.ProtoReflect(), visitAction, visitValue)
+		}
+	}
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				visit(list.Get(i).Message())
+			}
+		} else {
+			visit(value.Message())
+		}
+		return true
+	})
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestAPLLint(t *testing.T) {
+	rotation, err := apltext.Parse(`
+		actions cast_spell(spell_id=spell:42) if current_time > 5s & current_time < 2s
+		actions cast_spell(spell_id=spell:42) if aura_is_known(aura_id=spell:12345)
+		actions cast_spell(spell_id=spell:42) if aura_is_active(aura_id=spell:12345) | current_time > 1s
+		actions cast_spell(spell_id=spell:42) if aura_is_known(aura_id=spell:12345) & aura_is_active(aura_id=spell:12345)
+		actions cast_spell(spell_id=spell:42) if current_time >= 1s & current_time <= 1s
+		actions cast_spell(spell_id=spell:42)
+		actions wait(duration=1s)
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := &proto.Player{Name: "Caster", Class: proto.Class_ClassShaman, Spec: &proto.Player_ElementalShaman{}, Equipment: &proto.EquipmentSpec{}, Rotation: rotation}
+	result := ComputeStats(&proto.ComputeStatsRequest{
+		Raid: SinglePlayerRaidProto(player, nil, nil, nil),
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{{Name: "target", Level: 90, MobType: proto.MobType_MobTypeDemon}},
+		},
+	})
+	items := result.RaidStats.Parties[0].Players[0].RotationStats.PriorityList
+
+	expectValidation := func(idx int, expected string) {
+		t.Helper()
+		if !slices.ContainsFunc(items[idx].Validations, func(validation *proto.APLValidation) bool {
+			return strings.Contains(validation.Validation, expected)
+		}) {
+			t.Errorf("Expected a validation containing %q for item %d, got %v", expected, idx, items[idx].Validations)
+		}
+	}
+	expectNoLint := func(idx int) {
+		t.Helper()
+		for _, validation := range items[idx].Validations {
+			if !strings.HasPrefix(validation.Validation, "No aura found") {
+				t.Errorf("Expected no lint validations for item %d, got %v", idx, items[idx].Validations)
+			}
+		}
+	}
+
+	expectValidation(0, "contradicts")
+	expectValidation(1, "the aura is never known")
+	expectValidation(2, "never gains")
+	// The Aura Is Known check guards the aura, but makes the condition always false.
+	expectValidation(3, "the aura is never known")
+	if slices.ContainsFunc(items[3].Validations, func(validation *proto.APLValidation) bool {
+		return strings.Contains(validation.Validation, "never gains")
+	}) {
+		t.Errorf("Expected the guarded aura not to be reported")
+	}
+	expectNoLint(4)
+	expectNoLint(5)
+	expectValidation(6, "Never reached")
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Counts how often an APL action was considered and used, over all iterations.
+type aplActionProfile struct {
+	evaluations  int64
+	conditionMet int64
+	ready        int64
+	executions   int64
+}
+
+// Prepull actions run regardless of their condition, so they count as ready for each execution.
+func (profile *aplActionProfile) countPrepull() {
+	profile.evaluations++
+	profile.conditionMet++
+	profile.ready++
+}
+
+func (profile *aplActionProfile) toProto(iterations int32, minutes float64) *proto.APLItemProfile {
+	perIteration := func(count int64) float64 {
+		if iterations == 0 {
+			return 0
+		}
+		return float64(count) / float64(iterations)
+	}
+	perMinute := func(count int64) float64 {
+		if minutes == 0 {
+			return 0
+		}
+		return float64(count) / minutes
+	}
+
+	return &proto.APLItemProfile{
+		Evaluations:           perIteration(profile.evaluations),
+		ConditionMet:          perIteration(profile.conditionMet),
+		Ready:                 perIteration(profile.ready),
+		Executions:            perIteration(profile.executions),
+		EvaluationsPerMinute:  perMinute(profile.evaluations),
+		ConditionMetPerMinute: perMinute(profile.conditionMet),
+		ReadyPerMinute:        perMinute(profile.ready),
+		ExecutionsPerMinute:   perMinute(profile.executions),
+	}
+}
+
+// Returns the action getNextAction would pick, without counting the evaluation in the
+// action profiles, e.g. when checking whether a channel should be interrupted.
+func (rot *APLRotation) peekNextAction(sim *Simulation) *APLAction {
+	if rot.peekActions == nil {
+		rot.peekActions = rot.allAPLActions()
+		rot.peekProfiles = make([]aplActionProfile, len(rot.peekActions))
+	}
+	for i, action := range rot.peekActions {
+		rot.peekProfiles[i] = action.profile
+	}
+	nextAction := rot.getNextAction(sim)
+	for i, action := range rot.peekActions {
+		action.profile = rot.peekProfiles[i]
+	}
+	return nextAction
+}
+
+func (rot *APLRotation) doneIteration(sim *Simulation) {
+	rot.profileIterations++
+	rot.profileDuration += sim.CurrentTime
+}
+
+// Returns the profiles of the actions, indexed like their configs. Configs which were
+// hidden or invalid have empty profiles.
+func (rot *APLRotation) actionProfilesToProto(actions []*APLAction, idxMap []int, numConfigs int) []*proto.APLItemProfile {
+	minutes := rot.profileDuration.Minutes()
+	profiles := make([]*proto.APLItemProfile, numConfigs)
+	for i := range profiles {
+		profiles[i] = &proto.APLItemProfile{}
+	}
+	for i, action := range actions {
+		profiles[idxMap[i]] = action.profile.toProto(rot.profileIterations, minutes)
+	}
+	return profiles
+}
+
+func (rot *APLRotation) getProfile() *proto.APLProfile {
+	profile := &proto.APLProfile{
+		PrepullActions: rot.actionProfilesToProto(rot.prepullActions, rot.prepullIdxMap, len(rot.prepullValidations)),
+		PriorityList:   rot.actionProfilesToProto(rot.priorityList, rot.priorityListIdxMap, len(rot.priorityListValidations)),
+	}
+	for _, listValidations := range rot.actionListValidations {
+		listProfile := &proto.APLActionListProfile{}
+		if listValidations.list != nil {
+			listProfile.Name = listValidations.list.name
+			listProfile.Items = rot.actionProfilesToProto(listValidations.list.actions, listValidations.itemIdxMap, len(listValidations.items))
+		} else {
+			listProfile.Items = rot.actionProfilesToProto(nil, nil, len(listValidations.items))
+		}
+		profile.ActionLists = append(profile.ActionLists, listProfile)
+	}
+	return profile
+}
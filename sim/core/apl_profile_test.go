@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestAPLProfile(t *testing.T) {
+	sim := &Simulation{}
+	rot := &APLRotation{actionLists: make(map[string]*APLActionList)}
+
+	notReady := &APLAction{impl: &aplTestAction{ready: false}}
+	ready := &APLAction{impl: &aplTestAction{ready: true}}
+	shadowed := &APLAction{impl: &aplTestAction{ready: true}}
+	falseCondition := &APLAction{
+		condition: rot.newValueConst(&proto.APLValueConst{Val: "false"}, nil),
+		impl:      &aplTestAction{ready: true},
+	}
+
+	list := newTestActionList(rot, "list", notReady, ready)
+	callList := newTestListInvocation(rot, nil, "list", false)
+	callList.impl.Finalize(rot)
+
+	rot.priorityList = []*APLAction{falseCondition, callList, shadowed}
+	// The second config is hidden.
+	rot.priorityListIdxMap = []int{0, 2, 3}
+	rot.priorityListValidations = make([][]*proto.APLValidation, 4)
+	rot.actionListValidations = []*aplActionListValidations{
+		{list: list, itemIdxMap: []int{0, 1}, items: make([][]*proto.APLValidation, 2)},
+	}
+
+	for i := 0; i < 4; i++ {
+		nextAction := rot.getNextAction(sim)
+		if nextAction != ready {
+			t.Fatalf("Expected the ready action from the called list")
+		}
+		rot.executeNextAction(sim, nextAction)
+	}
+	sim.CurrentTime = time.Minute
+	rot.doneIteration(sim)
+	rot.doneIteration(sim)
+
+	profile := rot.getProfile()
+	expectItem := func(name string, item *proto.APLItemProfile, evaluations, conditionMet, ready, executions float64) {
+		t.Helper()
+		if item.Evaluations != evaluations || item.ConditionMet != conditionMet || item.Ready != ready || item.Executions != executions {
+			t.Errorf("Unexpected profile for %s: %v", name, item)
+		}
+		if item.ExecutionsPerMinute != executions {
+			t.Errorf("Expected %f executions per minute for %s, got %f", executions, name, item.ExecutionsPerMinute)
+		}
+	}
+
+	if len(profile.PriorityList) != 4 || len(profile.ActionLists) != 1 || len(profile.ActionLists[0].Items) != 2 {
+		t.Fatalf("Expected the profile to be indexed like the configs, got %v", profile)
+	}
+	expectItem("false condition", profile.PriorityList[0], 2, 0, 0, 0)
+	expectItem("hidden line", profile.PriorityList[1], 0, 0, 0, 0)
+	expectItem("call action list", profile.PriorityList[2], 2, 2, 2, 2)
+	expectItem("shadowed line", profile.PriorityList[3], 0, 0, 0, 0)
+	expectItem("not ready", profile.ActionLists[0].Items[0], 2, 2, 0, 0)
+	expectItem("ready", profile.ActionLists[0].Items[1], 2, 2, 2, 2)
+}
+
+func TestAPLProfilePeekNextAction(t *testing.T) {
+	sim := &Simulation{}
+	rot := &APLRotation{actionLists: make(map[string]*APLActionList)}
+
+	notReady := &APLAction{impl: &aplTestAction{ready: false}}
+	ready := &APLAction{impl: &aplTestAction{ready: true}}
+	rot.priorityList = []*APLAction{notReady, ready}
+
+	for i := 0; i < 3; i++ {
+		if nextAction := rot.peekNextAction(sim); nextAction != ready {
+			t.Fatalf("Expected the ready action")
+		}
+	}
+	if notReady.profile != (aplActionProfile{}) || ready.profile != (aplActionProfile{}) {
+		t.Errorf("Expected peeking not to count in the profiles, got %v and %v", notReady.profile, ready.profile)
+	}
+
+	rot.getNextAction(sim)
+	if ready.profile.evaluations != 1 {
+		t.Errorf("Expected 1 evaluation after getNextAction, got %d", ready.profile.evaluations)
+	}
+}
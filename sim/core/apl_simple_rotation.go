@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Builds the APL rotation equivalent to the options of a spec's simple rotation. The options are
+// the spec's rotation proto, in the protojson format of SimpleRotation.spec_rotation_json.
+type SimpleRotationCompiler func(player *proto.Player, specRotationJson string, cooldowns *proto.Cooldowns) (*proto.APLRotation, error)
+
+var simpleRotationCompilers = make(map[proto.Spec]SimpleRotationCompiler)
+
+// Registers the compiler for the simple rotation of a spec, which should match the simple
+// rotation generator of the spec's UI.
+func RegisterSimpleRotationCompiler(spec proto.Spec, compiler SimpleRotationCompiler) {
+	if _, ok := simpleRotationCompilers[spec]; ok {
+		panic("Already registered simple rotation compiler: " + spec.String())
+	}
+	simpleRotationCompilers[spec] = compiler
+}
+
+/**
+ * Compiles the simple rotation of the player into an explicit APL rotation, which can be edited further.
+ */
+func CompileSimpleRotation(request *proto.CompileSimpleRotationRequest) *proto.CompileSimpleRotationResult {
+	rotation, err := compileSimpleRotation(request.Player)
+	if err != nil {
+		return &proto.CompileSimpleRotationResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+	return &proto.CompileSimpleRotationResult{Rotation: rotation}
+}
+
+func compileSimpleRotation(player *proto.Player) (*proto.APLRotation, error) {
+	if player == nil || player.Spec == nil {
+		return nil, fmt.Errorf("no player to compile the simple rotation of")
+	}
+	spec := PlayerProtoToSpec(player)
+	compiler, ok := simpleRotationCompilers[spec]
+	if !ok {
+		return nil, fmt.Errorf("%s has no simple rotation", spec)
+	}
+
+	simple := player.GetRotation().GetSimple()
+	if simple == nil {
+		simple = &proto.SimpleRotation{}
+	}
+	cooldowns := simple.Cooldowns
+	if cooldowns == nil {
+		cooldowns = &proto.Cooldowns{}
+	}
+
+	rotation, err := compiler(player, simple.SpecRotationJson, cooldowns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile simple rotation: %w", err)
+	}
+	rotation.Type = proto.APLRotation_TypeAPL
+	// Kept so that switching back to the simple rotation restores its options.
+	rotation.Simple = googleProto.Clone(simple).(*proto.SimpleRotation)
+	return rotation, nil
+}
+
+// Parses the options of a simple rotation into the spec's rotation proto. Empty options leave the
+// proto at its defaults, like in the UI.
+func ParseSimpleRotationOptions(specRotationJson string, options googleProto.Message) error {
+	if specRotationJson == "" {
+		return nil
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal([]byte(specRotationJson), options)
+}
+
+// Parses an APL action in protojson format, for building rotations from the same snippets as the UI.
+func APLActionFromJson(json string) (*proto.APLAction, error) {
+	action := &proto.APLAction{}
+	if err := protojson.Unmarshal([]byte(json), action); err != nil {
+		return nil, fmt.Errorf("invalid APL action %s: %w", json, err)
+	}
+	return action, nil
+}
+
+// Parses an APL prepull action in protojson format, for building rotations from the same snippets as the UI.
+func APLPrepullActionFromJson(json string) (*proto.APLPrepullAction, error) {
+	action := &proto.APLPrepullAction{}
+	if err := protojson.Unmarshal([]byte(json), action); err != nil {
+		return nil, fmt.Errorf("invalid APL prepull action %s: %w", json, err)
+	}
+	return action, nil
+}
+
+func PrepullPotionAction(doAt string) *proto.APLPrepullAction {
+	if doAt == "" {
+		doAt = "-1s"
+	}
+	return &proto.APLPrepullAction{
+		Action: &proto.APLAction{
+			Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+				SpellId: &proto.ActionID{RawId: &proto.ActionID_OtherId{OtherId: proto.OtherAction_OtherActionPotion}},
+			}},
+		},
+		DoAtValue: newAPLConstConfig(doAt),
+	}
+}
+
+// Casts the cooldowns which are not controlled by other actions, after startAt if it is set.
+func AutocastCooldownsAction(startAt string) *proto.APLAction {
+	action := &proto.APLAction{
+		Action: &proto.APLAction_AutocastOtherCooldowns{AutocastOtherCooldowns: &proto.APLActionAutocastOtherCooldowns{}},
+	}
+	if startAt != "" {
+		action.Condition = &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+			Op:  proto.APLValueCompare_OpGt,
+			Lhs: &proto.APLValue{Value: &proto.APLValue_CurrentTime{CurrentTime: &proto.APLValueCurrentTime{}}},
+			Rhs: newAPLConstConfig(startAt),
+		}}}
+	}
+	return action
+}
+
+func ScheduledCooldownAction(schedule string, actionID *proto.ActionID) *proto.APLAction {
+	return &proto.APLAction{
+		Action: &proto.APLAction_Schedule{Schedule: &proto.APLActionSchedule{
+			Schedule: schedule,
+			InnerAction: &proto.APLAction{
+				Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: actionID}},
+			},
+		}},
+	}
+}
+
+// Returns a scheduled action for each cooldown with fixed timings.
+func SimpleCooldownActions(cooldowns *proto.Cooldowns) []*proto.APLAction {
+	var actions []*proto.APLAction
+	for _, cd := range cooldowns.Cooldowns {
+		if cd.Id == nil {
+			continue
+		}
+		schedule := MapSlice(cd.Timings, func(timing float64) string {
+			return strconv.FormatFloat(timing, 'f', 1, 64) + "s"
+		})
+		actions = append(actions, ScheduledCooldownAction(strings.Join(schedule, ", "), cd.Id))
+	}
+	return actions
+}
+
+// Returns the prepull potion, and the cooldown actions which most simple rotations start with.
+func StandardCooldownDefaults(cooldowns *proto.Cooldowns, prepotAt string, startAutocastCDsAt string) ([]*proto.APLPrepullAction, []*proto.APLAction) {
+	prepullActions := []*proto.APLPrepullAction{PrepullPotionAction(prepotAt)}
+	actions := append([]*proto.APLAction{AutocastCooldownsAction(startAutocastCDsAt)}, SimpleCooldownActions(cooldowns)...)
+	return prepullActions, actions
+}
+
+func newAPLConstConfig(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
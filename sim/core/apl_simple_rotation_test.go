@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func init() {
+	RegisterSimpleRotationCompiler(proto.Spec_SpecElementalShaman, func(_ *proto.Player, specRotationJson string, cooldowns *proto.Cooldowns) (*proto.APLRotation, error) {
+		if err := ParseSimpleRotationOptions(specRotationJson, &proto.ElementalShaman_Rotation{}); err != nil {
+			return nil, err
+		}
+		prepullActions, actions := StandardCooldownDefaults(cooldowns, "-2s", "")
+		dot, err := APLActionFromJson(`{"castSpell":{"spellId":{"spellId":42}}}`)
+		if err != nil {
+			return nil, err
+		}
+		return &proto.APLRotation{
+			PrepullActions: prepullActions,
+			PriorityList: MapSlice(append(actions, dot), func(action *proto.APLAction) *proto.APLListItem {
+				return &proto.APLListItem{Action: action}
+			}),
+		}, nil
+	})
+}
+
+func TestCompileSimpleRotation(t *testing.T) {
+	player := &proto.Player{
+		Name:      "Caster",
+		Class:     proto.Class_ClassShaman,
+		Spec:      &proto.Player_ElementalShaman{},
+		Equipment: &proto.EquipmentSpec{},
+		Rotation: &proto.APLRotation{
+			Type: proto.APLRotation_TypeSimple,
+			Simple: &proto.SimpleRotation{
+				SpecRotationJson: "{}",
+				Cooldowns: &proto.Cooldowns{Cooldowns: []*proto.Cooldown{
+					{Id: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 2825}}, Timings: []float64{10, 70.5}},
+					{Timings: []float64{5}},
+				}},
+			},
+		},
+	}
+
+	result := CompileSimpleRotation(&proto.CompileSimpleRotationRequest{Player: player})
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+	if result.Rotation.Type != proto.APLRotation_TypeAPL || result.Rotation.Simple.GetSpecRotationJson() != "{}" {
+		t.Fatalf("Expected an APL rotation which keeps the simple rotation options, got %v", result.Rotation)
+	}
+
+	expected := `prepull at -2s cast_spell(spell_id=other:OtherActionPotion)
+
+actions autocast_other_cooldowns
+actions schedule(schedule="10.0s, 70.5s", inner_action=cast_spell(spell_id=spell:2825))
+actions cast_spell(spell_id=spell:42)
+`
+	if text := apltext.Format(result.Rotation); text != expected {
+		t.Fatalf("Unexpected compiled rotation:\n%s\nExpected:\n%s", text, expected)
+	}
+
+	player.Rotation.Simple.SpecRotationJson = "{invalid"
+	if result := CompileSimpleRotation(&proto.CompileSimpleRotationRequest{Player: player}); result.Error == nil {
+		t.Fatalf("Expected an error for invalid options")
+	}
+	player.Spec = &proto.Player_FeralDruid{}
+	if result := CompileSimpleRotation(&proto.CompileSimpleRotationRequest{Player: player}); result.Error == nil {
+		t.Fatalf("Expected an error for a spec without a simple rotation")
+	}
+}
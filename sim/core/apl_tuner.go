@@ -0,0 +1,340 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	defaultAPLTuneMaxEvaluations = 200
+	defaultAPLTuneMaxPasses      = 5
+	defaultAPLTuneConfidence     = 0.95
+	// Number of steps in the range of a constant without a step.
+	defaultAPLTuneSteps = 10
+)
+
+// A number with an optional unit, like the values of APL constants.
+var aplTunableValRegex = regexp.MustCompile(`^(-?\d+(?:\.\d+)?)(%|ms|s|m)?$`)
+
+/**
+ * Searches for the values of the tunable APL constants in the raid which maximize raid DPS.
+ */
+func TuneAPL(request *proto.TuneAPLRequest) *proto.TuneAPLResult {
+	return runAPLTuner(request, simsignals.CreateSignals())
+}
+
+// A constant marked for tuning.
+type aplTunable struct {
+	name   string
+	config *proto.APLValueConst
+	unit   string
+
+	initialValue float64
+	values       []float64 // Values to try, in ascending order.
+}
+
+func newAPLTunable(config *proto.APLValueConst, idx int) (*aplTunable, error) {
+	tuning := config.Tuning
+	name := tuning.Name
+	if name == "" {
+		name = fmt.Sprintf("#%d", idx+1)
+	}
+
+	match := aplTunableValRegex.FindStringSubmatch(config.Val)
+	if match == nil {
+		return nil, fmt.Errorf("tunable constant %s must be a number, got %q", name, config.Val)
+	}
+	initialValue, _ := strconv.ParseFloat(match[1], 64)
+
+	if tuning.Min > tuning.Max {
+		return nil, fmt.Errorf("tunable constant %s has min %v greater than max %v", name, tuning.Min, tuning.Max)
+	}
+	step := tuning.Step
+	if step == 0 {
+		step = (tuning.Max - tuning.Min) / defaultAPLTuneSteps
+	}
+	if step < 0 || (step == 0 && tuning.Max > tuning.Min) {
+		return nil, fmt.Errorf("tunable constant %s has invalid step %v", name, tuning.Step)
+	}
+
+	tunable := &aplTunable{
+		name:         name,
+		config:       config,
+		unit:         match[2],
+		initialValue: initialValue,
+	}
+	for i := 0; ; i++ {
+		// Rounded to avoid values like 0.30000000000000004.
+		value := math.Round((tuning.Min+float64(i)*step)*1e6) / 1e6
+		if value > tuning.Max || (step == 0 && i > 0) {
+			break
+		}
+		tunable.values = append(tunable.values, value)
+	}
+	return tunable, nil
+}
+
+func (tunable *aplTunable) setValue(value float64) {
+	tunable.config.Val = strconv.FormatFloat(value, 'f', -1, 64) + tunable.unit
+}
+
+// Returns the tunable constants in the APL rotations of the raid, in the order of their fields.
+func findAPLTunables(raid *proto.Raid) ([]*aplTunable, error) {
+	var tunables []*aplTunable
+	names := map[string]bool{}
+	numFound := 0
+	var err error
+
+	var visit func(msg protoreflect.Message)
+	visit = func(msg protoreflect.Message) {
+		if config, ok := msg.Interface().(*proto.APLValueConst); ok && config.Tuning != nil {
+			tunable, tunableErr := newAPLTunable(config, numFound)
+			numFound++
+			if tunableErr != nil {
+				err = errors.Join(err, tunableErr)
+				return
+			}
+			if names[tunable.name] {
+				err = errors.Join(err, fmt.Errorf("duplicate tunable constant name %q", tunable.name))
+			}
+			names[tunable.name] = true
+			tunables = append(tunables, tunable)
+			return
+		}
+
+		fields := msg.Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if fd.Message() == nil || fd.IsMap() || !msg.Has(fd) {
+				continue
+			}
+			if fd.IsList() {
+				list := msg.Get(fd).List()
+				for j := 0; j < list.Len(); j++ {
+					visit(list.Get(j).Message())
+				}
+			} else {
+				visit(msg.Get(fd).Message())
+			}
+		}
+	}
+	if raid != nil {
+		visit(raid.ProtoReflect())
+	}
+	return tunables, err
+}
+
+type aplTuner struct {
+	// Copy of the request, whose tunable constants are set for each evaluation.
+	request  *proto.RaidSimRequest
+	tunables []*aplTunable
+	signals  simsignals.Signals
+
+	simOptions     *proto.SimOptions
+	maxEvaluations int
+	z              float64 // Z-score of the confidence level, steps are only taken if their gain is significant.
+
+	results     map[string]*itemSubstitutionSimResult
+	evaluations []*proto.APLTuneEvaluation
+	initial     *itemSubstitutionSimResult
+}
+
+var errAPLTuneLimitReached = errors.New("evaluation limit reached")
+
+// Sims the request with the given values, or returns the result of an earlier sim with them.
+func (tuner *aplTuner) evaluate(values []float64) (*itemSubstitutionSimResult, error) {
+	key := fmt.Sprint(values)
+	if result, ok := tuner.results[key]; ok {
+		return result, nil
+	}
+	if len(tuner.evaluations) >= tuner.maxEvaluations {
+		return nil, errAPLTuneLimitReached
+	}
+	if tuner.signals.Abort.IsTriggered() {
+		return nil, errors.New("aborted")
+	}
+
+	result, err := tuner.simValues(values, tuner.simOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := result.dpsEstimate()
+	tuner.results[key] = result
+	tuner.evaluations = append(tuner.evaluations, &proto.APLTuneEvaluation{
+		Values:    slices.Clone(values),
+		Dps:       estimate.mean,
+		DpsStderr: estimate.stderr,
+	})
+	return result, nil
+}
+
+// Sims the request with the given values and sim options.
+func (tuner *aplTuner) simValues(values []float64, simOptions *proto.SimOptions) (*itemSubstitutionSimResult, error) {
+	for i, tunable := range tuner.tunables {
+		tunable.setValue(values[i])
+	}
+	request := googleProto.Clone(tuner.request).(*proto.RaidSimRequest)
+	request.SimOptions = googleProto.Clone(simOptions).(*proto.SimOptions)
+
+	result := &itemSubstitutionSimResult{
+		Request: request,
+		Result:  runSimConcurrent(request, nil, tuner.signals),
+	}
+	if result.Result.Error != nil {
+		return nil, fmt.Errorf("sim with values %v failed: %s", values, result.Result.Error.Message)
+	}
+	return result, nil
+}
+
+// Re-sims the initial and best values with a seed which none of the evaluations used. The best
+// values were picked because their evaluations came out ahead, so those results overstate them.
+func (tuner *aplTuner) validate(initialValues []float64, bestValues []float64) (*itemSubstitutionSimResult, *itemSubstitutionSimResult, error) {
+	if tuner.signals.Abort.IsTriggered() {
+		return nil, nil, errors.New("aborted")
+	}
+
+	simOptions := googleProto.Clone(tuner.simOptions).(*proto.SimOptions)
+	simOptions.RandomSeed++
+
+	initial, err := tuner.simValues(initialValues, simOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	best, err := tuner.simValues(bestValues, simOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return initial, best, nil
+}
+
+// Runs coordinate descent from the initial values, and returns the best values found with their result.
+func (tuner *aplTuner) tune(maxPasses int) ([]float64, *itemSubstitutionSimResult, error) {
+	bestValues := MapSlice(tuner.tunables, func(tunable *aplTunable) float64 { return tunable.initialValue })
+	best, err := tuner.evaluate(bestValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	tuner.initial = best
+
+	for pass := 0; pass < maxPasses; pass++ {
+		changed := false
+		for i, tunable := range tuner.tunables {
+			base := slices.Clone(bestValues)
+			for _, value := range tunable.values {
+				candidate := slices.Clone(base)
+				candidate[i] = value
+				result, err := tuner.evaluate(candidate)
+				if errors.Is(err, errAPLTuneLimitReached) {
+					return bestValues, best, nil
+				} else if err != nil {
+					return nil, nil, err
+				}
+				// Noise alone would make the tuner wander between equally good values.
+				if delta, _ := dpsDelta(result, best); delta.mean > 0 && delta.isSignificant(tuner.z) {
+					best, bestValues = result, candidate
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return bestValues, best, nil
+}
+
+func runAPLTuner(request *proto.TuneAPLRequest, signals simsignals.Signals) *proto.TuneAPLResult {
+	errorResult := func(err error) *proto.TuneAPLResult {
+		return &proto.TuneAPLResult{Error: &proto.ErrorOutcome{Message: "apl tuner: " + err.Error()}}
+	}
+
+	if request.Request == nil {
+		return errorResult(errors.New("no request to tune"))
+	}
+	confidenceLevel := request.ConfidenceLevel
+	if confidenceLevel == 0 {
+		confidenceLevel = defaultAPLTuneConfidence
+	} else if confidenceLevel < 0 || confidenceLevel >= 1 {
+		return errorResult(fmt.Errorf("confidence level must be between 0 and 1, got %v", confidenceLevel))
+	}
+	maxEvaluations := int(request.MaxEvaluations)
+	if maxEvaluations == 0 {
+		maxEvaluations = defaultAPLTuneMaxEvaluations
+	}
+	maxPasses := int(request.MaxPasses)
+	if maxPasses == 0 {
+		maxPasses = defaultAPLTuneMaxPasses
+	}
+
+	tuner := &aplTuner{
+		request:        googleProto.Clone(request.Request).(*proto.RaidSimRequest),
+		signals:        signals,
+		maxEvaluations: maxEvaluations,
+		z:              confidenceZScore(confidenceLevel),
+		results:        map[string]*itemSubstitutionSimResult{},
+	}
+	var err error
+	if tuner.tunables, err = findAPLTunables(tuner.request.Raid); err != nil {
+		return errorResult(err)
+	}
+	if len(tuner.tunables) == 0 {
+		return errorResult(errors.New("no APL constants are marked as tunable"))
+	}
+
+	tuner.simOptions = &proto.SimOptions{}
+	if tuner.request.SimOptions != nil {
+		tuner.simOptions = googleProto.Clone(tuner.request.SimOptions).(*proto.SimOptions)
+	}
+	if request.Iterations != 0 {
+		tuner.simOptions.Iterations = request.Iterations
+	}
+	enableCommonRandomNumbers(tuner.simOptions)
+
+	bestValues, best, err := tuner.tune(maxPasses)
+	if err != nil {
+		return errorResult(err)
+	}
+	initial := tuner.initial
+	if best != initial {
+		initialValues := MapSlice(tuner.tunables, func(tunable *aplTunable) float64 { return tunable.initialValue })
+		if initial, best, err = tuner.validate(initialValues, bestValues); err != nil {
+			return errorResult(err)
+		}
+	}
+
+	result := &proto.TuneAPLResult{Evaluations: tuner.evaluations}
+	for i, tunable := range tuner.tunables {
+		tunable.setValue(bestValues[i])
+		result.Consts = append(result.Consts, &proto.TunedAPLConst{
+			Name:         tunable.name,
+			InitialValue: tunable.initialValue,
+			Value:        bestValues[i],
+			Val:          tunable.config.Val,
+		})
+	}
+	result.TunedRequest = tuner.request
+
+	z := tuner.z
+	initialEstimate, bestEstimate := initial.dpsEstimate(), best.dpsEstimate()
+	result.InitialDps = initialEstimate.mean
+	result.InitialDpsInterval = initialEstimate.interval(z)
+	result.Dps = bestEstimate.mean
+	result.DpsInterval = bestEstimate.interval(z)
+	if best != initial {
+		delta, _ := dpsDelta(best, initial)
+		result.DpsDelta = delta.mean
+		result.DpsDeltaInterval = delta.interval(z)
+		result.DpsDeltaSignificant = delta.isSignificant(z)
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestAPLTunableValues(t *testing.T) {
+	tunable, err := newAPLTunable(&proto.APLValueConst{Val: "20%", Tuning: &proto.APLValueConstTuning{Min: 0.1, Max: 0.4, Step: 0.1}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tunable.name != "#1" || tunable.initialValue != 20 || !slices.Equal(tunable.values, []float64{0.1, 0.2, 0.3, 0.4}) {
+		t.Fatalf("Unexpected tunable %s with initial value %v and values %v", tunable.name, tunable.initialValue, tunable.values)
+	}
+	tunable.setValue(0.3)
+	if tunable.config.Val != "0.3%" {
+		t.Fatalf("Expected the unit to be kept, got %q", tunable.config.Val)
+	}
+
+	for _, config := range []*proto.APLValueConst{
+		{Val: "true", Tuning: &proto.APLValueConstTuning{Max: 1}},
+		{Val: "1m30s", Tuning: &proto.APLValueConstTuning{Max: 1}},
+		{Val: "1", Tuning: &proto.APLValueConstTuning{Min: 2, Max: 1}},
+		{Val: "1", Tuning: &proto.APLValueConstTuning{Max: 1, Step: -1}},
+	} {
+		if _, err := newAPLTunable(config, 0); err == nil {
+			t.Errorf("Expected an error for %v", config)
+		}
+	}
+}
+
+func TestTuneAPL(t *testing.T) {
+	rotation, err := apltext.Parse(`
+		actions cast_spell(spell_id=spell:42) if !dot_is_active(spell_id=spell:42) & current_time >= const(val="10s", tuning={name="delay", min=0, max=20, step=5})
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := &proto.Player{Name: "Caster", Class: proto.Class_ClassShaman, Spec: &proto.Player_ElementalShaman{}, Equipment: &proto.EquipmentSpec{}, Rotation: rotation}
+	request := &proto.TuneAPLRequest{
+		Request: &proto.RaidSimRequest{
+			SimOptions: &proto.SimOptions{Iterations: 20, RandomSeed: 100},
+			Raid:       SinglePlayerRaidProto(player, nil, nil, nil),
+			Encounter: &proto.Encounter{
+				Targets:  []*proto.Target{{Name: "target", Level: 90, MobType: proto.MobType_MobTypeDemon}},
+				Duration: 30,
+			},
+		},
+	}
+
+	result := TuneAPL(request)
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+	if len(result.Consts) != 1 || result.Consts[0].Name != "delay" || result.Consts[0].InitialValue != 10 || result.Consts[0].Val != "0s" {
+		t.Fatalf("Expected the dot to be cast without delay, got %v", result.Consts)
+	}
+	if result.Dps <= result.InitialDps || result.DpsDelta <= 0 {
+		t.Fatalf("Expected the tuned DPS %0.3f to be higher than the initial DPS %0.3f", result.Dps, result.InitialDps)
+	}
+	// The initial value and the 4 other values of the delay, then another pass without changes.
+	if len(result.Evaluations) != 5 {
+		t.Fatalf("Expected 5 evaluations, got %d", len(result.Evaluations))
+	}
+
+	tunedConst := result.TunedRequest.Raid.Parties[0].Players[0].Rotation.PriorityList[0].Action.Condition.GetAnd().Vals[1].GetCmp().Rhs.GetConst()
+	if tunedConst.Val != "0s" || tunedConst.Tuning == nil {
+		t.Fatalf("Expected the tuned request to have the tuned value and keep the tuning range, got %v", tunedConst)
+	}
+	if request.Request.Raid.Parties[0].Players[0].Rotation.PriorityList[0].Action.Condition.GetAnd().Vals[1].GetCmp().Rhs.GetConst().Val != "10s" {
+		t.Fatalf("Expected the request to be unchanged")
+	}
+
+	request.MaxEvaluations = 2
+	if result := TuneAPL(request); result.Error != nil || len(result.Evaluations) != 2 {
+		t.Fatalf("Expected tuning to stop after 2 evaluations, got %d (%v)", len(result.Evaluations), result.Error)
+	}
+}
+
+func TestTuneAPLSignificance(t *testing.T) {
+	tunable, err := newAPLTunable(&proto.APLValueConst{Val: "1", Tuning: &proto.APLValueConstTuning{Min: 1, Max: 3, Step: 1}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newResult := func(values []float64) *itemSubstitutionSimResult {
+		var agg aggregator
+		for _, v := range values {
+			agg.add(v)
+		}
+		avg, stdev := agg.meanAndStdDev()
+		return &itemSubstitutionSimResult{
+			Request: &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: int32(len(values)), UseLabeledRands: true}},
+			Result: &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{
+				Dps: &proto.DistributionMetrics{Avg: avg, Stdev: stdev, AllValues: values},
+			}},
+		}
+	}
+
+	// The sims are already done, so the tuner only compares their results.
+	tuner := &aplTuner{
+		tunables: []*aplTunable{tunable},
+		z:        confidenceZScore(0.95),
+		results: map[string]*itemSubstitutionSimResult{
+			"[1]": newResult([]float64{900, 1100, 950, 1050, 1000, 800, 1200, 1000}),
+			// 50 DPS more on average, but only by noise.
+			"[2]": newResult([]float64{1200, 900, 1200, 900, 1200, 700, 1300, 1000}),
+			// 10 DPS more in every iteration.
+			"[3]": newResult([]float64{911, 1109, 960, 1061, 1010, 809, 1211, 1009}),
+		},
+	}
+	values, best, err := tuner.tune(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(values, []float64{3}) || best != tuner.results["[3]"] {
+		t.Fatalf("Expected only the significant gain to be taken, got values %v", values)
+	}
+}
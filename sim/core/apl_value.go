@@ -140,6 +140,8 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 		value = rot.newValueFocusRegenPerSecond(config.GetFocusRegenPerSecond(), config.Uuid)
 	case *proto.APLValue_EnergyTimeToTarget:
 		value = rot.newValueEnergyTimeToTarget(config.GetEnergyTimeToTarget(), config.Uuid)
+	case *proto.APLValue_ResourceTimeToTarget:
+		value = rot.newValueResourceTimeToTarget(config.GetResourceTimeToTarget(), config.Uuid)
 	case *proto.APLValue_FocusTimeToTarget:
 		value = rot.newValueFocusTimeToTarget(config.GetFocusTimeToTarget(), config.Uuid)
 	case *proto.APLValue_CurrentGenericResource:
@@ -253,11 +255,21 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 	case *proto.APLValue_SequenceTimeToReady:
 		value = rot.newValueSequenceTimeToReady(config.GetSequenceTimeToReady(), config.Uuid)
 
+	// Variables
+	case *proto.APLValue_Variable:
+		value = rot.newValueVariable(config.GetVariable(), config.Uuid)
+
 	// Properties
 	case *proto.APLValue_ChannelClipDelay:
 		value = rot.newValueChannelClipDelay(config.GetChannelClipDelay(), config.Uuid)
 	case *proto.APLValue_InputDelay:
 		value = rot.newValueInputDelay(config.GetInputDelay(), config.Uuid)
+	case *proto.APLValue_FrontOfTarget:
+		value = rot.newValueFrontOfTarget(config.GetFrontOfTarget(), config.Uuid)
+	case *proto.APLValue_DistanceToTarget:
+		value = rot.newValueDistanceToTarget(config.GetDistanceToTarget(), config.Uuid)
+	case *proto.APLValue_NumberTargetsInRange:
+		value = rot.newValueNumberTargetsInRange(config.GetNumberTargetsInRange(), config.Uuid)
 
 	default:
 		value = nil
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/wowsims/mop/sim/core/proto"
@@ -60,8 +61,64 @@ func (value *APLValueFrontOfTarget) Type() proto.APLValueType {
 	return proto.APLValueType_ValueTypeBool
 }
 func (value *APLValueFrontOfTarget) GetBool(sim *Simulation) bool {
-	return value.unit.PseudoStats.InFrontOfTarget
+	return value.unit.IsInFrontOf(value.unit.CurrentTarget)
 }
 func (value *APLValueFrontOfTarget) String() string {
 	return "Front of Target()"
 }
+
+type APLValueDistanceToTarget struct {
+	DefaultAPLValueImpl
+	unit   *Unit
+	target UnitReference
+}
+
+func (rot *APLRotation) newValueDistanceToTarget(config *proto.APLValueDistanceToTarget, _ *proto.UUID) APLValue {
+	target := rot.GetTargetUnit(config.TargetUnit)
+	if target.Get() == nil {
+		return nil
+	}
+	return &APLValueDistanceToTarget{
+		unit:   rot.unit,
+		target: target,
+	}
+}
+func (value *APLValueDistanceToTarget) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueDistanceToTarget) GetFloat(sim *Simulation) float64 {
+	value.unit.UpdatePosition(sim)
+	return value.unit.DistanceTo(value.target.Get())
+}
+func (value *APLValueDistanceToTarget) String() string {
+	return fmt.Sprintf("Distance to Target(%s)", value.target.Get().Label)
+}
+
+type APLValueNumberTargetsInRange struct {
+	DefaultAPLValueImpl
+	unit      *Unit
+	moveRange float64
+}
+
+func (rot *APLRotation) newValueNumberTargetsInRange(config *proto.APLValueNumberTargetsInRange, _ *proto.UUID) APLValue {
+	return &APLValueNumberTargetsInRange{
+		unit:      rot.unit,
+		moveRange: config.Range,
+	}
+}
+func (value *APLValueNumberTargetsInRange) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueNumberTargetsInRange) GetInt(sim *Simulation) int32 {
+	value.unit.UpdatePosition(sim)
+	numTargets := int32(0)
+	for _, target := range value.unit.Env.Encounter.ActiveTargetUnits {
+		if value.unit.DistanceTo(target) <= value.moveRange {
+			numTargets++
+		}
+	}
+	return numTargets
+}
+func (value *APLValueNumberTargetsInRange) String() string {
+	return fmt.Sprintf("Number of Targets in Range(%.1f)", value.moveRange)
+}
@@ -413,6 +413,45 @@ func (value *APLValueEnergyTimeToTarget) String() string {
 	return "Estimated Time To Target Energy"
 }
 
+type APLValueResourceTimeToTarget struct {
+	DefaultAPLValueImpl
+	unit         *Unit
+	resourceType proto.ResourceType
+	targetAmount APLValue
+}
+
+func (rot *APLRotation) newValueResourceTimeToTarget(config *proto.APLValueResourceTimeToTarget, uuid *proto.UUID) APLValue {
+	unit := rot.unit
+	if !unit.HasResource(config.ResourceType) {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "%s does not use %s", unit.Label, resourceTypeLabel(config.ResourceType))
+		return nil
+	}
+	if !canPredictResource(config.ResourceType) {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Cannot estimate the time to %s, since it is only generated by abilities", resourceTypeLabel(config.ResourceType))
+		return nil
+	}
+
+	targetAmount := rot.coerceTo(rot.newAPLValue(config.TargetAmount), proto.APLValueType_ValueTypeFloat)
+	if targetAmount == nil {
+		return nil
+	}
+
+	return &APLValueResourceTimeToTarget{
+		unit:         unit,
+		resourceType: config.ResourceType,
+		targetAmount: targetAmount,
+	}
+}
+func (value *APLValueResourceTimeToTarget) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueResourceTimeToTarget) GetDuration(sim *Simulation) time.Duration {
+	return value.unit.TimeToTargetResource(sim, value.resourceType, value.targetAmount.GetFloat(sim))
+}
+func (value *APLValueResourceTimeToTarget) String() string {
+	return fmt.Sprintf("Estimated Time To Target %s(%s)", resourceTypeLabel(value.resourceType), value.targetAmount)
+}
+
 type APLValueCurrentComboPoints struct {
 	DefaultAPLValueImpl
 	unit *Unit
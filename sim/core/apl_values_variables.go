@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// A named variable declared by the rotation. Variables are reset to their initial
+// value at the start of each iteration, and can be changed with Set Variable actions.
+type APLVariable struct {
+	name      string
+	initValue APLValue
+
+	boolVal     bool
+	intVal      int32
+	floatVal    float64
+	durationVal time.Duration
+}
+
+func (rot *APLRotation) newAPLVariable(config *proto.APLVariable) *APLVariable {
+	if config.Name == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Variable must provide a name")
+		return nil
+	}
+	if rot.getAPLVariable(config.Name) != nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Duplicate variable name: '%s'", config.Name)
+		return nil
+	}
+
+	initValue := rot.newAPLValue(config.Value)
+	if initValue == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Variable '%s' must provide an initial value", config.Name)
+		return nil
+	}
+	if initValue.Type() == proto.APLValueType_ValueTypeString || initValue.Type() == proto.APLValueType_ValueTypeUnknown {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Variable '%s' must have a boolean, number or duration value", config.Name)
+		return nil
+	}
+
+	return &APLVariable{
+		name:      config.Name,
+		initValue: initValue,
+	}
+}
+
+func (rot *APLRotation) getAPLVariable(name string) *APLVariable {
+	for _, variable := range rot.variables {
+		if variable.name == name {
+			return variable
+		}
+	}
+	return nil
+}
+
+func (variable *APLVariable) Type() proto.APLValueType {
+	return variable.initValue.Type()
+}
+
+// Stores the current result of value, which must have the same type as this variable.
+func (variable *APLVariable) set(sim *Simulation, value APLValue) {
+	switch variable.Type() {
+	case proto.APLValueType_ValueTypeBool:
+		variable.boolVal = value.GetBool(sim)
+	case proto.APLValueType_ValueTypeInt:
+		variable.intVal = value.GetInt(sim)
+	case proto.APLValueType_ValueTypeFloat:
+		variable.floatVal = value.GetFloat(sim)
+	case proto.APLValueType_ValueTypeDuration:
+		variable.durationVal = value.GetDuration(sim)
+	}
+}
+
+// Whether the current result of value differs from the stored value.
+func (variable *APLVariable) differsFrom(sim *Simulation, value APLValue) bool {
+	switch variable.Type() {
+	case proto.APLValueType_ValueTypeBool:
+		return variable.boolVal != value.GetBool(sim)
+	case proto.APLValueType_ValueTypeInt:
+		return variable.intVal != value.GetInt(sim)
+	case proto.APLValueType_ValueTypeFloat:
+		return variable.floatVal != value.GetFloat(sim)
+	case proto.APLValueType_ValueTypeDuration:
+		return variable.durationVal != value.GetDuration(sim)
+	}
+	return false
+}
+
+func (variable *APLVariable) reset(sim *Simulation) {
+	variable.set(sim, variable.initValue)
+}
+
+type APLValueVariable struct {
+	DefaultAPLValueImpl
+	variable *APLVariable
+}
+
+func (rot *APLRotation) newValueVariable(config *proto.APLValueVariable, uuid *proto.UUID) APLValue {
+	if config.Name == "" {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Variable() must provide a variable name")
+		return nil
+	}
+	variable := rot.getAPLVariable(config.Name)
+	if variable == nil {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "No variable with name: '%s'", config.Name)
+		return nil
+	}
+	return &APLValueVariable{
+		DefaultAPLValueImpl: DefaultAPLValueImpl{Uuid: uuid},
+		variable:            variable,
+	}
+}
+func (value *APLValueVariable) Type() proto.APLValueType {
+	return value.variable.Type()
+}
+func (value *APLValueVariable) GetBool(sim *Simulation) bool {
+	return value.variable.boolVal
+}
+func (value *APLValueVariable) GetInt(sim *Simulation) int32 {
+	return value.variable.intVal
+}
+func (value *APLValueVariable) GetFloat(sim *Simulation) float64 {
+	return value.variable.floatVal
+}
+func (value *APLValueVariable) GetDuration(sim *Simulation) time.Duration {
+	return value.variable.durationVal
+}
+func (value *APLValueVariable) String() string {
+	return fmt.Sprintf("Variable(%s)", value.variable.name)
+}
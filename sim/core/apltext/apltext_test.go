@@ -0,0 +1,151 @@
+package apltext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// UUIDs are only used by the UI, and are not part of the text format.
+func clearUUIDs(msg protoreflect.Message) {
+	if value, ok := msg.Interface().(*proto.APLValue); ok {
+		value.Uuid = nil
+	}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			for i := 0; i < v.List().Len(); i++ {
+				clearUUIDs(v.List().Get(i).Message())
+			}
+		} else {
+			clearUUIDs(v.Message())
+		}
+		return true
+	})
+}
+
+func TestRoundTripExistingAPLs(t *testing.T) {
+	files, err := filepath.Glob("../../../ui/*/*/apls/*.apl.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find APL files: %v", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		expected := &proto.APLRotation{}
+		if err := protojson.Unmarshal(data, expected); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", file, err)
+		}
+		clearUUIDs(expected.ProtoReflect())
+		expected.Type = proto.APLRotation_TypeAPL
+		expected.Simple = nil
+
+		text := Format(expected)
+		actual, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Failed to parse formatted %s: %v\n%s", file, err, text)
+		}
+		if !googleProto.Equal(expected, actual) {
+			t.Fatalf("Round trip of %s changed the rotation.\nText:\n%s\nExpected: %s\nActual: %s", file, text, expected, actual)
+		}
+		if reformatted := Format(actual); reformatted != text {
+			t.Fatalf("Formatting %s is not stable.\nFirst:\n%s\nSecond:\n%s", file, text, reformatted)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	rot, err := Parse(`
+		# Open with a sequence.
+		variable burst = false
+
+		prepull at -1s cast_spell(spell_id=other:OtherActionPotion)
+
+		actions [note="Keep the debuff up"] cast_spell(spell_id=spell:1234) if !dot_is_active(spell_id=spell:1234) & current_time > 10s
+		actions.aoe cast_spell(spell_id=spell:2345, target=Target:1) if number_targets >= 3
+		actions call_action_list(list_name="aoe")
+	`)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	expected := &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		Variables: []*proto.APLVariable{
+			{Name: "burst", Value: newConstValue("false")},
+		},
+		PrepullActions: []*proto.APLPrepullAction{{
+			DoAtValue: newConstValue("-1s"),
+			Action: &proto.APLAction{Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+				SpellId: &proto.ActionID{RawId: &proto.ActionID_OtherId{OtherId: proto.OtherAction_OtherActionPotion}},
+			}}},
+		}},
+		PriorityList: []*proto.APLListItem{
+			{
+				Notes: "Keep the debuff up",
+				Action: &proto.APLAction{
+					Condition: &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: []*proto.APLValue{
+						{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: &proto.APLValue{Value: &proto.APLValue_DotIsActive{DotIsActive: &proto.APLValueDotIsActive{
+							SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 1234}},
+						}}}}}},
+						{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+							Op:  proto.APLValueCompare_OpGt,
+							Lhs: &proto.APLValue{Value: &proto.APLValue_CurrentTime{CurrentTime: &proto.APLValueCurrentTime{}}},
+							Rhs: newConstValue("10s"),
+						}}},
+					}}}},
+					Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+						SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 1234}},
+					}},
+				},
+			},
+			{
+				Action: &proto.APLAction{Action: &proto.APLAction_CallActionList{CallActionList: &proto.APLActionCallActionList{ListName: "aoe"}}},
+			},
+		},
+		ActionLists: []*proto.APLActionList{{
+			Name: "aoe",
+			Items: []*proto.APLListItem{{
+				Action: &proto.APLAction{
+					Condition: &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+						Op:  proto.APLValueCompare_OpGe,
+						Lhs: &proto.APLValue{Value: &proto.APLValue_NumberTargets{NumberTargets: &proto.APLValueNumberTargets{}}},
+						Rhs: newConstValue("3"),
+					}}},
+					Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+						SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 2345}},
+						Target:  &proto.UnitReference{Type: proto.UnitReference_Target, Index: 1},
+					}},
+				},
+			}},
+		}},
+	}
+	if !googleProto.Equal(expected, rot) {
+		t.Fatalf("Unexpected rotation.\nExpected: %s\nActual: %s", expected, rot)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"actions cast_spel(spell_id=spell:1)",
+		"actions cast_spell(spell=spell:1)",
+		"actions cast_spell(spell_id=spell:abc)",
+		"actions wait(duration=1s) if cast_spell(spell_id=spell:1)",
+		"actions [hidden] wait(duration=1s)",
+		"actions sequence(actions=wait(duration=1s))",
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Expected an error parsing %q", text)
+		}
+	}
+}
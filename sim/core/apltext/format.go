@@ -0,0 +1,310 @@
+package apltext
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	aplActionName     = (&proto.APLAction{}).ProtoReflect().Descriptor().FullName()
+	aplValueName      = (&proto.APLValue{}).ProtoReflect().Descriptor().FullName()
+	actionIDName      = (&proto.ActionID{}).ProtoReflect().Descriptor().FullName()
+	unitReferenceName = (&proto.UnitReference{}).ProtoReflect().Descriptor().FullName()
+)
+
+// Written in place of an action or value which has not been set.
+const noneName = "none"
+
+// Words which cannot be written as a bare variable or action list name.
+var reservedWords = map[string]bool{
+	"variable": true,
+	"prepull":  true,
+	"actions":  true,
+	"at":       true,
+	"if":       true,
+	"true":     true,
+	"false":    true,
+	noneName:   true,
+}
+
+var (
+	identRegex  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	numberRegex = regexp.MustCompile(`^-?\d+(\.\d+)?([a-zA-Zµ%]+(\d+(\.\d+)?[a-zA-Zµ]+)*)?$`)
+)
+
+// Operator precedence, used to decide where parentheses are needed.
+const (
+	precOr = iota + 1
+	precAnd
+	precCmp
+	precSum
+	precProduct
+	precUnary
+	precPrimary
+)
+
+var comparisonOperatorStrings = map[proto.APLValueCompare_ComparisonOperator]string{}
+var mathOperatorStrings = map[proto.APLValueMath_MathOperator]string{}
+
+func init() {
+	for str, op := range comparisonOperators {
+		comparisonOperatorStrings[op] = str
+	}
+	for str, op := range mathOperators {
+		mathOperatorStrings[op] = str
+	}
+}
+
+// Format returns the text form of an APL. Only the variables, prepull actions and
+// action lists are included; the rotation type and simple rotation are not.
+func Format(rot *proto.APLRotation) string {
+	var sections []string
+
+	var lines []string
+	for _, variable := range rot.Variables {
+		lines = append(lines, fmt.Sprintf("variable %s = %s", formatName(variable.Name), formatValue(variable.Value, 0)))
+	}
+	sections = appendSection(sections, lines)
+
+	lines = nil
+	for _, item := range rot.PrepullActions {
+		line := "prepull"
+		if item.Hide {
+			line += " [hide]"
+		}
+		if item.DoAtValue != nil {
+			line += " at " + formatValue(item.DoAtValue, 0)
+		}
+		lines = append(lines, line+" "+formatAction(item.Action))
+	}
+	sections = appendSection(sections, lines)
+
+	sections = appendSection(sections, formatListItems("actions", rot.PriorityList))
+	for _, actionList := range rot.ActionLists {
+		sections = appendSection(sections, formatListItems("actions."+formatName(actionList.Name), actionList.Items))
+	}
+
+	if len(sections) == 0 {
+		return ""
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+// FormatAction returns the text form of a single action, as written after the list prefix
+// of a line.
+func FormatAction(action *proto.APLAction) string {
+	return formatAction(action)
+}
+
+func appendSection(sections []string, lines []string) []string {
+	if len(lines) == 0 {
+		return sections
+	}
+	return append(sections, strings.Join(lines, "\n"))
+}
+
+func formatListItems(prefix string, items []*proto.APLListItem) []string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		var attrs []string
+		if item.Hide {
+			attrs = append(attrs, "hide")
+		}
+		if item.Notes != "" {
+			attrs = append(attrs, "note="+strconv.Quote(item.Notes))
+		}
+
+		line := prefix
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		lines = append(lines, line+" "+formatAction(item.Action))
+	}
+	return lines
+}
+
+func formatName(name string) string {
+	if identRegex.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func formatAction(action *proto.APLAction) string {
+	if action == nil {
+		return noneName
+	}
+
+	str := formatOneof(action.ProtoReflect(), "action")
+	if action.Condition != nil {
+		str += " if " + formatValue(action.Condition, 0)
+	}
+	return str
+}
+
+// Formats a value, wrapping it in parentheses if it binds less tightly than minPrec.
+func formatValue(value *proto.APLValue, minPrec int) string {
+	str, prec := formatValueWithPrec(value)
+	if prec < minPrec {
+		return "(" + str + ")"
+	}
+	return str
+}
+
+func formatValueWithPrec(value *proto.APLValue) (string, int) {
+	if value == nil {
+		return noneName, precPrimary
+	}
+
+	switch v := value.Value.(type) {
+	case *proto.APLValue_Const:
+		if v.Const.Tuning != nil {
+			// Tuning ranges have no dedicated syntax.
+			break
+		} else if v.Const.Val == "true" || v.Const.Val == "false" {
+			return v.Const.Val, precPrimary
+		} else if numberRegex.MatchString(v.Const.Val) {
+			if strings.HasPrefix(v.Const.Val, "-") {
+				return v.Const.Val, precUnary
+			}
+			return v.Const.Val, precPrimary
+		}
+		return strconv.Quote(v.Const.Val), precPrimary
+	case *proto.APLValue_Or:
+		if len(v.Or.Vals) >= 2 {
+			return formatValueList(v.Or.Vals, " | ", precAnd), precOr
+		}
+	case *proto.APLValue_And:
+		if len(v.And.Vals) >= 2 {
+			return formatValueList(v.And.Vals, " & ", precCmp), precAnd
+		}
+	case *proto.APLValue_Not:
+		if v.Not.Val != nil {
+			return "!" + formatValue(v.Not.Val, precUnary), precUnary
+		}
+	case *proto.APLValue_Cmp:
+		if op, ok := comparisonOperatorStrings[v.Cmp.Op]; ok && v.Cmp.Lhs != nil && v.Cmp.Rhs != nil {
+			return formatValue(v.Cmp.Lhs, precSum) + " " + op + " " + formatValue(v.Cmp.Rhs, precSum), precCmp
+		}
+	case *proto.APLValue_Math:
+		if op, ok := mathOperatorStrings[v.Math.Op]; ok && v.Math.Lhs != nil && v.Math.Rhs != nil {
+			prec := precSum
+			if v.Math.Op == proto.APLValueMath_OpMul || v.Math.Op == proto.APLValueMath_OpDiv {
+				prec = precProduct
+			}
+			// Operators are left-associative, so only the right side needs parentheses at the same precedence.
+			return formatValue(v.Math.Lhs, prec) + " " + op + " " + formatValue(v.Math.Rhs, prec+1), prec
+		}
+	}
+
+	return formatOneof(value.ProtoReflect(), "value"), precPrimary
+}
+
+func formatValueList(vals []*proto.APLValue, separator string, minPrec int) string {
+	strs := make([]string, len(vals))
+	for i, val := range vals {
+		strs[i] = formatValue(val, minPrec)
+	}
+	return strings.Join(strs, separator)
+}
+
+// Formats the field which is set in msg's oneof as a call, with the fields of the inner message as arguments.
+func formatOneof(msg protoreflect.Message, oneofName protoreflect.Name) string {
+	fd := msg.WhichOneof(msg.Descriptor().Oneofs().ByName(oneofName))
+	if fd == nil {
+		return noneName
+	}
+
+	args := formatFields(msg.Get(fd).Message())
+	if len(args) == 0 {
+		return string(fd.Name())
+	}
+	return string(fd.Name()) + "(" + strings.Join(args, ", ") + ")"
+}
+
+func formatFields(msg protoreflect.Message) []string {
+	var args []string
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) {
+			continue
+		}
+		args = append(args, string(fd.Name())+"="+formatField(fd, msg.Get(fd)))
+	}
+	return args
+}
+
+func formatField(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if !fd.IsList() {
+		return formatSingular(fd, value)
+	}
+
+	list := value.List()
+	strs := make([]string, list.Len())
+	for i := range strs {
+		strs[i] = formatSingular(fd, list.Get(i))
+	}
+	return "[" + strings.Join(strs, ", ") + "]"
+}
+
+func formatSingular(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		msg := value.Message()
+		switch fd.Message().FullName() {
+		case aplActionName:
+			return formatAction(msg.Interface().(*proto.APLAction))
+		case aplValueName:
+			return formatValue(msg.Interface().(*proto.APLValue), 0)
+		case actionIDName:
+			if str := formatActionID(msg.Interface().(*proto.ActionID)); str != "" {
+				return str
+			}
+		case unitReferenceName:
+			if unitRef := msg.Interface().(*proto.UnitReference); unitRef.Owner == nil {
+				if unitRef.Index == 0 {
+					return unitRef.Type.String()
+				}
+				return fmt.Sprintf("%s:%d", unitRef.Type, unitRef.Index)
+			}
+		}
+		return "{" + strings.Join(formatFields(msg), ", ") + "}"
+	case protoreflect.EnumKind:
+		if enumValue := fd.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	default:
+		return value.String()
+	}
+}
+
+func formatActionID(actionID *proto.ActionID) string {
+	var str string
+	switch id := actionID.RawId.(type) {
+	case *proto.ActionID_SpellId:
+		str = fmt.Sprintf("spell:%d", id.SpellId)
+	case *proto.ActionID_ItemId:
+		str = fmt.Sprintf("item:%d", id.ItemId)
+	case *proto.ActionID_OtherId:
+		str = "other:" + id.OtherId.String()
+	default:
+		return ""
+	}
+	if actionID.Tag != 0 {
+		str += fmt.Sprintf(":%d", actionID.Tag)
+	}
+	return str
+}
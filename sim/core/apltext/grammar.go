@@ -0,0 +1,155 @@
+package apltext
+
+import (
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+var textLexer = lexer.MustSimple([]lexer.SimpleRule{
+	{Name: "Comment", Pattern: `#[^\n]*`},
+	{Name: "Whitespace", Pattern: `\s+`},
+	{Name: "String", Pattern: `"(\\.|[^"\\])*"`},
+	{Name: "Number", Pattern: `\d+(\.\d+)?([a-zA-Zµ%]+(\d+(\.\d+)?[a-zA-Zµ]+)*)?`},
+	{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+	{Name: "Operator", Pattern: `==|!=|<=|>=|[-+*/<>=!&|:,.()\[\]{}]`},
+})
+
+var textParser = participle.MustBuild[textFile](
+	participle.Lexer(textLexer),
+	participle.Elide("Whitespace", "Comment"),
+	participle.Unquote("String"),
+	participle.UseLookahead(4),
+)
+
+type textFile struct {
+	Statements []*textStatement `parser:"@@*"`
+}
+
+type textStatement struct {
+	Variable *textVariable `parser:"  'variable' @@"`
+	Prepull  *textPrepull  `parser:"| 'prepull' @@"`
+	Item     *textListItem `parser:"| 'actions' @@"`
+}
+
+type textVariable struct {
+	Pos   lexer.Position
+	Name  string    `parser:"@(Ident | String)"`
+	Value *textExpr `parser:"'=' @@"`
+}
+
+type textPrepull struct {
+	Attrs  []*textAttr `parser:"('[' @@ (',' @@)* ']')?"`
+	DoAt   *textExpr   `parser:"('at' @@)?"`
+	Action *textAction `parser:"@@"`
+}
+
+type textListItem struct {
+	Pos    lexer.Position
+	List   *string     `parser:"('.' @(Ident | String))?"`
+	Attrs  []*textAttr `parser:"('[' @@ (',' @@)* ']')?"`
+	Action *textAction `parser:"@@"`
+}
+
+type textAttr struct {
+	Pos   lexer.Position
+	Key   string    `parser:"@Ident"`
+	Value *textExpr `parser:"('=' @@)?"`
+}
+
+type textAction struct {
+	Call      *textCall `parser:"@@"`
+	Condition *textExpr `parser:"('if' @@)?"`
+}
+
+// Expressions, from lowest to highest precedence.
+
+type textExpr struct {
+	Left  *textAnd   `parser:"@@"`
+	Right []*textAnd `parser:"('|' @@)*"`
+}
+
+type textAnd struct {
+	Left  *textCmp   `parser:"@@"`
+	Right []*textCmp `parser:"('&' @@)*"`
+}
+
+type textCmp struct {
+	Left  *textSum `parser:"@@"`
+	Op    string   `parser:"(@('==' | '!=' | '<=' | '>=' | '<' | '>')"`
+	Right *textSum `parser:"@@)?"`
+}
+
+type textSum struct {
+	Left  *textProduct   `parser:"@@"`
+	Right []*textSumTerm `parser:"@@*"`
+}
+
+type textSumTerm struct {
+	Op   string       `parser:"@('+' | '-')"`
+	Term *textProduct `parser:"@@"`
+}
+
+type textProduct struct {
+	Left  *textUnary         `parser:"@@"`
+	Right []*textProductTerm `parser:"@@*"`
+}
+
+type textProductTerm struct {
+	Op   string     `parser:"@('*' | '/')"`
+	Term *textUnary `parser:"@@"`
+}
+
+type textUnary struct {
+	Not     *textUnary   `parser:"  '!' @@"`
+	Neg     *textUnary   `parser:"| '-' @@"`
+	Primary *textPrimary `parser:"| @@"`
+}
+
+type textPrimary struct {
+	Pos     lexer.Position
+	Number  *string      `parser:"  @Number"`
+	String  *string      `parser:"| @String"`
+	List    *textList    `parser:"| @@"`
+	Message *textMessage `parser:"| @@"`
+	Ref     *textRef     `parser:"| @@"`
+	Call    *textCall    `parser:"| @@"`
+	Sub     *textExpr    `parser:"| '(' @@ ')'"`
+}
+
+// A bracketed list, for repeated fields.
+type textList struct {
+	Elems []*textConditional `parser:"'[' (@@ (',' @@)*)? ']'"`
+}
+
+// A braced list of fields, for messages without a dedicated syntax.
+type textMessage struct {
+	Fields []*textArg `parser:"'{' (@@ (',' @@)*)? '}'"`
+}
+
+// A colon-separated reference, such as an action ID (spell:1234) or unit (Target:1).
+type textRef struct {
+	Kind  string         `parser:"@Ident"`
+	Parts []*textRefPart `parser:"(':' @@)+"`
+}
+
+type textRefPart struct {
+	Value string `parser:"@('-'? Number | Ident)"`
+}
+
+type textCall struct {
+	Pos  lexer.Position
+	Name string     `parser:"@Ident"`
+	Args []*textArg `parser:"('(' (@@ (',' @@)*)? ')')?"`
+}
+
+type textArg struct {
+	Pos   lexer.Position
+	Key   string           `parser:"@Ident '='"`
+	Value *textConditional `parser:"@@"`
+}
+
+// An expression with an optional condition, which is only valid when it describes an action.
+type textConditional struct {
+	Expr      *textExpr `parser:"@@"`
+	Condition *textExpr `parser:"('if' @@)?"`
+}
@@ -0,0 +1,547 @@
+package apltext
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Parse converts the text form of an APL into an APLRotation.
+func Parse(text string) (*proto.APLRotation, error) {
+	file, err := textParser.ParseString("", text)
+	if err != nil {
+		return nil, err
+	}
+
+	rot := &proto.APLRotation{Type: proto.APLRotation_TypeAPL}
+	actionLists := make(map[string]*proto.APLActionList)
+
+	for _, statement := range file.Statements {
+		switch {
+		case statement.Variable != nil:
+			value, err := parseValue(statement.Variable.Value)
+			if err != nil {
+				return nil, err
+			}
+			rot.Variables = append(rot.Variables, &proto.APLVariable{
+				Name:  statement.Variable.Name,
+				Value: value,
+			})
+
+		case statement.Prepull != nil:
+			prepull := statement.Prepull
+			item := &proto.APLPrepullAction{}
+			for _, attr := range prepull.Attrs {
+				if attr.Key != "hide" {
+					return nil, fmt.Errorf("%s: unknown prepull attribute %q", attr.Pos, attr.Key)
+				}
+				if item.Hide, err = parseBoolAttr(attr); err != nil {
+					return nil, err
+				}
+			}
+			if prepull.DoAt != nil {
+				if item.DoAtValue, err = parseValue(prepull.DoAt); err != nil {
+					return nil, err
+				}
+			}
+			if item.Action, err = parseAction(prepull.Action.Call, prepull.Action.Condition); err != nil {
+				return nil, err
+			}
+			rot.PrepullActions = append(rot.PrepullActions, item)
+
+		case statement.Item != nil:
+			listItem := statement.Item
+			item := &proto.APLListItem{}
+			for _, attr := range listItem.Attrs {
+				switch attr.Key {
+				case "hide":
+					item.Hide, err = parseBoolAttr(attr)
+				case "note":
+					item.Notes, err = parseStringAttr(attr)
+				default:
+					err = fmt.Errorf("%s: unknown action attribute %q", attr.Pos, attr.Key)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			if item.Action, err = parseAction(listItem.Action.Call, listItem.Action.Condition); err != nil {
+				return nil, err
+			}
+
+			if listItem.List == nil {
+				rot.PriorityList = append(rot.PriorityList, item)
+			} else {
+				actionList, ok := actionLists[*listItem.List]
+				if !ok {
+					actionList = &proto.APLActionList{Name: *listItem.List}
+					actionLists[*listItem.List] = actionList
+					rot.ActionLists = append(rot.ActionLists, actionList)
+				}
+				actionList.Items = append(actionList.Items, item)
+			}
+		}
+	}
+
+	return rot, nil
+}
+
+func parseBoolAttr(attr *textAttr) (bool, error) {
+	if attr.Value == nil {
+		return true, nil
+	}
+	if call := attr.Value.call(); call != nil && len(call.Args) == 0 && (call.Name == "true" || call.Name == "false") {
+		return call.Name == "true", nil
+	}
+	return false, fmt.Errorf("%s: attribute %q must be true or false", attr.Pos, attr.Key)
+}
+
+func parseStringAttr(attr *textAttr) (string, error) {
+	if attr.Value != nil {
+		if primary, negated := attr.Value.primary(); primary != nil && !negated && primary.String != nil {
+			return *primary.String, nil
+		}
+	}
+	return "", fmt.Errorf("%s: attribute %q must be a string", attr.Pos, attr.Key)
+}
+
+func parseAction(call *textCall, condition *textExpr) (*proto.APLAction, error) {
+	action := &proto.APLAction{}
+	if call.Name != noneName || len(call.Args) > 0 {
+		if err := parseOneof(action.ProtoReflect(), "action", call); err != nil {
+			return nil, err
+		}
+	}
+	if condition != nil {
+		var err error
+		if action.Condition, err = parseValue(condition); err != nil {
+			return nil, err
+		}
+	}
+	return action, nil
+}
+
+func parseValue(expr *textExpr) (*proto.APLValue, error) {
+	if len(expr.Right) == 0 {
+		return parseAnd(expr.Left)
+	}
+
+	vals, err := parseValues(expr.Left, expr.Right, parseAnd)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: vals}}}, nil
+}
+
+func parseAnd(and *textAnd) (*proto.APLValue, error) {
+	if len(and.Right) == 0 {
+		return parseCmp(and.Left)
+	}
+
+	vals, err := parseValues(and.Left, and.Right, parseCmp)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: vals}}}, nil
+}
+
+func parseValues[T any](left T, right []T, parseFn func(T) (*proto.APLValue, error)) ([]*proto.APLValue, error) {
+	vals := make([]*proto.APLValue, 0, len(right)+1)
+	for _, term := range append([]T{left}, right...) {
+		val, err := parseFn(term)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
+}
+
+var comparisonOperators = map[string]proto.APLValueCompare_ComparisonOperator{
+	"==": proto.APLValueCompare_OpEq,
+	"!=": proto.APLValueCompare_OpNe,
+	"<":  proto.APLValueCompare_OpLt,
+	"<=": proto.APLValueCompare_OpLe,
+	">":  proto.APLValueCompare_OpGt,
+	">=": proto.APLValueCompare_OpGe,
+}
+
+var mathOperators = map[string]proto.APLValueMath_MathOperator{
+	"+": proto.APLValueMath_OpAdd,
+	"-": proto.APLValueMath_OpSub,
+	"*": proto.APLValueMath_OpMul,
+	"/": proto.APLValueMath_OpDiv,
+}
+
+func parseCmp(cmp *textCmp) (*proto.APLValue, error) {
+	lhs, err := parseSum(cmp.Left)
+	if err != nil || cmp.Op == "" {
+		return lhs, err
+	}
+	rhs, err := parseSum(cmp.Right)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+		Op:  comparisonOperators[cmp.Op],
+		Lhs: lhs,
+		Rhs: rhs,
+	}}}, nil
+}
+
+func newMathValue(op string, lhs *proto.APLValue, rhs *proto.APLValue) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+		Op:  mathOperators[op],
+		Lhs: lhs,
+		Rhs: rhs,
+	}}}
+}
+
+func parseSum(sum *textSum) (*proto.APLValue, error) {
+	value, err := parseProduct(sum.Left)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range sum.Right {
+		rhs, err := parseProduct(term.Term)
+		if err != nil {
+			return nil, err
+		}
+		value = newMathValue(term.Op, value, rhs)
+	}
+	return value, nil
+}
+
+func parseProduct(product *textProduct) (*proto.APLValue, error) {
+	value, err := parseUnary(product.Left)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range product.Right {
+		rhs, err := parseUnary(term.Term)
+		if err != nil {
+			return nil, err
+		}
+		value = newMathValue(term.Op, value, rhs)
+	}
+	return value, nil
+}
+
+func parseUnary(unary *textUnary) (*proto.APLValue, error) {
+	switch {
+	case unary.Not != nil:
+		val, err := parseUnary(unary.Not)
+		if err != nil {
+			return nil, err
+		}
+		return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: val}}}, nil
+	case unary.Neg != nil:
+		// Negative numbers are kept as a single constant, e.g. for prepull timings like '-1s'.
+		if primary := unary.Neg.Primary; primary != nil && primary.Number != nil {
+			return newConstValue("-" + *primary.Number), nil
+		}
+		val, err := parseUnary(unary.Neg)
+		if err != nil {
+			return nil, err
+		}
+		return newMathValue("-", newConstValue("0"), val), nil
+	default:
+		return parsePrimary(unary.Primary)
+	}
+}
+
+func newConstValue(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
+
+func parsePrimary(primary *textPrimary) (*proto.APLValue, error) {
+	switch {
+	case primary.Number != nil:
+		return newConstValue(*primary.Number), nil
+	case primary.String != nil:
+		return newConstValue(*primary.String), nil
+	case primary.Sub != nil:
+		return parseValue(primary.Sub)
+	case primary.Call != nil:
+		call := primary.Call
+		if len(call.Args) == 0 {
+			switch call.Name {
+			case "true", "false":
+				return newConstValue(call.Name), nil
+			case noneName:
+				return &proto.APLValue{}, nil
+			}
+		}
+		value := &proto.APLValue{}
+		if err := parseOneof(value.ProtoReflect(), "value", call); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%s: expected a value", primary.Pos)
+	}
+}
+
+// Sets the field of msg's oneof named by the call, using the call's arguments as the fields of the inner message.
+func parseOneof(msg protoreflect.Message, oneofName protoreflect.Name, call *textCall) error {
+	fd := msg.Descriptor().Oneofs().ByName(oneofName).Fields().ByName(protoreflect.Name(call.Name))
+	if fd == nil || fd.Message() == nil {
+		return fmt.Errorf("%s: unknown %s %q", call.Pos, oneofName, call.Name)
+	}
+
+	inner := msg.NewField(fd).Message()
+	if err := parseArgs(inner, call.Args); err != nil {
+		return err
+	}
+	msg.Set(fd, protoreflect.ValueOfMessage(inner))
+	return nil
+}
+
+func parseArgs(msg protoreflect.Message, args []*textArg) error {
+	for _, arg := range args {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(arg.Key))
+		if fd == nil {
+			return fmt.Errorf("%s: unknown field %q for %s", arg.Pos, arg.Key, msg.Descriptor().Name())
+		}
+		if msg.Has(fd) {
+			return fmt.Errorf("%s: duplicate field %q", arg.Pos, arg.Key)
+		}
+		if err := parseField(msg, fd, arg.Value, arg.Pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, value *textConditional, pos lexer.Position) error {
+	if fd.IsMap() {
+		return fmt.Errorf("%s: map field %q is not supported", pos, fd.Name())
+	}
+
+	if !fd.IsList() {
+		fieldValue, err := parseSingular(fd, value, pos, func() protoreflect.Message { return msg.NewField(fd).Message() })
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, fieldValue)
+		return nil
+	}
+
+	primary, negated := value.primary()
+	if primary == nil || negated || primary.List == nil {
+		return fmt.Errorf("%s: field %q must be a list", pos, fd.Name())
+	}
+	list := msg.Mutable(fd).List()
+	for _, elem := range primary.List.Elems {
+		elemValue, err := parseSingular(fd, elem, pos, func() protoreflect.Message { return list.NewElement().Message() })
+		if err != nil {
+			return err
+		}
+		list.Append(elemValue)
+	}
+	return nil
+}
+
+func parseSingular(fd protoreflect.FieldDescriptor, value *textConditional, pos lexer.Position, newMessage func() protoreflect.Message) (protoreflect.Value, error) {
+	if fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == aplActionName {
+		call := value.Expr.call()
+		if call == nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: field %q must be an action", pos, fd.Name())
+		}
+		action, err := parseAction(call, value.Condition)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(action.ProtoReflect()), nil
+	}
+
+	if value.Condition != nil {
+		return protoreflect.Value{}, fmt.Errorf("%s: only actions can have an 'if' condition", pos)
+	}
+	if fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == aplValueName {
+		val, err := parseValue(value.Expr)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(val.ProtoReflect()), nil
+	}
+
+	primary, negated := value.Expr.primary()
+	if primary == nil {
+		return protoreflect.Value{}, fmt.Errorf("%s: field %q cannot be an expression", pos, fd.Name())
+	}
+	invalid := func() (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("%s: invalid value for field %q", pos, fd.Name())
+	}
+	number := ""
+	if primary.Number != nil {
+		number = *primary.Number
+		if negated {
+			number = "-" + number
+		}
+	} else if negated {
+		return invalid()
+	}
+	name := ""
+	if primary.Call != nil && len(primary.Call.Args) == 0 {
+		name = primary.Call.Name
+	}
+
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		msg := newMessage()
+		switch {
+		case primary.Message != nil:
+			if err := parseArgs(msg, primary.Message.Fields); err != nil {
+				return protoreflect.Value{}, err
+			}
+		case fd.Message().FullName() == actionIDName && primary.Ref != nil:
+			actionID, err := parseActionID(primary.Ref)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("%s: %w", pos, err)
+			}
+			return protoreflect.ValueOfMessage(actionID.ProtoReflect()), nil
+		case fd.Message().FullName() == unitReferenceName && (primary.Ref != nil || name != ""):
+			unitRef, err := parseUnitReference(primary.Ref, name)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("%s: %w", pos, err)
+			}
+			return protoreflect.ValueOfMessage(unitRef.ProtoReflect()), nil
+		default:
+			return invalid()
+		}
+		return protoreflect.ValueOfMessage(msg), nil
+	case protoreflect.EnumKind:
+		if name != "" {
+			if enumValue := fd.Enum().Values().ByName(protoreflect.Name(name)); enumValue != nil {
+				return protoreflect.ValueOfEnum(enumValue.Number()), nil
+			}
+		} else if n, err := strconv.ParseInt(number, 10, 32); err == nil {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+	case protoreflect.BoolKind:
+		if name == "true" || name == "false" {
+			return protoreflect.ValueOfBool(name == "true"), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, err := strconv.ParseInt(number, 10, 32); err == nil {
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+			return protoreflect.ValueOfInt64(n), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, err := strconv.ParseUint(number, 10, 32); err == nil {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+			return protoreflect.ValueOfUint64(n), nil
+		}
+	case protoreflect.FloatKind:
+		if f, err := strconv.ParseFloat(number, 32); err == nil {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, err := strconv.ParseFloat(number, 64); err == nil {
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	case protoreflect.StringKind:
+		if primary.String != nil {
+			return protoreflect.ValueOfString(*primary.String), nil
+		}
+	}
+	return invalid()
+}
+
+func parseActionID(ref *textRef) (*proto.ActionID, error) {
+	if len(ref.Parts) > 2 {
+		return nil, fmt.Errorf("invalid action ID")
+	}
+	actionID := &proto.ActionID{}
+	if len(ref.Parts) == 2 {
+		tag, err := strconv.ParseInt(ref.Parts[1].Value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid action ID tag %q", ref.Parts[1].Value)
+		}
+		actionID.Tag = int32(tag)
+	}
+
+	switch ref.Kind {
+	case "spell", "item":
+		id, err := strconv.ParseInt(ref.Parts[0].Value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s ID %q", ref.Kind, ref.Parts[0].Value)
+		}
+		if ref.Kind == "spell" {
+			actionID.RawId = &proto.ActionID_SpellId{SpellId: int32(id)}
+		} else {
+			actionID.RawId = &proto.ActionID_ItemId{ItemId: int32(id)}
+		}
+	case "other":
+		otherID, ok := proto.OtherAction_value[ref.Parts[0].Value]
+		if !ok {
+			return nil, fmt.Errorf("unknown other action %q", ref.Parts[0].Value)
+		}
+		actionID.RawId = &proto.ActionID_OtherId{OtherId: proto.OtherAction(otherID)}
+	default:
+		return nil, fmt.Errorf("unknown action ID type %q", ref.Kind)
+	}
+	return actionID, nil
+}
+
+func parseUnitReference(ref *textRef, name string) (*proto.UnitReference, error) {
+	index := int64(0)
+	if ref != nil {
+		if len(ref.Parts) != 1 {
+			return nil, fmt.Errorf("invalid unit reference")
+		}
+		var err error
+		if index, err = strconv.ParseInt(ref.Parts[0].Value, 10, 32); err != nil {
+			return nil, fmt.Errorf("invalid unit index %q", ref.Parts[0].Value)
+		}
+		name = ref.Kind
+	}
+
+	unitType, ok := proto.UnitReference_Type_value[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit type %q", name)
+	}
+	return &proto.UnitReference{Type: proto.UnitReference_Type(unitType), Index: int32(index)}, nil
+}
+
+// Returns the primary term of a plain expression, and whether it is negated.
+// Returns nil if the expression uses any operators other than negation.
+func (expr *textExpr) primary() (*textPrimary, bool) {
+	if len(expr.Right) > 0 || len(expr.Left.Right) > 0 {
+		return nil, false
+	}
+	cmp := expr.Left.Left
+	if cmp.Op != "" || len(cmp.Left.Right) > 0 || len(cmp.Left.Left.Right) > 0 {
+		return nil, false
+	}
+	unary := cmp.Left.Left.Left
+	if unary.Neg != nil && unary.Neg.Primary != nil {
+		return unary.Neg.Primary, true
+	}
+	return unary.Primary, false
+}
+
+// Returns the call of an expression which consists of a single call, or nil.
+func (expr *textExpr) call() *textCall {
+	if primary, negated := expr.primary(); primary != nil && !negated {
+		return primary.Call
+	}
+	return nil
+}
+
+func (value *textConditional) primary() (*textPrimary, bool) {
+	if value.Condition != nil {
+		return nil, false
+	}
+	return value.Expr.primary()
+}
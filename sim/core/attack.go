@@ -304,7 +304,9 @@ func (wa *WeaponAttack) swing(sim *Simulation) time.Duration {
 
 	if wa.replaceSwing != nil {
 		// Need to check APL here to allow last-moment HS queue casts.
-		wa.unit.ReactToEvent(sim)
+		if !wa.unit.isInteractive(sim) {
+			wa.unit.ReactToEvent(sim)
+		}
 
 		// Need to check this again in case the DoNextAction call swapped items.
 		if wa.replaceSwing != nil {
@@ -318,7 +320,7 @@ func (wa *WeaponAttack) swing(sim *Simulation) time.Duration {
 	wa.swingAt = sim.CurrentTime + wa.curSwingDuration
 	attackSpell.Cast(sim, wa.unit.CurrentTarget)
 
-	if !sim.Options.Interactive && wa.unit.Rotation != nil {
+	if !wa.unit.isInteractive(sim) && wa.unit.Rotation != nil {
 		wa.unit.ReactToEvent(sim)
 	}
 
@@ -200,7 +200,7 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 	}
 
 	if sim.Log != nil {
-		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
+		aura.logEvent(sim, proto.AuraLogEvent_StacksChanged, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
 	if aura.OnStacksChange != nil {
@@ -627,7 +627,7 @@ func (aura *Aura) Activate(sim *Simulation) {
 	aura.metrics.Procs++
 	if aura.IsActive() {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
-			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
+			aura.logEvent(sim, proto.AuraLogEvent_Refreshed, aura.stacks, aura.stacks)
 		}
 		aura.Refresh(sim)
 		return
@@ -712,7 +712,7 @@ func (aura *Aura) Activate(sim *Simulation) {
 	}
 
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
-		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
+		aura.logEvent(sim, proto.AuraLogEvent_Gained, 0, aura.stacks)
 	}
 
 	// don't invoke possible callbacks until the internal state is consistent
@@ -734,6 +734,9 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		} else {
 			aura.metrics.Uptime += sim.CurrentTime - max(aura.startTime, 0)
 		}
+		if timelines := aura.Unit.Metrics.timelines; timelines != nil {
+			timelines.addAuraUptime(aura.ActionID, aura.startTime, min(sim.CurrentTime, aura.expires))
+		}
 	}
 
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
@@ -741,7 +744,7 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		oldTime := sim.CurrentTime
 		sim.CurrentTime = min(sim.CurrentTime, aura.expires)
 		if sim.Log != nil {
-			aura.Unit.Log(sim, "Aura faded: %s", aura.ActionID)
+			aura.logEvent(sim, proto.AuraLogEvent_Faded, aura.stacks, 0)
 		}
 		sim.CurrentTime = oldTime
 	}
@@ -29,14 +29,18 @@ type bulkSimRunner struct {
 	SingleRaidSimRunner raidSimRunner
 	// Request used for this bulk simulation.
 	Request *proto.BulkSimRequest
+	// Number of simulations to run at the same time, defaults to one per CPU.
+	Concurrency int
 }
 
 func BulkSim(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
-	bulk := &bulkSimRunner{
+	return runBulkSim(signals, &bulkSimRunner{
 		SingleRaidSimRunner: runSim,
 		Request:             request,
-	}
+	}, progress)
+}
 
+func runBulkSim(signals simsignals.Signals, bulk *bulkSimRunner, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
 	result := bulk.Run(signals, progress)
 
 	if progress != nil {
@@ -143,6 +147,21 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		originalIterations = defaultIterationsPerCombo
 	}
 
+	confidenceLevel, err := bulkConfidenceLevel(b.Request.BulkSettings)
+	if err != nil {
+		return &proto.BulkSimResult{
+			Error: &proto.ErrorOutcome{Message: err.Error()},
+		}
+	}
+	z := confidenceZScore(confidenceLevel)
+
+	if b.Request.BulkSettings.CommonRandomNumbers {
+		if b.Request.BaseSettings.SimOptions == nil {
+			b.Request.BaseSettings.SimOptions = &proto.SimOptions{}
+		}
+		enableCommonRandomNumbers(b.Request.BaseSettings.SimOptions)
+	}
+
 	validCombos, newIters, err := buildCombos(signals, b.Request.BaseSettings, b.Request.BulkSettings, player)
 	if err != nil {
 		return &proto.BulkSimResult{
@@ -150,6 +169,21 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		}
 	}
 
+	if b.Request.BulkSettings.ReoptimizeGear {
+		for _, combo := range validCombos {
+			if signals.Abort.IsTriggered() {
+				return &proto.BulkSimResult{
+					Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted},
+				}
+			}
+			if err := reoptimizeGear(combo.req, combo.cl, b.Request.BulkSettings); err != nil {
+				return &proto.BulkSimResult{
+					Error: &proto.ErrorOutcome{Message: fmt.Sprintf("bulksim: failed to re-optimize gear: %v", err)},
+				}
+			}
+		}
+	}
+
 	// TODO(Riotdog-GehennasEU): Make this configurable?
 	maxResults := 30
 
@@ -194,6 +228,20 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		}
 	}
 
+	if b.Request.BulkSettings.AdaptiveIterations {
+		maxIterations := b.Request.BulkSettings.MaxAdaptiveIterations
+		if maxIterations <= 0 {
+			maxIterations = originalIterations * defaultMaxAdaptiveIterationsFactor
+		}
+		var tempBase *itemSubstitutionSimResult
+		var errorOutcome *proto.ErrorOutcome
+		rankedResults, tempBase, errorOutcome = b.refineRankedResults(signals, rankedResults, baseResult, maxResults, maxIterations, z, progress)
+		if errorOutcome != nil {
+			return &proto.BulkSimResult{Error: errorOutcome}
+		}
+		baseResult = tempBase
+	}
+
 	if baseResult == nil {
 		return &proto.BulkSimResult{
 			Error: &proto.ErrorOutcome{
@@ -211,12 +259,15 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 	bum.Auras = nil
 	bum.Resources = nil
 	bum.Pets = nil
+	clearAllValues(bum)
 
 	result = &proto.BulkSimResult{
 		EquippedGearResult: &proto.BulkComboResult{
 			UnitMetrics: bum,
 		},
+		ConfidenceLevel: confidenceLevel,
 	}
+	fillComboStats(result.EquippedGearResult, baseResult, baseResult, z)
 
 	for _, r := range rankedResults {
 		um := r.Result.GetRaidMetrics().GetParties()[0].GetPlayers()[0]
@@ -224,11 +275,14 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 		um.Auras = nil
 		um.Resources = nil
 		um.Pets = nil
-		result.Results = append(result.Results, &proto.BulkComboResult{
+		clearAllValues(um)
+		comboResult := &proto.BulkComboResult{
 			ItemsAdded:    r.ChangeLog.AddedItems,
 			UnitMetrics:   um,
 			TalentLoadout: r.ChangeLog.TalentLoadout,
-		})
+		}
+		fillComboStats(comboResult, r, baseResult, z)
+		result.Results = append(result.Results, comboResult)
 	}
 
 	if progress != nil {
@@ -242,7 +296,9 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 
 func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos []singleBulkSim, iterations int32, progress chan *proto.ProgressMetrics) ([]*itemSubstitutionSimResult, *itemSubstitutionSimResult, *proto.ErrorOutcome) {
 	concurrency := runtime.NumCPU() + 1
-	if concurrency <= 0 {
+	if b.Concurrency > 0 {
+		concurrency = b.Concurrency
+	} else if concurrency <= 0 {
 		concurrency = 2
 	}
 
@@ -407,7 +463,7 @@ func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest,
 		if !ok {
 			return nil, 0, fmt.Errorf("unknown item with id %d in bulk settings", is.Id)
 		}
-		for _, slot := range eligibleSlotsForItem(&item, isFuryWarrior) {
+		for _, slot := range EligibleSlotsForItem(&item, isFuryWarrior) {
 			distinctItemSlotCombos = append(distinctItemSlotCombos, &itemWithSlot{
 				Item:  is,
 				Slot:  slot,
@@ -836,6 +892,54 @@ func createNewRequestWithSubstitution(readonlyInputRequest *proto.RaidSimRequest
 	return request, changeLog
 }
 
+// reoptimizeGear re-optimizes the reforges and gems of the player's equipment in the given request,
+// and updates the change log so that it reports the re-optimized items.
+func reoptimizeGear(request *proto.RaidSimRequest, changeLog *raidSimRequestChangeLog, bulkSettings *proto.BulkSettings) error {
+	var gems []int32
+	if bulkSettings.AutoGem {
+		for _, gemID := range []int32{bulkSettings.DefaultRedGem, bulkSettings.DefaultYellowGem, bulkSettings.DefaultBlueGem, bulkSettings.DefaultMetaGem} {
+			if gemID != 0 {
+				gems = append(gems, gemID)
+			}
+		}
+	}
+
+	player := request.Raid.Parties[0].Players[0]
+	optimizer, err := runGearOptimizer(&proto.OptimizeGearRequest{
+		Player:           player,
+		RaidBuffs:        request.Raid.Buffs,
+		PartyBuffs:       request.Raid.Parties[0].Buffs,
+		Debuffs:          request.Raid.Debuffs,
+		Encounter:        request.Encounter,
+		EpWeights:        bulkSettings.EpWeights,
+		StatCaps:         bulkSettings.StatCaps,
+		OptimizeReforges: true,
+		Gems:             gems,
+		EnsureMetaReqMet: bulkSettings.EnsureMetaReqMet,
+		// Bulk sims assume that every belt has a belt buckle, as when filling in the default gems.
+		AssumeBeltBuckle: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	player.Equipment = optimizer.toEquipmentSpecProto()
+
+	// Combos with different talents share change log entries, so replace them instead of updating them.
+	changeLog.AddedItems = MapSlice(changeLog.AddedItems, func(added *proto.ItemSpecWithSlot) *proto.ItemSpecWithSlot {
+		// Removed items stay removed, and slots missing from the equipment have nothing to report.
+		if added.Item == nil || int(added.Slot) >= len(player.Equipment.Items) {
+			return added
+		}
+		return &proto.ItemSpecWithSlot{
+			Item: player.Equipment.Items[added.Slot],
+			Slot: added.Slot,
+		}
+	})
+
+	return nil
+}
+
 type ItemComboChecker map[int64]struct{}
 
 func (ic *ItemComboChecker) HasCombo(itema int32, itemb int32) bool {
@@ -0,0 +1,190 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+const defaultBulkConfidenceLevel = 0.95
+
+// Multiple of iterations_per_combo used as the adaptive iteration limit if none is set.
+const defaultMaxAdaptiveIterationsFactor = 8
+
+// Estimate of a combo's average DPS.
+type dpsEstimate struct {
+	mean   float64
+	stderr float64
+}
+
+func (r *itemSubstitutionSimResult) iterations() int32 {
+	return r.Request.GetSimOptions().GetIterations()
+}
+
+func (r *itemSubstitutionSimResult) dpsEstimate() dpsEstimate {
+	n := float64(r.iterations())
+	if r.Result == nil || r.Result.Error != nil || n < 2 {
+		return dpsEstimate{mean: r.Score()}
+	}
+	dps := r.Result.RaidMetrics.Dps
+	// The stdev in the metrics is the population stdev, so apply Bessel's correction
+	// to get the sample stdev before dividing by sqrt(n).
+	return dpsEstimate{
+		mean:   dps.Avg,
+		stderr: dps.Stdev * math.Sqrt(n/(n-1)) / math.Sqrt(n),
+	}
+}
+
+// Returns the estimate of a - b, assuming the two are independent.
+func (a dpsEstimate) minus(b dpsEstimate) dpsEstimate {
+	return dpsEstimate{
+		mean:   a.mean - b.mean,
+		stderr: math.Hypot(a.stderr, b.stderr),
+	}
+}
+
+// Returns the estimate of the difference in DPS between two results. If both were simmed
+// with common random numbers, the iterations both share are correlated, which cancels out
+// most of the noise. Otherwise the results are treated as independent, and paired is false.
+func dpsDelta(a *itemSubstitutionSimResult, b *itemSubstitutionSimResult) (delta dpsEstimate, paired bool) {
+	aEstimate, bEstimate := a.dpsEstimate(), b.dpsEstimate()
+	independent := aEstimate.minus(bEstimate)
+	if !a.Request.GetSimOptions().GetUseLabeledRands() || !b.Request.GetSimOptions().GetUseLabeledRands() {
+		return independent, false
+	}
+
+	aValues := a.Result.GetRaidMetrics().GetDps().GetAllValues()
+	bValues := b.Result.GetRaidMetrics().GetDps().GetAllValues()
+	n := min(len(aValues), len(bValues))
+	if n < 2 {
+		return independent, false
+	}
+
+	// A re-simmed result has more iterations than the other, so each mean keeps the standard
+	// error of its own iterations, and only the covariance comes from the shared iterations.
+	var aShared, bShared aggregator
+	var sumOfProducts float64
+	for i := 0; i < n; i++ {
+		aShared.add(aValues[i])
+		bShared.add(bValues[i])
+		sumOfProducts += aValues[i] * bValues[i]
+	}
+	covariance := (sumOfProducts - aShared.sum*bShared.sum/float64(n)) / float64(n-1)
+	variance := aEstimate.stderr*aEstimate.stderr + bEstimate.stderr*bEstimate.stderr -
+		2*covariance*float64(n)/(float64(a.iterations())*float64(b.iterations()))
+	return dpsEstimate{
+		mean:   independent.mean,
+		stderr: math.Sqrt(max(variance, 0)),
+	}, true
+}
+
+func (e dpsEstimate) interval(z float64) *proto.ConfidenceInterval {
+	return &proto.ConfidenceInterval{
+		Lower: e.mean - z*e.stderr,
+		Upper: e.mean + z*e.stderr,
+	}
+}
+
+// Whether the interval excludes 0.
+func (e dpsEstimate) isSignificant(z float64) bool {
+	return math.Abs(e.mean) > z*e.stderr
+}
+
+// Returns the z-score of a two-sided confidence interval at the given confidence level.
+func confidenceZScore(confidenceLevel float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidenceLevel)
+}
+
+func bulkConfidenceLevel(settings *proto.BulkSettings) (float64, error) {
+	level := settings.GetConfidenceLevel()
+	if level == 0 {
+		return defaultBulkConfidenceLevel, nil
+	}
+	if level <= 0 || level >= 1 {
+		return 0, fmt.Errorf("bulksim: confidence level must be between 0 and 1, got %v", level)
+	}
+	return level, nil
+}
+
+// Fills in the statistics of a combo result, relative to the result for the equipped gear.
+func fillComboStats(comboResult *proto.BulkComboResult, r *itemSubstitutionSimResult, base *itemSubstitutionSimResult, z float64) {
+	estimate := r.dpsEstimate()
+	comboResult.Iterations = r.iterations()
+	comboResult.DpsStderr = estimate.stderr
+	comboResult.DpsInterval = estimate.interval(z)
+
+	if r == base {
+		return
+	}
+	delta, paired := dpsDelta(r, base)
+	comboResult.DpsDelta = delta.mean
+	comboResult.DpsDeltaStderr = delta.stderr
+	comboResult.DpsDeltaInterval = delta.interval(z)
+	comboResult.DpsDeltaSignificant = delta.isSignificant(z)
+	if paired && delta.stderr > 0 {
+		independent := estimate.minus(base.dpsEstimate())
+		comboResult.DpsDeltaVarianceReduction = (independent.stderr * independent.stderr) / (delta.stderr * delta.stderr)
+	}
+}
+
+// The per-iteration values are only needed for computing the deltas, and are too large to return.
+func clearAllValues(unitMetrics *proto.UnitMetrics) {
+	for _, metrics := range []*proto.DistributionMetrics{unitMetrics.Dps, unitMetrics.Threat, unitMetrics.Dtps, unitMetrics.Tmi, unitMetrics.Hps, unitMetrics.Tto} {
+		if metrics != nil {
+			metrics.AllValues = nil
+		}
+	}
+}
+
+// Re-sims the top numResults results with doubled iterations, until every result is
+// separated from its neighbours in the ranking or has reached maxIterations.
+func (b *bulkSimRunner) refineRankedResults(signals simsignals.Signals, rankedResults []*itemSubstitutionSimResult, baseResult *itemSubstitutionSimResult, numResults int, maxIterations int32, z float64, progress chan *proto.ProgressMetrics) ([]*itemSubstitutionSimResult, *itemSubstitutionSimResult, *proto.ErrorOutcome) {
+	for {
+		// Include one more than is returned, so the cutoff is also separated.
+		numContenders := min(numResults+1, len(rankedResults))
+		unresolved := make([]bool, numContenders)
+		var iterations int32
+		for i := 0; i+1 < numContenders; i++ {
+			higher, lower := rankedResults[i], rankedResults[i+1]
+			if delta, _ := dpsDelta(higher, lower); delta.isSignificant(z) {
+				continue
+			}
+			for j, r := range []*itemSubstitutionSimResult{higher, lower} {
+				if r.iterations() < maxIterations {
+					unresolved[i+j] = true
+					iterations = max(iterations, r.iterations()*2)
+				}
+			}
+		}
+		if iterations == 0 {
+			return rankedResults, baseResult, nil
+		}
+		iterations = min(iterations, maxIterations)
+
+		var combos []singleBulkSim
+		var remaining []*itemSubstitutionSimResult
+		for i, r := range rankedResults {
+			if i < numContenders && unresolved[i] {
+				combos = append(combos, singleBulkSim{req: r.Request, cl: r.ChangeLog, eq: r.Substitution})
+			} else {
+				remaining = append(remaining, r)
+			}
+		}
+
+		resimmed, tempBase, errorOutcome := b.getRankedResults(signals, combos, iterations, progress)
+		if errorOutcome != nil {
+			return nil, nil, errorOutcome
+		}
+		if tempBase != nil {
+			baseResult = tempBase
+		}
+
+		rankedResults = append(remaining, resimmed...)
+		sort.Slice(rankedResults, func(i, j int) bool {
+			return rankedResults[i].Score() > rankedResults[j].Score()
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package core
 
 import (
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -474,3 +477,200 @@ func TestGenerateAllEquipmentSubstitutions(t *testing.T) {
 		})
 	}
 }
+
+func TestConfidenceZScore(t *testing.T) {
+	for _, tc := range []struct {
+		level float64
+		want  float64
+	}{
+		{level: 0.9, want: 1.645},
+		{level: 0.95, want: 1.960},
+		{level: 0.99, want: 2.576},
+	} {
+		if got := confidenceZScore(tc.level); math.Abs(got-tc.want) > 0.001 {
+			t.Errorf("confidenceZScore(%v) = %v, want %v", tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestFillComboStats(t *testing.T) {
+	newResult := func(avg float64, stdev float64, iterations int32) *itemSubstitutionSimResult {
+		return &itemSubstitutionSimResult{
+			Request: &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: iterations}},
+			Result: &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{
+				Dps: &proto.DistributionMetrics{Avg: avg, Stdev: stdev},
+			}},
+		}
+	}
+	base := newResult(1000, 99.995, 10000)
+	z := confidenceZScore(0.95)
+
+	near := &proto.BulkComboResult{}
+	fillComboStats(near, newResult(1002, 99.995, 10000), base, z)
+	if math.Abs(near.DpsStderr-1) > 1e-3 || math.Abs(near.DpsDeltaStderr-math.Sqrt2) > 1e-3 {
+		t.Fatalf("Unexpected standard errors: %v, %v", near.DpsStderr, near.DpsDeltaStderr)
+	}
+	if near.DpsDeltaSignificant {
+		t.Fatalf("Delta of %v with interval %v should not be significant", near.DpsDelta, near.DpsDeltaInterval)
+	}
+
+	far := &proto.BulkComboResult{}
+	fillComboStats(far, newResult(1010, 99.995, 10000), base, z)
+	if !far.DpsDeltaSignificant {
+		t.Fatalf("Delta of %v with interval %v should be significant", far.DpsDelta, far.DpsDeltaInterval)
+	}
+	if math.Abs(far.DpsDeltaInterval.Lower-(10-z*math.Sqrt2)) > 1e-2 {
+		t.Fatalf("Unexpected delta interval: %v", far.DpsDeltaInterval)
+	}
+}
+
+func TestRefineRankedResults(t *testing.T) {
+	const stdev = 300
+	means := map[*proto.RaidSimRequest]float64{}
+	newCombo := func(mean float64) singleBulkSim {
+		req := &proto.RaidSimRequest{SimOptions: &proto.SimOptions{}}
+		means[req] = mean
+		return singleBulkSim{
+			req: req,
+			cl:  &raidSimRequestChangeLog{},
+			eq:  &equipmentSubstitution{Items: []*itemWithSlot{{Item: &proto.ItemSpec{Id: int32(mean)}}}},
+		}
+	}
+
+	bulk := &bulkSimRunner{
+		SingleRaidSimRunner: func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, signals simsignals.Signals) *proto.RaidSimResult {
+			result := &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{
+				Dps: &proto.DistributionMetrics{Avg: means[rsr], Stdev: stdev},
+			}}
+			progress <- &proto.ProgressMetrics{CompletedIterations: rsr.SimOptions.Iterations, FinalRaidResult: result}
+			return result
+		},
+	}
+
+	progress := make(chan *proto.ProgressMetrics)
+	go func() {
+		for range progress {
+		}
+	}()
+
+	signals := simsignals.CreateSignals()
+	combos := []singleBulkSim{newCombo(1100), newCombo(1000), newCombo(1010), newCombo(900)}
+	ranked, _, errorOutcome := bulk.getRankedResults(signals, combos, 1000, progress)
+	if errorOutcome != nil {
+		t.Fatalf("getRankedResults() returned error: %v", errorOutcome.Message)
+	}
+
+	ranked, _, errorOutcome = bulk.refineRankedResults(signals, ranked, nil, 3, 16000, confidenceZScore(0.95), progress)
+	if errorOutcome != nil {
+		t.Fatalf("refineRankedResults() returned error: %v", errorOutcome.Message)
+	}
+
+	// Only the two close results need more iterations, until the difference of 10 DPS
+	// is larger than 1.96 * 300 * sqrt(2 / n).
+	want := []struct {
+		mean       float64
+		iterations int32
+	}{
+		{mean: 1100, iterations: 1000},
+		{mean: 1010, iterations: 8000},
+		{mean: 1000, iterations: 8000},
+		{mean: 900, iterations: 1000},
+	}
+	for i, r := range ranked {
+		if r.Score() != want[i].mean || r.iterations() != want[i].iterations {
+			t.Errorf("Result %d: got mean %v with %d iterations, want mean %v with %d iterations", i, r.Score(), r.iterations(), want[i].mean, want[i].iterations)
+		}
+	}
+}
+
+func TestPairedDpsDelta(t *testing.T) {
+	newResult := func(values []float64, labeledRands bool) *itemSubstitutionSimResult {
+		var agg aggregator
+		for _, v := range values {
+			agg.add(v)
+		}
+		avg, stdev := agg.meanAndStdDev()
+		return &itemSubstitutionSimResult{
+			Request: &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: int32(len(values)), UseLabeledRands: labeledRands}},
+			Result: &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{
+				Dps: &proto.DistributionMetrics{Avg: avg, Stdev: stdev, AllValues: values},
+			}},
+		}
+	}
+
+	// The combo is the base plus 10 DPS, with a little noise of its own.
+	baseValues := []float64{900, 1100, 950, 1050, 1000, 800, 1200, 1000}
+	comboValues := []float64{911, 1109, 960, 1061, 1010, 809, 1211, 1009}
+
+	independent, paired := dpsDelta(newResult(comboValues, false), newResult(baseValues, false))
+	if paired {
+		t.Fatalf("Results without common random numbers should not be paired")
+	}
+	delta, paired := dpsDelta(newResult(comboValues, true), newResult(baseValues, true))
+	if !paired {
+		t.Fatalf("Results with common random numbers should be paired")
+	}
+	if delta.mean != independent.mean {
+		t.Fatalf("Paired mean %v should equal independent mean %v", delta.mean, independent.mean)
+	}
+	if delta.stderr >= independent.stderr/10 {
+		t.Fatalf("Paired stderr %v should be much smaller than independent stderr %v", delta.stderr, independent.stderr)
+	}
+
+	z := confidenceZScore(0.95)
+	if independent.isSignificant(z) || !delta.isSignificant(z) {
+		t.Fatalf("Only the paired delta should be significant: independent %v, paired %v", independent, delta)
+	}
+
+	comboResult := &proto.BulkComboResult{}
+	fillComboStats(comboResult, newResult(comboValues, true), newResult(baseValues, true), z)
+	if comboResult.DpsDeltaVarianceReduction <= 100 {
+		t.Fatalf("Expected a large variance reduction, got %v", comboResult.DpsDeltaVarianceReduction)
+	}
+
+	// A re-simmed combo has more iterations than the base, and only shares the first ones
+	// with it. Its extra iterations are noisy, so the delta is no longer significant.
+	resimmedValues := append(slices.Clone(comboValues), 500, 1500, 600, 1400, 700, 1300, 800, 1300)
+	delta, paired = dpsDelta(newResult(resimmedValues, true), newResult(baseValues, true))
+	if !paired || delta.isSignificant(z) {
+		t.Fatalf("Expected the noise of the extra iterations to be included, got %v", delta)
+	}
+}
+
+func TestReoptimizeGear(t *testing.T) {
+	addToDatabase(optimizerItemDatabase)
+	player := &proto.Player{
+		Class:     proto.Class_ClassShaman,
+		Spec:      &proto.Player_ElementalShaman{},
+		Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{{Id: itemOptimizerCritHelm}}},
+	}
+	request := &proto.RaidSimRequest{
+		Raid:      SinglePlayerRaidProto(player, nil, nil, nil),
+		Encounter: &proto.Encounter{Duration: 180},
+	}
+	helm := &proto.ItemSpecWithSlot{Item: request.Raid.Parties[0].Players[0].Equipment.Items[0], Slot: proto.ItemSlot_ItemSlotHead}
+	removedOffHand := &proto.ItemSpecWithSlot{Slot: proto.ItemSlot_ItemSlotOffHand}
+	// The equipment has no finger slot, e.g. after a substitution of a shorter equipment spec.
+	ring := &proto.ItemSpecWithSlot{Item: &proto.ItemSpec{Id: itemOptimizerHasteRing}, Slot: proto.ItemSlot_ItemSlotFinger1}
+	changeLog := &raidSimRequestChangeLog{AddedItems: []*proto.ItemSpecWithSlot{helm, removedOffHand, ring}}
+
+	err := reoptimizeGear(request, changeLog, &proto.BulkSettings{
+		EpWeights: &proto.UnitStats{Stats: stats.Stats{stats.HitRating: 2, stats.CritRating: 1}.ToProtoArray()},
+		StatCaps:  &proto.UnitStats{Stats: stats.Stats{stats.HitRating: 400}.ToProtoArray()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reforgedHelm := request.Raid.Parties[0].Players[0].Equipment.Items[0]
+	if reforgedHelm.Reforging != 990201 {
+		t.Fatalf("Expected the helm to be reforged from crit to hit, got reforge %d", reforgedHelm.Reforging)
+	}
+	added := changeLog.AddedItems
+	if len(added) != 3 || added[0] == helm || added[0].Item != reforgedHelm || added[0].Slot != proto.ItemSlot_ItemSlotHead {
+		t.Fatalf("Expected the change log to report the reforged helm in a new entry, got %v", added)
+	}
+	if added[1] != removedOffHand || added[2] != ring {
+		t.Fatalf("Expected removed items and slots missing from the equipment to be kept, got %v", added)
+	}
+}
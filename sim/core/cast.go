@@ -164,8 +164,7 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 		// Hardcasts
 		if spell.CurCast.CastTime > 0 {
 			if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
-					spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
+				spell.Unit.logCastBegan(sim, spell, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			}
 
 			spell.Unit.Hardcast = Hardcast{
@@ -173,7 +172,7 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				ActionID: spell.ActionID,
 				OnComplete: func(sim *Simulation, target *Unit) {
 					if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-						spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+						spell.Unit.logCastCompleted(sim, spell)
 					}
 
 					if spell.Cost != nil {
@@ -207,9 +206,8 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 		}
 
 		if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-			spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
-				spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
-			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+			spell.Unit.logCastBegan(sim, spell, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
+			spell.Unit.logCastCompleted(sim, spell)
 		}
 
 		if spell.Cost != nil {
@@ -283,9 +281,8 @@ func (spell *Spell) makeCastFuncSimple() CastSuccessFunc {
 		}
 
 		if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-			spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
-				spell.ActionID, 0.0, "0s", "0s")
-			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+			spell.Unit.logCastBegan(sim, spell, 0, 0, 0)
+			spell.Unit.logCastCompleted(sim, spell)
 		}
 
 		if spell.MaxCharges > 0 {
@@ -313,9 +310,8 @@ func (spell *Spell) makeCastFuncSimple() CastSuccessFunc {
 func (spell *Spell) makeCastFuncAutosOrProcs() CastSuccessFunc {
 	return func(sim *Simulation, target *Unit) bool {
 		if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-			spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
-				spell.ActionID, 0.0, "0s", "0s")
-			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+			spell.Unit.logCastBegan(sim, spell, 0, 0, 0)
+			spell.Unit.logCastCompleted(sim, spell)
 		}
 
 		spell.applyEffects(sim, target)
@@ -333,9 +329,8 @@ func (spell *Spell) makeCastFuncAutosOrProcs() CastSuccessFunc {
 // Can be used for spells that proc off other spells and are the same spell id
 func (spell *Spell) Proc(sim *Simulation, target *Unit) {
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-		spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
-			spell.ActionID, 0.0, "0s", "0s")
-		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+		spell.Unit.logCastBegan(sim, spell, 0, 0, 0)
+		spell.Unit.logCastCompleted(sim, spell)
 	}
 
 	spell.applyEffects(sim, target)
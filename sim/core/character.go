@@ -126,7 +126,7 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 	character.GCD = character.NewTimer()
 	character.RotationTimer = character.NewTimer()
 
-	character.Label = fmt.Sprintf("%s (#%d)", character.Name, character.Index+1)
+	character.Label = playerLabel(character.Name, character.Index)
 
 	if player.Glyphs != nil {
 		character.glyphs = [6]int32{
@@ -171,6 +171,11 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 	}
 	character.PseudoStats.InFrontOfTarget = player.InFrontOfTarget
 
+	if player.Position != nil {
+		character.StartPosition = Vector2FromProto(player.Position)
+		character.hasStartPosition = true
+	}
+
 	if player.EnableItemSwap && player.ItemSwap != nil {
 		character.enableItemSwap(player.ItemSwap, character.DefaultCritMultiplier(), character.DefaultCritMultiplier(), character.DefaultCritMultiplier())
 	}
@@ -413,7 +418,7 @@ func (character *Character) initialize(agent Agent) {
 				return
 			}
 
-			if sim.Options.Interactive {
+			if character.isInteractive(sim) {
 				if character.GCD.IsReady(sim) {
 					sim.NeedsInput = true
 				}
@@ -626,6 +631,11 @@ func (character *Character) GetProcMaskForWeaponSlot(slot proto.ItemSlot) ProcMa
 	return ProcMaskEmpty
 }
 
+// Returns the label of the raid member with the given name and raid index.
+func playerLabel(name string, raidIndex int32) string {
+	return fmt.Sprintf("%s (#%d)", name, raidIndex+1)
+}
+
 func (character *Character) doneIteration(sim *Simulation) {
 	character.ItemSwap.doneIteration(sim)
 
@@ -673,6 +683,10 @@ func (character *Character) GetMetricsProto() *proto.UnitMetrics {
 	metrics.Name = character.Name
 	metrics.UnitIndex = character.UnitIndex
 	metrics.Auras = character.auraTracker.GetMetricsProto()
+	// Pets run a fixed custom rotation, so there are no lines worth profiling.
+	if character.Rotation != nil && character.Type == PlayerUnit {
+		metrics.AplProfile = character.Rotation.getProfile()
+	}
 
 	metrics.Pets = make([]*proto.UnitMetrics, len(character.Pets))
 	for i, pet := range character.Pets {
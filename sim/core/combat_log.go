@@ -0,0 +1,294 @@
+package core
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Written in place of a missing source or destination unit.
+const combatLogNilUnit = "0000000000000000,nil,0x80000000,0x80000000"
+
+// Unit flags of the combat log.
+const (
+	combatLogFlagsMine    = "0x511"  // Player controlled by the logging client.
+	combatLogFlagsRaid    = "0x514"  // Other raid member.
+	combatLogFlagsPet     = "0x1111" // Pet of a player.
+	combatLogFlagsHostile = "0xa48"  // Hostile NPC.
+)
+
+// Spell ID of the auto shot, used for ranged auto attacks.
+const combatLogAutoShotID = 75
+
+type combatLogUnit struct {
+	guid  string
+	name  string
+	flags string
+}
+
+func (unit *combatLogUnit) String() string {
+	return fmt.Sprintf("%s,\"%s\",%s,0x0", unit.guid, unit.name, unit.flags)
+}
+
+// combatLogWriter converts log events into lines of the game's combat log.
+type combatLogWriter struct {
+	w     *bufio.Writer
+	start time.Time
+
+	units    map[string]*combatLogUnit
+	petOwner map[string]string
+	numNPCs  int
+}
+
+// WriteCombatLog writes the log events of a raid sim result as a WoWCombatLog.txt file, so that
+// tools made for the game's logs can analyze it. The sim should have been run with LogEvents and
+// DebugFirstIteration set, so the events are those of a single iteration. The iteration starts at
+// the given time.
+//
+// Raid members, pets and targets get synthetic GUIDs. The sim doesn't know the names of spells,
+// so they are named after their action IDs instead. Messages, spent resources and shields have
+// no counterpart in the combat log and are left out.
+func WriteCombatLog(out io.Writer, result *proto.RaidSimResult, start time.Time) error {
+	clw := &combatLogWriter{
+		w:        bufio.NewWriter(out),
+		start:    start,
+		units:    make(map[string]*combatLogUnit),
+		petOwner: make(map[string]string),
+	}
+	clw.addUnits(result)
+
+	// Prepull actions are logged at the start of the iteration with negative timestamps, so
+	// the events need to be put in order.
+	events := slices.Clone(result.LogEvents)
+	slices.SortStableFunc(events, func(a, b *proto.LogEvent) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+
+	startTimestamp := 0.0
+	if len(events) > 0 {
+		startTimestamp = min(startTimestamp, events[0].Timestamp)
+	}
+	fmt.Fprintf(clw.w, "%s  COMBAT_LOG_VERSION,20,ADVANCED_LOG_ENABLED,0,BUILD_VERSION,5.5.0,PROJECT_ID,14\n", clw.timestamp(startTimestamp))
+	for _, event := range events {
+		clw.writeEvent(event)
+	}
+	return clw.w.Flush()
+}
+
+// Assigns GUIDs to the players, pets and targets of the sim, identified by their unit labels.
+func (clw *combatLogWriter) addUnits(result *proto.RaidSimResult) {
+	numPets := 0
+	if result.RaidMetrics != nil {
+		for partyIdx, party := range result.RaidMetrics.Parties {
+			for playerIdx, player := range party.Players {
+				// Empty slots of the raid have no metrics.
+				if player.Dps == nil {
+					continue
+				}
+				raidIndex := partyIdx*5 + playerIdx
+				label := playerLabel(player.Name, int32(raidIndex))
+				flags := Ternary(len(clw.units) == 0, combatLogFlagsMine, combatLogFlagsRaid)
+				clw.units[label] = &combatLogUnit{guid: fmt.Sprintf("Player-0-%08X", raidIndex+1), name: player.Name, flags: flags}
+
+				for _, pet := range player.Pets {
+					numPets++
+					petLabel := fmt.Sprintf("%s - %s", label, pet.Name)
+					clw.units[petLabel] = &combatLogUnit{guid: fmt.Sprintf("Pet-0-0-0-0-0-%010X", numPets), name: pet.Name, flags: combatLogFlagsPet}
+					clw.petOwner[petLabel] = label
+				}
+			}
+		}
+	}
+	if result.EncounterMetrics != nil {
+		// Events refer to targets by their labels, which only depend on their index.
+		for targetIdx, target := range result.EncounterMetrics.Targets {
+			clw.npc(targetLabel(int32(targetIdx))).name = target.Name
+		}
+	}
+}
+
+// Returns the unit with the given label. Unknown units are assumed to be hostile NPCs.
+func (clw *combatLogWriter) npc(label string) *combatLogUnit {
+	if unit, ok := clw.units[label]; ok {
+		return unit
+	}
+	clw.numNPCs++
+	unit := &combatLogUnit{guid: fmt.Sprintf("Creature-0-0-0-0-0-%010X", clw.numNPCs), name: label, flags: combatLogFlagsHostile}
+	clw.units[label] = unit
+	return unit
+}
+
+func (clw *combatLogWriter) timestamp(seconds float64) string {
+	return clw.start.Add(DurationFromSeconds(seconds)).Format("1/2 15:04:05.000")
+}
+
+func (clw *combatLogWriter) writeLine(event *proto.LogEvent, name string, fields ...string) {
+	fmt.Fprintf(clw.w, "%s  %s,%s\n", clw.timestamp(event.Timestamp), name, strings.Join(fields, ","))
+}
+
+// Returns the spell prefix of an event: spell ID, name and school.
+func combatLogSpell(action *proto.ActionID, school int32) string {
+	actionID := ProtoToActionID(action)
+	spellID := actionID.SpellID
+	if spellID == 0 {
+		spellID = actionID.ItemID
+	}
+	return fmt.Sprintf("%d,\"%s\",0x%x", spellID, actionID, combatLogSchool(SpellSchool(school)))
+}
+
+// Converts a school mask of the sim to the school mask of the game, which orders the schools differently.
+func combatLogSchool(school SpellSchool) int {
+	gameSchools := []struct {
+		school SpellSchool
+		mask   int
+	}{
+		{SpellSchoolPhysical, 0x1},
+		{SpellSchoolHoly, 0x2},
+		{SpellSchoolFire, 0x4},
+		{SpellSchoolNature, 0x8},
+		{SpellSchoolFrost, 0x10},
+		{SpellSchoolShadow, 0x20},
+		{SpellSchoolArcane, 0x40},
+	}
+
+	mask := 0
+	for _, gs := range gameSchools {
+		if school.Matches(gs.school) {
+			mask |= gs.mask
+		}
+	}
+	return max(mask, 0x1)
+}
+
+func combatLogBool(b bool) string {
+	return Ternary(b, "1", "nil")
+}
+
+func (clw *combatLogWriter) writeEvent(event *proto.LogEvent) {
+	// Only messages can be without a unit, and those aren't written.
+	if event.Unit == "" {
+		return
+	}
+	source := clw.npc(event.Unit)
+
+	switch e := event.Event.(type) {
+	case *proto.LogEvent_CastBegan:
+		if e.CastBegan.CastTime > 0 {
+			clw.writeLine(event, "SPELL_CAST_START", source.String(), combatLogNilUnit, combatLogSpell(e.CastBegan.Action, 0))
+		}
+	case *proto.LogEvent_CastCompleted:
+		if ProtoToActionID(e.CastCompleted).OtherID == proto.OtherAction_OtherActionNone {
+			clw.writeLine(event, "SPELL_CAST_SUCCESS", source.String(), combatLogNilUnit, combatLogSpell(e.CastCompleted, 0))
+		}
+	case *proto.LogEvent_Hit:
+		clw.writeHit(event, source, e.Hit)
+	case *proto.LogEvent_Aura:
+		clw.writeAura(event, source, e.Aura)
+	case *proto.LogEvent_Resource:
+		// Only gains from spells are logged by the game, not regeneration.
+		resource := e.Resource
+		powerType, ok := combatLogPowerTypes[resource.Type]
+		if !ok || resource.Spent || resource.Amount <= 0 || ProtoToActionID(resource.Action).OtherID != proto.OtherAction_OtherActionNone {
+			return
+		}
+		clw.writeLine(event, "SPELL_ENERGIZE", source.String(), source.String(), combatLogSpell(resource.Action, 0),
+			fmt.Sprintf("%0.0f,0,%d,%0.0f", resource.Amount, powerType, resource.Max))
+	case *proto.LogEvent_Pet:
+		if e.Pet.Type == proto.PetLogEvent_Summoned {
+			owner := clw.npc(clw.petOwner[event.Unit])
+			clw.writeLine(event, "SPELL_SUMMON", owner.String(), source.String(), "0,\"nil\",0x1")
+		} else {
+			clw.writeLine(event, "UNIT_DESTROYED", combatLogNilUnit, source.String(), "0")
+		}
+	}
+}
+
+func (clw *combatLogWriter) writeHit(event *proto.LogEvent, source *combatLogUnit, hit *proto.HitLogEvent) {
+	dest := clw.npc(hit.Target)
+	periodic := Ternary(hit.Tick, "SPELL_PERIODIC", "SPELL")
+	crit := hit.Outcome == proto.HitOutcome_HitOutcomeCrit
+
+	switch hit.Type {
+	case proto.HitLogEvent_Healing:
+		clw.writeLine(event, periodic+"_HEAL", source.String(), dest.String(), combatLogSpell(hit.Action, hit.SpellSchool),
+			fmt.Sprintf("%0.0f,%0.0f,0,0,%s", hit.Amount, hit.Amount, combatLogBool(crit)))
+		return
+	case proto.HitLogEvent_Shielding:
+		return
+	}
+
+	actionID := ProtoToActionID(hit.Action)
+	prefix := periodic
+	spell := combatLogSpell(hit.Action, hit.SpellSchool)
+	fields := []string{source.String(), dest.String()}
+	switch actionID.OtherID {
+	case proto.OtherAction_OtherActionAttack:
+		prefix = "SWING"
+	case proto.OtherAction_OtherActionShoot:
+		prefix = "RANGE"
+		spell = fmt.Sprintf("%d,\"Auto Shot\",0x1", combatLogAutoShotID)
+	}
+	if prefix != "SWING" {
+		fields = append(fields, spell)
+	}
+
+	switch hit.Outcome {
+	case proto.HitOutcome_HitOutcomeMiss, proto.HitOutcome_HitOutcomeDodge, proto.HitOutcome_HitOutcomeParry:
+		missType := strings.ToUpper(strings.TrimPrefix(hit.Outcome.String(), "HitOutcome"))
+		fields = append(fields, fmt.Sprintf("%s,%s", missType, combatLogBool(actionID.Tag == 2)))
+		clw.writeLine(event, prefix+"_MISSED", fields...)
+	default:
+		glance := hit.Outcome == proto.HitOutcome_HitOutcomeGlance || hit.Outcome == proto.HitOutcome_HitOutcomeGlanceBlock
+		crush := hit.Outcome == proto.HitOutcome_HitOutcomeCrush
+		fields = append(fields, fmt.Sprintf("%0.0f,%0.0f,-1,0x%x,0,0,0,%s,%s,%s", hit.Amount, hit.Amount, combatLogSchool(SpellSchool(hit.SpellSchool)),
+			combatLogBool(crit), combatLogBool(glance), combatLogBool(crush)))
+		clw.writeLine(event, prefix+"_DAMAGE", fields...)
+	}
+}
+
+func (clw *combatLogWriter) writeAura(event *proto.LogEvent, owner *combatLogUnit, aura *proto.AuraLogEvent) {
+	// The sim doesn't record who applied an aura, so buffs are attributed to their owner and
+	// debuffs to nobody.
+	source := owner.String()
+	auraType := "BUFF"
+	if owner.flags == combatLogFlagsHostile {
+		source = combatLogNilUnit
+		auraType = "DEBUFF"
+	}
+	spell := combatLogSpell(aura.Aura, 0)
+
+	switch aura.Type {
+	case proto.AuraLogEvent_Gained:
+		clw.writeLine(event, "SPELL_AURA_APPLIED", source, owner.String(), spell, auraType)
+	case proto.AuraLogEvent_Faded:
+		clw.writeLine(event, "SPELL_AURA_REMOVED", source, owner.String(), spell, auraType)
+	case proto.AuraLogEvent_Refreshed:
+		clw.writeLine(event, "SPELL_AURA_REFRESH", source, owner.String(), spell, auraType)
+	case proto.AuraLogEvent_StacksChanged:
+		// Stacks going to or from 0 are already covered by the aura being applied or removed.
+		if aura.Stacks == 0 || aura.OldStacks == 0 {
+			return
+		}
+		name := Ternary(aura.Stacks > aura.OldStacks, "SPELL_AURA_APPLIED_DOSE", "SPELL_AURA_REMOVED_DOSE")
+		clw.writeLine(event, name, source, owner.String(), spell, auraType, fmt.Sprintf("%d", aura.Stacks))
+	}
+}
+
+// Power types of the game for the resources of the sim.
+var combatLogPowerTypes = map[proto.ResourceType]int{
+	proto.ResourceType_ResourceTypeMana:        0,
+	proto.ResourceType_ResourceTypeRage:        1,
+	proto.ResourceType_ResourceTypeFocus:       2,
+	proto.ResourceType_ResourceTypeEnergy:      3,
+	proto.ResourceType_ResourceTypeComboPoints: 4,
+	proto.ResourceType_ResourceTypeRunicPower:  6,
+	proto.ResourceType_ResourceTypeSolarEnergy: 8,
+	proto.ResourceType_ResourceTypeLunarEnergy: 8,
+	proto.ResourceType_ResourceTypeChi:         12,
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestWriteCombatLog(t *testing.T) {
+	dps := &proto.DistributionMetrics{}
+	result := &proto.RaidSimResult{
+		RaidMetrics: &proto.RaidMetrics{Parties: []*proto.PartyMetrics{{Players: []*proto.UnitMetrics{
+			{Name: "Rogue", Dps: dps, Pets: []*proto.UnitMetrics{{Name: "Shadow"}}},
+			{},
+		}}}},
+		EncounterMetrics: &proto.EncounterMetrics{Targets: []*proto.UnitMetrics{{Name: "Target 1"}}},
+		LogEvents: []*proto.LogEvent{
+			{Timestamp: 0, Unit: "Rogue (#1)", Event: &proto.LogEvent_CastCompleted{CastCompleted: ActionID{SpellID: 1752}.ToProto()}},
+			{Timestamp: -1, Unit: "Rogue (#1)", Event: &proto.LogEvent_Aura{Aura: &proto.AuraLogEvent{Type: proto.AuraLogEvent_Gained, Aura: ActionID{SpellID: 1784}.ToProto()}}},
+			{Timestamp: 0, Unit: "Rogue (#1)", Event: &proto.LogEvent_Hit{Hit: &proto.HitLogEvent{
+				Action:      ActionID{SpellID: 1752}.ToProto(),
+				Target:      "Target 1",
+				Outcome:     proto.HitOutcome_HitOutcomeCrit,
+				Amount:      1000,
+				SpellSchool: int32(SpellSchoolPhysical),
+			}}},
+			{Timestamp: 0.5, Unit: "Rogue (#1)", Event: &proto.LogEvent_Hit{Hit: &proto.HitLogEvent{
+				Action:      ActionID{OtherID: proto.OtherAction_OtherActionAttack, Tag: 2}.ToProto(),
+				Target:      "Target 1",
+				Outcome:     proto.HitOutcome_HitOutcomeDodge,
+				SpellSchool: int32(SpellSchoolPhysical),
+			}}},
+			{Timestamp: 1, Unit: "Target 1", Event: &proto.LogEvent_Aura{Aura: &proto.AuraLogEvent{
+				Type:      proto.AuraLogEvent_StacksChanged,
+				Aura:      ActionID{SpellID: 2818}.ToProto(),
+				OldStacks: 1,
+				Stacks:    2,
+			}}},
+			{Timestamp: 1, Unit: "Rogue (#1)", Event: &proto.LogEvent_Hit{Hit: &proto.HitLogEvent{
+				Action:      ActionID{SpellID: 2818}.ToProto(),
+				Target:      "Target 1",
+				Outcome:     proto.HitOutcome_HitOutcomeHit,
+				Tick:        true,
+				Amount:      200,
+				SpellSchool: int32(SpellSchoolNature),
+			}}},
+			{Timestamp: 1, Unit: "Rogue (#1)", Event: &proto.LogEvent_Resource{Resource: &proto.ResourceLogEvent{
+				Type:   proto.ResourceType_ResourceTypeEnergy,
+				Action: ActionID{OtherID: proto.OtherAction_OtherActionEnergyRegen}.ToProto(),
+				Amount: 1,
+				Max:    100,
+			}}},
+			{Timestamp: 1.5, Unit: "Rogue (#1)", Event: &proto.LogEvent_Resource{Resource: &proto.ResourceLogEvent{
+				Type:   proto.ResourceType_ResourceTypeComboPoints,
+				Action: ActionID{SpellID: 1752}.ToProto(),
+				Amount: 1,
+				Max:    5,
+			}}},
+			{Timestamp: 2, Unit: "Rogue (#1) - Shadow", Event: &proto.LogEvent_Pet{Pet: &proto.PetLogEvent{Type: proto.PetLogEvent_Summoned}}},
+			{Timestamp: 2, Event: &proto.LogEvent_Message{Message: "Hello"}},
+		},
+	}
+
+	var sb strings.Builder
+	if err := WriteCombatLog(&sb, result, time.Date(2024, 1, 2, 20, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	rogue := `Player-0-00000001,"Rogue",0x511,0x0`
+	target := `Creature-0-0-0-0-0-0000000001,"Target 1",0xa48,0x0`
+	expected := []string{
+		`1/2 19:59:59.000  COMBAT_LOG_VERSION,20,ADVANCED_LOG_ENABLED,0,BUILD_VERSION,5.5.0,PROJECT_ID,14`,
+		`1/2 19:59:59.000  SPELL_AURA_APPLIED,` + rogue + `,` + rogue + `,1784,"{SpellID: 1784}",0x1,BUFF`,
+		`1/2 20:00:00.000  SPELL_CAST_SUCCESS,` + rogue + `,` + combatLogNilUnit + `,1752,"{SpellID: 1752}",0x1`,
+		`1/2 20:00:00.000  SPELL_DAMAGE,` + rogue + `,` + target + `,1752,"{SpellID: 1752}",0x1,1000,1000,-1,0x1,0,0,0,1,nil,nil`,
+		`1/2 20:00:00.500  SWING_MISSED,` + rogue + `,` + target + `,DODGE,1`,
+		`1/2 20:00:01.000  SPELL_AURA_APPLIED_DOSE,` + combatLogNilUnit + `,` + target + `,2818,"{SpellID: 2818}",0x1,DEBUFF,2`,
+		`1/2 20:00:01.000  SPELL_PERIODIC_DAMAGE,` + rogue + `,` + target + `,2818,"{SpellID: 2818}",0x8,200,200,-1,0x8,0,0,0,nil,nil,nil`,
+		`1/2 20:00:01.500  SPELL_ENERGIZE,` + rogue + `,` + rogue + `,1752,"{SpellID: 1752}",0x1,1,0,4,5`,
+		`1/2 20:00:02.000  SPELL_SUMMON,` + rogue + `,Pet-0-0-0-0-0-0000000001,"Shadow",0x1111,0x0,0,"nil",0x1`,
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(expected), len(lines), sb.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("Line %d:\nExpected: %s\nActual:   %s", i, expected[i], line)
+		}
+	}
+}
+
+func TestWriteCombatLogTargetNames(t *testing.T) {
+	result := &proto.RaidSimResult{
+		EncounterMetrics: &proto.EncounterMetrics{Targets: []*proto.UnitMetrics{{Name: "Lei Shen"}, {Name: "Add"}}},
+		LogEvents: []*proto.LogEvent{
+			{Timestamp: 0, Unit: "Target 2", Event: &proto.LogEvent_CastCompleted{CastCompleted: ActionID{SpellID: 1}.ToProto()}},
+		},
+	}
+
+	var sb strings.Builder
+	if err := WriteCombatLog(&sb, result, time.Date(2024, 1, 2, 20, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events refer to the second target by its label, which must map to its GUID and name.
+	expected := `SPELL_CAST_SUCCESS,Creature-0-0-0-0-0-0000000002,"Add",0xa48,0x0,`
+	if !strings.Contains(sb.String(), expected) {
+		t.Fatalf("Expected the cast of the second target, got:\n%s", sb.String())
+	}
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// The DPS of one of the compared sims, and its difference to the first sim.
+type SimComparison struct {
+	Result *proto.RaidSimResult
+
+	Dps      float64
+	DpsError float64 // Half-width of the confidence interval.
+
+	DpsDelta            float64
+	DpsDeltaError       float64 // Half-width of the confidence interval.
+	DpsDeltaSignificant bool    // Whether the confidence interval of the delta excludes 0.
+}
+
+// Runs the requests with identical seeds and common random numbers, and returns the DPS of
+// each, with its difference to the first request at the given confidence level (e.g. 0.95).
+// If seed is 0, the seed of the first request is used.
+func CompareRaidSims(requests []*proto.RaidSimRequest, seed int64, confidenceLevel float64) ([]SimComparison, error) {
+	if len(requests) < 2 {
+		return nil, fmt.Errorf("compare: need at least 2 requests, got %d", len(requests))
+	}
+	if confidenceLevel <= 0 || confidenceLevel >= 1 {
+		return nil, fmt.Errorf("compare: confidence level must be between 0 and 1, got %v", confidenceLevel)
+	}
+	if seed == 0 {
+		seed = requests[0].GetSimOptions().GetRandomSeed()
+	}
+
+	results := make([]*itemSubstitutionSimResult, len(requests))
+	for i, request := range requests {
+		request = googleProto.Clone(request).(*proto.RaidSimRequest)
+		if request.SimOptions == nil {
+			request.SimOptions = &proto.SimOptions{}
+		}
+		request.SimOptions.RandomSeed = seed
+		enableCommonRandomNumbers(request.SimOptions)
+		// Without a seed, the first request picks one for the others.
+		seed = request.SimOptions.RandomSeed
+
+		result := RunRaidSimConcurrent(request)
+		if result.Error != nil {
+			return nil, fmt.Errorf("compare: sim %d failed: %s", i+1, result.Error.Message)
+		}
+		results[i] = &itemSubstitutionSimResult{Request: request, Result: result}
+	}
+
+	z := confidenceZScore(confidenceLevel)
+	comparisons := make([]SimComparison, len(results))
+	for i, r := range results {
+		estimate := r.dpsEstimate()
+		comparisons[i] = SimComparison{
+			Result:   r.Result,
+			Dps:      estimate.mean,
+			DpsError: z * estimate.stderr,
+		}
+		if i > 0 {
+			delta, _ := dpsDelta(r, results[0])
+			comparisons[i].DpsDelta = delta.mean
+			comparisons[i].DpsDeltaError = z * delta.stderr
+			comparisons[i].DpsDeltaSignificant = delta.isSignificant(z)
+		}
+	}
+	return comparisons, nil
+}
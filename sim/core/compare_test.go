@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestCompareRaidSims(t *testing.T) {
+	newRequest := func(delay string) *proto.RaidSimRequest {
+		rotation, err := apltext.Parse(`actions cast_spell(spell_id=spell:42) if !dot_is_active(spell_id=spell:42) & current_time >= const(val="` + delay + `")`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		player := &proto.Player{Name: "Caster", Class: proto.Class_ClassShaman, Spec: &proto.Player_ElementalShaman{}, Equipment: &proto.EquipmentSpec{}, Rotation: rotation}
+		return &proto.RaidSimRequest{
+			SimOptions: &proto.SimOptions{Iterations: 20, RandomSeed: 100},
+			Raid:       SinglePlayerRaidProto(player, nil, nil, nil),
+			Encounter: &proto.Encounter{
+				Targets:  []*proto.Target{{Name: "target", Level: 90, MobType: proto.MobType_MobTypeDemon}},
+				Duration: 30,
+			},
+		}
+	}
+
+	comparisons, err := CompareRaidSims([]*proto.RaidSimRequest{newRequest("0s"), newRequest("0s"), newRequest("10s")}, 0, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comparisons) != 3 || comparisons[0].Dps <= 0 {
+		t.Fatalf("Expected 3 comparisons with damage dealt, got %v", comparisons)
+	}
+	if same := comparisons[1]; same.Dps != comparisons[0].Dps || same.DpsDelta != 0 || same.DpsDeltaError != 0 || same.DpsDeltaSignificant {
+		t.Fatalf("Expected identical requests to have a delta of 0, got %0.3f ± %0.3f", same.DpsDelta, same.DpsDeltaError)
+	}
+	if delayed := comparisons[2]; delayed.DpsDelta >= 0 || !delayed.DpsDeltaSignificant {
+		t.Fatalf("Expected delaying the dot to significantly lower the DPS, got %0.3f ± %0.3f", delayed.DpsDelta, delayed.DpsDeltaError)
+	}
+
+	if _, err := CompareRaidSims([]*proto.RaidSimRequest{newRequest("0s")}, 0, 0.95); err == nil {
+		t.Fatalf("Expected an error for a single request")
+	}
+	if _, err := CompareRaidSims([]*proto.RaidSimRequest{newRequest("0s"), newRequest("0s")}, 0, 1); err == nil {
+		t.Fatalf("Expected an error for a confidence level of 1")
+	}
+}
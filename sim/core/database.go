@@ -23,6 +23,10 @@ var ItemEffectRandPropPointsByIlvl = map[int32]ItemEffectRandPropPoints{}
 var ConsumablesByID = map[int32]Consumable{}
 var SpellEffectsById = map[int32]*proto.SpellEffect{}
 
+// Only needed for importers, which refer to enchants and glyphs by their spell ID.
+var EnchantsBySpellID = map[int32]Enchant{}
+var GlyphItemIDsBySpellID = map[int32]int32{}
+
 var mutex = &sync.Mutex{}
 
 func addToDatabase(newDB *proto.SimDatabase) {
@@ -47,6 +51,9 @@ func addToDatabase(newDB *proto.SimDatabase) {
 		if _, ok := EnchantsByEffectID[v.EffectId]; !ok {
 			EnchantsByEffectID[v.EffectId] = EnchantFromProto(v)
 		}
+		if _, ok := EnchantsBySpellID[v.SpellId]; !ok && v.SpellId != 0 {
+			EnchantsBySpellID[v.SpellId] = EnchantFromProto(v)
+		}
 	}
 
 	for _, v := range newDB.Gems {
@@ -75,6 +82,11 @@ func addToDatabase(newDB *proto.SimDatabase) {
 			SpellEffectsById[v.Id] = v
 		}
 	}
+	for _, v := range newDB.Glyphs {
+		if _, ok := GlyphItemIDsBySpellID[v.SpellId]; !ok {
+			GlyphItemIDsBySpellID[v.SpellId] = v.ItemId
+		}
+	}
 }
 
 type ReforgeStat struct {
@@ -242,19 +254,31 @@ func RandomSuffixFromProto(pData *proto.ItemRandomSuffix) RandomSuffix {
 
 type Enchant struct {
 	EffectID      int32 // Used by UI to apply effect to tooltip
+	SpellID       int32 // Only needed for importers
 	Stats         stats.Stats
 	EnchantEffect *proto.ItemEffect
 	Name          string         // Only needed for unit tests
 	Type          proto.ItemType // Only needed for unit tests
+
+	// Only needed for choosing enchants, see EnchantAppliesToItem.
+	ExtraTypes         []proto.ItemType
+	EnchantType        proto.EnchantType
+	ClassAllowlist     []proto.Class
+	RequiredProfession proto.Profession
 }
 
 func EnchantFromProto(pData *proto.SimEnchant) Enchant {
 	return Enchant{
-		EffectID:      pData.EffectId,
-		Stats:         stats.FromProtoArray(pData.Stats),
-		EnchantEffect: pData.EnchantEffect,
-		Name:          pData.Name,
-		Type:          pData.Type,
+		EffectID:           pData.EffectId,
+		SpellID:            pData.SpellId,
+		Stats:              stats.FromProtoArray(pData.Stats),
+		EnchantEffect:      pData.EnchantEffect,
+		Name:               pData.Name,
+		Type:               pData.Type,
+		ExtraTypes:         pData.ExtraTypes,
+		EnchantType:        pData.EnchantType,
+		ClassAllowlist:     pData.ClassAllowlist,
+		RequiredProfession: pData.RequiredProfession,
 	}
 }
 
@@ -433,6 +457,13 @@ func GetEnchantByEffectID(effectID int32) *Enchant {
 	return nil
 }
 
+func GetEnchantBySpellID(spellID int32) *Enchant {
+	if enchant, ok := EnchantsBySpellID[spellID]; ok {
+		return &enchant
+	}
+	return nil
+}
+
 func (equipment *Equipment) ToEquipmentSpecProto() *proto.EquipmentSpec {
 	return &proto.EquipmentSpec{
 		Items: MapSlice(equipment[:], func(item Item) *proto.ItemSpec {
@@ -786,7 +817,8 @@ var itemTypeToSlotsMap = map[proto.ItemType][]proto.ItemSlot{
 	// ItemType_ItemTypeWeapon is excluded intentionally - the slot cannot be decided based on type alone for weapons.
 }
 
-func eligibleSlotsForItem(item *Item, isFuryWarrior bool) []proto.ItemSlot {
+// Returns the slots the item can be equipped in. Fury warriors can equip two-handers in either hand.
+func EligibleSlotsForItem(item *Item, isFuryWarrior bool) []proto.ItemSlot {
 	if item == nil {
 		return nil
 	}
@@ -813,6 +845,81 @@ func eligibleSlotsForItem(item *Item, isFuryWarrior bool) []proto.ItemSlot {
 	return nil
 }
 
+// Returns the slots the enchant can be applied to. See getEligibleEnchantSlots in proto_utils/utils.ts.
+func EligibleSlotsForEnchant(enchant *Enchant) []proto.ItemSlot {
+	var slots []proto.ItemSlot
+	for _, itemType := range append([]proto.ItemType{enchant.Type}, enchant.ExtraTypes...) {
+		if itemType == proto.ItemType_ItemTypeWeapon {
+			slots = append(slots, proto.ItemSlot_ItemSlotMainHand, proto.ItemSlot_ItemSlotOffHand)
+		} else {
+			slots = append(slots, itemTypeToSlotsMap[itemType]...)
+		}
+	}
+	return slots
+}
+
+// Whether the enchant can be applied to the item. See enchantAppliesToItem in proto_utils/utils.ts.
+func EnchantAppliesToItem(enchant *Enchant, item *Item) bool {
+	// Weapon enchants fit either hand, so two-handers in the off hand don't add any slots here.
+	itemSlots := EligibleSlotsForItem(item, false)
+	if !slices.ContainsFunc(EligibleSlotsForEnchant(enchant), func(slot proto.ItemSlot) bool {
+		return slices.Contains(itemSlots, slot)
+	}) {
+		return false
+	}
+
+	switch enchant.EnchantType {
+	case proto.EnchantType_EnchantTypeTwoHand:
+		if item.HandType != proto.HandType_HandTypeTwoHand {
+			return false
+		}
+	case proto.EnchantType_EnchantTypeStaff:
+		if item.WeaponType != proto.WeaponType_WeaponTypeStaff {
+			return false
+		}
+	case proto.EnchantType_EnchantTypeShield:
+		if item.WeaponType != proto.WeaponType_WeaponTypeShield {
+			return false
+		}
+	}
+
+	// All off-hand enchants can be applied to shields as well.
+	isOffHandItem := item.WeaponType == proto.WeaponType_WeaponTypeOffHand ||
+		(item.WeaponType == proto.WeaponType_WeaponTypeShield && enchant.EnchantType != proto.EnchantType_EnchantTypeShield)
+	if (enchant.EnchantType == proto.EnchantType_EnchantTypeOffHand) != isOffHandItem {
+		return false
+	}
+
+	if enchant.Type == proto.ItemType_ItemTypeRanged {
+		switch item.RangedWeaponType {
+		case proto.RangedWeaponType_RangedWeaponTypeBow, proto.RangedWeaponType_RangedWeaponTypeCrossbow, proto.RangedWeaponType_RangedWeaponTypeGun:
+		default:
+			return false
+		}
+	} else if item.RangedWeaponType != proto.RangedWeaponType_RangedWeaponTypeWand && item.RangedWeaponType > 0 {
+		return false
+	}
+
+	return true
+}
+
+// Whether a player of the class with the professions can use the enchant. Engineering
+// enchants are tinkers, which are handled separately. See canEquipEnchant in proto_utils/utils.ts.
+func CanEquipEnchant(enchant *Enchant, class proto.Class, professions ...proto.Profession) bool {
+	if len(enchant.ClassAllowlist) > 0 && !slices.Contains(enchant.ClassAllowlist, class) {
+		return false
+	}
+
+	switch enchant.RequiredProfession {
+	case proto.Profession_ProfessionUnknown:
+		return true
+	case proto.Profession_Engineering:
+		return false
+	default:
+		return slices.Contains(professions, enchant.RequiredProfession)
+	}
+}
+
 func ColorIntersects(g proto.GemColor, o proto.GemColor) bool {
 	if g == o {
 		return true
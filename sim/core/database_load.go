@@ -12,6 +12,7 @@ import (
 func init() {
 	db := database.Load()
 	WITH_DB = true
+	DatabaseVersion = database.Version()
 
 	simDB := &proto.SimDatabase{
 		Items:                    make([]*proto.SimItem, len(db.Items)),
@@ -22,6 +23,7 @@ func init() {
 		RandomSuffixes:           make([]*proto.ItemRandomSuffix, len(db.RandomSuffixes)),
 		Consumables:              make([]*proto.Consumable, len(db.Consumables)),
 		SpellEffects:             make([]*proto.SpellEffect, len(db.SpellEffects)),
+		Glyphs:                   make([]*proto.SimGlyph, len(db.GlyphIds)),
 	}
 
 	for i, item := range db.Items {
@@ -58,6 +60,12 @@ func init() {
 			EnchantEffect: enchant.EnchantEffect,
 			Name:          enchant.Name,
 			Type:          enchant.Type,
+			SpellId:       enchant.SpellId,
+
+			ExtraTypes:         enchant.ExtraTypes,
+			EnchantType:        enchant.EnchantType,
+			ClassAllowlist:     enchant.ClassAllowlist,
+			RequiredProfession: enchant.RequiredProfession,
 		}
 	}
 
@@ -94,5 +102,12 @@ func init() {
 		simDB.SpellEffects[i] = effect
 	}
 
+	for i, glyph := range db.GlyphIds {
+		simDB.Glyphs[i] = &proto.SimGlyph{
+			ItemId:  glyph.ItemId,
+			SpellId: glyph.SpellId,
+		}
+	}
+
 	addToDatabase(simDB)
 }
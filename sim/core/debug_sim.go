@@ -0,0 +1,365 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	googleProto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+// DebugSim runs a single iteration of a raid sim step by step, so the state of a player
+// can be inspected between events, e.g. to see why its rotation chose an action.
+//
+// Unlike InteractiveSim all units follow their own rotations. The debugger only decides
+// how far to run, stopping early when one of its breakpoints matches a combat log event.
+type DebugSim struct {
+	sim  *Simulation
+	unit *Unit
+
+	Breakpoints []*proto.DebugBreakpoint
+
+	log           *eventLog
+	breakpointHit int32
+	done          bool
+}
+
+// Creates a debugger for the given iteration of the sim, stopped before its first event.
+func NewDebugSim(rsr *proto.RaidSimRequest, seed int64, playerIndex int32) (*DebugSim, error) {
+	rsr = googleProto.Clone(rsr).(*proto.RaidSimRequest)
+	if rsr.SimOptions == nil {
+		rsr.SimOptions = &proto.SimOptions{}
+	}
+	if seed != 0 {
+		rsr.SimOptions.RandomSeed = seed
+	}
+
+	sim := NewSim(rsr, simsignals.Signals{})
+	if playerIndex < 0 || int(playerIndex) >= len(sim.Raid.AllPlayerUnits) {
+		return nil, fmt.Errorf("raid has no player with index %d", playerIndex)
+	}
+
+	// Same as runSim, so the iteration matches the first iteration of a raid sim.
+	presimResult := sim.runPresims(rsr)
+	if presimResult != nil && presimResult.Error != nil {
+		return nil, errors.New(presimResult.Error.Message)
+	}
+	if sim.Encounter.EndFightAtHealth > 0 && presimResult != nil {
+		sim.BaseDuration = time.Duration(presimResult.AvgIterationDuration) * time.Second
+		sim.Duration = time.Duration(presimResult.AvgIterationDuration) * time.Second
+		sim.Encounter.DurationIsEstimate = false
+	}
+
+	ds := &DebugSim{
+		sim:           sim,
+		unit:          sim.Raid.AllPlayerUnits[playerIndex],
+		log:           &eventLog{keepEvents: true},
+		breakpointHit: -1,
+	}
+	sim.enableLogging(ds.log)
+	sim.reset()
+	sim.PrePull()
+	return ds, nil
+}
+
+// Runs the sim as far as the mode says, or until a breakpoint is hit. The time is only
+// used by the Time mode. Fails if the sim has no pending event left to run.
+func (ds *DebugSim) Step(mode proto.DebugStepRequest_Mode, until time.Duration) error {
+	ds.log.text.Reset()
+	ds.log.events = nil
+	ds.breakpointHit = -1
+	if ds.done {
+		return nil
+	}
+
+	sim := ds.sim
+	unit := ds.unit
+	// When stepping by GCD in the middle of one, stop once it is over.
+	gcdUsed := !unit.GCD.IsReady(sim)
+
+	for {
+		nextEventAt, pa, err := ds.nextEvent()
+		if err != nil {
+			return err
+		}
+		if mode == proto.DebugStepRequest_Time && nextEventAt > until {
+			return nil
+		}
+
+		numEvents := len(ds.log.events)
+		if sim.Step() {
+			sim.Cleanup()
+			ds.done = true
+			return nil
+		}
+		if ds.checkBreakpoints(ds.log.events[numEvents:]) {
+			return nil
+		}
+
+		switch mode {
+		case proto.DebugStepRequest_Event:
+			// Cancelled actions are skipped by the sim without running anything.
+			if pa == nil || !pa.cancelled {
+				return nil
+			}
+		case proto.DebugStepRequest_GCD:
+			if !unit.GCD.IsReady(sim) {
+				gcdUsed = true
+			}
+			if rotationAt, ok := ds.nextRotationAt(); gcdUsed && ok {
+				// Stop at the time of the decision, which Step() would advance to anyway.
+				if rotationAt > sim.CurrentTime {
+					sim.advance(rotationAt)
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// Returns the time of the next event, and the pending action it runs if it isn't a weapon
+// attack or task. Mirrors the order of Simulation.Step().
+func (ds *DebugSim) nextEvent() (time.Duration, *PendingAction, error) {
+	sim := ds.sim
+	if len(sim.pendingActions) == 0 {
+		return 0, nil, errors.New("no pending event")
+	}
+	pa := sim.pendingActions[len(sim.pendingActions)-1]
+	if pa.NextActionAt >= sim.minWeaponAttackTime && sim.minWeaponAttackTime <= sim.minTaskTime {
+		return sim.minWeaponAttackTime, nil, nil
+	}
+	if pa.NextActionAt >= sim.minTaskTime {
+		return sim.minTaskTime, nil, nil
+	}
+	return pa.NextActionAt, pa, nil
+}
+
+// Returns the time of the next event if it evaluates the rotation of the player with its
+// GCD ready.
+func (ds *DebugSim) nextRotationAt() (time.Duration, bool) {
+	sim := ds.sim
+	for i := len(sim.pendingActions) - 1; i >= 0; i-- {
+		pa := sim.pendingActions[i]
+		if pa.cancelled {
+			continue
+		}
+		if pa.NextActionAt >= sim.minWeaponAttackTime || pa.NextActionAt >= sim.minTaskTime || pa.NextActionAt > sim.endOfCombatDuration {
+			return 0, false
+		}
+		return pa.NextActionAt, pa == ds.unit.rotationAction && ds.unit.GCD.ReadyAt() <= pa.NextActionAt
+	}
+	return 0, false
+}
+
+func (ds *DebugSim) checkBreakpoints(events []*proto.LogEvent) bool {
+	for _, event := range events {
+		for i, breakpoint := range ds.Breakpoints {
+			if debugBreakpointMatches(breakpoint, event) {
+				ds.breakpointHit = int32(i)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func debugBreakpointMatches(breakpoint *proto.DebugBreakpoint, event *proto.LogEvent) bool {
+	if breakpoint.Unit != "" && breakpoint.Unit != event.Unit {
+		return false
+	}
+	sameAction := func(breakpointID *proto.ActionID, eventID *proto.ActionID) bool {
+		if breakpointID == nil {
+			return false
+		}
+		id := ProtoToActionID(breakpointID)
+		if id.Tag == 0 {
+			return id.SameActionIgnoreTag(ProtoToActionID(eventID))
+		}
+		return id.SameAction(ProtoToActionID(eventID))
+	}
+
+	switch e := event.Event.(type) {
+	case *proto.LogEvent_CastBegan:
+		return sameAction(breakpoint.GetCast(), e.CastBegan.Action)
+	case *proto.LogEvent_Aura:
+		return sameAction(breakpoint.GetAura(), e.Aura.Aura) &&
+			(len(breakpoint.AuraEvents) == 0 || slices.Contains(breakpoint.AuraEvents, e.Aura.Type))
+	}
+	return false
+}
+
+// Returns the current state of the sim, including the combat log events of the last step.
+func (ds *DebugSim) State() *proto.DebugState {
+	sim := ds.sim
+	state := &proto.DebugState{
+		CurrentTime:    sim.CurrentTime.Seconds(),
+		RemainingTime:  max(0, sim.GetRemainingDuration()).Seconds(),
+		Done:           ds.done,
+		Player:         ds.unitState(ds.unit),
+		PendingActions: ds.pendingActionStates(),
+		Events:         ds.log.events,
+		BreakpointHit:  ds.breakpointHit,
+	}
+	if target := ds.unit.CurrentTarget; target != nil {
+		state.Target = ds.unitState(target)
+	}
+	if !ds.done {
+		state.NextAction = ds.unit.Rotation.debugNextAction(sim)
+	}
+	return state
+}
+
+func (ds *DebugSim) unitState(unit *Unit) *proto.DebugUnitState {
+	sim := ds.sim
+	state := &proto.DebugUnitState{
+		Label:         unit.Label,
+		GcdRemaining:  unit.GCD.TimeToReady(sim).Seconds(),
+		CastRemaining: max(0, unit.Hardcast.Expires-sim.CurrentTime).Seconds(),
+	}
+
+	for _, resource := range unitResources(unit) {
+		state.Resources = append(state.Resources, &proto.DebugResource{
+			Type:          resource.resourceType,
+			SecondaryType: resource.secondaryType,
+			Value:         resource.level(),
+		})
+	}
+	for _, aura := range unit.GetAuras() {
+		if !aura.IsActive() {
+			continue
+		}
+		auraState := &proto.DebugAura{
+			Id:     aura.ActionID.ToProto(),
+			Label:  aura.Label,
+			Stacks: aura.GetStacks(),
+		}
+		if remaining := aura.RemainingDuration(sim); remaining == NeverExpires {
+			auraState.Permanent = true
+		} else {
+			auraState.Remaining = remaining.Seconds()
+		}
+		state.Auras = append(state.Auras, auraState)
+	}
+	for _, spell := range unit.Spellbook {
+		if timeToReady := spell.TimeToReady(sim); timeToReady > 0 && !spell.ActionID.IsEmptyAction() {
+			state.Cooldowns = append(state.Cooldowns, &proto.DebugCooldown{
+				Id:          spell.ActionID.ToProto(),
+				TimeToReady: timeToReady.Seconds(),
+			})
+		}
+		for _, dot := range append(slices.Clone(spell.dots), spell.aoeDot) {
+			if dot == nil || !dot.IsActive() {
+				continue
+			}
+			state.Dots = append(state.Dots, &proto.DebugDot{
+				Id:             spell.ActionID.ToProto(),
+				Target:         dot.Unit.Label,
+				Remaining:      dot.RemainingDuration(sim).Seconds(),
+				RemainingTicks: dot.RemainingTicks(),
+				Stacks:         dot.GetStacks(),
+			})
+		}
+	}
+	return state
+}
+
+// Returns the pending actions in the order they will run, described where they are known.
+func (ds *DebugSim) pendingActionStates() []*proto.DebugPendingAction {
+	sim := ds.sim
+	known := make(map[*PendingAction]*proto.DebugPendingAction)
+	for _, unit := range sim.AllUnits {
+		if unit.rotationAction != nil {
+			known[unit.rotationAction] = &proto.DebugPendingAction{Type: "Rotation", Unit: unit.Label}
+		}
+		if unit.hardcastAction != nil {
+			known[unit.hardcastAction] = &proto.DebugPendingAction{Type: "Hardcast", Unit: unit.Label}
+		}
+		for _, spell := range unit.Spellbook {
+			for _, dot := range append(slices.Clone(spell.dots), spell.aoeDot) {
+				if dot != nil && dot.tickAction != nil {
+					known[dot.tickAction] = &proto.DebugPendingAction{Type: "Dot Tick", Unit: unit.Label, Id: spell.ActionID.ToProto()}
+				}
+			}
+		}
+	}
+
+	var states []*proto.DebugPendingAction
+	// The last pending action is the next one, and the first is a sentinel.
+	for i := len(sim.pendingActions) - 1; i > 0; i-- {
+		pa := sim.pendingActions[i]
+		if pa.cancelled {
+			continue
+		}
+		state := &proto.DebugPendingAction{}
+		if knownState, ok := known[pa]; ok {
+			state = googleProto.Clone(knownState).(*proto.DebugPendingAction)
+		}
+		state.Time = pa.NextActionAt.Seconds()
+		state.Priority = int32(pa.Priority)
+		states = append(states, state)
+	}
+	return states
+}
+
+// Returns the APL line the rotation would execute if it was evaluated now, without
+// counting the evaluation in the action profiles.
+func (rot *APLRotation) debugNextAction(sim *Simulation) *proto.DebugAPLLine {
+	if rot == nil || rot.config == nil {
+		return nil
+	}
+
+	nextAction := rot.peekNextAction(sim)
+	callers := slices.Clone(rot.nextActionCallers)
+	if nextAction == nil {
+		return nil
+	}
+
+	line := rot.findAPLLine(nextAction)
+	if line == nil {
+		return nil
+	}
+	// Callers are recorded innermost first.
+	for i := len(callers) - 1; i >= 0; i-- {
+		switch impl := callers[i].impl.(type) {
+		case *APLActionCallActionList:
+			line.Callers = append(line.Callers, impl.list.name)
+		case *APLActionRunActionList:
+			line.Callers = append(line.Callers, impl.list.name)
+		}
+	}
+	return line
+}
+
+// Returns the location of the list item containing the action.
+func (rot *APLRotation) findAPLLine(action *APLAction) *proto.DebugAPLLine {
+	contains := func(item *APLAction) bool {
+		return slices.Contains(item.GetAllActions(), action)
+	}
+
+	if i := slices.IndexFunc(rot.priorityList, contains); i != -1 {
+		idx := rot.priorityListIdxMap[i]
+		return &proto.DebugAPLLine{
+			Index: int32(idx),
+			Text:  apltext.FormatAction(rot.config.PriorityList[idx].Action),
+		}
+	}
+	for listIdx, listValidations := range rot.actionListValidations {
+		if listValidations.list == nil {
+			continue
+		}
+		if i := slices.IndexFunc(listValidations.list.actions, contains); i != -1 {
+			idx := listValidations.itemIdxMap[i]
+			return &proto.DebugAPLLine{
+				List:  listValidations.list.name,
+				Index: int32(idx),
+				Text:  apltext.FormatAction(rot.config.ActionLists[listIdx].Items[idx].Action),
+			}
+		}
+	}
+	return nil
+}
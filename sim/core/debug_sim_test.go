@@ -0,0 +1,178 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func init() {
+	RegisterAgentFactory(
+		proto.Player_AfflictionWarlock{},
+		proto.Spec_SpecAfflictionWarlock,
+		NewFakeDebugAgent,
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_AfflictionWarlock)
+			if !ok {
+				panic("Invalid spec value for Affliction Warlock!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+// A caster with a single dot on the GCD, so that there is a GCD to step over.
+func NewFakeDebugAgent(char *Character, _ *proto.Player) Agent {
+	fa := &FakeAgent{
+		Character: *char,
+	}
+
+	fa.Init = func() {
+		fa.Spell = fa.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: 42},
+			SpellSchool: SpellSchoolShadow,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagIgnoreArmor | SpellFlagAPL,
+			Cast: CastConfig{
+				DefaultCast: Cast{
+					GCD: GCDDefault,
+				},
+			},
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			Dot: DotConfig{
+				Aura: Aura{
+					Label: "fakedot",
+				},
+				NumberOfTicks: 6,
+				TickLength:    time.Second * 3,
+
+				OnSnapshot: func(sim *Simulation, target *Unit, dot *Dot, isRollover bool) {
+					dot.Snapshot(target, 100)
+				},
+				OnTick: func(sim *Simulation, target *Unit, dot *Dot) {
+					dot.CalcAndDealPeriodicSnapshotDamage(sim, target, dot.OutcomeTick)
+				},
+			},
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.Dot(target).Apply(sim)
+			},
+		})
+		fa.Dot = fa.Spell.CurDot()
+	}
+
+	return fa
+}
+
+func newTestDebugSim(t *testing.T, seed int64) *DebugSim {
+	rotation, err := apltext.Parse(`
+		actions call_action_list(list_name="dot")
+		actions.dot cast_spell(spell_id=spell:42) if !dot_is_active(spell_id=spell:42)
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := NewDebugSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 100},
+		Raid:       SinglePlayerRaidProto(&proto.Player{Name: "Caster", Class: proto.Class_ClassWarlock, Spec: &proto.Player_AfflictionWarlock{}, Equipment: &proto.EquipmentSpec{}, Rotation: rotation}, nil, nil, nil),
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 90, MobType: proto.MobType_MobTypeDemon}},
+			Duration: 30,
+		},
+	}, seed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
+
+func TestDebugSim(t *testing.T) {
+	dotID := ActionID{SpellID: 42}.ToProto()
+	ds := newTestDebugSim(t, 5)
+
+	state := ds.State()
+	next := state.NextAction
+	if next == nil || next.List != "dot" || next.Index != 0 || !slices.Equal(next.Callers, []string{"dot"}) {
+		t.Fatalf("Expected the dot line to be next, got %v", next)
+	}
+	if next.Text != "cast_spell(spell_id=spell:42) if !dot_is_active(spell_id=spell:42)" {
+		t.Fatalf("Unexpected text of the next line: %s", next.Text)
+	}
+
+	ds.Breakpoints = []*proto.DebugBreakpoint{{Breakpoint: &proto.DebugBreakpoint_Cast{Cast: dotID}}}
+	ds.Step(proto.DebugStepRequest_Resume, 0)
+	state = ds.State()
+	if state.BreakpointHit != 0 || state.CurrentTime != 0 {
+		t.Fatalf("Expected the cast breakpoint to be hit at the pull, got %d at %0.2f", state.BreakpointHit, state.CurrentTime)
+	}
+	if len(state.Player.Dots) != 1 || state.Player.Dots[0].RemainingTicks != 6 {
+		t.Fatalf("Expected the dot to be active with 6 ticks, got %v", state.Player.Dots)
+	}
+	if !slices.ContainsFunc(state.PendingActions, func(pa *proto.DebugPendingAction) bool { return pa.Type == "Dot Tick" }) {
+		t.Fatalf("Expected a pending dot tick, got %v", state.PendingActions)
+	}
+	if state.NextAction != nil {
+		t.Fatalf("Expected no line to be ready while the dot is active, got %v", state.NextAction)
+	}
+
+	ds.Breakpoints = []*proto.DebugBreakpoint{{
+		Breakpoint: &proto.DebugBreakpoint_Aura{Aura: dotID},
+		AuraEvents: []proto.AuraLogEvent_Type{proto.AuraLogEvent_Faded},
+	}}
+	ds.Step(proto.DebugStepRequest_Resume, 0)
+	state = ds.State()
+	if state.BreakpointHit != 0 || state.CurrentTime < 15 || len(state.Player.Dots) != 0 {
+		t.Fatalf("Expected the dot to have faded, got breakpoint %d at %0.2f with dots %v", state.BreakpointHit, state.CurrentTime, state.Player.Dots)
+	}
+	fadedAt := state.CurrentTime
+
+	ds.Step(proto.DebugStepRequest_Event, 0)
+	if state = ds.State(); state.CurrentTime < fadedAt || state.Done {
+		t.Fatalf("Expected a single event to run, got time %0.2f", state.CurrentTime)
+	}
+
+	ds.Step(proto.DebugStepRequest_Time, time.Second*25)
+	if state = ds.State(); state.CurrentTime > 25 || state.Done || state.BreakpointHit != -1 {
+		t.Fatalf("Expected to stop before 25s, got %0.2f", state.CurrentTime)
+	}
+
+	ds.Step(proto.DebugStepRequest_Resume, 0)
+	if state = ds.State(); !state.Done || state.BreakpointHit != -1 || state.CurrentTime != 30 {
+		t.Fatalf("Expected the iteration to run to the end, got %v", state)
+	}
+	ds.Step(proto.DebugStepRequest_Event, 0)
+	if state = ds.State(); !state.Done || len(state.Events) != 0 {
+		t.Fatalf("Expected a finished iteration to stay done")
+	}
+
+	// The same seed replays the same iteration.
+	runIteration := func() []*proto.LogEvent {
+		ds := newTestDebugSim(t, 5)
+		ds.Step(proto.DebugStepRequest_GCD, 0)
+		// Stepping over the GCD of the dot stops at the decision once the GCD is ready.
+		rotationAt, ok := ds.nextRotationAt()
+		if gcdReadyAt := ds.unit.GCD.ReadyAt(); gcdReadyAt <= 0 || ds.sim.CurrentTime != gcdReadyAt || !ok || rotationAt != gcdReadyAt {
+			t.Fatalf("Expected to stop at the next decision when the GCD is ready at %s, stopped at %s", gcdReadyAt, ds.sim.CurrentTime)
+		}
+		return ds.State().Events
+	}
+	first, second := runIteration(), runIteration()
+	if len(first) == 0 || len(first) != len(second) || first[len(first)-1].String() != second[len(second)-1].String() {
+		t.Fatalf("Expected the same events for the same seed, got %d and %d", len(first), len(second))
+	}
+}
+
+func TestDebugSimWithoutPendingEvents(t *testing.T) {
+	ds := newTestDebugSim(t, 5)
+	ds.sim.pendingActions = nil
+
+	if err := ds.Step(proto.DebugStepRequest_Event, 0); err == nil || err.Error() != "no pending event" {
+		t.Fatalf("Expected stepping without pending events to fail, got %v", err)
+	}
+}
@@ -107,7 +107,15 @@ func (eb *energyBar) AddEnergy(sim *Simulation, amount float64, metrics *Resourc
 	metrics.AddEvent(amount, newEnergy-eb.currentEnergy)
 
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
+		eb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "energy",
+			Action: metrics.ActionID.ToProto(),
+			Amount: amount,
+			Before: eb.currentEnergy,
+			After:  newEnergy,
+			Max:    eb.maxEnergy,
+		})
 	}
 
 	eb.currentEnergy = newEnergy
@@ -122,7 +130,16 @@ func (eb *energyBar) SpendEnergy(sim *Simulation, amount float64, metrics *Resou
 	metrics.AddEvent(-amount, -amount)
 
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Spent %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
+		eb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "energy",
+			Action: metrics.ActionID.ToProto(),
+			Spent:  true,
+			Amount: amount,
+			Before: eb.currentEnergy,
+			After:  newEnergy,
+			Max:    eb.maxEnergy,
+		})
 	}
 
 	eb.currentEnergy = newEnergy
@@ -201,7 +218,16 @@ func (eb *energyBar) AddComboPoints(sim *Simulation, pointsToAdd int32, metrics
 	metrics.AddEvent(float64(pointsToAdd), float64(newComboPoints-eb.comboPoints))
 
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Gained %d %s from %s (%d --> %d) of %0.0f total.", pointsToAdd, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
+		eb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:       metrics.Type,
+			Name:       eb.comboPointsResourceName,
+			Action:     metrics.ActionID.ToProto(),
+			Amount:     float64(pointsToAdd),
+			Before:     float64(eb.comboPoints),
+			After:      float64(newComboPoints),
+			Max:        float64(eb.maxComboPoints),
+			WholeUnits: true,
+		})
 	}
 
 	eb.comboPoints = newComboPoints
@@ -219,7 +245,17 @@ func (eb *energyBar) spendComboPointsInternal(sim *Simulation, pointsToSpend int
 	pointsToSpend = min(pointsToSpend, eb.comboPoints)
 	newComboPoints := eb.comboPoints - pointsToSpend
 	if sim.Log != nil {
-		eb.unit.Log(sim, "Spent %d %s from %s (%d --> %d) of %0.0f total.", pointsToSpend, eb.comboPointsResourceName, metrics.ActionID, eb.comboPoints, newComboPoints, eb.maxComboPoints)
+		eb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:       metrics.Type,
+			Name:       eb.comboPointsResourceName,
+			Action:     metrics.ActionID.ToProto(),
+			Spent:      true,
+			Amount:     float64(pointsToSpend),
+			Before:     float64(eb.comboPoints),
+			After:      float64(newComboPoints),
+			Max:        float64(eb.maxComboPoints),
+			WholeUnits: true,
+		})
 	}
 	metrics.AddEvent(float64(-pointsToSpend), float64(-pointsToSpend))
 	eb.comboPoints = newComboPoints
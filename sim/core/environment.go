@@ -48,6 +48,9 @@ type Environment struct {
 
 	// Used to model variation in pet stat inheritance
 	heartbeatOffset time.Duration
+
+	// Whether units are placed on a 2D ground plane, see setupPositions().
+	usePositions bool
 }
 
 func NewEnvironment(raidProto *proto.Raid, encounterProto *proto.Encounter, runFakePrepull bool) (*Environment, *proto.RaidStats, *proto.EncounterStats) {
@@ -109,6 +112,8 @@ func (env *Environment) construct(raidProto *proto.Raid, encounterProto *proto.E
 		}
 	}
 
+	env.setupPositions()
+
 	env.State = Constructed
 }
 
@@ -295,6 +300,15 @@ func (env *Environment) GetTarget(index int32) *Target {
 func (env *Environment) GetTargetUnit(index int32) *Unit {
 	return &env.Encounter.Targets[index].Unit
 }
+
+// Returns the active target at the given index, see GetNumTargets(). Targets can be
+// enabled and disabled mid-iteration, so indices are only valid until the next change.
+func (env *Environment) GetActiveTarget(index int32) *Target {
+	return env.Encounter.ActiveTargets[index]
+}
+func (env *Environment) GetActiveTargetUnit(index int32) *Unit {
+	return env.Encounter.ActiveTargetUnits[index]
+}
 func (env *Environment) NextTarget(target *Unit) *Target {
 	return env.Encounter.Targets[target.Index].NextTarget()
 }
@@ -359,6 +373,8 @@ func (env *Environment) GetUnit(ref *proto.UnitReference, contextUnit *Unit) *Un
 			return nil
 		}
 		return contextUnit.CurrentTarget
+	case proto.UnitReference_LowestHealthAlly:
+		return env.Raid.GetLowestHealthPercentAllyUnit()
 	}
 
 	return nil
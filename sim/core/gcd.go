@@ -77,6 +77,12 @@ func (unit *Unit) ReactToEvent(sim *Simulation) {
 	}
 }
 
+// In interactive mode the rotation of the first player in the raid is controlled by the
+// caller (see InteractiveSim), while all other units keep following their own rotations.
+func (unit *Unit) isInteractive(sim *Simulation) bool {
+	return sim.Options.Interactive && len(sim.Raid.AllPlayerUnits) > 0 && sim.Raid.AllPlayerUnits[0] == unit
+}
+
 // Call this to stop the GCD loop for a unit.
 // This is mostly used for pets that get summoned / expire.
 func (unit *Unit) CancelGCDTimer(sim *Simulation) {
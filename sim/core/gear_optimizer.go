@@ -0,0 +1,533 @@
+package core
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// Maximum number of passes over all slots before giving up on converging.
+	maxGearOptimizerPasses = 20
+	// Number of gems considered per socket, both with and without matching the socket color.
+	gearOptimizerGemsPerSocket = 3
+	// Score lost per gem missing from the meta gem requirements, large enough to outweigh any stats.
+	metaGemRequirementPenalty = 1e6
+)
+
+/**
+ * Chooses reforges, gems and enchants for the player's equipment which
+ * maximize the value of their stats.
+ */
+func OptimizeGear(request *proto.OptimizeGearRequest) (result *proto.OptimizeGearResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			errStr := ""
+			switch errt := err.(type) {
+			case string:
+				errStr = errt
+			case error:
+				errStr = errt.Error()
+			}
+
+			errStr += "\nStack Trace:\n" + string(debug.Stack())
+			result = &proto.OptimizeGearResult{
+				Error: &proto.ErrorOutcome{Message: errStr},
+			}
+		}
+	}()
+
+	optimizer, err := runGearOptimizer(request)
+	if err != nil {
+		return &proto.OptimizeGearResult{
+			Error: &proto.ErrorOutcome{Message: err.Error()},
+		}
+	}
+
+	equipment := optimizer.toEquipmentSpecProto()
+	return &proto.OptimizeGearResult{
+		Equipment:    equipment,
+		FinalStats:   computeOptimizerFinalStats(request, equipment),
+		InitialScore: optimizer.initialScore,
+		Score:        optimizer.currentScore(),
+	}
+}
+
+func runGearOptimizer(request *proto.OptimizeGearRequest) (*gearOptimizer, error) {
+	if request.Player == nil || request.Player.Equipment == nil {
+		return nil, errors.New("No player equipment to optimize")
+	}
+
+	env, _ := newOptimizerEnvironment(request, request.Player.Equipment)
+	character := env.Raid.Parties[0].Players[0].GetCharacter()
+	optimizer, err := newGearOptimizer(request, character.initialStatsWithoutDeps, &character.StatDependencyManager)
+	if err != nil {
+		return nil, err
+	}
+
+	optimizer.optimize()
+	return optimizer, nil
+}
+
+// Builds the environment of the player wearing the equipment, with all buffs and consumes applied.
+func newOptimizerEnvironment(request *proto.OptimizeGearRequest, equipment *proto.EquipmentSpec) (*Environment, *proto.RaidStats) {
+	player := googleProto.Clone(request.Player).(*proto.Player)
+	player.Equipment = equipment
+
+	encounter := request.Encounter
+	if encounter == nil {
+		encounter = &proto.Encounter{}
+	}
+
+	env, raidStats, _ := NewEnvironment(SinglePlayerRaidProto(player, request.PartyBuffs, request.RaidBuffs, request.Debuffs), encounter, true)
+	return env, raidStats
+}
+
+func computeOptimizerFinalStats(request *proto.OptimizeGearRequest, equipment *proto.EquipmentSpec) *proto.UnitStats {
+	_, raidStats := newOptimizerEnvironment(request, equipment)
+	return raidStats.Parties[0].Players[0].FinalStats
+}
+
+type gearOptimizer struct {
+	weights          stats.Stats
+	caps             stats.Stats
+	ensureMetaReqMet bool
+	assumeBeltBuckle bool
+	isBlacksmithing  bool
+
+	// Used to filter the candidate enchants.
+	class       proto.Class
+	professions []proto.Profession
+
+	// Dependencies of the character, e.g. from intellect to spell power or
+	// stat multipliers from buffs, which turn raw stats into final stats.
+	statDeps *stats.StatDependencyManager
+
+	equipment *proto.EquipmentSpec
+	slots     []*gearOptimizerSlot
+
+	// Current stats of the character before dependencies, and the gem colors in their sockets.
+	total       stats.Stats
+	totalColors GemColorCounts
+
+	initialScore float64
+}
+
+// A single combination of gems for all sockets of an item.
+type gemCombo struct {
+	gems      []Gem
+	stats     stats.Stats // Includes the socket bonus, if earned.
+	colors    GemColorCounts
+	metaGemID int32
+}
+
+type gearOptimizerSlot struct {
+	spec *proto.ItemSpec
+	item Item
+
+	reforges     []*ReforgeStat
+	reforgeStats []stats.Stats
+	enchants     []Enchant
+	gemCombos    []gemCombo
+
+	// Indices of the current choices.
+	reforgeIdx  int
+	enchantIdx  int
+	gemComboIdx int
+
+	stats stats.Stats
+}
+
+func newGearOptimizer(request *proto.OptimizeGearRequest, statsWithoutDeps stats.Stats, statDeps *stats.StatDependencyManager) (*gearOptimizer, error) {
+	opt := &gearOptimizer{
+		ensureMetaReqMet: request.EnsureMetaReqMet,
+		assumeBeltBuckle: request.AssumeBeltBuckle,
+		isBlacksmithing:  request.Player.Profession1 == proto.Profession_Blacksmithing || request.Player.Profession2 == proto.Profession_Blacksmithing,
+		class:            request.Player.Class,
+		professions:      []proto.Profession{request.Player.Profession1, request.Player.Profession2},
+		statDeps:         statDeps,
+		equipment:        googleProto.Clone(request.Player.Equipment).(*proto.EquipmentSpec),
+	}
+
+	if request.StatCaps != nil {
+		opt.caps = stats.FromUnitStatsProto(request.StatCaps)
+	}
+	if request.EpWeights != nil {
+		opt.weights = stats.FromUnitStatsProto(request.EpWeights)
+	} else {
+		for stat, cap := range opt.caps {
+			if cap > 0 {
+				opt.weights[stat] = 1
+			}
+		}
+	}
+
+	if opt.weights.Equals(stats.Stats{}) {
+		return nil, errors.New("Either stat weights or stat caps are required to optimize gear")
+	}
+
+	candidateGems, err := lookupOptimizerGems(request.Gems)
+	if err != nil {
+		return nil, err
+	}
+	candidateEnchants, err := lookupOptimizerEnchants(request.Enchants)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, itemSpec := range opt.equipment.Items {
+		if itemSpec == nil || itemSpec.Id == 0 {
+			continue
+		}
+
+		slot := opt.newSlot(itemSpec, request.OptimizeReforges, candidateGems, candidateEnchants)
+		opt.slots = append(opt.slots, slot)
+		opt.totalColors = opt.totalColors.Add(slot.gemCombos[slot.gemComboIdx].colors)
+	}
+
+	// Stats from everything other than the optimized equipment stay fixed, so
+	// the character's stats can be updated by swapping out item contributions.
+	opt.total = statsWithoutDeps
+
+	return opt, nil
+}
+
+func lookupOptimizerGems(gemIDs []int32) ([]Gem, error) {
+	gems := make([]Gem, 0, len(gemIDs))
+	for _, gemID := range gemIDs {
+		gem, ok := GemsByID[gemID]
+		if !ok {
+			return nil, fmt.Errorf("No gem with id: %d", gemID)
+		}
+		gems = append(gems, gem)
+	}
+	return gems, nil
+}
+
+func lookupOptimizerEnchants(effectIDs []int32) ([]Enchant, error) {
+	enchants := make([]Enchant, 0, len(effectIDs))
+	for _, effectID := range effectIDs {
+		enchant, ok := EnchantsByEffectID[effectID]
+		if !ok {
+			return nil, fmt.Errorf("No enchant with id: %d", effectID)
+		}
+		enchants = append(enchants, enchant)
+	}
+	return enchants, nil
+}
+
+func (opt *gearOptimizer) newSlot(spec *proto.ItemSpec, optimizeReforges bool, candidateGems []Gem, candidateEnchants []Enchant) *gearOptimizerSlot {
+	item := NewItem(ProtoToEquipmentSpec(&proto.EquipmentSpec{Items: []*proto.ItemSpec{spec}})[0])
+
+	slot := &gearOptimizerSlot{
+		spec: spec,
+		item: item,
+	}
+
+	// Reforges
+	slot.reforges = []*ReforgeStat{item.Reforging}
+	if optimizeReforges {
+		slot.reforges = []*ReforgeStat{nil}
+		for _, reforge := range sortedReforgeStats() {
+			if validateReforging(&item, reforge) {
+				slot.reforges = append(slot.reforges, &reforge)
+			}
+		}
+		slot.reforgeIdx = slices.IndexFunc(slot.reforges, func(reforge *ReforgeStat) bool {
+			return (reforge == nil && item.Reforging == nil) || (reforge != nil && item.Reforging != nil && reforge.ID == item.Reforging.ID)
+		})
+		if slot.reforgeIdx == -1 {
+			slot.reforges = append(slot.reforges, item.Reforging)
+			slot.reforgeIdx = len(slot.reforges) - 1
+		}
+	}
+	slot.reforgeStats = MapSlice(slot.reforges, func(reforge *ReforgeStat) stats.Stats {
+		reforgedItem := item
+		reforgedItem.Reforging = reforge
+		return ItemEquipmentBaseStats(reforgedItem)
+	})
+
+	// Enchants
+	slot.enchants = []Enchant{item.Enchant}
+	for _, enchant := range candidateEnchants {
+		if enchant.EffectID != item.Enchant.EffectID && EnchantAppliesToItem(&enchant, &item) && CanEquipEnchant(&enchant, opt.class, opt.professions...) {
+			slot.enchants = append(slot.enchants, enchant)
+		}
+	}
+
+	// Gems
+	slot.gemCombos = []gemCombo{opt.newGemCombo(item, item.Gems)}
+	if len(candidateGems) > 0 {
+		sockets := slices.Clone(item.GemSockets)
+		if opt.hasExtraSocket(item.Type) {
+			sockets = append(sockets, proto.GemColor_GemColorPrismatic)
+		}
+		for len(sockets) < len(item.Gems) {
+			// Extra sockets, e.g. an equipped belt buckle, accept any gem.
+			sockets = append(sockets, proto.GemColor_GemColorPrismatic)
+		}
+
+		socketCandidates := make([][]Gem, len(sockets))
+		for socketIdx, socketColor := range sockets {
+			socketCandidates[socketIdx] = opt.socketCandidates(socketColor, candidateGems)
+			if len(socketCandidates[socketIdx]) == 0 {
+				// Keep whatever is in sockets which none of the candidates fit.
+				equippedGem := Gem{}
+				if socketIdx < len(item.Gems) {
+					equippedGem = item.Gems[socketIdx]
+				}
+				socketCandidates[socketIdx] = []Gem{equippedGem}
+			}
+		}
+		slot.gemCombos = append(slot.gemCombos, opt.allGemCombos(item, socketCandidates)...)
+	}
+
+	slot.stats = slot.currentStats()
+	return slot
+}
+
+// Whether the item gets an extra socket it doesn't have yet, if belt buckles are
+// assumed or if blacksmiths socket their bracers and gloves.
+func (opt *gearOptimizer) hasExtraSocket(itemType proto.ItemType) bool {
+	switch itemType {
+	case proto.ItemType_ItemTypeWaist:
+		return opt.assumeBeltBuckle
+	case proto.ItemType_ItemTypeWrist, proto.ItemType_ItemTypeHands:
+		return opt.isBlacksmithing
+	}
+	return false
+}
+
+func sortedReforgeStats() []ReforgeStat {
+	reforges := make([]ReforgeStat, 0, len(ReforgeStatsByID))
+	for _, reforge := range ReforgeStatsByID {
+		reforges = append(reforges, reforge)
+	}
+	slices.SortFunc(reforges, func(a, b ReforgeStat) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return reforges
+}
+
+// Whether the gem can be placed in a socket of the given color.
+func gemEligibleForSocket(gem Gem, socketColor proto.GemColor) bool {
+	switch socketColor {
+	case proto.GemColor_GemColorMeta, proto.GemColor_GemColorCogwheel, proto.GemColor_GemColorShaTouched:
+		return gem.Color == socketColor
+	default:
+		return gem.Color != proto.GemColor_GemColorMeta && gem.Color != proto.GemColor_GemColorCogwheel && gem.Color != proto.GemColor_GemColorShaTouched
+	}
+}
+
+// Returns the most valuable gems for a socket, both overall and among the
+// ones matching the socket color, plus the best gem for each capped stat.
+func (opt *gearOptimizer) socketCandidates(socketColor proto.GemColor, candidateGems []Gem) []Gem {
+	eligible := FilterSlice(candidateGems, func(gem Gem) bool {
+		return gemEligibleForSocket(gem, socketColor)
+	})
+	slices.SortStableFunc(eligible, func(a, b Gem) int {
+		return cmp.Compare(opt.value(b.Stats), opt.value(a.Stats))
+	})
+
+	candidates := eligible[:min(gearOptimizerGemsPerSocket, len(eligible))]
+	matching := FilterSlice(eligible, func(gem Gem) bool {
+		return ColorIntersects(socketColor, gem.Color)
+	})
+	candidates = append(candidates, matching[:min(gearOptimizerGemsPerSocket, len(matching))]...)
+
+	for stat, cap := range opt.caps {
+		if cap <= 0 {
+			continue
+		}
+		if idx := slices.IndexFunc(eligible, func(gem Gem) bool { return gem.Stats[stat] > 0 }); idx != -1 {
+			candidates = append(candidates, eligible[idx])
+		}
+	}
+
+	return slices.CompactFunc(slices.SortedStableFunc(slices.Values(candidates), func(a, b Gem) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), func(a, b Gem) bool {
+		return a.ID == b.ID
+	})
+}
+
+func (opt *gearOptimizer) allGemCombos(item Item, socketCandidates [][]Gem) []gemCombo {
+	var combos []gemCombo
+	gems := make([]Gem, len(socketCandidates))
+
+	var addCombos func(socketIdx int)
+	addCombos = func(socketIdx int) {
+		if socketIdx == len(socketCandidates) {
+			combos = append(combos, opt.newGemCombo(item, slices.Clone(gems)))
+			return
+		}
+		for _, gem := range socketCandidates[socketIdx] {
+			gems[socketIdx] = gem
+			addCombos(socketIdx + 1)
+		}
+	}
+	addCombos(0)
+
+	return combos
+}
+
+func (opt *gearOptimizer) newGemCombo(item Item, gems []Gem) gemCombo {
+	item.Gems = gems
+	item.Enchant = Enchant{}
+
+	combo := gemCombo{
+		gems:   gems,
+		stats:  ItemEquipmentGemAndEnchantStats(item),
+		colors: CountGemColors(gems),
+	}
+	for _, gem := range gems {
+		if gem.Color == proto.GemColor_GemColorMeta {
+			combo.metaGemID = gem.ID
+		}
+	}
+	return combo
+}
+
+func (slot *gearOptimizerSlot) statsFor(reforgeIdx int, enchantIdx int, gemComboIdx int) stats.Stats {
+	return slot.reforgeStats[reforgeIdx].Add(slot.enchants[enchantIdx].Stats).Add(slot.gemCombos[gemComboIdx].stats)
+}
+
+func (slot *gearOptimizerSlot) currentStats() stats.Stats {
+	return slot.statsFor(slot.reforgeIdx, slot.enchantIdx, slot.gemComboIdx)
+}
+
+// Value of the stats without considering caps, used for ranking gems.
+func (opt *gearOptimizer) value(itemStats stats.Stats) float64 {
+	value := 0.0
+	for stat, weight := range opt.weights {
+		value += weight * itemStats[stat]
+	}
+	return value
+}
+
+func (opt *gearOptimizer) score(total stats.Stats, colors GemColorCounts, metaGemID int32) float64 {
+	if opt.statDeps != nil {
+		total = opt.statDeps.ApplyStatDependencies(total)
+	}
+
+	score := 0.0
+	for stat, weight := range opt.weights {
+		value := total[stat]
+		if cap := opt.caps[stat]; cap > 0 {
+			value = min(value, cap)
+		}
+		score += weight * value
+	}
+
+	if opt.ensureMetaReqMet {
+		if condition, ok := MetaGemConditions[metaGemID]; ok {
+			score -= metaGemRequirementPenalty * float64(condition.NumMissing(colors))
+		}
+	}
+
+	return score
+}
+
+func (opt *gearOptimizer) currentScore() float64 {
+	return opt.score(opt.total, opt.totalColors, opt.metaGemID())
+}
+
+func (opt *gearOptimizer) metaGemID() int32 {
+	for _, slot := range opt.slots {
+		if metaGemID := slot.gemCombos[slot.gemComboIdx].metaGemID; metaGemID != 0 {
+			return metaGemID
+		}
+	}
+	return 0
+}
+
+// Repeatedly picks the best reforge, enchant and gems for one item at a time
+// while keeping the others fixed, until no item can be improved any further.
+func (opt *gearOptimizer) optimize() {
+	opt.initialScore = opt.currentScore()
+	for pass := 0; pass < maxGearOptimizerPasses; pass++ {
+		improved := false
+		for _, slot := range opt.slots {
+			if opt.optimizeSlot(slot) {
+				improved = true
+			}
+		}
+		if !improved {
+			return
+		}
+	}
+}
+
+// Alternates between picking the best gems for the slot's reforge and enchant, and the best
+// reforge and enchant for its gems, until neither changes. Scoring every combination of gems,
+// reforge and enchant at once would grow too quickly with the number of sockets.
+func (opt *gearOptimizer) optimizeSlot(slot *gearOptimizerSlot) bool {
+	currentCombo := slot.gemCombos[slot.gemComboIdx]
+	otherStats := opt.total.Subtract(slot.stats)
+	otherColors := opt.totalColors.Subtract(currentCombo.colors)
+
+	otherMetaGemID := opt.metaGemID()
+	if otherMetaGemID == currentCombo.metaGemID {
+		otherMetaGemID = 0
+	}
+
+	scoreFor := func(reforgeIdx int, enchantIdx int, gemComboIdx int) float64 {
+		combo := slot.gemCombos[gemComboIdx]
+		metaGemID := Ternary(combo.metaGemID != 0, combo.metaGemID, otherMetaGemID)
+		return opt.score(otherStats.Add(slot.statsFor(reforgeIdx, enchantIdx, gemComboIdx)), otherColors.Add(combo.colors), metaGemID)
+	}
+
+	bestScore := opt.currentScore()
+	bestReforgeIdx, bestEnchantIdx, bestGemComboIdx := slot.reforgeIdx, slot.enchantIdx, slot.gemComboIdx
+	// Only switch for a real improvement, so that equal choices keep the current gear.
+	// This also guarantees that the loop ends.
+	for changed := true; changed; {
+		changed = false
+		for gemComboIdx := range slot.gemCombos {
+			if score := scoreFor(bestReforgeIdx, bestEnchantIdx, gemComboIdx); score > bestScore+1e-6 {
+				bestScore = score
+				bestGemComboIdx = gemComboIdx
+				changed = true
+			}
+		}
+		for reforgeIdx := range slot.reforges {
+			for enchantIdx := range slot.enchants {
+				if score := scoreFor(reforgeIdx, enchantIdx, bestGemComboIdx); score > bestScore+1e-6 {
+					bestScore = score
+					bestReforgeIdx, bestEnchantIdx = reforgeIdx, enchantIdx
+					changed = true
+				}
+			}
+		}
+	}
+
+	if bestReforgeIdx == slot.reforgeIdx && bestEnchantIdx == slot.enchantIdx && bestGemComboIdx == slot.gemComboIdx {
+		return false
+	}
+
+	slot.reforgeIdx, slot.enchantIdx, slot.gemComboIdx = bestReforgeIdx, bestEnchantIdx, bestGemComboIdx
+	slot.stats = slot.currentStats()
+	opt.total = otherStats.Add(slot.stats)
+	opt.totalColors = otherColors.Add(slot.gemCombos[slot.gemComboIdx].colors)
+	return true
+}
+
+func (opt *gearOptimizer) toEquipmentSpecProto() *proto.EquipmentSpec {
+	for _, slot := range opt.slots {
+		spec := slot.spec
+		if reforge := slot.reforges[slot.reforgeIdx]; reforge != nil {
+			spec.Reforging = reforge.ID
+		} else {
+			spec.Reforging = 0
+		}
+		spec.Enchant = slot.enchants[slot.enchantIdx].EffectID
+		spec.Gems = MapSlice(slot.gemCombos[slot.gemComboIdx].gems, func(gem Gem) int32 { return gem.ID })
+	}
+	return opt.equipment
+}
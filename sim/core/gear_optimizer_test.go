@@ -0,0 +1,262 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const (
+	itemOptimizerCritHelm  = 990001
+	itemOptimizerHasteRing = 990002
+	itemOptimizerGemHelm   = 990003
+	itemOptimizerGemBelt   = 990004
+	itemOptimizerGemChest  = 990005
+	itemOptimizerHands     = 990006
+	itemOptimizerOneHand   = 990007
+
+	gemOptimizerCrit      = 990101
+	gemOptimizerHaste     = 990102
+	gemOptimizerCritHaste = 990103
+	gemOptimizerInt       = 990104
+	gemOptimizerSpell     = 990105
+	gemOptimizerMeta      = 68778 // Agile Shadowspirit Diamond, requires 3 red gems
+
+	enchantOptimizerTwoHand       = 990301
+	enchantOptimizerWeapon        = 990302
+	enchantOptimizerLeatherworker = 990303
+)
+
+func optimizerTestStats(stat stats.Stat, value float64) map[int32]float64 {
+	return map[int32]float64{int32(stat): value}
+}
+
+var optimizerItemDatabase = &proto.SimDatabase{
+	Items: []*proto.SimItem{
+		{
+			Id:             itemOptimizerCritHelm,
+			Type:           proto.ItemType_ItemTypeHead,
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {Stats: optimizerTestStats(stats.CritRating, 1000)}},
+		},
+		{
+			Id:             itemOptimizerHasteRing,
+			Type:           proto.ItemType_ItemTypeFinger,
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {Stats: optimizerTestStats(stats.HasteRating, 500)}},
+		},
+		{
+			Id:             itemOptimizerGemHelm,
+			Type:           proto.ItemType_ItemTypeHead,
+			GemSockets:     []proto.GemColor{proto.GemColor_GemColorMeta, proto.GemColor_GemColorRed, proto.GemColor_GemColorYellow},
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {}},
+		},
+		{
+			Id:             itemOptimizerGemBelt,
+			Type:           proto.ItemType_ItemTypeWaist,
+			GemSockets:     []proto.GemColor{proto.GemColor_GemColorYellow},
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {}},
+		},
+		{
+			Id:             itemOptimizerGemChest,
+			Type:           proto.ItemType_ItemTypeChest,
+			GemSockets:     []proto.GemColor{proto.GemColor_GemColorRed, proto.GemColor_GemColorYellow},
+			SocketBonus:    stats.Stats{stats.CritRating: 60}.ToProtoArray(),
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {}},
+		},
+		{
+			Id:             itemOptimizerHands,
+			Type:           proto.ItemType_ItemTypeHands,
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {}},
+		},
+		{
+			Id:             itemOptimizerOneHand,
+			Type:           proto.ItemType_ItemTypeWeapon,
+			HandType:       proto.HandType_HandTypeOneHand,
+			WeaponType:     proto.WeaponType_WeaponTypeSword,
+			ScalingOptions: map[int32]*proto.ScalingItemProperties{0: {}},
+		},
+	},
+	Enchants: []*proto.SimEnchant{
+		{EffectId: enchantOptimizerTwoHand, Type: proto.ItemType_ItemTypeWeapon, EnchantType: proto.EnchantType_EnchantTypeTwoHand, Stats: stats.Stats{stats.CritRating: 500}.ToProtoArray()},
+		{EffectId: enchantOptimizerWeapon, Type: proto.ItemType_ItemTypeWeapon, Stats: stats.Stats{stats.CritRating: 100}.ToProtoArray()},
+		{EffectId: enchantOptimizerLeatherworker, Type: proto.ItemType_ItemTypeHands, RequiredProfession: proto.Profession_Leatherworking, Stats: stats.Stats{stats.CritRating: 300}.ToProtoArray()},
+	},
+	Gems: []*proto.SimGem{
+		{Id: gemOptimizerCrit, Color: proto.GemColor_GemColorRed, Stats: stats.Stats{stats.CritRating: 160}.ToProtoArray()},
+		{Id: gemOptimizerHaste, Color: proto.GemColor_GemColorYellow, Stats: stats.Stats{stats.HasteRating: 160}.ToProtoArray()},
+		{Id: gemOptimizerCritHaste, Color: proto.GemColor_GemColorOrange, Stats: stats.Stats{stats.CritRating: 80, stats.HasteRating: 80}.ToProtoArray()},
+		{Id: gemOptimizerInt, Color: proto.GemColor_GemColorRed, Stats: stats.Stats{stats.Intellect: 80}.ToProtoArray()},
+		{Id: gemOptimizerSpell, Color: proto.GemColor_GemColorRed, Stats: stats.Stats{stats.SpellPower: 90}.ToProtoArray()},
+		{Id: gemOptimizerMeta, Color: proto.GemColor_GemColorMeta, Stats: stats.Stats{stats.Agility: 216}.ToProtoArray()},
+	},
+	ReforgeStats: []*proto.ReforgeStat{
+		{Id: 990201, FromStat: proto.Stat_StatCritRating, ToStat: proto.Stat_StatHitRating, Multiplier: 0.4},
+		{Id: 990202, FromStat: proto.Stat_StatCritRating, ToStat: proto.Stat_StatHasteRating, Multiplier: 0.4},
+		{Id: 990203, FromStat: proto.Stat_StatHasteRating, ToStat: proto.Stat_StatHitRating, Multiplier: 0.4},
+		{Id: 990204, FromStat: proto.Stat_StatHasteRating, ToStat: proto.Stat_StatCritRating, Multiplier: 0.4},
+	},
+}
+
+func runTestGearOptimizer(t *testing.T, request *proto.OptimizeGearRequest) *gearOptimizer {
+	return runTestGearOptimizerWithDeps(t, request, nil)
+}
+
+func runTestGearOptimizerWithDeps(t *testing.T, request *proto.OptimizeGearRequest, statDeps *stats.StatDependencyManager) *gearOptimizer {
+	addToDatabase(optimizerItemDatabase)
+
+	// Stats of the character are just the stats of their equipment.
+	equipment := ProtoToEquipment(request.Player.Equipment)
+
+	optimizer, err := newGearOptimizer(request, equipment.Stats(proto.Spec_SpecUnknown), statDeps)
+	if err != nil {
+		t.Fatalf("Failed to create optimizer: %v", err)
+	}
+	optimizer.optimize()
+	return optimizer
+}
+
+func TestOptimizeGearReforgesToHitCap(t *testing.T) {
+	optimizer := runTestGearOptimizer(t, &proto.OptimizeGearRequest{
+		Player: &proto.Player{Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{
+			{Id: itemOptimizerCritHelm},
+			{Id: itemOptimizerHasteRing},
+		}}},
+		EpWeights:        &proto.UnitStats{Stats: stats.Stats{stats.HitRating: 2, stats.CritRating: 1, stats.HasteRating: 0.8}.ToProtoArray()},
+		StatCaps:         &proto.UnitStats{Stats: stats.Stats{stats.HitRating: 400}.ToProtoArray()},
+		OptimizeReforges: true,
+	})
+
+	// Reaching the hit cap with the helm leaves the ring free to reforge into crit.
+	expected := stats.Stats{stats.HitRating: 400, stats.CritRating: 800, stats.HasteRating: 300}
+	if !optimizer.total.Equals(expected) {
+		t.Fatalf("Expected stats %s, got %s", expected, optimizer.total)
+	}
+}
+
+func TestOptimizeGearSocketBonus(t *testing.T) {
+	optimizer := runTestGearOptimizer(t, &proto.OptimizeGearRequest{
+		Player: &proto.Player{Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{
+			{Id: itemOptimizerGemChest},
+		}}},
+		EpWeights: &proto.UnitStats{Stats: stats.Stats{stats.CritRating: 1, stats.HasteRating: 0.9}.ToProtoArray()},
+		Gems:      []int32{gemOptimizerCrit, gemOptimizerHaste, gemOptimizerCritHaste},
+	})
+
+	// The orange gem keeps the socket bonus while being worth more than the yellow one.
+	gems := optimizer.toEquipmentSpecProto().Items[0].Gems
+	if len(gems) != 2 || gems[0] != gemOptimizerCrit || gems[1] != gemOptimizerCritHaste {
+		t.Fatalf("Expected crit and crit/haste gems, got %v", gems)
+	}
+}
+
+func TestOptimizeGearMetaRequirements(t *testing.T) {
+	request := &proto.OptimizeGearRequest{
+		Player: &proto.Player{Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{
+			{Id: itemOptimizerGemHelm, Gems: []int32{gemOptimizerMeta}},
+			{Id: itemOptimizerGemBelt},
+		}}},
+		EpWeights: &proto.UnitStats{Stats: stats.Stats{stats.CritRating: 1, stats.HasteRating: 2}.ToProtoArray()},
+		Gems:      []int32{gemOptimizerCrit, gemOptimizerHaste, gemOptimizerCritHaste},
+	}
+
+	if optimizer := runTestGearOptimizer(t, request); optimizer.totalColors.Red != 0 {
+		t.Fatalf("Expected only haste gems without meta requirements, got %d red gems", optimizer.totalColors.Red)
+	}
+
+	request.EnsureMetaReqMet = true
+	optimizer := runTestGearOptimizer(t, request)
+	if !MetaGemConditions[gemOptimizerMeta].IsMet(optimizer.totalColors) {
+		t.Fatalf("Expected meta gem requirements to be met, got %d red gems", optimizer.totalColors.Red)
+	}
+	if optimizer.metaGemID() != gemOptimizerMeta {
+		t.Fatalf("Expected meta gem to stay equipped")
+	}
+}
+
+func TestOptimizeGearStatDependencies(t *testing.T) {
+	request := &proto.OptimizeGearRequest{
+		Player: &proto.Player{Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{
+			{Id: itemOptimizerGemChest},
+		}}},
+		EpWeights: &proto.UnitStats{Stats: stats.Stats{stats.SpellPower: 1}.ToProtoArray()},
+		Gems:      []int32{gemOptimizerInt, gemOptimizerSpell},
+	}
+
+	if gems := runTestGearOptimizer(t, request).toEquipmentSpecProto().Items[0].Gems; gems[0] != gemOptimizerSpell {
+		t.Fatalf("Expected the spell power gem without stat dependencies, got %v", gems)
+	}
+
+	// With 1 spell power per intellect and a 20% intellect multiplier, the intellect gem is worth 96 spell power.
+	statDeps := stats.NewStatDependencyManager()
+	statDeps.AddStatDependency(stats.Intellect, stats.SpellPower, 1)
+	statDeps.MultiplyStat(stats.Intellect, 1.2)
+	statDeps.FinalizeStatDeps()
+
+	optimizer := runTestGearOptimizerWithDeps(t, request, &statDeps)
+	if gems := optimizer.toEquipmentSpecProto().Items[0].Gems; gems[0] != gemOptimizerInt {
+		t.Fatalf("Expected the intellect gem with stat dependencies, got %v", gems)
+	}
+}
+
+func TestOptimizeGearExtraSockets(t *testing.T) {
+	request := &proto.OptimizeGearRequest{
+		Player: &proto.Player{Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{
+			{Id: itemOptimizerGemBelt},
+			{Id: itemOptimizerHands},
+		}}},
+		EpWeights: &proto.UnitStats{Stats: stats.Stats{stats.CritRating: 1}.ToProtoArray()},
+		Gems:      []int32{gemOptimizerCrit},
+	}
+
+	items := runTestGearOptimizer(t, request).toEquipmentSpecProto().Items
+	if len(items[0].Gems) != 1 {
+		t.Fatalf("Expected no belt buckle socket on a belt without a buckle, got %v", items[0].Gems)
+	}
+	if len(items[1].Gems) != 0 {
+		t.Fatalf("Expected no extra socket on gloves without Blacksmithing, got %v", items[1].Gems)
+	}
+
+	request.Player.Profession1 = proto.Profession_Blacksmithing
+	items = runTestGearOptimizer(t, request).toEquipmentSpecProto().Items
+	if len(items[1].Gems) != 1 || items[1].Gems[0] != gemOptimizerCrit {
+		t.Fatalf("Expected the Blacksmithing socket on gloves to be filled, got %v", items[1].Gems)
+	}
+
+	request.AssumeBeltBuckle = true
+	items = runTestGearOptimizer(t, request).toEquipmentSpecProto().Items
+	if len(items[0].Gems) != 2 || items[0].Gems[1] != gemOptimizerCrit {
+		t.Fatalf("Expected the assumed belt buckle socket to be filled, got %v", items[0].Gems)
+	}
+
+	request.AssumeBeltBuckle = false
+	request.Player.Equipment.Items[0].Gems = []int32{gemOptimizerHaste, gemOptimizerHaste}
+	items = runTestGearOptimizer(t, request).toEquipmentSpecProto().Items
+	if len(items[0].Gems) != 2 || items[0].Gems[1] != gemOptimizerCrit {
+		t.Fatalf("Expected the equipped belt buckle socket to be filled, got %v", items[0].Gems)
+	}
+}
+
+func TestOptimizeGearEnchantRestrictions(t *testing.T) {
+	request := &proto.OptimizeGearRequest{
+		Player: &proto.Player{Equipment: &proto.EquipmentSpec{Items: []*proto.ItemSpec{
+			{Id: itemOptimizerOneHand},
+			{Id: itemOptimizerHands},
+		}}},
+		EpWeights: &proto.UnitStats{Stats: stats.Stats{stats.CritRating: 1}.ToProtoArray()},
+		Enchants:  []int32{enchantOptimizerTwoHand, enchantOptimizerWeapon, enchantOptimizerLeatherworker},
+	}
+
+	items := runTestGearOptimizer(t, request).toEquipmentSpecProto().Items
+	if items[0].Enchant != enchantOptimizerWeapon {
+		t.Fatalf("Expected the one-hander to skip the two-hand enchant, got %d", items[0].Enchant)
+	}
+	if items[1].Enchant != 0 {
+		t.Fatalf("Expected no Leatherworking enchant without Leatherworking, got %d", items[1].Enchant)
+	}
+
+	request.Player.Profession2 = proto.Profession_Leatherworking
+	items = runTestGearOptimizer(t, request).toEquipmentSpecProto().Items
+	if items[1].Enchant != enchantOptimizerLeatherworker {
+		t.Fatalf("Expected the Leatherworking enchant on gloves, got %d", items[1].Enchant)
+	}
+}
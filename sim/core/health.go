@@ -55,7 +55,15 @@ func (hb *healthBar) GainHealth(sim *Simulation, amount float64, metrics *Resour
 	metrics.AddEvent(amount, newHealth-oldHealth)
 
 	if sim.Log != nil {
-		hb.unit.Log(sim, "Gained %0.3f health from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldHealth, newHealth, hb.MaxHealth())
+		hb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "health",
+			Action: metrics.ActionID.ToProto(),
+			Amount: amount,
+			Before: oldHealth,
+			After:  newHealth,
+			Max:    hb.MaxHealth(),
+		})
 	}
 
 	hb.currentHealth = newHealth
@@ -81,7 +89,16 @@ func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
 	}
 
 	if sim.Log != nil {
-		hb.unit.Log(sim, "Spent %0.3f health from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldHealth, newHealth, hb.MaxHealth())
+		hb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "health",
+			Action: metrics.ActionID.ToProto(),
+			Spent:  true,
+			Amount: amount,
+			Before: oldHealth,
+			After:  newHealth,
+			Max:    hb.MaxHealth(),
+		})
 	}
 
 	hb.currentHealth = newHealth
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type addonExport struct {
+	Class       string `json:"class"`
+	Race        string `json:"race"`
+	Professions []struct {
+		Name string `json:"name"`
+	} `json:"professions"`
+	Talents string `json:"talents"`
+	Glyphs  struct {
+		Major []json.RawMessage `json:"major"`
+		Minor []json.RawMessage `json:"minor"`
+	} `json:"glyphs"`
+	Gear struct {
+		Items []map[string]any `json:"items"`
+	} `json:"gear"`
+}
+
+// Parses the output of the WowSimsExporter in-game addon.
+func ParseAddonExport(data string) (*Character, error) {
+	export := &addonExport{}
+	if err := json.Unmarshal([]byte(data), export); err != nil {
+		return nil, errors.New("please use a valid addon export")
+	}
+
+	var err error
+	c := &Character{TalentsString: export.Talents}
+	if c.Class, err = parseClass(export.Class); err != nil {
+		return nil, err
+	}
+	if c.Race, err = parseRace(export.Race); err != nil {
+		return nil, err
+	}
+	for _, professionData := range export.Professions {
+		profession, err := parseProfession(professionData.Name)
+		if err != nil {
+			return nil, err
+		}
+		c.Professions = append(c.Professions, profession)
+	}
+
+	majorGlyphs := c.addonGlyphIDs(export.Glyphs.Major)
+	minorGlyphs := c.addonGlyphIDs(export.Glyphs.Minor)
+	c.Glyphs = &proto.Glyphs{
+		Major1: majorGlyphs[0],
+		Major2: majorGlyphs[1],
+		Major3: majorGlyphs[2],
+		Minor1: minorGlyphs[0],
+		Minor2: minorGlyphs[1],
+		Minor3: minorGlyphs[2],
+	}
+
+	items, err := parseAddonGear(export.Gear.Items)
+	if err != nil {
+		return nil, err
+	}
+	c.setItems(items)
+	return c, nil
+}
+
+// Returns the item IDs of the glyphs, padded to 3 glyphs.
+func (c *Character) addonGlyphIDs(glyphs []json.RawMessage) []int32 {
+	ids := make([]int32, 3)
+	for i, glyph := range glyphs[:min(len(glyphs), len(ids))] {
+		var name string
+		if err := json.Unmarshal(glyph, &name); err == nil {
+			// Older addon versions export glyphs by their English name only, which the
+			// database doesn't contain.
+			if name != "" {
+				c.warnf("Glyph %q is exported by name, which is not supported", name)
+			}
+			continue
+		}
+
+		var spell struct {
+			SpellID int32 `json:"spellID"`
+		}
+		if err := json.Unmarshal(glyph, &spell); err != nil {
+			c.warnf("Could not parse glyph %s", glyph)
+			continue
+		}
+		ids[i] = c.glyphItemID(spell.SpellID)
+	}
+	return ids
+}
+
+// The gear is an EquipmentSpec in JSON format, except that empty slots and gem sockets are
+// null and it has a version field.
+func parseAddonGear(items []map[string]any) ([]*proto.ItemSpec, error) {
+	var itemSpecs []*proto.ItemSpec
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if gems, ok := item["gems"].([]any); ok {
+			for i, gem := range gems {
+				if gem == nil {
+					gems[i] = 0
+				}
+			}
+		}
+
+		itemJson, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		itemSpec := &proto.ItemSpec{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(itemJson, itemSpec); err != nil {
+			return nil, fmt.Errorf("could not parse item %s: %w", itemJson, err)
+		}
+		itemSpecs = append(itemSpecs, itemSpec)
+	}
+	return itemSpecs, nil
+}
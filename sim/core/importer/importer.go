@@ -0,0 +1,273 @@
+// Package importer parses characters exported by other tools, the same way as the importers
+// of the individual sim UI, so they can be simmed without the UI.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// The character settings contained in an export.
+type Character struct {
+	Class         proto.Class
+	Race          proto.Race
+	Items         []*proto.ItemSpec // In the order of the export. Use Equipment to assign them to slots.
+	TalentsString string            // Empty if the export has no talents.
+	Glyphs        *proto.Glyphs     // Nil if the export has no glyphs.
+	Professions   []proto.Profession
+
+	// Parts of the export that were left out, e.g. because they are not in the database.
+	Warnings []string
+}
+
+type Format string
+
+const (
+	FormatAddon              Format = "addon"
+	FormatSixtyUpgrades      Format = "60u"
+	FormatWowheadGearPlanner Format = "wowhead"
+)
+
+var Formats = []Format{FormatAddon, FormatSixtyUpgrades, FormatWowheadGearPlanner}
+
+// Guesses the format of an export.
+func DetectFormat(data string) (Format, error) {
+	data = strings.TrimSpace(data)
+	if wowheadLinkRegexp.MatchString(data) {
+		return FormatWowheadGearPlanner, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return "", errors.New("unrecognized export, expected an addon or Sixty Upgrades export, or a Wowhead gear planner link")
+	}
+	if _, ok := fields["character"]; ok {
+		return FormatSixtyUpgrades, nil
+	}
+	return FormatAddon, nil
+}
+
+// Parses an export in the given format, or any format if format is empty.
+func Parse(format Format, data string) (*Character, error) {
+	if format == "" {
+		var err error
+		if format, err = DetectFormat(data); err != nil {
+			return nil, err
+		}
+	}
+
+	switch format {
+	case FormatAddon:
+		return ParseAddonExport(data)
+	case FormatSixtyUpgrades:
+		return ParseSixtyUpgradesExport(data)
+	case FormatWowheadGearPlanner:
+		return ParseWowheadGearPlannerLink(data)
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+}
+
+// Returns a player with the imported settings.
+func (c *Character) Player() (*proto.Player, error) {
+	player := &proto.Player{}
+	if err := c.applyToPlayer(player); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+// Returns a copy of the settings with the imported settings applied to their player, like
+// importing in the individual sim UI. Settings the export doesn't contain, e.g. the spec
+// options, rotation and consumes, are kept.
+func (c *Character) ApplyTo(settings *proto.IndividualSimSettings) (*proto.IndividualSimSettings, error) {
+	if settings == nil {
+		settings = &proto.IndividualSimSettings{}
+	}
+	settings = googleProto.Clone(settings).(*proto.IndividualSimSettings)
+	if settings.Player == nil {
+		settings.Player = &proto.Player{}
+	}
+	if class := settings.Player.Class; class != proto.Class_ClassUnknown && class != c.Class {
+		return nil, fmt.Errorf("wrong class, expected %s but found %s", className(class), className(c.Class))
+	}
+	if err := c.applyToPlayer(settings.Player); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (c *Character) applyToPlayer(player *proto.Player) error {
+	// Which slots the items fit in depends on the spec, which the exports don't contain.
+	equipment, err := c.Equipment(player.GetFuryWarrior() != nil)
+	if err != nil {
+		return err
+	}
+	player.Class = c.Class
+	player.Race = c.Race
+	player.Equipment = equipment
+	if c.TalentsString != "" && c.TalentsString != "--" {
+		player.TalentsString = c.TalentsString
+	}
+	if c.Glyphs != nil {
+		player.Glyphs = googleProto.Clone(c.Glyphs).(*proto.Glyphs)
+	}
+	if len(c.Professions) > 0 {
+		player.Profession1 = c.Professions[0]
+		player.Profession2 = proto.Profession_ProfessionUnknown
+		if len(c.Professions) > 1 {
+			player.Profession2 = c.Professions[1]
+		}
+	}
+	return nil
+}
+
+func (c *Character) warnf(format string, args ...any) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Looks up the items in the database, like lookupEquipmentSpec in the UI. Anything
+// that isn't in the database is left out.
+func (c *Character) setItems(items []*proto.ItemSpec) {
+	for _, itemSpec := range items {
+		if itemSpec == nil || itemSpec.Id == 0 {
+			continue
+		}
+		if core.GetItemByID(itemSpec.Id) == nil {
+			c.warnf("Item %d is not in the database", itemSpec.Id)
+			continue
+		}
+		c.lookupItemSpec(itemSpec)
+		c.Items = append(c.Items, itemSpec)
+	}
+}
+
+// Assigns the items to their slots, indexed by slot. Fury warriors may equip
+// two-handers in both hands.
+func (c *Character) Equipment(isFuryWarrior bool) (*proto.EquipmentSpec, error) {
+	equipment := &proto.EquipmentSpec{Items: make([]*proto.ItemSpec, core.NumItemSlots)}
+	for _, itemSpec := range c.Items {
+		assigned := false
+		for _, slot := range core.EligibleSlotsForItem(core.GetItemByID(itemSpec.Id), isFuryWarrior) {
+			if equipment.Items[slot] == nil {
+				equipment.Items[slot] = googleProto.Clone(itemSpec).(*proto.ItemSpec)
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			return nil, fmt.Errorf("no slots left to equip item %d", itemSpec.Id)
+		}
+	}
+
+	for i, itemSpec := range equipment.Items {
+		if itemSpec == nil {
+			equipment.Items[i] = &proto.ItemSpec{}
+		}
+	}
+	return equipment, nil
+}
+
+func (c *Character) lookupItemSpec(itemSpec *proto.ItemSpec) {
+	if itemSpec.Enchant != 0 && core.GetEnchantByEffectID(itemSpec.Enchant) == nil {
+		if enchant := core.GetEnchantBySpellID(itemSpec.Enchant); enchant != nil {
+			itemSpec.Enchant = enchant.EffectID
+		} else {
+			c.warnf("Enchant %d on item %d is not in the database", itemSpec.Enchant, itemSpec.Id)
+			itemSpec.Enchant = 0
+		}
+	}
+	if itemSpec.Tinker != 0 && core.GetEnchantByEffectID(itemSpec.Tinker) == nil {
+		c.warnf("Tinker %d on item %d is not in the database", itemSpec.Tinker, itemSpec.Id)
+		itemSpec.Tinker = 0
+	}
+	for i, gemID := range itemSpec.Gems {
+		if _, ok := core.GemsByID[gemID]; gemID != 0 && !ok {
+			c.warnf("Gem %d on item %d is not in the database", gemID, itemSpec.Id)
+			itemSpec.Gems[i] = 0
+		}
+	}
+	if _, ok := core.RandomSuffixesByID[itemSpec.RandomSuffix]; itemSpec.RandomSuffix != 0 && !ok {
+		c.warnf("Random suffix %d on item %d is not in the database", itemSpec.RandomSuffix, itemSpec.Id)
+		itemSpec.RandomSuffix = 0
+	}
+	if _, ok := core.ReforgeStatsByID[itemSpec.Reforging]; itemSpec.Reforging != 0 && !ok {
+		c.warnf("Reforge %d on item %d is not in the database", itemSpec.Reforging, itemSpec.Id)
+		itemSpec.Reforging = 0
+	}
+}
+
+// Returns the item ID of the glyph with the given spell ID, or 0 if it isn't in the database.
+func (c *Character) glyphItemID(spellID int32) int32 {
+	if spellID == 0 {
+		return 0
+	}
+	itemID, ok := core.GlyphItemIDsBySpellID[spellID]
+	if !ok {
+		c.warnf("Glyph %d is not in the database", spellID)
+	}
+	return itemID
+}
+
+func normalizeName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(name))
+}
+
+var raceNames = map[proto.Race]string{
+	proto.Race_RaceBloodElf:         "Blood Elf",
+	proto.Race_RaceDraenei:          "Draenei",
+	proto.Race_RaceDwarf:            "Dwarf",
+	proto.Race_RaceGnome:            "Gnome",
+	proto.Race_RaceGoblin:           "Goblin",
+	proto.Race_RaceHuman:            "Human",
+	proto.Race_RaceNightElf:         "Night Elf",
+	proto.Race_RaceOrc:              "Orc",
+	proto.Race_RaceAlliancePandaren: "Pandaren (A)",
+	proto.Race_RaceHordePandaren:    "Pandaren (H)",
+	proto.Race_RaceTauren:           "Tauren",
+	proto.Race_RaceTroll:            "Troll",
+	proto.Race_RaceUndead:           "Undead",
+	proto.Race_RaceWorgen:           "Worgen",
+}
+
+func parseRace(name string) (proto.Race, error) {
+	normalized := normalizeName(name)
+	for race, raceName := range raceNames {
+		if normalizeName(raceName) == normalized {
+			return race, nil
+		}
+	}
+	return proto.Race_RaceUnknown, fmt.Errorf("could not parse race %q", name)
+}
+
+func className(class proto.Class) string {
+	return strings.TrimPrefix(class.String(), "Class")
+}
+
+func parseClass(name string) (proto.Class, error) {
+	normalized := normalizeName(name)
+	for value := range proto.Class_name {
+		class := proto.Class(value)
+		if class != proto.Class_ClassUnknown && !strings.HasPrefix(className(class), "Extra") && normalizeName(className(class)) == normalized {
+			return class, nil
+		}
+	}
+	return proto.Class_ClassUnknown, fmt.Errorf("could not parse class %q", name)
+}
+
+func parseProfession(name string) (proto.Profession, error) {
+	normalized := normalizeName(name)
+	for value, professionName := range proto.Profession_name {
+		profession := proto.Profession(value)
+		if profession != proto.Profession_ProfessionUnknown && normalizeName(professionName) == normalized {
+			return profession, nil
+		}
+	}
+	return proto.Profession_ProfessionUnknown, fmt.Errorf("could not parse profession %q", name)
+}
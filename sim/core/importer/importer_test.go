@@ -0,0 +1,288 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	testHelm         = 1001
+	testRing         = 1002
+	testTwoHander    = 1003
+	testGem          = 2001
+	testEnchant      = 3001 // Effect ID.
+	testEnchantSpell = 3002
+	testGlyphSpell   = 4001
+	testGlyphItem    = 4002
+)
+
+func init() {
+	core.ItemsByID[testHelm] = core.Item{ID: testHelm, Type: proto.ItemType_ItemTypeHead}
+	core.ItemsByID[testRing] = core.Item{ID: testRing, Type: proto.ItemType_ItemTypeFinger}
+	core.ItemsByID[testTwoHander] = core.Item{ID: testTwoHander, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeTwoHand}
+	core.GemsByID[testGem] = core.Gem{ID: testGem}
+	enchant := core.Enchant{EffectID: testEnchant, SpellID: testEnchantSpell, Type: proto.ItemType_ItemTypeHead}
+	core.EnchantsByEffectID[testEnchant] = enchant
+	core.EnchantsBySpellID[testEnchantSpell] = enchant
+	core.GlyphItemIDsBySpellID[testGlyphSpell] = testGlyphItem
+}
+
+func expectItems(t *testing.T, equipment *proto.EquipmentSpec, expected map[proto.ItemSlot]*proto.ItemSpec) {
+	t.Helper()
+	if len(equipment.Items) != int(core.NumItemSlots) {
+		t.Fatalf("Expected %d item slots, got %d", core.NumItemSlots, len(equipment.Items))
+	}
+	for i, item := range equipment.Items {
+		slot := proto.ItemSlot(i)
+		want, ok := expected[slot]
+		if !ok {
+			want = &proto.ItemSpec{}
+		}
+		if !googleProto.Equal(item, want) {
+			t.Errorf("Expected %s to be %v, got %v", slot, want, item)
+		}
+	}
+}
+
+func equipment(t *testing.T, c *Character, isFuryWarrior bool) *proto.EquipmentSpec {
+	t.Helper()
+	equipment, err := c.Equipment(isFuryWarrior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return equipment
+}
+
+func expectWarning(t *testing.T, c *Character, substr string) {
+	t.Helper()
+	for _, warning := range c.Warnings {
+		if strings.Contains(warning, substr) {
+			return
+		}
+	}
+	t.Errorf("Expected a warning containing %q, got %q", substr, c.Warnings)
+}
+
+func TestParseAddonExport(t *testing.T) {
+	c, err := ParseAddonExport(`{
+		"class": "warrior",
+		"race": "NightElf",
+		"professions": [{"name": "Blacksmithing", "level": 600}, {"name": "Mining", "level": 600}],
+		"talents": "113332",
+		"glyphs": {"major": [{"spellID": 4001}, "Glyph of Something"], "minor": []},
+		"gear": {
+			"version": "v2",
+			"items": [
+				{"id": 1001, "enchant": 3001, "gems": [2001, null], "reforging": 999},
+				null,
+				{"id": 1002},
+				{"id": 1003},
+				{"id": 1003},
+				{"id": 9999}
+			]
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Class != proto.Class_ClassWarrior || c.Race != proto.Race_RaceNightElf {
+		t.Errorf("Expected a night elf warrior, got %s %s", c.Race, c.Class)
+	}
+	if len(c.Professions) != 2 || c.Professions[0] != proto.Profession_Blacksmithing || c.Professions[1] != proto.Profession_Mining {
+		t.Errorf("Expected blacksmithing and mining, got %v", c.Professions)
+	}
+	if c.TalentsString != "113332" {
+		t.Errorf("Expected talents 113332, got %s", c.TalentsString)
+	}
+	if !googleProto.Equal(c.Glyphs, &proto.Glyphs{Major1: testGlyphItem}) {
+		t.Errorf("Expected only the first major glyph, got %v", c.Glyphs)
+	}
+	// Only fury warriors can equip both two-handers.
+	if _, err := c.Equipment(false); err == nil {
+		t.Errorf("Expected an error when equipping two two-handers without fury")
+	}
+	expectItems(t, equipment(t, c, true), map[proto.ItemSlot]*proto.ItemSpec{
+		proto.ItemSlot_ItemSlotHead:     {Id: testHelm, Enchant: testEnchant, Gems: []int32{testGem, 0}},
+		proto.ItemSlot_ItemSlotFinger1:  {Id: testRing},
+		proto.ItemSlot_ItemSlotMainHand: {Id: testTwoHander},
+		proto.ItemSlot_ItemSlotOffHand:  {Id: testTwoHander},
+	})
+	expectWarning(t, c, "Glyph \"Glyph of Something\"")
+	expectWarning(t, c, "Reforge 999")
+	expectWarning(t, c, "Item 9999")
+}
+
+func TestParseSixtyUpgradesExport(t *testing.T) {
+	c, err := ParseSixtyUpgradesExport(`{
+		"character": {"gameClass": "DEATHKNIGHT", "race": "Pandaren (H)"},
+		"talents": [{"spellId": 123}],
+		"items": [
+			{"id": 1001, "name": "Helm", "enchant": {"id": 3002}, "gems": [{"id": 2001}, null, {"id": 0}], "reforge": null},
+			{"id": 1002, "name": "Ring", "suffixId": 5, "reforge": {"id": 999}}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Class != proto.Class_ClassDeathKnight || c.Race != proto.Race_RaceHordePandaren {
+		t.Errorf("Expected a horde pandaren death knight, got %s %s", c.Race, c.Class)
+	}
+	if c.Glyphs != nil || c.TalentsString != "" {
+		t.Errorf("Expected no glyphs or talents, got %v and %q", c.Glyphs, c.TalentsString)
+	}
+	expectItems(t, equipment(t, c, false), map[proto.ItemSlot]*proto.ItemSpec{
+		proto.ItemSlot_ItemSlotHead:    {Id: testHelm, Enchant: testEnchant, Gems: []int32{testGem}},
+		proto.ItemSlot_ItemSlotFinger1: {Id: testRing},
+	})
+	expectWarning(t, c, "Talents")
+	expectWarning(t, c, "random suffix and reforge of Ring")
+}
+
+// Encodes a number the way wowheadHashReader.readBits decodes it.
+func encodeWowheadNumber(value int32) string {
+	var offset int32
+	for numDigits := 1; ; numDigits++ {
+		capacity := int32(1) << (5 * numDigits)
+		if value < offset+capacity {
+			value -= offset
+			digits := make([]byte, numDigits)
+			for i := numDigits - 1; i > 0; i-- {
+				digits[i] = wowheadHashChars[value&63]
+				value >>= 6
+			}
+			prefix := int32(63) &^ (63 >> (numDigits - 1))
+			digits[0] = wowheadHashChars[prefix|value]
+			return string(digits)
+		}
+		offset += capacity
+	}
+}
+
+func TestWowheadHashReader(t *testing.T) {
+	for _, value := range []int32{0, 1, 31, 32, 33, 1055, 1056, 12345, 40000, 1 << 20} {
+		encoded := encodeWowheadNumber(value)
+		r := &wowheadHashReader{}
+		for i := range encoded {
+			r.digits = append(r.digits, int32(strings.IndexByte(wowheadHashChars, encoded[i])))
+		}
+		if decoded := r.readBits(); decoded != value || len(r.digits) != 0 {
+			t.Errorf("Expected %d to decode from %q, got %d with %d digits left", value, encoded, decoded, len(r.digits))
+		}
+	}
+}
+
+func TestParseWowheadGearPlannerLink(t *testing.T) {
+	// The glyph string skips its first character, then has a slot index and a 4 digit spell ID.
+	glyphs := "0" + "3" + "03x1" + "6" + "zzzz"
+	hash := "B" +
+		encodeWowheadNumber(1) + // Gender.
+		encodeWowheadNumber(90) + // Level.
+		encodeWowheadNumber(6) + encodeWowheadNumber(0o123123) + // Talents of the first tree.
+		encodeWowheadNumber(0) + encodeWowheadNumber(0) +
+		encodeWowheadNumber(int32(len(glyphs))) + glyphs +
+		encodeWowheadNumber(3) + // Items.
+		// Head with a reforge, 1 gem and 1 enchant.
+		encodeWowheadNumber(1<<5|1<<2|1) + encodeWowheadNumber(1) + encodeWowheadNumber(testHelm) +
+		encodeWowheadNumber(999) + encodeWowheadNumber(testGem) + encodeWowheadNumber(testEnchantSpell) +
+		// Ring with a random suffix.
+		encodeWowheadNumber(1<<6) + encodeWowheadNumber(11) + encodeWowheadNumber(testRing) + encodeWowheadNumber(5<<1|1) +
+		// Shirt, which is left out.
+		encodeWowheadNumber(0) + encodeWowheadNumber(4) + encodeWowheadNumber(1234)
+
+	if testGlyphSpell != (0<<15 | 3<<10 | 29<<5 | 1) {
+		t.Fatalf("Glyph string doesn't encode the test glyph")
+	}
+
+	c, err := ParseWowheadGearPlannerLink("https://www.wowhead.com/mop-classic/gear-planner/death-knight/night-elf/" + hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Class != proto.Class_ClassDeathKnight || c.Race != proto.Race_RaceNightElf {
+		t.Errorf("Expected a night elf death knight, got %s %s", c.Race, c.Class)
+	}
+	if c.TalentsString != "123123" {
+		t.Errorf("Expected talents 123123, got %s", c.TalentsString)
+	}
+	if !googleProto.Equal(c.Glyphs, &proto.Glyphs{Major1: testGlyphItem}) {
+		t.Errorf("Expected only the first major glyph, got %v", c.Glyphs)
+	}
+	expectItems(t, equipment(t, c, false), map[proto.ItemSlot]*proto.ItemSpec{
+		proto.ItemSlot_ItemSlotHead:    {Id: testHelm, Enchant: testEnchant, Gems: []int32{testGem}},
+		proto.ItemSlot_ItemSlotFinger1: {Id: testRing},
+	})
+	expectWarning(t, c, "Reforge 999")
+	expectWarning(t, c, "Random suffix -5")
+	expectWarning(t, c, "Glyph 1048575")
+}
+
+func TestDetectFormat(t *testing.T) {
+	for data, expected := range map[string]Format{
+		"https://www.wowhead.com/mop-classic/gear-planner/mage/gnome/BABC": FormatWowheadGearPlanner,
+		`{"character": {"gameClass": "MAGE"}}`:                             FormatSixtyUpgrades,
+		`{"class": "Mage"}`:                                                FormatAddon,
+	} {
+		if format, err := DetectFormat(data); err != nil || format != expected {
+			t.Errorf("Expected %q to be detected as %s, got %s (%v)", data, expected, format, err)
+		}
+	}
+	if _, err := DetectFormat("not an export"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestApplyTo(t *testing.T) {
+	c, err := Parse("", `{"class": "Warrior", "race": "Human", "professions": [], "talents": "", "glyphs": {"major": [], "minor": []}, "gear": {"items": [{"id": 1001}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := &proto.IndividualSimSettings{
+		Player: &proto.Player{
+			Class:         proto.Class_ClassWarrior,
+			Race:          proto.Race_RaceOrc,
+			TalentsString: "111111",
+			Profession1:   proto.Profession_Engineering,
+			Spec:          &proto.Player_FuryWarrior{},
+		},
+	}
+	settings, err := c.ApplyTo(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := settings.Player
+	if player.Race != proto.Race_RaceHuman || player.Equipment.Items[proto.ItemSlot_ItemSlotHead].Id != testHelm {
+		t.Errorf("Expected the race and gear to be imported, got %v", player)
+	}
+	if player.TalentsString != "111111" || player.Profession1 != proto.Profession_Engineering || player.GetFuryWarrior() == nil {
+		t.Errorf("Expected settings missing from the export to be kept, got %v", player)
+	}
+	if base.Player.Race != proto.Race_RaceOrc {
+		t.Errorf("Expected the base settings to be unchanged")
+	}
+
+	base.Player.Class = proto.Class_ClassMage
+	if _, err := c.ApplyTo(base); err == nil {
+		t.Errorf("Expected an error when importing a different class")
+	}
+
+	// The slots depend on the spec being imported into.
+	c, err = Parse("", `{"class": "Warrior", "race": "Human", "professions": [], "talents": "", "glyphs": {"major": [], "minor": []}, "gear": {"items": [{"id": 1003}, {"id": 1003}]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base.Player.Class = proto.Class_ClassWarrior
+	if settings, err := c.ApplyTo(base); err != nil || settings.Player.Equipment.Items[proto.ItemSlot_ItemSlotOffHand].Id != testTwoHander {
+		t.Errorf("Expected a fury warrior to equip both two-handers, got %v", err)
+	}
+	base.Player.Spec = &proto.Player_ArmsWarrior{}
+	if _, err := c.ApplyTo(base); err == nil {
+		t.Errorf("Expected an error when an arms warrior equips two two-handers")
+	}
+}
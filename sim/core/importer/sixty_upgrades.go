@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+type sixtyUpgradesID struct {
+	ID int32 `json:"id"`
+}
+
+type sixtyUpgradesExport struct {
+	Character struct {
+		GameClass string `json:"gameClass"`
+		Race      string `json:"race"`
+	} `json:"character"`
+	Talents []struct {
+		SpellID int32 `json:"spellId"`
+	} `json:"talents"`
+	Items []struct {
+		ID       int32              `json:"id"`
+		Name     string             `json:"name"`
+		Enchant  *sixtyUpgradesID   `json:"enchant"`
+		Gems     []*sixtyUpgradesID `json:"gems"`
+		Reforge  *sixtyUpgradesID   `json:"reforge"`
+		SuffixID int32              `json:"suffixId"`
+	} `json:"items"`
+}
+
+// Parses an export from Sixty Upgrades.
+func ParseSixtyUpgradesExport(data string) (*Character, error) {
+	export := &sixtyUpgradesExport{}
+	if err := json.Unmarshal([]byte(data), export); err != nil {
+		return nil, errors.New("please use a valid Sixty Upgrades export")
+	}
+
+	var err error
+	c := &Character{}
+	if c.Class, err = parseClass(export.Character.GameClass); err != nil {
+		return nil, err
+	}
+	if c.Race, err = parseRace(export.Character.Race); err != nil {
+		return nil, err
+	}
+	if len(export.Talents) > 0 {
+		// Talents are exported by spell ID, which the UI can't convert to a talents string yet either.
+		c.warnf("Talents are not supported for Sixty Upgrades exports")
+	}
+
+	var items []*proto.ItemSpec
+	for _, itemData := range export.Items {
+		itemSpec := &proto.ItemSpec{Id: itemData.ID}
+		if itemData.Enchant != nil {
+			itemSpec.Enchant = itemData.Enchant.ID
+		}
+		for _, gem := range itemData.Gems {
+			if gem != nil && gem.ID != 0 {
+				itemSpec.Gems = append(itemSpec.Gems, gem.ID)
+			}
+		}
+		// Sixty Upgrades exports the wrong random suffixes, so they are left out, along with the
+		// reforge which depends on them.
+		if itemData.SuffixID != 0 {
+			c.warnf("Removed the random suffix and reforge of %s, since Sixty Upgrades exports the wrong random suffixes", itemData.Name)
+		} else if itemData.Reforge != nil {
+			itemSpec.Reforging = itemData.Reforge.ID
+		}
+		items = append(items, itemSpec)
+	}
+	c.setItems(items)
+	return c, nil
+}
@@ -0,0 +1,254 @@
+package importer
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+var wowheadLinkRegexp = regexp.MustCompile(`mop-classic/gear-planner/([a-z-]+)/([a-z-]+)/([a-zA-Z0-9_-]+)`)
+
+const wowheadHashChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// Wowhead slot IDs of the slots the sim supports.
+var wowheadSlotIDs = map[int32]proto.ItemSlot{
+	1:  proto.ItemSlot_ItemSlotHead,
+	2:  proto.ItemSlot_ItemSlotNeck,
+	3:  proto.ItemSlot_ItemSlotShoulder,
+	15: proto.ItemSlot_ItemSlotBack,
+	5:  proto.ItemSlot_ItemSlotChest,
+	9:  proto.ItemSlot_ItemSlotWrist,
+	10: proto.ItemSlot_ItemSlotHands,
+	6:  proto.ItemSlot_ItemSlotWaist,
+	7:  proto.ItemSlot_ItemSlotLegs,
+	8:  proto.ItemSlot_ItemSlotFeet,
+	11: proto.ItemSlot_ItemSlotFinger1,
+	12: proto.ItemSlot_ItemSlotFinger2,
+	13: proto.ItemSlot_ItemSlotTrinket1,
+	14: proto.ItemSlot_ItemSlotTrinket2,
+	16: proto.ItemSlot_ItemSlotMainHand,
+	17: proto.ItemSlot_ItemSlotOffHand,
+}
+
+type wowheadItem struct {
+	slotID          int32
+	itemID          int32
+	randomEnchantID int32
+	reforge         int32
+	gemItemIDs      []int32
+	enchantIDs      []int32 // Spell IDs.
+}
+
+type wowheadGearPlanner struct {
+	talents string
+	glyphs  [9]int32 // Spell IDs, indexed by glyph slot.
+	items   []wowheadItem
+}
+
+// Reads the character hash of a gear planner link, which is a sequence of base 64 digits.
+type wowheadHashReader struct {
+	digits []int32
+}
+
+func (r *wowheadHashReader) shift() int32 {
+	if len(r.digits) == 0 {
+		return 0
+	}
+	digit := r.digits[0]
+	r.digits = r.digits[1:]
+	return digit
+}
+
+// Reads a variable length number. The number of leading 1 bits of the first digit is the
+// number of digits that follow.
+func (r *wowheadHashReader) readBits() int32 {
+	if len(r.digits) == 0 {
+		return 0
+	}
+	var offset int32
+	numDigits := 1
+	for n := r.digits[0]; n&32 != 0; n <<= 1 {
+		numDigits++
+	}
+	value := r.shift() & (63 >> numDigits)
+	for i := 1; i < numDigits; i++ {
+		offset += 1 << (5 * i)
+		value = value<<6 | r.shift()
+	}
+	return value + offset
+}
+
+// Reads the talents of up to 3 trees, as octal digits.
+func (r *wowheadHashReader) readTalents() string {
+	var talents strings.Builder
+	for tree := 0; tree < 3; tree++ {
+		remaining := r.readBits()
+		for remaining > 0 {
+			group := ""
+			bits := r.readBits()
+			for remaining > 0 && len(group) < 7 {
+				group = string(rune('0'+bits&7)) + group
+				bits >>= 3
+				remaining--
+			}
+			talents.WriteString(group)
+		}
+		talents.WriteString("-")
+	}
+	return strings.TrimRight(talents.String(), "-")
+}
+
+func parseWowheadHash(hash string) (*wowheadGearPlanner, error) {
+	version := strings.IndexByte(wowheadHashChars, hash[0])
+	if version > 1 {
+		return nil, errors.New("unsupported Wowhead gear planner link version")
+	}
+	r := &wowheadHashReader{}
+	for i := 1; i < len(hash); i++ {
+		r.digits = append(r.digits, int32(strings.IndexByte(wowheadHashChars, hash[i])))
+	}
+
+	planner := &wowheadGearPlanner{}
+	r.readBits() // Gender.
+	r.readBits() // Level.
+
+	planner.talents = r.readTalents()
+	numGlyphDigits := int(r.readBits())
+	if numGlyphDigits > len(r.digits) {
+		return nil, errors.New("invalid Wowhead gear planner link")
+	}
+	var glyphs strings.Builder
+	for _, digit := range r.digits[:numGlyphDigits] {
+		glyphs.WriteByte(wowheadHashChars[digit])
+	}
+	r.digits = r.digits[numGlyphDigits:]
+	planner.glyphs = parseWowheadGlyphs(glyphs.String())
+
+	for numItems := r.readBits(); numItems > 0; numItems-- {
+		var flags int32
+		if version < 1 {
+			flags = r.shift()
+			if (flags>>5)&1 != 0 {
+				flags = flags&31 | 64
+			} else {
+				flags &= 31
+			}
+		} else {
+			flags = r.readBits()
+		}
+
+		item := wowheadItem{
+			slotID: r.readBits(),
+			itemID: r.readBits(),
+		}
+		if (flags>>6)&1 != 0 {
+			randomEnchantID := r.readBits()
+			negative := randomEnchantID&1 != 0
+			randomEnchantID >>= 1
+			if negative {
+				randomEnchantID = -randomEnchantID
+			}
+			item.randomEnchantID = randomEnchantID
+		}
+		if (flags>>5)&1 != 0 {
+			item.reforge = r.readBits()
+		}
+		for numGems := (flags >> 2) & 7; numGems > 0; numGems-- {
+			item.gemItemIDs = append(item.gemItemIDs, r.readBits())
+		}
+		for numEnchants := flags & 3; numEnchants > 0; numEnchants-- {
+			item.enchantIDs = append(item.enchantIDs, r.readBits())
+		}
+		planner.items = append(planner.items, item)
+	}
+	return planner, nil
+}
+
+// Glyphs are a slot index followed by a spell ID of 4 base 32 digits. The first character
+// is skipped.
+func parseWowheadGlyphs(glyphs string) [9]int32 {
+	const base32Chars = "0123456789abcdefghjkmnpqrstvwxyz"
+
+	var spellIDs [9]int32
+	for cur := 1; cur < len(glyphs); {
+		slot := strings.IndexByte(base32Chars, glyphs[cur])
+		cur++
+		if slot < 0 || slot >= len(spellIDs) {
+			continue
+		}
+		if cur+4 > len(glyphs) {
+			break
+		}
+
+		var spellID int32
+		valid := true
+		for _, char := range []byte(glyphs[cur : cur+4]) {
+			digit := strings.IndexByte(base32Chars, char)
+			valid = valid && digit >= 0
+			spellID = spellID<<5 | int32(digit)
+		}
+		cur += 4
+		if valid {
+			spellIDs[slot] = spellID
+		}
+	}
+	return spellIDs
+}
+
+// Parses a link to the Wowhead gear planner, like
+// https://www.wowhead.com/mop-classic/gear-planner/CLASS/RACE/HASH.
+func ParseWowheadGearPlannerLink(link string) (*Character, error) {
+	match := wowheadLinkRegexp.FindStringSubmatch(link)
+	if match == nil {
+		return nil, errors.New(`invalid Wowhead link, must look like "https://www.wowhead.com/mop-classic/gear-planner/CLASS/RACE/XXXX"`)
+	}
+
+	var err error
+	c := &Character{}
+	if c.Class, err = parseClass(match[1]); err != nil {
+		return nil, err
+	}
+	if c.Race, err = parseRace(match[2]); err != nil {
+		return nil, err
+	}
+
+	planner, err := parseWowheadHash(match[3])
+	if err != nil {
+		return nil, err
+	}
+	c.TalentsString = planner.talents
+	c.Glyphs = &proto.Glyphs{
+		Major1: c.glyphItemID(planner.glyphs[3]),
+		Major2: c.glyphItemID(planner.glyphs[4]),
+		Major3: c.glyphItemID(planner.glyphs[5]),
+		Minor1: c.glyphItemID(planner.glyphs[6]),
+		Minor2: c.glyphItemID(planner.glyphs[7]),
+		Minor3: c.glyphItemID(planner.glyphs[8]),
+	}
+
+	var items []*proto.ItemSpec
+	for _, item := range planner.items {
+		if _, ok := wowheadSlotIDs[item.slotID]; !ok {
+			continue
+		}
+		itemSpec := &proto.ItemSpec{
+			Id:           item.itemID,
+			RandomSuffix: item.randomEnchantID,
+			Reforging:    item.reforge,
+			Gems:         item.gemItemIDs,
+		}
+		if len(item.enchantIDs) > 0 {
+			if enchant := core.GetEnchantBySpellID(item.enchantIDs[0]); enchant != nil {
+				itemSpec.Enchant = enchant.EffectID
+			} else {
+				c.warnf("Enchant %d on item %d is not in the database", item.enchantIDs[0], item.itemID)
+			}
+		}
+		items = append(items, itemSpec)
+	}
+	c.setItems(items)
+	return c, nil
+}
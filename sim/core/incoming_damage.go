@@ -0,0 +1,98 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const defaultIncomingDamageDummyHealth = 400_000.0
+
+// Applies periodic damage from the primary target to every target dummy, so
+// that healers have a realistic amount of missing health to work with. The
+// first dummy stands in for the tank and can be configured to take additional
+// damage.
+func (raid *Raid) applyIncomingDamageModel(model *proto.IncomingDamageModel) {
+	dummies := raid.GetTargetDummies()
+	if len(dummies) == 0 {
+		return
+	}
+
+	cadence := model.CadenceSeconds
+	if cadence == 0 {
+		cadence = 1.0
+	}
+	variation := Clamp(model.DamageVariation, 0, 1)
+
+	dummyHealth := model.DummyHealth
+	if dummyHealth == 0 {
+		dummyHealth = defaultIncomingDamageDummyHealth
+	}
+
+	// Damage is dealt through a real spell so that absorbs and damage taken
+	// modifiers on the dummies work as they would on players.
+	boss := dummies[0].Env.Encounter.TargetUnits[0]
+	incomingDamageSpell := boss.RegisterSpell(SpellConfig{
+		ActionID:    ActionID{OtherID: proto.OtherAction_OtherActionIncomingDamage},
+		SpellSchool: SpellSchoolPhysical,
+		ProcMask:    ProcMaskEmpty,
+		Flags:       SpellFlagIgnoreAttackerModifiers | SpellFlagNoOnCastComplete | SpellFlagPassiveSpell,
+
+		DamageMultiplier: 1,
+	})
+
+	for i, dummy := range dummies {
+		dummy.AddStat(stats.Health, dummyHealth-dummy.GetStat(stats.Health))
+		dummy.registerDamageTaken()
+
+		dtps := model.Dtps
+		if i == 0 {
+			dtps += model.TankDtps
+		}
+
+		if dtps > 0 {
+			dummy.registerIncomingDamage(incomingDamageSpell, dtps, cadence, variation)
+		}
+	}
+}
+
+// Removes health for all damage taken, including damage from the boss that
+// isn't part of the incoming damage model.
+func (td *TargetDummy) registerDamageTaken() {
+	// Dummies never die, since dead units can't be healed.
+	removeHealth := func(sim *Simulation, result *SpellResult) {
+		if damage := min(result.Damage, td.CurrentHealth()-1); damage > 0 {
+			td.RemoveHealth(sim, damage)
+		}
+	}
+
+	MakePermanent(td.RegisterAura(Aura{
+		Label: "Incoming Damage",
+		OnSpellHitTaken: func(aura *Aura, sim *Simulation, spell *Spell, result *SpellResult) {
+			removeHealth(sim, result)
+		},
+		OnPeriodicDamageTaken: func(aura *Aura, sim *Simulation, spell *Spell, result *SpellResult) {
+			removeHealth(sim, result)
+		},
+	}))
+}
+
+func (td *TargetDummy) registerIncomingDamage(spell *Spell, dtps float64, cadenceSeconds float64, variation float64) {
+	cadence := DurationFromSeconds(cadenceSeconds)
+	damagePerEvent := dtps * cadenceSeconds
+
+	td.RegisterResetEffect(func(sim *Simulation) {
+		pa := &PendingAction{
+			NextActionAt: cadence,
+		}
+
+		pa.OnAction = func(sim *Simulation) {
+			roll := 2*sim.RandomFloat("Incoming Damage") - 1
+			spell.CalcAndDealDamage(sim, &td.Unit, damagePerEvent*(1+roll*variation), spell.OutcomeAlwaysHit)
+
+			pa.NextActionAt = sim.CurrentTime + cadence
+			sim.AddPendingAction(pa)
+		}
+
+		sim.AddPendingAction(pa)
+	})
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func newIncomingDamageSim(model *proto.IncomingDamageModel) *Simulation {
+	raid := SinglePlayerRaidProto(&proto.Player{Name: "Caster", Class: proto.Class_ClassWarlock, Spec: &proto.Player_AfflictionWarlock{}, Equipment: &proto.EquipmentSpec{}}, nil, nil, nil)
+	raid.TargetDummies = 2
+	raid.IncomingDamage = model
+
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: raid,
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{NewDefaultTarget()},
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+	return sim
+}
+
+func missingHealth(unit *Unit) float64 {
+	return unit.MaxHealth() - unit.CurrentHealth()
+}
+
+func TestIncomingDamageModel(t *testing.T) {
+	sim := newIncomingDamageSim(&proto.IncomingDamageModel{Dtps: 1000, TankDtps: 2000, CadenceSeconds: 2, DummyHealth: 100_000})
+	dummies := sim.Raid.GetTargetDummies()
+	tank, other := &dummies[0].Unit, &dummies[1].Unit
+
+	if tank.MaxHealth() != 100_000 || other.MaxHealth() != 100_000 {
+		t.Fatalf("Expected the dummies to have the configured health, got %0.0f and %0.0f", tank.MaxHealth(), other.MaxHealth())
+	}
+
+	sim.Step()
+	for sim.CurrentTime < time.Second*10 {
+		sim.Step()
+	}
+
+	// Without variation, every 2 seconds the tank takes 2 seconds of both the raid and
+	// tank damage, and the other dummy only the raid damage.
+	incomingDamage := sim.Encounter.TargetUnits[0].GetSpell(ActionID{OtherID: proto.OtherAction_OtherActionIncomingDamage})
+	for unit, damagePerHit := range map[*Unit]float64{tank: 6000, other: 2000} {
+		hits := incomingDamage.SpellMetrics[unit.UnitIndex].Hits
+		if hits < 4 {
+			t.Fatalf("Expected %s to be hit every 2 seconds, got %d hits", unit.Label, hits)
+		}
+		if missing := missingHealth(unit); !WithinToleranceFloat64(missing, damagePerHit*float64(hits), 0.01) {
+			t.Fatalf("Expected %s to have taken %0.0f damage per hit over %d hits, got %0.2f", unit.Label, damagePerHit, hits, missing)
+		}
+	}
+}
+
+func TestIncomingDamageNeverKillsDummies(t *testing.T) {
+	sim := newIncomingDamageSim(&proto.IncomingDamageModel{Dtps: 1_000_000})
+	dummies := sim.Raid.GetTargetDummies()
+
+	sim.Step()
+	for sim.CurrentTime < time.Second*3 {
+		sim.Step()
+	}
+	for _, dummy := range dummies {
+		if dummy.CurrentHealth() != 1 || !dummy.IsActive() {
+			t.Fatalf("Expected %s to be left alive on 1 health, got %0.2f", dummy.Label, dummy.CurrentHealth())
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	googleProto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+// InteractiveSim runs a sim step by step for an external controller, e.g. a reinforcement
+// learning agent, which chooses the actions of the first player in the raid.
+//
+// Each step the controller picks one of the player's actions and receives the damage dealt
+// by the player and its pets until the player is ready to act again. Action 0 waits for
+// WaitDuration, the other actions cast the spells returned by Actions().
+//
+// Only the first player is controlled. The rest of the raid, and the pets of every player,
+// keep following their own APL rotations.
+type InteractiveSim struct {
+	sim       *Simulation
+	character *Character
+
+	// How long the wait action, and any action that can't be cast, pauses the player for.
+	WaitDuration time.Duration
+
+	actions     []*Spell
+	auras       []*Aura
+	targetAuras []*Aura
+	resources   []unitResource
+	labels      []string
+
+	damageDone float64
+	done       bool
+}
+
+func NewInteractiveSim(rsr *proto.RaidSimRequest) (*InteractiveSim, error) {
+	rsr = googleProto.Clone(rsr).(*proto.RaidSimRequest)
+	if rsr.SimOptions == nil {
+		rsr.SimOptions = &proto.SimOptions{}
+	}
+	rsr.SimOptions.Interactive = true
+	// Players are expected to have an APL rotation, even if it isn't used to choose actions.
+	for _, party := range rsr.GetRaid().GetParties() {
+		for _, player := range party.Players {
+			if player != nil && player.Rotation == nil {
+				player.Rotation = &proto.APLRotation{}
+			}
+		}
+	}
+
+	sim := NewSim(rsr, simsignals.Signals{})
+	if len(sim.Raid.AllPlayerUnits) == 0 {
+		return nil, errors.New("raid has no players")
+	}
+	character := sim.Raid.GetPlayerFromUnit(sim.Raid.AllPlayerUnits[0]).GetCharacter()
+
+	is := &InteractiveSim{
+		sim:          sim,
+		character:    character,
+		WaitDuration: time.Millisecond * 100,
+		resources:    unitResources(&character.Unit),
+		done:         true,
+	}
+
+	is.labels = append(is.labels, "Remaining Duration", "GCD")
+	for _, resource := range is.resources {
+		if resource.secondaryType != proto.SecondaryResourceType_SecondaryResourceTypeNone {
+			is.labels = append(is.labels, "Resource: "+resource.secondaryType.String())
+		} else {
+			is.labels = append(is.labels, "Resource: "+resource.resourceType.String())
+		}
+	}
+	for _, spell := range character.Spellbook {
+		if spell.Flags.Matches(SpellFlagAPL) && !spell.Flags.Matches(SpellFlagPrepullOnly) {
+			is.actions = append(is.actions, spell)
+			is.labels = append(is.labels, "Cooldown: "+spell.ActionID.String())
+		}
+	}
+	for _, aura := range character.GetAuras() {
+		if !aura.ActionID.IsEmptyAction() {
+			is.auras = append(is.auras, aura)
+			is.labels = append(is.labels, auraLabels("Aura", aura)...)
+		}
+	}
+	if target := character.CurrentTarget; target != nil {
+		for _, aura := range target.GetAuras() {
+			if !aura.ActionID.IsEmptyAction() {
+				is.targetAuras = append(is.targetAuras, aura)
+				is.labels = append(is.labels, auraLabels("Target Aura", aura)...)
+			}
+		}
+	}
+
+	return is, nil
+}
+
+func auraLabels(prefix string, aura *Aura) []string {
+	labels := []string{fmt.Sprintf("%s: %s", prefix, aura.Label)}
+	if aura.MaxStacks > 0 {
+		labels = append(labels, fmt.Sprintf("%s Stacks: %s", prefix, aura.Label))
+	}
+	return labels
+}
+
+// Returns the spells cast by actions 1 to len(Actions()).
+func (is *InteractiveSim) Actions() []ActionID {
+	actionIDs := make([]ActionID, len(is.actions))
+	for i, spell := range is.actions {
+		actionIDs[i] = spell.ActionID
+	}
+	return actionIDs
+}
+
+// Returns the number of actions, including the wait action.
+func (is *InteractiveSim) NumActions() int {
+	return len(is.actions) + 1
+}
+
+// Returns which actions can currently be taken.
+func (is *InteractiveSim) ActionMask(dst []bool) []bool {
+	dst = append(dst[:0], true)
+	for _, spell := range is.actions {
+		dst = append(dst, !is.done && spell.CanCast(is.sim, is.character.CurrentTarget))
+	}
+	return dst
+}
+
+// Returns the names of the observation values, in order.
+func (is *InteractiveSim) ObservationLabels() []string {
+	return is.labels
+}
+
+// Appends the current observation to dst[:0]. Durations are in seconds, and auras that
+// are inactive have a remaining duration of 0.
+func (is *InteractiveSim) Observation(dst []float64) []float64 {
+	sim := is.sim
+	remaining := sim.GetRemainingDuration()
+
+	dst = append(dst[:0], remaining.Seconds(), is.character.GCD.TimeToReady(sim).Seconds())
+	for _, resource := range is.resources {
+		dst = append(dst, resource.level())
+	}
+	for _, spell := range is.actions {
+		dst = append(dst, spell.TimeToReady(sim).Seconds())
+	}
+	for _, auras := range [][]*Aura{is.auras, is.targetAuras} {
+		for _, aura := range auras {
+			dst = append(dst, min(aura.RemainingDuration(sim), remaining).Seconds())
+			if aura.MaxStacks > 0 {
+				dst = append(dst, float64(aura.GetStacks()))
+			}
+		}
+	}
+	return dst
+}
+
+// Starts a new iteration with the given seed, and runs it until the player first needs input.
+func (is *InteractiveSim) Reset(seed int64) {
+	sim := is.sim
+	sim.NeedsInput = false
+	sim.Reseed(seed)
+	sim.Reset()
+	sim.PrePull()
+
+	is.damageDone = 0
+	is.done = false
+	is.run()
+}
+
+// Takes the given action and runs the sim until the player needs input again or the
+// iteration is over. Returns the damage dealt in the meantime, and whether the iteration
+// is over.
+func (is *InteractiveSim) Step(action int) (reward float64, done bool) {
+	if is.done {
+		return 0, true
+	}
+	sim := is.sim
+	character := is.character
+
+	casted := false
+	if action > 0 && action <= len(is.actions) {
+		spell := is.actions[action-1]
+		target := character.CurrentTarget
+		casted = spell.CanCast(sim, target) && spell.Cast(sim, target)
+	}
+
+	if !casted {
+		sim.NeedsInput = false
+		character.WaitUntil(sim, sim.CurrentTime+is.WaitDuration)
+	} else if !character.GCD.IsReady(sim) || character.Hardcast.Expires > sim.CurrentTime {
+		sim.NeedsInput = false
+	}
+
+	is.run()
+
+	damageDone := is.totalDamage()
+	reward = damageDone - is.damageDone
+	is.damageDone = damageDone
+	return reward, is.done
+}
+
+func (is *InteractiveSim) run() {
+	for !is.sim.NeedsInput {
+		if is.sim.Step() {
+			is.sim.Cleanup()
+			is.done = true
+			return
+		}
+	}
+}
+
+// Total damage dealt to the encounter targets by the player and its pets, in this iteration.
+func (is *InteractiveSim) totalDamage() float64 {
+	totalDamage := 0.0
+	addDamage := func(unit *Unit) {
+		for _, spell := range unit.Spellbook {
+			for _, target := range is.sim.Encounter.TargetUnits {
+				totalDamage += spell.SpellMetrics[target.UnitIndex].TotalDamage
+			}
+		}
+	}
+	addDamage(&is.character.Unit)
+	for _, pet := range is.character.Pets {
+		addDamage(&pet.Unit)
+	}
+	return totalDamage
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func init() {
+	RegisterAgentFactory(
+		proto.Player_ShadowPriest{},
+		proto.Spec_SpecShadowPriest,
+		NewFakeInteractiveAgent,
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_ShadowPriest)
+			if !ok {
+				panic("Invalid spec value for Shadow Priest!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+// A caster with a single dot, which is offered as an action since it can be cast by APLs.
+func NewFakeInteractiveAgent(char *Character, _ *proto.Player) Agent {
+	fa := &FakeAgent{
+		Character: *char,
+	}
+
+	fa.Init = func() {
+		fa.Spell = fa.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: 42},
+			SpellSchool: SpellSchoolShadow,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagIgnoreArmor | SpellFlagAPL,
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			Dot: DotConfig{
+				Aura: Aura{
+					Label: "fakedot",
+				},
+				NumberOfTicks: 6,
+				TickLength:    time.Second * 3,
+
+				OnSnapshot: func(sim *Simulation, target *Unit, dot *Dot, isRollover bool) {
+					dot.Snapshot(target, 100)
+				},
+				OnTick: func(sim *Simulation, target *Unit, dot *Dot) {
+					dot.CalcAndDealPeriodicSnapshotDamage(sim, target, dot.OutcomeTick)
+				},
+			},
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.Dot(target).Apply(sim)
+			},
+		})
+		fa.Dot = fa.Spell.CurDot()
+	}
+
+	return fa
+}
+
+func runInteractiveEpisode(t *testing.T, is *InteractiveSim, seed int64) float64 {
+	dotAction := slices.Index(is.Actions(), ActionID{SpellID: 42}) + 1
+	if dotAction == 0 {
+		t.Fatalf("Expected the dot spell in the actions, got %v", is.Actions())
+	}
+	dotRemaining := slices.IndexFunc(is.ObservationLabels(), func(label string) bool {
+		return strings.HasPrefix(label, "Target Aura: fakedot")
+	})
+	if dotRemaining == -1 {
+		t.Fatalf("Expected the dot in the observation, got %v", is.ObservationLabels())
+	}
+
+	is.Reset(seed)
+	var observation []float64
+	totalReward := 0.0
+	for steps := 0; ; steps++ {
+		if steps > 10000 {
+			t.Fatalf("Iteration did not finish")
+		}
+		observation = is.Observation(observation)
+		if len(observation) != len(is.ObservationLabels()) {
+			t.Fatalf("Expected %d observation values, got %d", len(is.ObservationLabels()), len(observation))
+		}
+
+		action := 0
+		if observation[dotRemaining] == 0 {
+			action = dotAction
+		}
+		reward, done := is.Step(action)
+		totalReward += reward
+		if done {
+			return totalReward
+		}
+	}
+}
+
+func TestInteractiveSim(t *testing.T) {
+	is, err := NewInteractiveSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 100},
+		Raid:       SinglePlayerRaidProto(&proto.Player{Name: "Caster", Class: proto.Class_ClassPriest, Spec: &proto.Player_ShadowPriest{}, Equipment: &proto.EquipmentSpec{}}, nil, nil, nil),
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 90, MobType: proto.MobType_MobTypeDemon}},
+			Duration: 30,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reward := runInteractiveEpisode(t, is, 1)
+	if reward <= 0 {
+		t.Fatalf("Expected damage to be dealt, got %0.3f", reward)
+	}
+	if again := runInteractiveEpisode(t, is, 1); again != reward {
+		t.Fatalf("Expected the same seed to deal the same damage, got %0.3f and %0.3f", reward, again)
+	}
+	if _, done := is.Step(0); !done {
+		t.Fatalf("Expected a finished iteration to stay done")
+	}
+}
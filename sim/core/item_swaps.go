@@ -274,7 +274,7 @@ func (swap *ItemSwap) GetUnequippedItemBySlot(slot proto.ItemSlot) *Item {
 }
 
 func (swap *ItemSwap) EligibleSlotsForItem(itemID int32) []proto.ItemSlot {
-	eligibleSlots := eligibleSlotsForItem(GetItemByID(itemID), swap.isFuryWarrior)
+	eligibleSlots := EligibleSlotsForItem(GetItemByID(itemID), swap.isFuryWarrior)
 
 	if len(eligibleSlots) == 0 {
 		return []proto.ItemSlot{}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// eventLog records the debug logs as typed events, and formats them into the text logs.
+type eventLog struct {
+	text strings.Builder
+
+	keepEvents bool
+	events     []*proto.LogEvent
+}
+
+func (el *eventLog) add(event *proto.LogEvent) {
+	el.text.WriteString(FormatLogEvent(event))
+	el.text.WriteByte('\n')
+	if el.keepEvents {
+		el.events = append(el.events, event)
+	}
+}
+
+// Enables logging for the sim, with sim.Log adding message events.
+func (sim *Simulation) enableLogging(events *eventLog) {
+	sim.eventLog = events
+	sim.Log = func(message string, vals ...interface{}) {
+		sim.logEvent(nil, &proto.LogEvent{Event: &proto.LogEvent_Message{Message: fmt.Sprintf(message, vals...)}})
+	}
+}
+
+func (sim *Simulation) disableLogging() {
+	sim.eventLog = nil
+	sim.Log = nil
+}
+
+// Adds an event for the unit, which may be nil. Does nothing unless logging is enabled.
+func (sim *Simulation) logEvent(unit *Unit, event *proto.LogEvent) {
+	if sim.eventLog == nil {
+		return
+	}
+	event.Timestamp = sim.CurrentTime.Seconds()
+	if unit != nil {
+		event.Unit = unit.Label
+	}
+	sim.eventLog.add(event)
+}
+
+func (unit *Unit) logCastBegan(sim *Simulation, spell *Spell, cost float64, castTime time.Duration, effectiveTime time.Duration) {
+	sim.logEvent(unit, &proto.LogEvent{Event: &proto.LogEvent_CastBegan{CastBegan: &proto.CastLogEvent{
+		Action:        spell.ActionID.ToProto(),
+		Cost:          cost,
+		CastTime:      int64(castTime),
+		EffectiveTime: int64(effectiveTime),
+	}}})
+}
+
+func (unit *Unit) logCastCompleted(sim *Simulation, spell *Spell) {
+	sim.logEvent(unit, &proto.LogEvent{Event: &proto.LogEvent_CastCompleted{CastCompleted: spell.ActionID.ToProto()}})
+}
+
+func hitOutcomeToProto(outcome HitOutcome) proto.HitOutcome {
+	return proto.HitOutcome(proto.HitOutcome_value["HitOutcome"+outcome.String()])
+}
+
+func (spell *Spell) logHit(sim *Simulation, hitType proto.HitLogEvent_Type, target *Unit, outcome HitOutcome, isTick bool, amount float64, threat float64) {
+	sim.logEvent(spell.Unit, &proto.LogEvent{Event: &proto.LogEvent_Hit{Hit: &proto.HitLogEvent{
+		Type:        hitType,
+		Action:      spell.ActionID.ToProto(),
+		Target:      target.Label,
+		Outcome:     hitOutcomeToProto(outcome),
+		Tick:        isTick,
+		Amount:      amount,
+		SpellSchool: int32(spell.SpellSchool),
+		Threat:      threat,
+	}}})
+}
+
+func (aura *Aura) logEvent(sim *Simulation, auraType proto.AuraLogEvent_Type, oldStacks int32, stacks int32) {
+	sim.logEvent(aura.Unit, &proto.LogEvent{Event: &proto.LogEvent_Aura{Aura: &proto.AuraLogEvent{
+		Type:      auraType,
+		Aura:      aura.ActionID.ToProto(),
+		OldStacks: oldStacks,
+		Stacks:    stacks,
+	}}})
+}
+
+func (unit *Unit) logResource(sim *Simulation, event *proto.ResourceLogEvent) {
+	sim.logEvent(unit, &proto.LogEvent{Event: &proto.LogEvent_Resource{Resource: event}})
+}
+
+func (pet *Pet) logPet(sim *Simulation, petType proto.PetLogEvent_Type) {
+	sim.logEvent(&pet.Unit, &proto.LogEvent{Event: &proto.LogEvent_Pet{Pet: &proto.PetLogEvent{Type: petType}}})
+}
+
+// FormatLogEvent returns the line of the text logs for an event, without the trailing newline.
+func FormatLogEvent(event *proto.LogEvent) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%0.2f] ", event.Timestamp)
+	if event.Unit != "" {
+		sb.WriteString("[" + event.Unit + "] ")
+	}
+
+	switch e := event.Event.(type) {
+	case *proto.LogEvent_Message:
+		sb.WriteString(e.Message)
+	case *proto.LogEvent_CastBegan:
+		fmt.Fprintf(&sb, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
+			ProtoToActionID(e.CastBegan.Action), e.CastBegan.Cost, time.Duration(e.CastBegan.CastTime), time.Duration(e.CastBegan.EffectiveTime))
+	case *proto.LogEvent_CastCompleted:
+		fmt.Fprintf(&sb, "Completed cast %s", ProtoToActionID(e.CastCompleted))
+	case *proto.LogEvent_Hit:
+		formatHit(&sb, e.Hit)
+	case *proto.LogEvent_Aura:
+		aura := e.Aura
+		switch aura.Type {
+		case proto.AuraLogEvent_Gained:
+			fmt.Fprintf(&sb, "Aura gained: %s", ProtoToActionID(aura.Aura))
+		case proto.AuraLogEvent_Faded:
+			fmt.Fprintf(&sb, "Aura faded: %s", ProtoToActionID(aura.Aura))
+		case proto.AuraLogEvent_Refreshed:
+			fmt.Fprintf(&sb, "Aura refreshed: %s", ProtoToActionID(aura.Aura))
+		case proto.AuraLogEvent_StacksChanged:
+			fmt.Fprintf(&sb, "%s stacks: %d --> %d", ProtoToActionID(aura.Aura), aura.OldStacks, aura.Stacks)
+		}
+	case *proto.LogEvent_Resource:
+		formatResource(&sb, e.Resource)
+	case *proto.LogEvent_Pet:
+		if e.Pet.Type == proto.PetLogEvent_Summoned {
+			sb.WriteString("Pet summoned")
+		} else {
+			sb.WriteString("Pet dismissed")
+		}
+	}
+	return sb.String()
+}
+
+func formatHit(sb *strings.Builder, hit *proto.HitLogEvent) {
+	outcome := strings.TrimPrefix(hit.Outcome.String(), "HitOutcome")
+	tick := ""
+	if hit.Tick {
+		tick = "tick "
+	}
+
+	switch hit.Type {
+	case proto.HitLogEvent_Damage:
+		fmt.Fprintf(sb, "[%s] %s %s%s", hit.Target, ProtoToActionID(hit.Action), tick, outcome)
+		// Only landed hits show the damage, even if it was 0.
+		switch hit.Outcome {
+		case proto.HitOutcome_HitOutcomeEmpty, proto.HitOutcome_HitOutcomeMiss, proto.HitOutcome_HitOutcomeDodge, proto.HitOutcome_HitOutcomeParry:
+		default:
+			fmt.Fprintf(sb, " for %0.3f damage", hit.Amount)
+		}
+		fmt.Fprintf(sb, " (SpellSchool: %d). (Threat: %0.3f)", hit.SpellSchool, hit.Threat)
+	case proto.HitLogEvent_Healing:
+		fmt.Fprintf(sb, "[%s] %s %s%s for %0.3f healing. (Threat: %0.3f)", hit.Target, ProtoToActionID(hit.Action), tick, outcome, hit.Amount, hit.Threat)
+	case proto.HitLogEvent_Shielding:
+		fmt.Fprintf(sb, "[%s] %s %s%s for %0.3f shielding. (Threat: %0.3f)", hit.Target, ProtoToActionID(hit.Action), tick, outcome, hit.Amount, hit.Threat)
+	}
+}
+
+func formatResource(sb *strings.Builder, resource *proto.ResourceLogEvent) {
+	verb := "Gained"
+	if resource.Spent {
+		verb = "Spent"
+	}
+	if resource.WholeUnits {
+		fmt.Fprintf(sb, "%s %d %s from %s (%d --> %d) of %0.0f total.", verb, int32(resource.Amount), resource.Name, ProtoToActionID(resource.Action), int32(resource.Before), int32(resource.After), resource.Max)
+	} else {
+		fmt.Fprintf(sb, "%s %0.3f %s from %s (%0.3f --> %0.3f) of %0.0f total.", verb, resource.Amount, resource.Name, ProtoToActionID(resource.Action), resource.Before, resource.After, resource.Max)
+	}
+}
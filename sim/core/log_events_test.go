@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// The text logs are parsed by the UI, so the formatted events must keep the legacy formats.
+func TestFormatLogEvent(t *testing.T) {
+	action := ActionID{SpellID: 1752}.ToProto()
+	cases := []struct {
+		event    *proto.LogEvent
+		expected string
+	}{
+		{
+			event:    &proto.LogEvent{Timestamp: 1.5, Event: &proto.LogEvent_Message{Message: "Hello"}},
+			expected: "[1.50] Hello",
+		},
+		{
+			event: &proto.LogEvent{Timestamp: 2, Unit: "Rogue", Event: &proto.LogEvent_CastBegan{CastBegan: &proto.CastLogEvent{
+				Action:        action,
+				Cost:          40,
+				CastTime:      int64(time.Second),
+				EffectiveTime: int64(time.Second),
+			}}},
+			expected: "[2.00] [Rogue] Casting {SpellID: 1752} (Cost = 40.000, Cast Time = 1s, Effective Time = 1s)",
+		},
+		{
+			event:    &proto.LogEvent{Timestamp: 3, Unit: "Rogue", Event: &proto.LogEvent_CastCompleted{CastCompleted: action}},
+			expected: "[3.00] [Rogue] Completed cast {SpellID: 1752}",
+		},
+		{
+			event: &proto.LogEvent{Timestamp: 3, Unit: "Rogue", Event: &proto.LogEvent_Hit{Hit: &proto.HitLogEvent{
+				Type:        proto.HitLogEvent_Damage,
+				Action:      action,
+				Target:      "Target 1",
+				Outcome:     proto.HitOutcome_HitOutcomeCrit,
+				Amount:      1234.5,
+				SpellSchool: int32(SpellSchoolPhysical),
+				Threat:      10,
+			}}},
+			expected: "[3.00] [Rogue] [Target 1] {SpellID: 1752} Crit for 1234.500 damage (SpellSchool: 2). (Threat: 10.000)",
+		},
+		{
+			event: &proto.LogEvent{Timestamp: 3, Unit: "Rogue", Event: &proto.LogEvent_Hit{Hit: &proto.HitLogEvent{
+				Type:        proto.HitLogEvent_Damage,
+				Action:      action,
+				Target:      "Target 1",
+				Outcome:     proto.HitOutcome_HitOutcomeDodge,
+				Tick:        true,
+				SpellSchool: int32(SpellSchoolPhysical),
+			}}},
+			expected: "[3.00] [Rogue] [Target 1] {SpellID: 1752} tick Dodge (SpellSchool: 2). (Threat: 0.000)",
+		},
+		{
+			event: &proto.LogEvent{Timestamp: 4, Unit: "Rogue", Event: &proto.LogEvent_Aura{Aura: &proto.AuraLogEvent{
+				Type:      proto.AuraLogEvent_StacksChanged,
+				Aura:      action,
+				OldStacks: 1,
+				Stacks:    2,
+			}}},
+			expected: "[4.00] [Rogue] {SpellID: 1752} stacks: 1 --> 2",
+		},
+		{
+			event: &proto.LogEvent{Timestamp: 5, Unit: "Rogue", Event: &proto.LogEvent_Resource{Resource: &proto.ResourceLogEvent{
+				Name:       "combo points",
+				Action:     action,
+				Amount:     1,
+				Before:     0,
+				After:      1,
+				Max:        5,
+				WholeUnits: true,
+			}}},
+			expected: "[5.00] [Rogue] Gained 1 combo points from {SpellID: 1752} (0 --> 1) of 5 total.",
+		},
+		{
+			event: &proto.LogEvent{Timestamp: 6, Unit: "Rogue", Event: &proto.LogEvent_Resource{Resource: &proto.ResourceLogEvent{
+				Name:   "energy",
+				Action: action,
+				Spent:  true,
+				Amount: 40,
+				Before: 100,
+				After:  60,
+				Max:    100,
+			}}},
+			expected: "[6.00] [Rogue] Spent 40.000 energy from {SpellID: 1752} (100.000 --> 60.000) of 100 total.",
+		},
+		{
+			event:    &proto.LogEvent{Timestamp: 7, Unit: "Pet", Event: &proto.LogEvent_Pet{Pet: &proto.PetLogEvent{Type: proto.PetLogEvent_Dismissed}}},
+			expected: "[7.00] [Pet] Pet dismissed",
+		},
+	}
+
+	for _, c := range cases {
+		if actual := FormatLogEvent(c.event); actual != c.expected {
+			t.Errorf("Expected %q, got %q", c.expected, actual)
+		}
+	}
+}
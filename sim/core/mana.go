@@ -96,7 +96,15 @@ func (unit *Unit) AddMana(sim *Simulation, amount float64, metrics *ResourceMetr
 	metrics.AddEvent(amount, newMana-oldMana)
 
 	if sim.Log != nil {
-		unit.Log(sim, "Gained %0.3f mana from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldMana, newMana, unit.MaxMana())
+		unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "mana",
+			Action: metrics.ActionID.ToProto(),
+			Amount: amount,
+			Before: oldMana,
+			After:  newMana,
+			Max:    unit.MaxMana(),
+		})
 	}
 
 	unit.currentMana = newMana
@@ -112,7 +120,16 @@ func (unit *Unit) SpendMana(sim *Simulation, amount float64, metrics *ResourceMe
 	metrics.AddEvent(-amount, -amount)
 
 	if sim.Log != nil {
-		unit.Log(sim, "Spent %0.3f mana from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, unit.CurrentMana(), newMana, unit.MaxMana())
+		unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "mana",
+			Action: metrics.ActionID.ToProto(),
+			Spent:  true,
+			Amount: amount,
+			Before: unit.CurrentMana(),
+			After:  newMana,
+			Max:    unit.MaxMana(),
+		})
 	}
 
 	unit.currentMana = newMana
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Minimum number of gems of each color needed to activate a meta gem.
+type MetaGemCondition struct {
+	MinRed    int
+	MinYellow int
+	MinBlue   int
+}
+
+// Keep these in order by gem ID. Mists of Pandaria meta gems have no requirements,
+// so only older meta gems are listed here.
+var MetaGemConditions = map[int32]MetaGemCondition{
+	52289: {MinYellow: 2},             // Fleet Shadowspirit Diamond
+	52291: {MinRed: 3},                // Chaotic Shadowspirit Diamond
+	52292: {MinYellow: 1, MinBlue: 1}, // Bracing Shadowspirit Diamond
+	52293: {MinBlue: 3},               // Eternal Shadowspirit Diamond
+	52294: {MinYellow: 2},             // Austere Shadowspirit Diamond
+	52295: {MinRed: 1, MinYellow: 1},  // Effulgent Shadowspirit Diamond
+	52296: {MinYellow: 2},             // Ember Shadowspirit Diamond
+	52297: {MinYellow: 1, MinBlue: 1}, // Revitalizing Shadowspirit Diamond
+	52298: {MinRed: 2},                // Destructive Shadowspirit Diamond
+	52299: {MinBlue: 2},               // Powerful Shadowspirit Diamond
+	52300: {MinYellow: 1, MinBlue: 1}, // Enigmatic Shadowspirit Diamond
+	52301: {MinYellow: 1, MinBlue: 1}, // Impassive Shadowspirit Diamond
+	52302: {MinYellow: 1, MinBlue: 1}, // Forlorn Shadowspirit Diamond
+	68778: {MinRed: 3},                // Agile Shadowspirit Diamond
+	68779: {MinRed: 3},                // Reverberating Shadowspirit Diamond
+	68780: {MinRed: 3},                // Burning Shadowspirit Diamond
+}
+
+// Number of gems counting towards each primary color, for meta gem requirements.
+type GemColorCounts struct {
+	Red    int
+	Yellow int
+	Blue   int
+}
+
+func (counts GemColorCounts) Add(other GemColorCounts) GemColorCounts {
+	return GemColorCounts{
+		Red:    counts.Red + other.Red,
+		Yellow: counts.Yellow + other.Yellow,
+		Blue:   counts.Blue + other.Blue,
+	}
+}
+
+func (counts GemColorCounts) Subtract(other GemColorCounts) GemColorCounts {
+	return GemColorCounts{
+		Red:    counts.Red - other.Red,
+		Yellow: counts.Yellow - other.Yellow,
+		Blue:   counts.Blue - other.Blue,
+	}
+}
+
+func CountGemColors(gems []Gem) GemColorCounts {
+	counts := GemColorCounts{}
+	for _, gem := range gems {
+		if gem.ID == 0 || gem.Color == proto.GemColor_GemColorMeta {
+			continue
+		}
+		if ColorIntersects(proto.GemColor_GemColorRed, gem.Color) {
+			counts.Red++
+		}
+		if ColorIntersects(proto.GemColor_GemColorYellow, gem.Color) {
+			counts.Yellow++
+		}
+		if ColorIntersects(proto.GemColor_GemColorBlue, gem.Color) {
+			counts.Blue++
+		}
+	}
+	return counts
+}
+
+// Returns how many more gems are needed to activate the meta gem.
+func (condition MetaGemCondition) NumMissing(counts GemColorCounts) int {
+	return max(0, condition.MinRed-counts.Red) + max(0, condition.MinYellow-counts.Yellow) + max(0, condition.MinBlue-counts.Blue)
+}
+
+func (condition MetaGemCondition) IsMet(counts GemColorCounts) bool {
+	return condition.NumMissing(counts) == 0
+}
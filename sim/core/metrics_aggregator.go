@@ -101,6 +101,8 @@ type UnitMetrics struct {
 	oomTimeSum   float64
 	actions      map[ActionID]*ActionMetrics
 	resources    []*ResourceMetrics
+
+	timelines *unitTimelines // Only set if timeline metrics are enabled.
 }
 
 // Metrics for the current iteration, for 1 agent. Keep this as a separate
@@ -174,6 +176,7 @@ type SpellMetrics struct {
 	TotalHealing           float64 // Healing done by all casts of this spell.
 	TotalCritHealing       float64 // Healing done by all critical casts of this spell.
 	TotalShielding         float64 // Shielding done by all casts of this spell.
+	TotalOverhealing       float64 // Healing by all casts of this spell that exceeded the target's missing health.
 	TotalCastTime          time.Duration
 }
 
@@ -205,6 +208,7 @@ type TargetedActionMetrics struct {
 	Healing           float64
 	CritHealing       float64
 	Shielding         float64
+	Overhealing       float64
 	CastTime          time.Duration
 }
 
@@ -236,6 +240,7 @@ func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
 		Healing:           tam.Healing,
 		CritHealing:       tam.CritHealing,
 		Shielding:         tam.Shielding,
+		Overhealing:       tam.Overhealing,
 		CastTimeMs:        float64(tam.CastTime.Milliseconds()),
 	}
 }
@@ -397,6 +402,7 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		tam.Healing += spellTargetMetrics.TotalHealing
 		tam.CritHealing += spellTargetMetrics.TotalCritHealing
 		tam.Shielding += spellTargetMetrics.TotalShielding
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
 		if !spell.Flags.Matches(SpellFlagPassiveSpell) {
 			tam.CastTime += spellTargetMetrics.TotalCastTime
 		}
@@ -418,6 +424,9 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 // Assumes that doneIteration() has already been called on the pet metrics.
 func (unitMetrics *UnitMetrics) AddFinalPetMetrics(petMetrics *UnitMetrics) {
 	unitMetrics.dps.Total += petMetrics.dps.Total
+	if unitMetrics.timelines != nil && petMetrics.timelines != nil {
+		unitMetrics.timelines.iterationDamage.addTimeline(&petMetrics.timelines.iterationDamage)
+	}
 }
 
 func (unitMetrics *UnitMetrics) AddOOMTime(sim *Simulation, dur time.Duration) {
@@ -449,6 +458,9 @@ func (unitMetrics *UnitMetrics) reset() {
 	for _, resourceMetrics := range unitMetrics.resources {
 		resourceMetrics.reset()
 	}
+	if unitMetrics.timelines != nil {
+		unitMetrics.timelines.reset()
+	}
 }
 
 // This should be called when a Sim iteration is complete.
@@ -497,6 +509,10 @@ func (unitMetrics *UnitMetrics) doneIteration(unit *Unit, sim *Simulation) {
 	if unitMetrics.Died {
 		unitMetrics.numItersDead++
 	}
+
+	if unitMetrics.timelines != nil {
+		unitMetrics.timelines.doneIteration(sim)
+	}
 }
 
 func (unitMetrics *UnitMetrics) calculateTMI(unit *Unit, sim *Simulation) float64 {
@@ -596,6 +612,10 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 		}
 	}
 
+	if unitMetrics.timelines != nil {
+		protoMetrics.Timelines = unitMetrics.timelines.ToProto()
+	}
+
 	return protoMetrics
 }
 
@@ -12,12 +12,21 @@ type MovementAction struct {
 	srcPosition float64       // starting position
 	startTime   time.Duration // starting time of the movement
 	speed       float64       // theoretical movement speed, can be 0
+
+	// Movement on the ground plane, used when positions are modeled.
+	srcVector   Vector2 // starting position
+	velocity    Vector2 // yards per second, can be zero
+	destination Vector2
 }
 
 func (action *MovementAction) GetCurrentPosition(sim *Simulation) float64 {
 	return action.srcPosition + float64(sim.CurrentTime-action.startTime)*action.speed/float64(time.Second)
 }
 
+func (action *MovementAction) GetCurrentVector(sim *Simulation) Vector2 {
+	return action.srcVector.Add(action.velocity.Scale((sim.CurrentTime - action.startTime).Seconds()))
+}
+
 func (unit *Unit) initMovement() {
 	unit.moveAura = unit.GetOrRegisterAura(Aura{
 		Label:     "Movement",
@@ -51,9 +60,37 @@ func (unit *Unit) MoveTo(moveRange float64, sim *Simulation) {
 	}
 
 	unit.UpdatePosition(sim)
+
+	// With positions, move straight towards or away from the current target.
+	if unit.Env.usePositions && (unit.CurrentTarget != nil) {
+		direction := unit.Position.Sub(unit.CurrentTarget.Position).Normalized()
+		if direction == (Vector2{}) {
+			direction = unit.CurrentTarget.FacingDirection().Scale(TernaryFloat64(unit.PseudoStats.InFrontOfTarget, 1, -1))
+		}
+
+		unit.MoveToPosition(unit.CurrentTarget.Position.Add(direction.Scale(moveRange)), sim)
+		return
+	}
+
 	moveDistance := moveRange - unit.DistanceFromTarget
 	timeToMove := time.Duration(math.Abs(moveDistance)/unit.GetMovementSpeed()*1000) * time.Millisecond
-	registerMovementAction(unit, sim, unit.GetMovementSpeed()*TernaryFloat64(moveDistance < 0, -1., 1.), sim.CurrentTime+timeToMove)
+	registerMovementAction(unit, sim, unit.GetMovementSpeed()*TernaryFloat64(moveDistance < 0, -1., 1.), Vector2{}, unit.Position, sim.CurrentTime+timeToMove)
+}
+
+// Moves the unit in a straight line to the given point on the ground plane.
+func (unit *Unit) MoveToPosition(destination Vector2, sim *Simulation) {
+	unit.UpdatePosition(sim)
+
+	moveVector := destination.Sub(unit.Position)
+	moveDistance := moveVector.Length()
+
+	if moveDistance == 0 {
+		return
+	}
+
+	speed := unit.GetMovementSpeed()
+	timeToMove := time.Duration(moveDistance/speed*1000) * time.Millisecond
+	registerMovementAction(unit, sim, 0., moveVector.Scale(speed/moveDistance), destination, sim.CurrentTime+timeToMove)
 }
 
 func (unit *Unit) MoveDuration(duration time.Duration, sim *Simulation) {
@@ -62,7 +99,31 @@ func (unit *Unit) MoveDuration(duration time.Duration, sim *Simulation) {
 	}
 
 	unit.UpdatePosition(sim)
-	registerMovementAction(unit, sim, 0., sim.CurrentTime+duration)
+	registerMovementAction(unit, sim, 0., Vector2{}, unit.Position, sim.CurrentTime+duration)
+}
+
+// Forces the unit to move for the given duration, e.g. for a boss mechanic.
+// Hardcasts that cannot be performed while moving are allowed to finish first,
+// matching the Movement preset.
+func (unit *Unit) ForceMovement(duration time.Duration, sim *Simulation) {
+	if duration <= 0 {
+		return
+	}
+
+	if (unit.Hardcast.Expires > sim.CurrentTime) && !unit.Hardcast.CanMove {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     unit.Hardcast.Expires,
+			Priority: ActionPriorityPrePull + 1,
+
+			OnAction: func(sim *Simulation) {
+				unit.MoveDuration(duration, sim)
+			},
+		})
+
+		return
+	}
+
+	unit.MoveDuration(duration, sim)
 }
 
 func (unit *Unit) UpdatePosition(sim *Simulation) {
@@ -71,7 +132,12 @@ func (unit *Unit) UpdatePosition(sim *Simulation) {
 	}
 
 	oldDist := unit.DistanceFromTarget
-	unit.DistanceFromTarget = unit.movementAction.GetCurrentPosition(sim)
+	if unit.Env.usePositions {
+		unit.Position = unit.movementAction.GetCurrentVector(sim)
+		unit.DistanceFromTarget = unit.DistanceTo(unit.CurrentTarget)
+	} else {
+		unit.DistanceFromTarget = unit.movementAction.GetCurrentPosition(sim)
+	}
 	if oldDist == unit.DistanceFromTarget {
 		return
 	}
@@ -109,10 +175,15 @@ func (unit *Unit) FinalizeMovement(sim *Simulation) {
 	unit.UpdatePosition(sim)
 	unit.moveAura.Deactivate(sim)
 
+	// Explicitly placed units may have walked around their target.
+	if unit.hasStartPosition && (unit.CurrentTarget != nil) {
+		unit.PseudoStats.InFrontOfTarget = unit.IsInFrontOf(unit.CurrentTarget)
+	}
+
 	unit.OnMovement(sim, unit.DistanceFromTarget, MovementEnd)
 }
 
-func registerMovementAction(unit *Unit, sim *Simulation, speed float64, endTime time.Duration) {
+func registerMovementAction(unit *Unit, sim *Simulation, speed float64, velocity Vector2, destination Vector2, endTime time.Duration) {
 	if unit.movementAction != nil {
 		unit.movementAction.Cancel(sim)
 	} else {
@@ -123,6 +194,9 @@ func registerMovementAction(unit *Unit, sim *Simulation, speed float64, endTime
 		startTime:   sim.CurrentTime,
 		speed:       speed,
 		srcPosition: unit.DistanceFromTarget,
+		srcVector:   unit.Position,
+		velocity:    velocity,
+		destination: destination,
 	}
 
 	movementAction.NextActionAt = endTime
@@ -164,7 +238,9 @@ func (unit *Unit) MultiplyMovementSpeed(sim *Simulation, amount float64) {
 	}
 
 	// we have a pending movement action that depends on our movement speed
-	if unit.movementAction != nil && unit.movementAction.speed != 0 {
+	if unit.movementAction != nil && unit.movementAction.velocity != (Vector2{}) {
+		unit.MoveToPosition(unit.movementAction.destination, sim)
+	} else if unit.movementAction != nil && unit.movementAction.speed != 0 {
 		dest := unit.movementAction.speed * float64(unit.movementAction.NextActionAt-unit.movementAction.startTime) / float64(time.Second)
 		unit.MoveTo(dest, sim)
 	}
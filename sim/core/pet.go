@@ -233,7 +233,7 @@ func (pet *Pet) Enable(sim *Simulation, petAgent PetAgent) {
 	if sim.Log != nil {
 		pet.Log(sim, "Pet stats: %s", pet.GetStats().FlatString())
 		pet.Log(sim, "Pet inherited stats: %s", pet.ApplyStatDependencies(pet.inheritedStats).FlatString())
-		pet.Log(sim, "Pet summoned")
+		pet.logPet(sim, proto.PetLogEvent_Summoned)
 	}
 
 	sim.addTracker(&pet.auraTracker)
@@ -383,7 +383,7 @@ func (pet *Pet) Disable(sim *Simulation) {
 	sim.removeTracker(&pet.auraTracker)
 
 	if sim.Log != nil {
-		pet.Log(sim, "Pet dismissed")
+		pet.logPet(sim, proto.PetLogEvent_Dismissed)
 		pet.Log(sim, pet.GetStats().FlatString())
 	}
 }
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Vector2 is a point or direction on the ground plane of the encounter, in yards.
+type Vector2 struct {
+	X float64
+	Y float64
+}
+
+func Vector2FromProto(position *proto.Position) Vector2 {
+	if position == nil {
+		return Vector2{}
+	}
+
+	return Vector2{X: position.X, Y: position.Y}
+}
+
+func (v Vector2) Add(other Vector2) Vector2 {
+	return Vector2{X: v.X + other.X, Y: v.Y + other.Y}
+}
+
+func (v Vector2) Sub(other Vector2) Vector2 {
+	return Vector2{X: v.X - other.X, Y: v.Y - other.Y}
+}
+
+func (v Vector2) Scale(factor float64) Vector2 {
+	return Vector2{X: v.X * factor, Y: v.Y * factor}
+}
+
+func (v Vector2) Dot(other Vector2) float64 {
+	return v.X*other.X + v.Y*other.Y
+}
+
+func (v Vector2) Length() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+func (v Vector2) DistanceTo(other Vector2) float64 {
+	return v.Sub(other).Length()
+}
+
+// Returns the unit vector pointing in the same direction, or the zero vector
+// if v has no length.
+func (v Vector2) Normalized() Vector2 {
+	length := v.Length()
+
+	if length == 0 {
+		return Vector2{}
+	}
+
+	return v.Scale(1 / length)
+}
+
+// Direction targets face when they have nobody to look at. Players without an
+// explicit position are placed along this axis.
+var defaultFacing = Vector2{X: 1}
+
+// Whether the encounter models unit positions. This is only enabled when a
+// position is configured for at least one unit, so that existing setups keep
+// using the single-axis DistanceFromTarget model.
+func (env *Environment) UsePositions() bool {
+	return env.usePositions
+}
+
+// Places every unit on the ground plane. Units with an explicit position keep
+// it, while the rest are placed StartDistanceFromTarget yards in front of or
+// behind the first target, depending on their InFrontOfTarget setting.
+func (env *Environment) setupPositions() {
+	for _, unit := range env.AllUnits {
+		if unit.hasStartPosition {
+			env.usePositions = true
+			break
+		}
+	}
+
+	if !env.usePositions {
+		return
+	}
+
+	primaryTarget := env.Encounter.TargetUnits[0]
+
+	for _, unit := range env.Raid.AllUnits {
+		if !unit.hasStartPosition {
+			offset := defaultFacing.Scale(TernaryFloat64(unit.PseudoStats.InFrontOfTarget, 1, -1) * unit.StartDistanceFromTarget)
+			unit.StartPosition = primaryTarget.StartPosition.Add(offset)
+		}
+
+		unit.Position = unit.StartPosition
+
+		if unit.CurrentTarget != nil {
+			unit.StartDistanceFromTarget = unit.StartPosition.DistanceTo(unit.CurrentTarget.StartPosition)
+		}
+	}
+
+	for _, target := range env.Encounter.TargetUnits {
+		target.Position = target.StartPosition
+	}
+
+	// Facing only depends on positions for explicitly placed units.
+	for _, unit := range env.Raid.AllUnits {
+		if unit.hasStartPosition && (unit.CurrentTarget != nil) {
+			unit.PseudoStats.InFrontOfTarget = unit.IsInFrontOf(unit.CurrentTarget)
+		}
+	}
+}
+
+// Returns the distance in yards between this unit and another unit. Without
+// positional modeling every target is assumed to be DistanceFromTarget away.
+func (unit *Unit) DistanceTo(other *Unit) float64 {
+	if (other == nil) || !unit.Env.usePositions {
+		return unit.DistanceFromTarget
+	}
+
+	return unit.Position.DistanceTo(other.Position)
+}
+
+// Direction the unit is facing. Units face their current target, or along
+// the default axis if they have none.
+func (unit *Unit) FacingDirection() Vector2 {
+	if unit.CurrentTarget != nil {
+		if facing := unit.CurrentTarget.Position.Sub(unit.Position).Normalized(); facing != (Vector2{}) {
+			return facing
+		}
+	}
+
+	return defaultFacing
+}
+
+// Whether this unit is standing inside the frontal arc of the given target.
+// Falls back to the InFrontOfTarget setting when positions are not modeled or
+// the units are stacked on top of each other.
+func (unit *Unit) IsInFrontOf(target *Unit) bool {
+	if !unit.Env.usePositions {
+		return unit.PseudoStats.InFrontOfTarget
+	}
+
+	offset := unit.Position.Sub(target.Position)
+
+	if offset == (Vector2{}) {
+		return unit.PseudoStats.InFrontOfTarget
+	}
+
+	return offset.Dot(target.FacingDirection()) > 0
+}
+
+// Returns the active targets within radius yards of the given position.
+func (encounter *Encounter) TargetUnitsInRadius(center Vector2, radius float64) []*Unit {
+	return FilterSlice(encounter.ActiveTargetUnits, func(target *Unit) bool {
+		return target.Position.DistanceTo(center) <= radius
+	})
+}
+
+// Returns the targets hit by an AoE centered on the given unit. Without
+// positional modeling, or if the spell has no AoeRadius, every active target
+// is hit.
+//
+// The returned slice is owned by the spell, or by the encounter, and must not
+// be modified or kept. The next call for the same spell overwrites it, so AoEs
+// whose hits can cast the same spell again (e.g. chained explosions) need to
+// iterate over a copy.
+func (spell *Spell) TargetsInRadius(center *Unit) []*Unit {
+	return spell.TargetsInRadiusOfPosition(center.Position)
+}
+
+// Same as TargetsInRadius, but for ground-targeted AoEs that stay where they
+// were placed.
+func (spell *Spell) TargetsInRadiusOfPosition(center Vector2) []*Unit {
+	encounter := &spell.Unit.Env.Encounter
+
+	if (spell.AoeRadius == 0) || !spell.Unit.Env.usePositions {
+		return encounter.ActiveTargetUnits
+	}
+
+	spell.aoeTargets = spell.aoeTargets[:0]
+
+	for _, target := range encounter.ActiveTargetUnits {
+		if target.Position.DistanceTo(center) <= spell.AoeRadius {
+			spell.aoeTargets = append(spell.aoeTargets, target)
+		}
+	}
+
+	return spell.aoeTargets
+}
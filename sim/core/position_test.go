@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+)
+
+func setupPositionTestEnv() (*Environment, *Unit, []*Unit) {
+	env := &Environment{usePositions: true}
+	player := &Unit{Env: env, Position: Vector2{X: -5}}
+
+	targets := []*Unit{
+		{Env: env, Position: Vector2{}},
+		{Env: env, Position: Vector2{X: 4, Y: 3}},
+		{Env: env, Position: Vector2{X: 30}},
+	}
+	env.Encounter.ActiveTargetUnits = targets
+	player.CurrentTarget = targets[0]
+
+	return env, player, targets
+}
+
+func TestTargetsInRadius(t *testing.T) {
+	env, player, targets := setupPositionTestEnv()
+	spell := &Spell{Unit: player, AoeRadius: 8}
+
+	if hits := spell.TargetsInRadius(player); len(hits) != 1 || hits[0] != targets[0] {
+		t.Fatalf("Expected only the first target around the player, got %d targets", len(hits))
+	}
+
+	if hits := spell.TargetsInRadius(targets[0]); len(hits) != 2 {
+		t.Fatalf("Expected 2 targets around the first target, got %d", len(hits))
+	}
+
+	if hits := spell.TargetsInRadiusOfPosition(Vector2{X: 25}); len(hits) != 1 || hits[0] != targets[2] {
+		t.Fatalf("Expected only the third target around (25, 0), got %d targets", len(hits))
+	}
+
+	env.usePositions = false
+	if hits := spell.TargetsInRadius(player); len(hits) != len(targets) {
+		t.Fatalf("Expected all targets without positional modeling, got %d", len(hits))
+	}
+}
+
+func TestDistanceAndFacing(t *testing.T) {
+	_, player, targets := setupPositionTestEnv()
+	player.DistanceFromTarget = 5
+
+	if dist := player.DistanceTo(targets[1]); dist != 9.486832980505138 {
+		t.Fatalf("Unexpected distance %f", dist)
+	}
+
+	// The first target faces the player, so the player is in front of it.
+	targets[0].CurrentTarget = player
+	if !player.IsInFrontOf(targets[0]) {
+		t.Fatalf("Expected player to be in front of the target")
+	}
+
+	targets[0].CurrentTarget = targets[2]
+	if player.IsInFrontOf(targets[0]) {
+		t.Fatalf("Expected player to be behind the target")
+	}
+
+	player.Env.usePositions = false
+	if dist := player.DistanceTo(targets[2]); dist != 5 {
+		t.Fatalf("Expected DistanceFromTarget without positional modeling, got %f", dist)
+	}
+}
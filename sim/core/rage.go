@@ -118,11 +118,19 @@ func (rb *rageBar) AddRage(sim *Simulation, amount float64, metrics *ResourceMet
 	metrics.AddEvent(amount, newRage-rb.currentRage)
 
 	if sim.Log != nil {
-		rb.unit.Log(sim, "Gained %0.3f rage from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rb.currentRage, newRage, 100.0)
+		rb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "rage",
+			Action: metrics.ActionID.ToProto(),
+			Amount: amount,
+			Before: rb.currentRage,
+			After:  newRage,
+			Max:    100.0,
+		})
 	}
 
 	rb.currentRage = newRage
-	if !sim.Options.Interactive {
+	if !rb.unit.isInteractive(sim) {
 		rb.unit.ReactToEvent(sim)
 	}
 }
@@ -136,7 +144,16 @@ func (rb *rageBar) SpendRage(sim *Simulation, amount float64, metrics *ResourceM
 	metrics.AddEvent(-amount, -amount)
 
 	if sim.Log != nil {
-		rb.unit.Log(sim, "Spent %0.3f rage from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rb.currentRage, newRage, 100.0)
+		rb.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "rage",
+			Action: metrics.ActionID.ToProto(),
+			Spent:  true,
+			Amount: amount,
+			Before: rb.currentRage,
+			After:  newRage,
+			Max:    100.0,
+		})
 	}
 
 	rb.currentRage = newRage
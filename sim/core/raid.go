@@ -1,6 +1,7 @@
 package core
 
 import (
+	"cmp"
 	"slices"
 
 	"github.com/wowsims/mop/sim/core/proto"
@@ -160,6 +161,65 @@ func (raid *Raid) GetLowestHealthAllyUnit() *Unit {
 	return lowestHealthUnit
 }
 
+// Returns the player with the lowest health percent, which is how smart heals
+// pick their targets. Ties go to the player earliest in the raid.
+func (raid *Raid) GetLowestHealthPercentAllyUnit() *Unit {
+	var lowestHealthUnit *Unit
+	for _, unit := range raid.AllPlayerUnits {
+		if unit.HasHealthBar() && unit.IsActive() && (lowestHealthUnit == nil || unit.CurrentHealthPercent() < lowestHealthUnit.CurrentHealthPercent()) {
+			lowestHealthUnit = unit
+		}
+	}
+	return lowestHealthUnit
+}
+
+// Returns up to numTargets players sorted from lowest to highest health
+// percent, for smart heals that hit several players.
+func (raid *Raid) GetSmartHealTargets(numTargets int) []*Unit {
+	targets := FilterSlice(raid.AllPlayerUnits, func(unit *Unit) bool {
+		return unit.HasHealthBar() && unit.IsActive()
+	})
+
+	slices.SortStableFunc(targets, func(a, b *Unit) int {
+		return cmp.Compare(a.CurrentHealthPercent(), b.CurrentHealthPercent())
+	})
+
+	return targets[:min(numTargets, len(targets))]
+}
+
+// Selects up to numTargets distinct random players for a targeted boss
+// mechanic, skipping any excluded units (usually the tanks), or every valid
+// player if numTargets is 0. In individual sims the lone player is instead
+// selected with the probability they would have in a raid of raidSize.
+func (raid *Raid) SelectRandomPlayers(sim *Simulation, numTargets int32, raidSize int32, label string, excluded ...*Unit) []*Unit {
+	validTargets := FilterSlice(raid.AllPlayerUnits, func(unit *Unit) bool {
+		return !slices.Contains(excluded, unit)
+	})
+
+	if (numTargets <= 0) || ((int(numTargets) >= len(validTargets)) && (raid.Size() > 1)) {
+		return validTargets
+	}
+
+	if raid.Size() == 1 {
+		if (len(validTargets) > 0) && ((raidSize <= 1) || sim.Proc(float64(numTargets)/float64(raidSize), label)) {
+			return validTargets
+		}
+
+		return nil
+	}
+
+	selected := make([]*Unit, 0, numTargets)
+
+	for idx := int32(0); idx < numTargets; idx++ {
+		roll := int(sim.RandomFloat(label) * float64(len(validTargets)))
+		selected = append(selected, validTargets[roll])
+		validTargets[roll] = validTargets[len(validTargets)-1]
+		validTargets = validTargets[:len(validTargets)-1]
+	}
+
+	return selected
+}
+
 // Makes a new raid.
 func NewRaid(raidConfig *proto.Raid) *Raid {
 	numParties := int(raidConfig.NumActiveParties)
@@ -314,7 +374,13 @@ func (raid *Raid) applyCharacterEffects(raidConfig *proto.Raid) *proto.RaidStats
 		// Apply all buffs to the players in this party.
 		for playerIdx, player := range party.Players {
 			if playerIdx >= len(partyConfig.Players) {
-				// This happens for target dummies.
+				// This happens for target dummies, which only need health when
+				// they take damage from the incoming damage model.
+				if raidConfig.IncomingDamage != nil {
+					character := player.GetCharacter()
+					character.EnableHealthBar()
+					character.AddStats(character.baseStats)
+				}
 				continue
 			}
 			playerConfig := partyConfig.Players[playerIdx]
@@ -336,6 +402,10 @@ func (raid *Raid) applyCharacterEffects(raidConfig *proto.Raid) *proto.RaidStats
 		raidStats.Parties = append(raidStats.Parties, partyStats)
 	}
 
+	if raidConfig.IncomingDamage != nil {
+		raid.applyIncomingDamageModel(raidConfig.IncomingDamage)
+	}
+
 	return raidStats
 }
 
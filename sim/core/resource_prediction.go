@@ -0,0 +1,237 @@
+package core
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Whether the unit has the resource. Holy power and the other secondary resources use
+// ResourceTypeGenericResource, and chi uses the combo points of the energy bar.
+func (unit *Unit) HasResource(resourceType proto.ResourceType) bool {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeMana:
+		return unit.HasManaBar()
+	case proto.ResourceType_ResourceTypeEnergy:
+		return unit.HasEnergyBar()
+	case proto.ResourceType_ResourceTypeComboPoints, proto.ResourceType_ResourceTypeChi:
+		return unit.HasEnergyBar() && unit.maxComboPoints > 0
+	case proto.ResourceType_ResourceTypeRage:
+		return unit.HasRageBar()
+	case proto.ResourceType_ResourceTypeFocus:
+		return unit.HasFocusBar()
+	case proto.ResourceType_ResourceTypeRunicPower, proto.ResourceType_ResourceTypeBloodRune, proto.ResourceType_ResourceTypeFrostRune,
+		proto.ResourceType_ResourceTypeUnholyRune, proto.ResourceType_ResourceTypeDeathRune:
+		return unit.HasRunicPowerBar()
+	case proto.ResourceType_ResourceTypeGenericResource:
+		return unit.secondaryResourceBar != nil
+	}
+	return false
+}
+
+// Returns the current amount of the resource. Death runes can be spent as any rune type, so
+// they count towards the blood, frost and unholy amounts as well as the death one.
+func (unit *Unit) CurrentResource(resourceType proto.ResourceType) float64 {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeMana:
+		return unit.CurrentMana()
+	case proto.ResourceType_ResourceTypeEnergy:
+		return unit.CurrentEnergy()
+	case proto.ResourceType_ResourceTypeComboPoints, proto.ResourceType_ResourceTypeChi:
+		return float64(unit.ComboPoints())
+	case proto.ResourceType_ResourceTypeRage:
+		return unit.CurrentRage()
+	case proto.ResourceType_ResourceTypeFocus:
+		return unit.CurrentFocus()
+	case proto.ResourceType_ResourceTypeRunicPower:
+		return unit.CurrentRunicPower()
+	case proto.ResourceType_ResourceTypeBloodRune:
+		return float64(unit.CurrentBloodRunes() + unit.CurrentDeathRunes())
+	case proto.ResourceType_ResourceTypeFrostRune:
+		return float64(unit.CurrentFrostRunes() + unit.CurrentDeathRunes())
+	case proto.ResourceType_ResourceTypeUnholyRune:
+		return float64(unit.CurrentUnholyRunes() + unit.CurrentDeathRunes())
+	case proto.ResourceType_ResourceTypeDeathRune:
+		return float64(unit.CurrentDeathRunes())
+	case proto.ResourceType_ResourceTypeGenericResource:
+		return float64(unit.secondaryResourceBar.Value())
+	}
+	return 0
+}
+
+// Whether TimeToTargetResource can predict the resource. Runic power, combo points, chi and
+// holy power are only generated by abilities, so there is no regeneration to predict.
+func canPredictResource(resourceType proto.ResourceType) bool {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeMana, proto.ResourceType_ResourceTypeEnergy, proto.ResourceType_ResourceTypeFocus,
+		proto.ResourceType_ResourceTypeRage, proto.ResourceType_ResourceTypeBloodRune, proto.ResourceType_ResourceTypeFrostRune,
+		proto.ResourceType_ResourceTypeUnholyRune, proto.ResourceType_ResourceTypeDeathRune:
+		return true
+	}
+	return false
+}
+
+// Returns the estimated time until the unit has at least the given amount of the resource,
+// from passive regeneration and auto attack rage alone. Resources which can't be predicted
+// and amounts above the maximum return NeverExpires until the amount is reached.
+func (unit *Unit) TimeToTargetResource(sim *Simulation, resourceType proto.ResourceType, amount float64) time.Duration {
+	if unit.CurrentResource(resourceType) >= amount {
+		return 0
+	}
+
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeMana:
+		if amount > unit.MaxMana() {
+			return NeverExpires
+		}
+		return unit.TimeUntilManaRegen(amount)
+	case proto.ResourceType_ResourceTypeEnergy:
+		if unit.hasNoRegen || amount > unit.MaximumEnergy() {
+			return NeverExpires
+		}
+		return unit.TimeToTargetEnergy(amount)
+	case proto.ResourceType_ResourceTypeFocus:
+		if amount > unit.MaximumFocus() {
+			return NeverExpires
+		}
+		return unit.TimeToTargetFocus(amount)
+	case proto.ResourceType_ResourceTypeRage:
+		return unit.timeToRage(sim, amount)
+	case proto.ResourceType_ResourceTypeBloodRune, proto.ResourceType_ResourceTypeFrostRune,
+		proto.ResourceType_ResourceTypeUnholyRune, proto.ResourceType_ResourceTypeDeathRune:
+		return unit.timeToRuneCount(sim, resourceType, amount)
+	}
+	return NeverExpires
+}
+
+// Rage is generated by landed auto attacks, so this assumes that every upcoming swing lands
+// and none is replaced, e.g. by Heroic Strike.
+func (unit *Unit) timeToRage(sim *Simulation, amount float64) time.Duration {
+	aa := &unit.AutoAttacks
+	if amount > unit.MaximumRage() || unit.GetCurrentPowerBar() != RageBar || !aa.mh.enabled || aa.mh.curSwingDuration <= 0 {
+		return NeverExpires
+	}
+
+	mhRage := unit.rageBar.currentHitFactor * aa.mh.SwingSpeed
+	if mhRage <= 0 {
+		return NeverExpires
+	}
+
+	dualWielding := aa.IsDualWielding && aa.oh.enabled && aa.oh.curSwingDuration > 0
+	ohRage := unit.rageBar.currentHitFactor / 2 * aa.oh.SwingSpeed
+
+	rage := unit.CurrentRage()
+	mhSwingAt := max(aa.mh.swingAt, sim.CurrentTime)
+	ohSwingAt := max(aa.oh.swingAt, sim.CurrentTime)
+	for {
+		var swingAt time.Duration
+		if dualWielding && ohSwingAt < mhSwingAt {
+			swingAt = ohSwingAt
+			rage += ohRage
+			ohSwingAt += aa.oh.curSwingDuration
+		} else {
+			swingAt = mhSwingAt
+			rage += mhRage
+			mhSwingAt += aa.mh.curSwingDuration
+		}
+		if rage >= amount {
+			return swingAt - sim.CurrentTime
+		}
+	}
+}
+
+// Returns the time until the unit has the given number of runes usable as the rune type.
+// Death runes are usable as any type, so a blood amount also counts death runes in the frost
+// and unholy slots, as long as they don't revert before they regenerate.
+func (unit *Unit) timeToRuneCount(sim *Simulation, resourceType proto.ResourceType, amount float64) time.Duration {
+	var readyAts []time.Duration
+	for slot := range int8(len(unit.runeMeta)) {
+		isDeath := unit.RuneIsDeath(slot)
+		if !isDeath && !runeSlotHasType(slot, resourceType) {
+			continue
+		}
+
+		readyAt := sim.CurrentTime
+		if !unit.RuneIsActive(slot) {
+			readyAt = unit.runeMeta[slot].regenAt
+			if isDeath && !runeSlotHasType(slot, resourceType) && unit.runeMeta[slot].revertAt <= readyAt {
+				continue
+			}
+		}
+		readyAts = append(readyAts, readyAt)
+	}
+
+	count := int(math.Ceil(amount))
+	if count > len(readyAts) {
+		return NeverExpires
+	}
+
+	slices.Sort(readyAts)
+	readyAt := readyAts[count-1]
+	if readyAt >= NeverExpires {
+		return NeverExpires
+	}
+	return max(0, readyAt-sim.CurrentTime)
+}
+
+// Whether the rune slot regenerates as the rune type when it isn't a death rune. Death runes
+// only come from conversions, so no slot has the death type.
+func runeSlotHasType(slot int8, resourceType proto.ResourceType) bool {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeBloodRune:
+		return slot == 0 || slot == 1
+	case proto.ResourceType_ResourceTypeFrostRune:
+		return slot == 2 || slot == 3
+	case proto.ResourceType_ResourceTypeUnholyRune:
+		return slot == 4 || slot == 5
+	}
+	return false
+}
+
+// Returns the amount of the resource which the spell costs, or 0 if its cost is not in the resource.
+func (spell *Spell) ResourceCost(resourceType proto.ResourceType) float64 {
+	if spell.Cost == nil {
+		return 0
+	}
+
+	switch cost := spell.Cost.ResourceCostImpl.(type) {
+	case *ManaCost:
+		if resourceType == proto.ResourceType_ResourceTypeMana {
+			return spell.Cost.GetCurrentCost()
+		}
+	case *EnergyCost:
+		if resourceType == proto.ResourceType_ResourceTypeEnergy {
+			return spell.Cost.GetCurrentCost()
+		}
+	case *RageCost:
+		if resourceType == proto.ResourceType_ResourceTypeRage {
+			return spell.Cost.GetCurrentCost()
+		}
+	case *FocusCost:
+		if resourceType == proto.ResourceType_ResourceTypeFocus {
+			return spell.Cost.GetCurrentCost()
+		}
+	case *RuneCostImpl:
+		switch resourceType {
+		case proto.ResourceType_ResourceTypeRunicPower:
+			return cost.RunicPowerCost
+		case proto.ResourceType_ResourceTypeBloodRune:
+			return float64(cost.BloodRuneCost)
+		case proto.ResourceType_ResourceTypeFrostRune:
+			return float64(cost.FrostRuneCost)
+		case proto.ResourceType_ResourceTypeUnholyRune:
+			return float64(cost.UnholyRuneCost)
+		case proto.ResourceType_ResourceTypeDeathRune:
+			return float64(cost.DeathRuneCost)
+		}
+	}
+	return 0
+}
+
+// Returns the name of the resource type for validation messages, e.g. "RunicPower".
+func resourceTypeLabel(resourceType proto.ResourceType) string {
+	return strings.TrimPrefix(resourceType.String(), "ResourceType")
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Hash of the item database contents, set when the database is loaded.
+var DatabaseVersion string
+
+// Identifies the build of the sim, so that results cached on disk are not reused after the
+// sim code changes. Builds from a modified tree also include a hash of the executable.
+var simBuildVersion = sync.OnceValue(func() string {
+	version := fmt.Sprintf("proto%d", GetCurrentProtoVersion())
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version
+	}
+	version += "-" + buildInfo.Main.Version
+
+	modified := false
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version += "-" + setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+
+	if modified {
+		if path, err := os.Executable(); err == nil {
+			if data, err := os.ReadFile(path); err == nil {
+				hash := sha256.Sum256(data)
+				version += "-" + hex.EncodeToString(hash[:])
+			}
+		}
+	}
+	return version
+})
+
+// Returns a hash of the request, which is the same for all requests that produce the same
+// results with this build of the sim and database. The request id is ignored, since it only
+// identifies the request.
+func RaidSimRequestHash(rsr *proto.RaidSimRequest) string {
+	rsr = googleProto.Clone(rsr).(*proto.RaidSimRequest)
+	rsr.RequestId = ""
+
+	data, err := googleProto.MarshalOptions{Deterministic: true}.Marshal(rsr)
+	if err != nil {
+		panic(err)
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", simBuildVersion(), DatabaseVersion)
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Returns whether running the request always produces the same results. Requests without
+// a random seed use a different one each time, so their results can't be reused.
+func IsDeterministicRequest(rsr *proto.RaidSimRequest) bool {
+	return rsr.SimOptions != nil && rsr.SimOptions.RandomSeed != 0
+}
+
+// SimResultCache keeps the results of deterministic raid sims, keyed by the canonical hash
+// of their request. The most recently used results are kept in memory, and if a directory
+// is given all results are also written to it, so they can be reused by other processes.
+type SimResultCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	dir        string
+
+	entries map[string]*list.Element
+	order   *list.List // Most recently used at the front.
+}
+
+type simResultCacheEntry struct {
+	hash   string
+	result *proto.RaidSimResult
+}
+
+// Creates a cache holding up to maxEntries results in memory, and all results in dir if it
+// is not empty.
+func NewSimResultCache(maxEntries int, dir string) (*SimResultCache, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("error creating cache directory: %v", err)
+		}
+	}
+	return &SimResultCache{
+		maxEntries: maxEntries,
+		dir:        dir,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}, nil
+}
+
+// Returns the cached result for the request, or nil if there is none. The returned result
+// is a copy, with its cache info set.
+func (cache *SimResultCache) Get(rsr *proto.RaidSimRequest) *proto.RaidSimResult {
+	if !IsDeterministicRequest(rsr) {
+		return nil
+	}
+	hash := RaidSimRequestHash(rsr)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	var result *proto.RaidSimResult
+	if elem, ok := cache.entries[hash]; ok {
+		cache.order.MoveToFront(elem)
+		result = elem.Value.(*simResultCacheEntry).result
+	} else if cache.dir != "" {
+		var err error
+		result, err = cache.read(hash)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Failed to read cached result %s: %s", hash, err)
+			}
+			return nil
+		}
+		cache.add(hash, result)
+	} else {
+		return nil
+	}
+
+	result = googleProto.Clone(result).(*proto.RaidSimResult)
+	result.CacheInfo = &proto.ResultCacheInfo{RequestHash: hash, Hit: true}
+	return result
+}
+
+// Stores the result of the request, if the request is deterministic and the sim succeeded.
+// Sets the cache info of the result.
+func (cache *SimResultCache) Put(rsr *proto.RaidSimRequest, result *proto.RaidSimResult) {
+	if !IsDeterministicRequest(rsr) || result == nil || result.Error != nil {
+		return
+	}
+	hash := RaidSimRequestHash(rsr)
+	result.CacheInfo = &proto.ResultCacheInfo{RequestHash: hash}
+
+	stored := googleProto.Clone(result).(*proto.RaidSimResult)
+	stored.CacheInfo = nil
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if elem, ok := cache.entries[hash]; ok {
+		cache.order.MoveToFront(elem)
+		elem.Value.(*simResultCacheEntry).result = stored
+	} else {
+		cache.add(hash, stored)
+	}
+	if cache.dir != "" {
+		if err := cache.write(hash, stored); err != nil {
+			log.Printf("Failed to write cached result %s: %s", hash, err)
+		}
+	}
+}
+
+// Returns the number of results held in memory.
+func (cache *SimResultCache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.order.Len()
+}
+
+func (cache *SimResultCache) add(hash string, result *proto.RaidSimResult) {
+	if cache.maxEntries <= 0 {
+		return
+	}
+	cache.entries[hash] = cache.order.PushFront(&simResultCacheEntry{hash: hash, result: result})
+	for cache.order.Len() > cache.maxEntries {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*simResultCacheEntry).hash)
+	}
+}
+
+func (cache *SimResultCache) path(hash string) string {
+	return filepath.Join(cache.dir, hash+".binpb")
+}
+
+func (cache *SimResultCache) read(hash string) (*proto.RaidSimResult, error) {
+	data, err := os.ReadFile(cache.path(hash))
+	if err != nil {
+		return nil, err
+	}
+	result := &proto.RaidSimResult{}
+	if err := googleProto.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (cache *SimResultCache) write(hash string, result *proto.RaidSimResult) error {
+	data, err := googleProto.Marshal(result)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so other processes never read a partial result.
+	tmp, err := os.CreateTemp(cache.dir, hash+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), cache.path(hash))
+}
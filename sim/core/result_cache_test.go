@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestRaidSimRequestHash(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		RequestId:  "a",
+		Encounter:  &proto.Encounter{Duration: 180},
+		SimOptions: &proto.SimOptions{Iterations: 100, RandomSeed: 1},
+	}
+	sameRequest := &proto.RaidSimRequest{
+		RequestId:  "b",
+		Encounter:  &proto.Encounter{Duration: 180},
+		SimOptions: &proto.SimOptions{Iterations: 100, RandomSeed: 1},
+	}
+	otherSeed := &proto.RaidSimRequest{
+		Encounter:  &proto.Encounter{Duration: 180},
+		SimOptions: &proto.SimOptions{Iterations: 100, RandomSeed: 2},
+	}
+
+	if RaidSimRequestHash(request) != RaidSimRequestHash(sameRequest) {
+		t.Fatalf("Expected requests differing only in their request id to have the same hash")
+	}
+	if RaidSimRequestHash(request) == RaidSimRequestHash(otherSeed) {
+		t.Fatalf("Expected requests with different seeds to have different hashes")
+	}
+	if request.RequestId != "a" {
+		t.Fatalf("Expected the request to be unchanged")
+	}
+
+	prevDatabaseVersion := DatabaseVersion
+	defer func() { DatabaseVersion = prevDatabaseVersion }()
+	hash := RaidSimRequestHash(request)
+	DatabaseVersion = "other"
+	if RaidSimRequestHash(request) == hash {
+		t.Fatalf("Expected a different database to change the hash")
+	}
+}
+
+func TestSimResultCache(t *testing.T) {
+	newRequest := func(seed int64) *proto.RaidSimRequest {
+		return &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 100, RandomSeed: seed}}
+	}
+	newResult := func(dps float64) *proto.RaidSimResult {
+		return &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{Dps: &proto.DistributionMetrics{Avg: dps}}}
+	}
+
+	dir := t.TempDir()
+	cache, err := NewSimResultCache(2, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := newResult(1000)
+	cache.Put(newRequest(1), result)
+	if result.CacheInfo == nil || result.CacheInfo.Hit {
+		t.Fatalf("Expected the stored result to have cache info without a hit, got %v", result.CacheInfo)
+	}
+	cache.Put(newRequest(0), newResult(2000))
+	cache.Put(newRequest(2), &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: "failed"}})
+	if cache.Get(newRequest(0)) != nil || cache.Get(newRequest(2)) != nil {
+		t.Fatalf("Expected results of requests without a seed and failed sims not to be cached")
+	}
+
+	cached := cache.Get(newRequest(1))
+	if cached == nil || !cached.CacheInfo.Hit || cached.RaidMetrics.Dps.Avg != 1000 {
+		t.Fatalf("Expected the cached result, got %v", cached)
+	}
+
+	cache.Put(newRequest(3), newResult(3000))
+	cache.Put(newRequest(4), newResult(4000))
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 results in memory, got %d", cache.Len())
+	}
+
+	// Evicted results are still read from the cache directory, also by other caches.
+	otherCache, err := NewSimResultCache(2, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []*SimResultCache{cache, otherCache} {
+		if cached := c.Get(newRequest(1)); cached == nil || cached.RaidMetrics.Dps.Avg != 1000 {
+			t.Fatalf("Expected the result from the cache directory, got %v", cached)
+		}
+	}
+}
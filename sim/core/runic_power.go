@@ -204,7 +204,15 @@ func (rp *runicPowerBar) addRunicPowerInternal(sim *Simulation, amount float64,
 	}
 
 	if sim.Log != nil {
-		rp.character.Log(sim, "Gained %0.3f runic power from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower, rp.maxRunicPower)
+		rp.character.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "runic power",
+			Action: metrics.ActionID.ToProto(),
+			Amount: amount,
+			Before: rp.currentRunicPower,
+			After:  newRunicPower,
+			Max:    rp.maxRunicPower,
+		})
 	}
 
 	rp.currentRunicPower = newRunicPower
@@ -238,7 +246,16 @@ func (rp *runicPowerBar) spendRunicPower(sim *Simulation, amount float64, metric
 	}
 
 	if sim.Log != nil {
-		rp.character.Log(sim, "Spent %0.3f runic power from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, rp.currentRunicPower, newRunicPower, rp.maxRunicPower)
+		rp.character.logResource(sim, &proto.ResourceLogEvent{
+			Type:   metrics.Type,
+			Name:   "runic power",
+			Action: metrics.ActionID.ToProto(),
+			Spent:  true,
+			Amount: amount,
+			Before: rp.currentRunicPower,
+			After:  newRunicPower,
+			Max:    rp.maxRunicPower,
+		})
 	}
 
 	rp.currentRunicPower = newRunicPower
@@ -16,6 +16,7 @@ type SecondaryResourceBar interface {
 	Gain(sim *Simulation, amount int32, action ActionID)           // Gain the amount specified from the action
 	Reset(sim *Simulation)                                         // Resets the current resource bar
 	Value() int32                                                  // Returns the current amount of resource
+	Type() proto.SecondaryResourceType                             // Returns the type of resource the bar tracks
 	RegisterOnGain(callback OnGainCallback)                        // Registers a callback that will be called. Gain = amount gained, realGain = actual amount gained due to caps
 	RegisterOnSpend(callback OnSpendCallback)                      // Registers a callback that will be called when the resource was spend
 }
@@ -54,16 +55,16 @@ func (bar *DefaultSecondaryResourceBarImpl) Gain(sim *Simulation, amount int32,
 	metrics := bar.GetMetric(action)
 	metrics.AddEvent(float64(amount), float64(amountGained))
 	if sim.Log != nil {
-		bar.unit.Log(
-			sim,
-			"Gained %d %s from %s (%d --> %d) of %d total.",
-			amountGained,
-			proto.SecondaryResourceType_name[int32(bar.config.Type)],
-			action,
-			oldValue,
-			bar.value,
-			bar.config.Max,
-		)
+		bar.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:       metrics.Type,
+			Name:       proto.SecondaryResourceType_name[int32(bar.config.Type)],
+			Action:     action.ToProto(),
+			Amount:     float64(amountGained),
+			Before:     float64(oldValue),
+			After:      float64(bar.value),
+			Max:        float64(bar.config.Max),
+			WholeUnits: true,
+		})
 	}
 
 	bar.invokeOnGain(sim, amount, amountGained, action)
@@ -89,16 +90,17 @@ func (bar *DefaultSecondaryResourceBarImpl) Spend(sim *Simulation, amount int32,
 
 	metrics := bar.GetMetric(action)
 	if sim.Log != nil {
-		bar.unit.Log(
-			sim,
-			"Spent %d %s from %s (%d --> %d) of %d total.",
-			amount,
-			proto.SecondaryResourceType_name[int32(bar.config.Type)],
-			metrics.ActionID,
-			bar.value,
-			bar.value-amount,
-			bar.config.Max,
-		)
+		bar.unit.logResource(sim, &proto.ResourceLogEvent{
+			Type:       metrics.Type,
+			Name:       proto.SecondaryResourceType_name[int32(bar.config.Type)],
+			Action:     metrics.ActionID.ToProto(),
+			Spent:      true,
+			Amount:     float64(amount),
+			Before:     float64(bar.value),
+			After:      float64(bar.value - amount),
+			Max:        float64(bar.config.Max),
+			WholeUnits: true,
+		})
 	}
 
 	metrics.AddEvent(float64(-amount), float64(-amount))
@@ -123,6 +125,11 @@ func (bar *DefaultSecondaryResourceBarImpl) Value() int32 {
 	return bar.value
 }
 
+// Type implements SecondaryResourceBar.
+func (bar *DefaultSecondaryResourceBarImpl) Type() proto.SecondaryResourceType {
+	return bar.config.Type
+}
+
 func (bar *DefaultSecondaryResourceBarImpl) Max() int32 {
 	return bar.config.Max
 }
@@ -1,6 +1,10 @@
 package core
 
-import "strconv"
+import (
+	"strconv"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
 
 type ShieldConfig struct {
 	SelfOnly bool // Set to true to only create the self-shield.
@@ -19,9 +23,8 @@ type Shield struct {
 }
 
 func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
-	caster := shield.Spell.Unit
 	target := shield.Aura.Unit
-	//attackTable := caster.AttackTables[target.UnitIndex]
+	//attackTable := shield.Spell.Unit.AttackTables[target.UnitIndex]
 
 	// Shields are not affected by healing pseudostats the same way heals are.
 	// So we only apply the spell-specific multiplier.
@@ -40,7 +43,7 @@ func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
 	shield.Spell.SpellMetrics[target.UnitIndex].Hits++
 
 	if sim.Log != nil {
-		caster.Log(sim, "%s %s Hit for %0.3f shielding. (Threat: %0.3f)", target.LogLabel(), shield.Spell.ActionID, shieldAmount, threat)
+		shield.Spell.logHit(sim, proto.HitLogEvent_Shielding, target, OutcomeHit, false, shieldAmount, threat)
 	}
 }
 
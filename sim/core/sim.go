@@ -9,7 +9,6 @@ import (
 	"runtime/debug"
 	"slices"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -43,7 +42,8 @@ type Simulation struct {
 	ProgressReport func(*proto.ProgressMetrics)
 	Signals        simsignals.Signals
 
-	Log func(string, ...interface{})
+	Log      func(string, ...interface{})
+	eventLog *eventLog // Set whenever Log is.
 
 	executePhase int32 // 20, 25, 35, 45 or 90 for the respective execute range, 100 otherwise
 
@@ -63,6 +63,8 @@ type Simulation struct {
 
 	minTaskTime time.Duration
 	tasks       []Task
+
+	timelineUnits []*Unit // Units recording timeline metrics, if enabled.
 }
 
 func (sim *Simulation) rescheduleTracker(trackerTime time.Duration) {
@@ -195,7 +197,7 @@ func newSimWithEnv(env *Environment, simOptions *proto.SimOptions, signals simsi
 		rseed = time.Now().UnixNano()
 	}
 
-	return &Simulation{
+	sim := &Simulation{
 		Environment: env,
 		Options:     simOptions,
 
@@ -215,6 +217,8 @@ func newSimWithEnv(env *Environment, simOptions *proto.SimOptions, signals simsi
 			},
 		},
 	}
+	sim.initTimelines()
+	return sim
 }
 
 // Returns a random float64 between 0.0 (inclusive) and 1.0 (exclusive).
@@ -256,6 +260,20 @@ func makeTestRandSeed(rseed int64, label string) int64 {
 	return int64(hash(label + strconv.FormatInt(rseed, 16)))
 }
 
+// Sets up options for common random numbers, so that sims of different variants of the
+// same setup use identical per-label seeds in each iteration. Differences between the
+// variants can then be compared iteration by iteration, which has far less variance
+// than comparing independent sims.
+func enableCommonRandomNumbers(simOptions *proto.SimOptions) {
+	// When there is no user-supplied seed it needs to be a randomly-selected seed,
+	// so that run-run differences still exist.
+	if simOptions.RandomSeed == 0 {
+		simOptions.RandomSeed = time.Now().UnixNano()
+	}
+	simOptions.UseLabeledRands = true
+	simOptions.SaveAllValues = true
+}
+
 func (sim *Simulation) RandomExpFloat(label string) float64 {
 	return rand.New(sim.labelRand(label)).ExpFloat64()
 }
@@ -293,11 +311,9 @@ func (sim *Simulation) Reseed(seed int64) {
 func (sim *Simulation) run() *proto.RaidSimResult {
 	t0 := time.Now()
 
-	logsBuffer := &strings.Builder{}
+	logs := &eventLog{keepEvents: sim.Options.LogEvents}
 	if sim.Options.Debug || sim.Options.DebugFirstIteration {
-		sim.Log = func(message string, vals ...interface{}) {
-			logsBuffer.WriteString(fmt.Sprintf("[%0.2f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
-		}
+		sim.enableLogging(logs)
 	}
 
 	// Uncomment this to print logs directly to console.
@@ -314,7 +330,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 	totalDuration := firstIterationDuration
 
 	if !sim.Options.Debug {
-		sim.Log = nil
+		sim.disableLogging()
 	}
 
 	var st time.Time
@@ -353,7 +369,8 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		RaidMetrics:      sim.Raid.GetMetrics(),
 		EncounterMetrics: sim.Encounter.GetMetricsProto(),
 
-		Logs:                   logsBuffer.String(),
+		Logs:                   logs.text.String(),
+		LogEvents:              logs.events,
 		FirstIterationDuration: firstIterationDuration.Seconds(),
 		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
 		IterationsDone:         sim.Options.Iterations,
@@ -566,6 +583,9 @@ func (sim *Simulation) advanceTasks() {
 
 // Advance moves time forward counting down auras, CDs, mana regen, etc
 func (sim *Simulation) advance(nextTime time.Duration) {
+	for _, unit := range sim.timelineUnits {
+		unit.Metrics.timelines.recordResources(nextTime)
+	}
 	sim.CurrentTime = nextTime
 
 	// this is a loop to handle duplicate ExecuteProportions, e.g. if they're all set to 100%, you reach
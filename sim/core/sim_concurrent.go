@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"slices"
 
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
@@ -194,6 +195,7 @@ func (rsrc *raidSimResultCombiner) addActionMetrics(unit *proto.UnitMetrics, add
 		baseTgt.Healing += addTgt.Healing
 		baseTgt.CritHealing += addTgt.CritHealing
 		baseTgt.Shielding += addTgt.Shielding
+		baseTgt.Overhealing += addTgt.Overhealing
 		baseTgt.CastTimeMs += addTgt.CastTimeMs
 	}
 }
@@ -259,11 +261,140 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 		rsrc.addResourceMetrics(base, addResource)
 	}
 
+	rsrc.combineTimelines(base, add.Timelines, isLast)
+	rsrc.combineAPLProfile(base, add.AplProfile, weight)
+
 	for i, addPet := range add.Pets {
 		rsrc.combineUnitMetrics(base.Pets[i], addPet, isLast, weight)
 	}
 }
 
+// Adds the values times their weights to the sums, extending the sums as needed.
+func addWeightedTimeline(sums []float64, values []float64, weights []float64) []float64 {
+	for len(sums) < len(values) {
+		sums = append(sums, 0)
+	}
+	for i, value := range values {
+		sums[i] += value * weights[i]
+	}
+	return sums
+}
+
+func divideTimeline(sums []float64, weights []float64) {
+	for i := range sums {
+		if i < len(weights) && weights[i] > 0 {
+			sums[i] /= weights[i]
+		}
+	}
+}
+
+// Timelines are averaged per bucket, weighted by the simulated seconds of the bucket in each
+// result, or by the number of iterations for casts. Until the last result, the combined
+// timelines hold the weighted sums.
+func (rsrc *raidSimResultCombiner) combineTimelines(base *proto.UnitMetrics, add *proto.UnitTimelines, isLast bool) {
+	if add == nil {
+		return
+	}
+	if base.Timelines == nil {
+		base.Timelines = &proto.UnitTimelines{BucketSeconds: add.BucketSeconds}
+	}
+	bt := base.Timelines
+
+	iterations := make([]float64, len(add.Iterations))
+	for i, n := range add.Iterations {
+		iterations[i] = float64(n)
+		if i < len(bt.Iterations) {
+			bt.Iterations[i] += n
+		} else {
+			bt.Iterations = append(bt.Iterations, n)
+		}
+	}
+	bt.Seconds = addWeightedTimeline(bt.Seconds, add.Seconds, slices.Repeat([]float64{1}, len(add.Seconds)))
+	bt.Dps = addWeightedTimeline(bt.Dps, add.Dps, add.Seconds)
+
+	for _, addResource := range add.Resources {
+		idx := slices.IndexFunc(bt.Resources, func(r *proto.ResourceTimeline) bool {
+			return r.Type == addResource.Type && r.SecondaryType == addResource.SecondaryType
+		})
+		if idx == -1 {
+			idx = len(bt.Resources)
+			bt.Resources = append(bt.Resources, &proto.ResourceTimeline{Type: addResource.Type, SecondaryType: addResource.SecondaryType})
+		}
+		bt.Resources[idx].Levels = addWeightedTimeline(bt.Resources[idx].Levels, addResource.Levels, add.Seconds)
+	}
+	for _, addAura := range add.Auras {
+		idx := slices.IndexFunc(bt.Auras, func(a *proto.AuraTimeline) bool { return a.Id.String() == addAura.Id.String() })
+		if idx == -1 {
+			idx = len(bt.Auras)
+			bt.Auras = append(bt.Auras, &proto.AuraTimeline{Id: addAura.Id})
+		}
+		bt.Auras[idx].Uptime = addWeightedTimeline(bt.Auras[idx].Uptime, addAura.Uptime, add.Seconds)
+	}
+	for _, addCast := range add.Casts {
+		idx := slices.IndexFunc(bt.Casts, func(c *proto.CastTimeline) bool { return c.Id.String() == addCast.Id.String() })
+		if idx == -1 {
+			idx = len(bt.Casts)
+			bt.Casts = append(bt.Casts, &proto.CastTimeline{Id: addCast.Id})
+		}
+		bt.Casts[idx].Casts = addWeightedTimeline(bt.Casts[idx].Casts, addCast.Casts, iterations)
+	}
+
+	if isLast {
+		combinedIterations := make([]float64, len(bt.Iterations))
+		for i, n := range bt.Iterations {
+			combinedIterations[i] = float64(n)
+		}
+		divideTimeline(bt.Dps, bt.Seconds)
+		for _, resource := range bt.Resources {
+			divideTimeline(resource.Levels, bt.Seconds)
+		}
+		for _, aura := range bt.Auras {
+			divideTimeline(aura.Uptime, bt.Seconds)
+		}
+		for _, cast := range bt.Casts {
+			divideTimeline(cast.Casts, combinedIterations)
+		}
+	}
+}
+
+// Profiles are averages per iteration and minute, so they are weighted like the other averages.
+func (rsrc *raidSimResultCombiner) combineAPLProfile(base *proto.UnitMetrics, add *proto.APLProfile, weight float64) {
+	if add == nil {
+		return
+	}
+	if base.AplProfile == nil {
+		base.AplProfile = &proto.APLProfile{}
+	}
+	bp := base.AplProfile
+
+	combineItems := func(base []*proto.APLItemProfile, add []*proto.APLItemProfile) []*proto.APLItemProfile {
+		for len(base) < len(add) {
+			base = append(base, &proto.APLItemProfile{})
+		}
+		for i, addItem := range add {
+			baseItem := base[i]
+			baseItem.Evaluations += addItem.Evaluations * weight
+			baseItem.ConditionMet += addItem.ConditionMet * weight
+			baseItem.Ready += addItem.Ready * weight
+			baseItem.Executions += addItem.Executions * weight
+			baseItem.EvaluationsPerMinute += addItem.EvaluationsPerMinute * weight
+			baseItem.ConditionMetPerMinute += addItem.ConditionMetPerMinute * weight
+			baseItem.ReadyPerMinute += addItem.ReadyPerMinute * weight
+			baseItem.ExecutionsPerMinute += addItem.ExecutionsPerMinute * weight
+		}
+		return base
+	}
+
+	bp.PrepullActions = combineItems(bp.PrepullActions, add.PrepullActions)
+	bp.PriorityList = combineItems(bp.PriorityList, add.PriorityList)
+	for len(bp.ActionLists) < len(add.ActionLists) {
+		bp.ActionLists = append(bp.ActionLists, &proto.APLActionListProfile{Name: add.ActionLists[len(bp.ActionLists)].Name})
+	}
+	for i, addList := range add.ActionLists {
+		bp.ActionLists[i].Items = combineItems(bp.ActionLists[i].Items, addList.Items)
+	}
+}
+
 func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast bool, weight float64) {
 	rsrc.combineDistMetrics(rsrc.Combined.RaidMetrics.Dps, result.RaidMetrics.Dps, isLast, weight)
 	rsrc.combineDistMetrics(rsrc.Combined.RaidMetrics.Hps, result.RaidMetrics.Hps, isLast, weight)
@@ -286,6 +417,7 @@ func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast
 
 	if rsrc.Debug {
 		rsrc.Combined.Logs += "-SIMSTART-\n" + result.Logs
+		rsrc.Combined.LogEvents = append(rsrc.Combined.LogEvents, result.LogEvents...)
 	}
 }
 
@@ -304,6 +436,7 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 
 	if !rsrc.Debug {
 		newRsr.Logs = baseRsr.Logs
+		newRsr.LogEvents = baseRsr.LogEvents
 	}
 
 	for i, party := range baseRsr.RaidMetrics.Parties {
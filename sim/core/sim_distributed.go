@@ -0,0 +1,97 @@
+package core
+
+import (
+	"log"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+// ShardRunner runs raid sims outside of this process, e.g. on worker processes. Raid sims
+// and stat weights are split into shards by iterations, and bulk sims run one combo per shard.
+type ShardRunner interface {
+	// Number of shards which can run at the same time.
+	Capacity() int
+	// Runs a single-threaded raid sim to completion. Shards lost to a failing worker should be
+	// re-issued by the runner, so an error is only returned if the sim itself failed or was aborted.
+	RunShard(request *proto.RaidSimRequest, signals simsignals.Signals) *proto.RaidSimResult
+}
+
+// raidSimFunc runs a raid sim, sending its progress to the channel and closing it when done.
+type raidSimFunc func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, simsignals.Signals) *proto.RaidSimResult
+
+type shardResult struct {
+	idx    int
+	result *proto.RaidSimResult
+}
+
+// Splits a raid sim into one shard per slot of the runner, runs all shards and combines their results.
+func runSimDistributed(runner ShardRunner, request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) (result *proto.RaidSimResult) {
+	defer func() {
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     request.SimOptions.Iterations,
+				CompletedIterations: result.IterationsDone,
+				FinalRaidResult:     result,
+			}
+			close(progress)
+		}
+	}()
+
+	splitRes := SplitSimRequestForConcurrency(request, int32(max(runner.Capacity(), 1)))
+	if splitRes.ErrorResult != "" {
+		return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: splitRes.ErrorResult}}
+	}
+
+	if !request.SimOptions.IsTest {
+		log.Printf("Running %d iterations on %d shards.", request.SimOptions.Iterations, splitRes.SplitsDone)
+	}
+
+	// Buffered, so shards still running after an error don't block.
+	results := make(chan shardResult, len(splitRes.Requests))
+	for i, req := range splitRes.Requests {
+		go func() {
+			results <- shardResult{idx: i, result: runner.RunShard(req, signals)}
+		}()
+	}
+
+	finalResults := make([]*proto.RaidSimResult, len(splitRes.Requests))
+	var iterationsDone int32
+	for range splitRes.Requests {
+		shard := <-results
+		if shard.result.Error != nil {
+			// Stop the other shards, they are of no use anymore.
+			signals.Abort.Trigger()
+			return shard.result
+		}
+		finalResults[shard.idx] = shard.result
+		iterationsDone += shard.result.IterationsDone
+
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     request.SimOptions.Iterations,
+				CompletedIterations: iterationsDone,
+				Dps:                 shard.result.RaidMetrics.Dps.Avg,
+				Hps:                 shard.result.RaidMetrics.Hps.Avg,
+			}
+		}
+	}
+
+	return CombineConcurrentSimResults(finalResults, request.SimOptions.Debug)
+}
+
+// Runs a raid sim for a bulk sim combo as a single shard.
+func distributedBulkSimRunner(runner ShardRunner) raidSimRunner {
+	return func(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, _ bool, signals simsignals.Signals) *proto.RaidSimResult {
+		result := runner.RunShard(request, signals)
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     request.SimOptions.Iterations,
+				CompletedIterations: request.SimOptions.Iterations,
+				FinalRaidResult:     result,
+			}
+			close(progress)
+		}
+		return result
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+type fakeShardRunner struct {
+	capacity  int
+	failSeed  int64
+	mu        sync.Mutex
+	numShards int
+}
+
+func (r *fakeShardRunner) Capacity() int { return r.capacity }
+
+// Returns a result with the seed of the shard as its DPS.
+func (r *fakeShardRunner) RunShard(request *proto.RaidSimRequest, signals simsignals.Signals) *proto.RaidSimResult {
+	r.mu.Lock()
+	r.numShards++
+	r.mu.Unlock()
+
+	if r.failSeed != 0 && request.SimOptions.RandomSeed == r.failSeed {
+		return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: "shard failed"}}
+	}
+	iterations := request.SimOptions.Iterations
+	distMetrics := func(avg float64) *proto.DistributionMetrics {
+		return &proto.DistributionMetrics{Avg: avg, AggregatorData: &proto.AggregatorData{N: iterations, SumSq: avg * avg * float64(iterations)}}
+	}
+	return &proto.RaidSimResult{
+		RaidMetrics:      &proto.RaidMetrics{Dps: distMetrics(float64(request.SimOptions.RandomSeed)), Hps: distMetrics(0)},
+		EncounterMetrics: &proto.EncounterMetrics{},
+		IterationsDone:   iterations,
+	}
+}
+
+func TestRunSimDistributed(t *testing.T) {
+	request := &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 10, RandomSeed: 100, IsTest: true}}
+	runner := &fakeShardRunner{capacity: 3}
+	progress := make(chan *proto.ProgressMetrics, 10)
+
+	result := runSimDistributed(runner, request, progress, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %s", result.Error.Message)
+	}
+	if runner.numShards != 3 || result.IterationsDone != 10 {
+		t.Fatalf("Expected 10 iterations over 3 shards, got %d over %d", result.IterationsDone, runner.numShards)
+	}
+	// The shards start at seeds 100, 104 and 107, with 4, 3 and 3 iterations.
+	if expected := (100.0*4 + 104*3 + 107*3) / 10; math.Abs(result.RaidMetrics.Dps.Avg-expected) > 1e-9 {
+		t.Fatalf("Expected combined DPS %f, got %f", expected, result.RaidMetrics.Dps.Avg)
+	}
+
+	var last *proto.ProgressMetrics
+	for msg := range progress {
+		last = msg
+	}
+	if last.FinalRaidResult != result || last.CompletedIterations != 10 {
+		t.Fatalf("Expected the final progress message to have the result, got %v", last)
+	}
+}
+
+func TestRunSimDistributedError(t *testing.T) {
+	request := &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 10, RandomSeed: 100, IsTest: true}}
+	signals := simsignals.CreateSignals()
+
+	result := runSimDistributed(&fakeShardRunner{capacity: 3, failSeed: 104}, request, nil, signals)
+	if result.Error == nil || result.Error.Message != "shard failed" {
+		t.Fatalf("Expected the shard error, got %v", result.Error)
+	}
+	if !signals.Abort.IsTriggered() {
+		t.Fatalf("Expected the other shards to be aborted")
+	}
+}
@@ -21,6 +21,11 @@ func (s *triggerSignal) IsTriggered() bool {
 	return false
 }
 
+// Returns a channel which is closed once the signal is triggered.
+func (s *triggerSignal) Done() <-chan struct{} {
+	return s.channel
+}
+
 type Signals struct {
 	Abort triggerSignal
 }
@@ -38,6 +38,10 @@ type SpellConfig struct {
 	Charges      int // The maximum amount of charges this spell can have
 	RechargeTime time.Duration
 
+	// Optional AoE radius in yards. Only has an effect when positions are
+	// modeled and the spell picks its targets with TargetsInRadius().
+	AoeRadius float64
+
 	BonusHitPercent      float64
 	BonusCritPercent     float64
 	BonusSpellPower      float64
@@ -105,9 +109,13 @@ type Spell struct {
 	ExtraCastCondition CanCastCondition
 
 	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check for DistanceFromTarget.
-	MinRange     float64
-	MaxRange     float64
-	MaxCharges   int // Maximum amount of charges the spell can have
+	MinRange   float64
+	MaxRange   float64
+	MaxCharges int // Maximum amount of charges the spell can have
+
+	AoeRadius  float64
+	aoeTargets []*Unit // Reused buffer for TargetsInRadius()
+
 	charges      int // Current amount of charges the spell has
 	RechargeTime time.Duration
 
@@ -257,6 +265,8 @@ func (unit *Unit) RegisterSpell(config SpellConfig) *Spell {
 		charges:      config.Charges,
 		MaxCharges:   config.Charges,
 		RechargeTime: config.RechargeTime,
+
+		AoeRadius: config.AoeRadius,
 	}
 
 	switch {
@@ -333,7 +343,8 @@ func (unit *Unit) RegisterSpell(config SpellConfig) *Spell {
 		spell.MaxRange = config.MaxRange
 		oldExtraCastCondition := spell.ExtraCastCondition
 		spell.ExtraCastCondition = func(sim *Simulation, target *Unit) bool {
-			if ((spell.MinRange != 0) && (spell.Unit.DistanceFromTarget < spell.MinRange)) || ((spell.MaxRange != 0) && (spell.Unit.DistanceFromTarget > spell.MaxRange)) {
+			distance := spell.Unit.DistanceTo(target)
+			if ((spell.MinRange != 0) && (distance < spell.MinRange)) || ((spell.MaxRange != 0) && (distance > spell.MaxRange)) {
 				/*if sim.Log != nil {
 					sim.Log("Cannot cast spell %s, out of range!", spell.ActionID)
 				}*/
@@ -578,7 +589,7 @@ func (spell *Spell) CanCast(sim *Simulation, target *Unit) bool {
 		return false
 	}
 
-	if ((spell.DefaultCast.GCD > 0) || (spell.Flags.Matches(SpellFlagMCD) && spell.Unit.Rotation.inSequence)) && !spell.Unit.GCD.IsReady(sim) {
+	if ((spell.DefaultCast.GCD > 0) || (spell.Flags.Matches(SpellFlagMCD) && spell.Unit.Rotation != nil && spell.Unit.Rotation.inSequence)) && !spell.Unit.GCD.IsReady(sim) {
 		//if sim.Log != nil {
 		//	sim.Log("Cant cast because of GCD")
 		//}
@@ -622,9 +633,8 @@ func (spell *Spell) Cast(sim *Simulation, target *Unit) bool {
 // Skips the actual cast and applies spell effects immediately.
 func (spell *Spell) SkipCastAndApplyEffects(sim *Simulation, target *Unit) {
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-		spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s)",
-			spell.ActionID, spell.DefaultCast.Cost, time.Duration(0))
-		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
+		spell.Unit.logCastBegan(sim, spell, spell.DefaultCast.Cost, 0, 0)
+		spell.Unit.logCastCompleted(sim, spell)
 	}
 	spell.applyEffects(sim, target)
 }
@@ -632,6 +642,9 @@ func (spell *Spell) SkipCastAndApplyEffects(sim *Simulation, target *Unit) {
 func (spell *Spell) applyEffects(sim *Simulation, target *Unit) {
 	spell.SpellMetrics[target.UnitIndex].Casts++
 	spell.casts++
+	if timelines := spell.Unit.Metrics.timelines; timelines != nil && !spell.Flags.Matches(SpellFlagPassiveSpell) {
+		timelines.addCast(sim, spell.ActionID)
+	}
 
 	// Not sure if we want to split this flag into its own?
 	// Both are used to optimize away unneccesery calls and 99%
@@ -644,9 +657,8 @@ func (spell *Spell) applyEffects(sim *Simulation, target *Unit) {
 }
 
 func (spell *Spell) ApplyAOEThreatIgnoreMultipliers(threatAmount float64) {
-	numTargets := spell.Unit.Env.GetNumTargets()
-	for i := int32(0); i < numTargets; i++ {
-		spell.SpellMetrics[i].TotalThreat += threatAmount
+	for _, target := range spell.Unit.Env.Encounter.ActiveTargetUnits {
+		spell.SpellMetrics[target.UnitIndex].TotalThreat += threatAmount
 	}
 }
 func (spell *Spell) ApplyAOEThreat(threatAmount float64) {
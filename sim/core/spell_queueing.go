@@ -111,6 +111,16 @@ func (spell *Spell) CanQueue(sim *Simulation, target *Unit) bool {
 	return true
 }
 
+// Returns when the timers which the spell waits on (hardcasts, its cooldowns and the GCD if
+// it uses it) are ready, ignoring resources. May be in the past.
+func (spell *Spell) CastTimersReadyAt() time.Duration {
+	readyAt := max(spell.Unit.Hardcast.Expires, BothTimersReadyAt(spell.CD.Timer, spell.SharedCD.Timer))
+	if (spell.DefaultCast.GCD > 0) || spell.Flags.Matches(SpellFlagMCD) {
+		readyAt = max(readyAt, spell.Unit.GCD.ReadyAt())
+	}
+	return readyAt
+}
+
 // Helper function for APL checks to prevent infinite loops
 func (spell *Spell) CanCastOrQueue(sim *Simulation, target *Unit) bool {
 	return spell.Unit.CanQueueSpell(sim) && spell.CanQueue(sim, target)
@@ -120,15 +130,8 @@ func (spell *Spell) CastOrQueue(sim *Simulation, target *Unit) {
 	if spell.CanCast(sim, target) {
 		spell.Cast(sim, target)
 	} else if spell.CanQueue(sim, target) {
-		// Determine which timer the spell is waiting on
-		queueTime := max(spell.Unit.Hardcast.Expires, BothTimersReadyAt(spell.CD.Timer, spell.SharedCD.Timer))
-
-		if (spell.DefaultCast.GCD > 0) || spell.Flags.Matches(SpellFlagMCD) {
-			queueTime = max(queueTime, spell.Unit.GCD.ReadyAt())
-		}
-
 		// Schedule the cast to go off without delay
-		spell.Unit.QueueSpell(sim, spell, target, queueTime)
+		spell.Unit.QueueSpell(sim, spell, target, spell.CastTimersReadyAt())
 	} else {
 		// Fallback to make sure there is always log output
 		spell.Cast(sim, target)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
 )
 
@@ -299,6 +300,10 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.SpellMetrics[result.Target.UnitIndex].TotalBlockDamage += result.Damage
 		}
 		spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+
+		if timelines := spell.Unit.Metrics.timelines; timelines != nil && spell.Unit.IsOpponent(result.Target) {
+			timelines.addDamage(sim, result.Damage)
+		}
 	}
 
 	// Mark total damage done in raid so far for health based fights.
@@ -308,11 +313,7 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 	}
 
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-		if isPeriodic {
-			spell.Unit.Log(sim, "%s %s tick %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
-		} else {
-			spell.Unit.Log(sim, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
-		}
+		spell.logHit(sim, proto.HitLogEvent_Damage, result.Target, result.Outcome, isPeriodic, result.Damage, result.Threat)
 	}
 
 	if !spell.Flags.Matches(SpellFlagNoOnDamageDealt) {
@@ -435,15 +436,13 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	spell.SpellMetrics[result.Target.UnitIndex].TotalHealing += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
 	if result.Target.HasHealthBar() {
+		missingHealth := result.Target.MaxHealth() - result.Target.CurrentHealth()
+		spell.SpellMetrics[result.Target.UnitIndex].TotalOverhealing += max(0, result.Damage-missingHealth)
 		result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
 	}
 
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
-		if isPeriodic {
-			spell.Unit.Log(sim, "%s %s tick %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
-		} else {
-			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
-		}
+		spell.logHit(sim, proto.HitLogEvent_Healing, result.Target, result.Outcome, isPeriodic, result.Damage, result.Threat)
 	}
 
 	if isPeriodic {
@@ -507,6 +506,11 @@ func (spell *Spell) attackerDamageMultiplierInternal(attackTable *AttackTable) f
 }
 
 func (result *SpellResult) applyTargetModifiers(sim *Simulation, spell *Spell, attackTable *AttackTable, isPeriodic bool) {
+	if !attackTable.Defender.IsEnabled() {
+		result.Damage = 0
+		return
+	}
+
 	if spell.Flags.Matches(SpellFlagIgnoreTargetModifiers) {
 		return
 	}
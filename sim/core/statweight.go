@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math"
 	"strings"
-	"time"
 
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
@@ -66,6 +65,11 @@ type StatWeightValues struct {
 	WeightsStdev  UnitStats
 	EpValues      UnitStats
 	EpValuesStdev UnitStats
+
+	// Summed variances of the differences to the baseline sim, both as simmed with common
+	// random numbers and as they would be with independent random numbers.
+	pairedVariance      float64
+	independentVariance float64
 }
 
 func NewStatWeightValues() StatWeightValues {
@@ -83,9 +87,18 @@ func (swv *StatWeightValues) ToProto() *proto.StatWeightValues {
 		WeightsStdev:  swv.WeightsStdev.ExportWeights(),
 		EpValues:      swv.EpValues.ExportWeights(),
 		EpValuesStdev: swv.EpValuesStdev.ExportWeights(),
+
+		VarianceReduction: swv.varianceReduction(),
 	}
 }
 
+func (swv *StatWeightValues) varianceReduction() float64 {
+	if swv.pairedVariance <= 0 {
+		return 0
+	}
+	return swv.independentVariance / swv.pairedVariance
+}
+
 type StatWeightsResult struct {
 	Dps    StatWeightValues
 	Hps    StatWeightValues
@@ -131,21 +144,13 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 	raidProto := SinglePlayerRaidProto(swr.Player, swr.PartyBuffs, swr.RaidBuffs, swr.Debuffs)
 	raidProto.Tanks = swr.Tanks
 
-	swr.SimOptions.SaveAllValues = true
-
 	// Cut in half since we're doing above and below separately.
 	// This number needs to be the same for the baseline sim too, so that RNG lines up perfectly.
 	swr.SimOptions.Iterations /= 2
 
-	// Make sure an RNG seed is always set because it gives more consistent results.
-	// When there is no user-supplied seed it needs to be a randomly-selected seed
-	// though, so that run-run differences still exist.
-	if swr.SimOptions.RandomSeed == 0 {
-		swr.SimOptions.RandomSeed = time.Now().UnixNano()
-	}
-
-	// Reduce variance even more by using test-level RNG controls.
-	swr.SimOptions.UseLabeledRands = true
+	// Weights are computed from the per-iteration differences to the baseline sim,
+	// so every sim needs to use the same random numbers.
+	enableCommonRandomNumbers(swr.SimOptions)
 
 	swBaseResponse := &proto.StatWeightRequestsData{
 		BaseRequest: &proto.RaidSimRequest{
@@ -272,10 +277,18 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 			for i := range baselineMetrics.AllValues {
 				lo.add(modLowMetrics.AllValues[i] - baselineMetrics.AllValues[i])
 			}
-			lo.scale(1 / statResult.StatData.ModLow)
 			for i := range baselineMetrics.AllValues {
 				hi.add(modHighMetrics.AllValues[i] - baselineMetrics.AllValues[i])
 			}
+
+			if len(baselineMetrics.AllValues) > 0 {
+				_, loStdev := lo.meanAndStdDev()
+				_, hiStdev := hi.meanAndStdDev()
+				weightResults.pairedVariance += loStdev*loStdev + hiStdev*hiStdev
+				weightResults.independentVariance += 2*baselineMetrics.Stdev*baselineMetrics.Stdev + modLowMetrics.Stdev*modLowMetrics.Stdev + modHighMetrics.Stdev*modHighMetrics.Stdev
+			}
+
+			lo.scale(1 / statResult.StatData.ModLow)
 			hi.scale(1 / statResult.StatData.ModHigh)
 
 			mean, stdev := lo.merge(&hi).meanAndStdDev()
@@ -321,8 +334,9 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 	return result.ToProto()
 }
 
-// Run stat weight sims and compute weights.
-func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.StatWeightsResult {
+// Run stat weight sims and compute weights. Each sim is run with simFunc, or in this
+// process if it is nil.
+func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals, simFunc raidSimFunc) *proto.StatWeightsResult {
 	requestData := buildStatWeightRequests(request)
 
 	var iterationsTotal int32 = requestData.BaseRequest.SimOptions.Iterations
@@ -359,10 +373,12 @@ func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.Prog
 		return nil
 	}
 
-	simFunc := runSimConcurrent
-	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
-	if IsRunningInWasm() {
-		simFunc = RunSim
+	if simFunc == nil {
+		simFunc = runSimConcurrent
+		// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+		if IsRunningInWasm() {
+			simFunc = RunSim
+		}
 	}
 
 	baseProgress := make(chan *proto.ProgressMetrics, 100)
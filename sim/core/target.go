@@ -14,6 +14,7 @@ type Encounter struct {
 	Targets           []*Target
 	ActiveTargets     []*Target
 	TargetUnits       []*Unit
+	ActiveTargetUnits []*Unit
 
 	ExecuteProportion_20 float64
 	ExecuteProportion_25 float64
@@ -53,6 +54,7 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		encounter.Targets = append(encounter.Targets, target)
 		encounter.ActiveTargets = append(encounter.ActiveTargets, target)
 		encounter.TargetUnits = append(encounter.TargetUnits, &target.Unit)
+		encounter.ActiveTargetUnits = append(encounter.ActiveTargetUnits, &target.Unit)
 	}
 	if len(encounter.Targets) == 0 {
 		// Add a dummy target. The only case where targets aren't specified is when
@@ -61,6 +63,14 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		encounter.Targets = append(encounter.Targets, target)
 		encounter.ActiveTargets = append(encounter.ActiveTargets, target)
 		encounter.TargetUnits = append(encounter.TargetUnits, &target.Unit)
+		encounter.ActiveTargetUnits = append(encounter.ActiveTargetUnits, &target.Unit)
+	}
+
+	// A scripted timeline takes over the controller target, replacing any preset AI.
+	if (options.Script != nil) && (encounterScriptAIFactory != nil) {
+		if controllerIndex := options.Script.ControllerIndex; (controllerIndex >= 0) && (int(controllerIndex) < len(encounter.Targets)) {
+			encounter.Targets[controllerIndex].AI = encounterScriptAIFactory(options.Script)
+		}
 	}
 
 	// If UseHealth is set, we use the sum of targets health. After creating the targets to make sure stat modifications are done
@@ -121,6 +131,11 @@ type Target struct {
 	AI TargetAI
 }
 
+// Returns the label of the target with the given index in the encounter.
+func targetLabel(targetIndex int32) string {
+	return "Target " + strconv.Itoa(int(targetIndex)+1)
+}
+
 func NewTarget(options *proto.Target, targetIndex int32) *Target {
 	unitStats := stats.Stats{}
 	if options.Stats != nil {
@@ -131,7 +146,7 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 		Unit: Unit{
 			Type:        EnemyUnit,
 			Index:       targetIndex,
-			Label:       "Target " + strconv.Itoa(int(targetIndex)+1),
+			Label:       targetLabel(targetIndex),
 			Level:       options.Level,
 			MobType:     options.MobType,
 			auraTracker: newAuraTracker(),
@@ -165,6 +180,11 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 	target.PseudoStats.InFrontOfTarget = true
 	target.PseudoStats.DamageSpread = options.DamageSpread
 
+	if options.Position != nil {
+		target.StartPosition = Vector2FromProto(options.Position)
+		target.hasStartPosition = true
+	}
+
 	preset := GetPresetTargetWithID(options.Id)
 	if preset != nil && preset.AI != nil {
 		target.AI = preset.AI()
@@ -174,6 +194,10 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 }
 
 func (target *Target) Reset(sim *Simulation) {
+	if !target.IsActive {
+		target.SetActive(true)
+	}
+
 	target.Unit.reset(sim, nil)
 	target.CurrentTarget = target.defaultTarget
 
@@ -183,12 +207,46 @@ func (target *Target) Reset(sim *Simulation) {
 	}
 }
 
+// Enables or disables a target mid-iteration, e.g. for adds that spawn partway
+// through an encounter. Inactive targets are excluded from ActiveTargets and
+// take no damage.
+func (target *Target) SetActive(active bool) {
+	target.IsActive = active
+	target.enabled = active
+
+	encounter := &target.Env.Encounter
+	encounter.ActiveTargets = FilterSlice(encounter.Targets, func(t *Target) bool {
+		return t.IsActive
+	})
+	encounter.ActiveTargetUnits = MapSlice(encounter.ActiveTargets, func(t *Target) *Unit {
+		return &t.Unit
+	})
+}
+
+// Returns the unit currently tanking this target. Individual non-tank sims have
+// no tank, so tank-targeted boss abilities fall back to the first player.
+func (target *Target) TankOrFirstPlayer() *Unit {
+	if target.CurrentTarget != nil {
+		return target.CurrentTarget
+	}
+
+	return &target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+}
+
+// Returns the active target after this one, wrapping around. Inactive targets
+// cycle to the first active target after them.
 func (target *Target) NextTarget() *Target {
-	nextIndex := target.Index + 1
-	if nextIndex >= target.Env.GetNumTargets() {
-		nextIndex = 0
+	activeTargets := target.Env.Encounter.ActiveTargets
+	if len(activeTargets) == 0 {
+		return target
+	}
+
+	for _, nextTarget := range activeTargets {
+		if nextTarget.Index > target.Index {
+			return nextTarget
+		}
 	}
-	return target.Env.GetTarget(nextIndex)
+	return activeTargets[0]
 }
 
 func (target *Target) GetMetricsProto() *proto.UnitMetrics {
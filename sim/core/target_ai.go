@@ -63,6 +63,14 @@ func (target *Target) ExecuteCustomRotation(sim *Simulation) {
 
 type AIFactory func() TargetAI
 
+// Builds the generic TargetAI that interprets data-driven encounter scripts.
+// Registered by the encounters package, since core cannot import it.
+var encounterScriptAIFactory func(*proto.EncounterScript) TargetAI
+
+func RegisterEncounterScriptAI(factory func(*proto.EncounterScript) TargetAI) {
+	encounterScriptAIFactory = factory
+}
+
 type PresetTarget struct {
 	// String in folder-structure format identifying a category for this unit, e.g. "Black Temple/Bosses".
 	PathPrefix string
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestSetActiveTargets(t *testing.T) {
+	env := &Environment{}
+	for i := int32(0); i < 3; i++ {
+		target := &Target{Unit: Unit{Env: env, Index: i}, IsActive: true}
+		env.Encounter.Targets = append(env.Encounter.Targets, target)
+		env.Encounter.TargetUnits = append(env.Encounter.TargetUnits, &target.Unit)
+	}
+	targets := env.Encounter.Targets
+	targets[0].SetActive(true)
+
+	if env.GetNumTargets() != 3 || targets[0].NextTarget() != targets[1] || targets[2].NextTarget() != targets[0] {
+		t.Fatalf("Expected targets to cycle over all 3 targets")
+	}
+
+	targets[1].SetActive(false)
+	if env.GetNumTargets() != 2 || len(env.Encounter.ActiveTargetUnits) != 2 {
+		t.Fatalf("Expected 2 active targets, got %d", env.GetNumTargets())
+	}
+	if env.GetActiveTarget(1) != targets[2] || env.GetActiveTargetUnit(1) != &targets[2].Unit {
+		t.Fatalf("Expected the second active target to be the third target")
+	}
+	if env.GetTarget(1) != targets[1] || env.GetTargetUnit(2) != &targets[2].Unit {
+		t.Fatalf("Expected target lookups by index to include inactive targets")
+	}
+	if targets[0].NextTarget() != targets[2] || targets[1].NextTarget() != targets[2] || targets[2].NextTarget() != targets[0] {
+		t.Fatalf("Expected target cycling to skip the inactive target")
+	}
+
+	targets[0].SetActive(false)
+	targets[2].SetActive(false)
+	if env.GetNumTargets() != 0 || targets[2].NextTarget() != targets[2] {
+		t.Fatalf("Expected a target to be its own next target without active targets")
+	}
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Sums of a value in each bucket of a timeline, over all iterations.
+type timeline struct {
+	bucketSize time.Duration
+	values     []float64
+}
+
+func (tl *timeline) bucket(t time.Duration) int {
+	idx := int(max(t, 0) / tl.bucketSize)
+	for len(tl.values) <= idx {
+		tl.values = append(tl.values, 0)
+	}
+	return idx
+}
+
+// Adds an amount at a single point in time.
+func (tl *timeline) addAt(t time.Duration, amount float64) {
+	tl.values[tl.bucket(t)] += amount
+}
+
+// Adds an amount per second over a span of time, split over the buckets it overlaps.
+func (tl *timeline) addSpan(from time.Duration, to time.Duration, perSecond float64) {
+	from = max(from, 0)
+	for from < to {
+		idx := tl.bucket(from)
+		end := min(to, time.Duration(idx+1)*tl.bucketSize)
+		tl.values[idx] += (end - from).Seconds() * perSecond
+		from = end
+	}
+}
+
+func (tl *timeline) addTimeline(other *timeline) {
+	for i, value := range other.values {
+		tl.values[tl.bucket(time.Duration(i)*tl.bucketSize)] += value
+	}
+}
+
+func (tl *timeline) reset() {
+	tl.values = tl.values[:0]
+}
+
+// Returns the values divided by the given weights, with one value per weight.
+func (tl *timeline) averages(weights []float64) []float64 {
+	averages := make([]float64, len(weights))
+	for i, value := range tl.values {
+		if i < len(weights) && weights[i] > 0 {
+			averages[i] = value / weights[i]
+		}
+	}
+	return averages
+}
+
+// A resource of a unit, with a getter for its current level.
+type unitResource struct {
+	resourceType  proto.ResourceType
+	secondaryType proto.SecondaryResourceType
+	level         func() float64
+}
+
+// Returns the resources the unit has a bar for.
+func unitResources(unit *Unit) []unitResource {
+	var resources []unitResource
+	addResource := func(resourceType proto.ResourceType, level func() float64) {
+		resources = append(resources, unitResource{resourceType: resourceType, level: level})
+	}
+	if unit.HasManaBar() {
+		addResource(proto.ResourceType_ResourceTypeMana, unit.CurrentMana)
+	}
+	if unit.HasRageBar() {
+		addResource(proto.ResourceType_ResourceTypeRage, unit.CurrentRage)
+	}
+	if unit.HasEnergyBar() {
+		addResource(proto.ResourceType_ResourceTypeEnergy, unit.CurrentEnergy)
+		if unit.MaxComboPoints() > 0 {
+			comboPointsType := Ternary(unit.comboPointsResourceName == "chi", proto.ResourceType_ResourceTypeChi, proto.ResourceType_ResourceTypeComboPoints)
+			addResource(comboPointsType, func() float64 { return float64(unit.ComboPoints()) })
+		}
+	}
+	if unit.HasFocusBar() {
+		addResource(proto.ResourceType_ResourceTypeFocus, unit.CurrentFocus)
+	}
+	if unit.HasRunicPowerBar() {
+		addResource(proto.ResourceType_ResourceTypeRunicPower, unit.CurrentRunicPower)
+	}
+	if bar := unit.secondaryResourceBar; bar != nil {
+		addResource(proto.ResourceType_ResourceTypeGenericResource, func() float64 { return float64(bar.Value()) })
+		resources[len(resources)-1].secondaryType = bar.Type()
+	}
+	return resources
+}
+
+type resourceTimeline struct {
+	timeline
+	unitResource
+}
+
+type actionTimeline struct {
+	timeline
+	actionID ActionID
+}
+
+// Timeline metrics of a unit, recorded when SimOptions.TimelineBucketSeconds is set.
+type unitTimelines struct {
+	bucketSize time.Duration
+
+	iterations []int32
+	seconds    timeline
+
+	// Damage done in the current iteration, so pets can add theirs to their owner.
+	iterationDamage timeline
+	damage          timeline
+
+	resources []*resourceTimeline
+	// Time up to which the resource levels have been recorded, in the current iteration.
+	resourcesRecordedAt time.Duration
+
+	auras       []*actionTimeline
+	auraIndices map[ActionID]int
+	casts       []*actionTimeline
+	castIndices map[ActionID]int
+}
+
+func newUnitTimelines(unit *Unit, bucketSize time.Duration) *unitTimelines {
+	ut := &unitTimelines{
+		bucketSize:      bucketSize,
+		seconds:         timeline{bucketSize: bucketSize},
+		iterationDamage: timeline{bucketSize: bucketSize},
+		damage:          timeline{bucketSize: bucketSize},
+		auraIndices:     make(map[ActionID]int),
+		castIndices:     make(map[ActionID]int),
+	}
+
+	for _, resource := range unitResources(unit) {
+		ut.resources = append(ut.resources, &resourceTimeline{
+			timeline:     timeline{bucketSize: bucketSize},
+			unitResource: resource,
+		})
+	}
+
+	return ut
+}
+
+// Enables timeline metrics for all units, if requested by the sim options.
+func (sim *Simulation) initTimelines() {
+	if sim.Options.TimelineBucketSeconds <= 0 {
+		return
+	}
+	bucketSize := DurationFromSeconds(sim.Options.TimelineBucketSeconds)
+	for _, unit := range sim.AllUnits {
+		unit.Metrics.timelines = newUnitTimelines(unit, bucketSize)
+		sim.timelineUnits = append(sim.timelineUnits, unit)
+	}
+}
+
+// Records the resource levels of the unit up to the given time. Resources only change within
+// events, so their levels are constant in between.
+func (ut *unitTimelines) recordResources(until time.Duration) {
+	for _, resource := range ut.resources {
+		resource.addSpan(ut.resourcesRecordedAt, until, resource.level())
+	}
+	ut.resourcesRecordedAt = until
+}
+
+func (ut *unitTimelines) addDamage(sim *Simulation, damage float64) {
+	ut.iterationDamage.addAt(sim.CurrentTime, damage)
+}
+
+func actionTimelineFor(timelines *[]*actionTimeline, indices map[ActionID]int, actionID ActionID, bucketSize time.Duration) *actionTimeline {
+	idx, ok := indices[actionID]
+	if !ok {
+		idx = len(*timelines)
+		indices[actionID] = idx
+		*timelines = append(*timelines, &actionTimeline{timeline: timeline{bucketSize: bucketSize}, actionID: actionID})
+	}
+	return (*timelines)[idx]
+}
+
+func (ut *unitTimelines) addAuraUptime(actionID ActionID, from time.Duration, to time.Duration) {
+	actionTimelineFor(&ut.auras, ut.auraIndices, actionID, ut.bucketSize).addSpan(from, to, 1)
+}
+
+func (ut *unitTimelines) addCast(sim *Simulation, actionID ActionID) {
+	actionTimelineFor(&ut.casts, ut.castIndices, actionID, ut.bucketSize).addAt(sim.CurrentTime, 1)
+}
+
+func (ut *unitTimelines) reset() {
+	ut.iterationDamage.reset()
+	ut.resourcesRecordedAt = 0
+}
+
+// This should be called when a Sim iteration is complete, after pets have added their damage.
+func (ut *unitTimelines) doneIteration(sim *Simulation) {
+	ut.recordResources(sim.Duration)
+	ut.damage.addTimeline(&ut.iterationDamage)
+
+	ut.seconds.addSpan(0, sim.Duration, 1)
+	numBuckets := int((sim.Duration + ut.bucketSize - 1) / ut.bucketSize)
+	for len(ut.iterations) < numBuckets {
+		ut.iterations = append(ut.iterations, 0)
+	}
+	for i := 0; i < numBuckets; i++ {
+		ut.iterations[i]++
+	}
+}
+
+func (ut *unitTimelines) ToProto() *proto.UnitTimelines {
+	seconds := ut.seconds.values
+	iterations := make([]float64, len(ut.iterations))
+	for i, n := range ut.iterations {
+		iterations[i] = float64(n)
+	}
+
+	timelines := &proto.UnitTimelines{
+		BucketSeconds: ut.bucketSize.Seconds(),
+		Iterations:    slices.Clone(ut.iterations),
+		Seconds:       slices.Clone(seconds),
+		Dps:           ut.damage.averages(seconds),
+	}
+	for _, resource := range ut.resources {
+		timelines.Resources = append(timelines.Resources, &proto.ResourceTimeline{
+			Type:          resource.resourceType,
+			SecondaryType: resource.secondaryType,
+			Levels:        resource.averages(seconds),
+		})
+	}
+	for _, aura := range ut.auras {
+		timelines.Auras = append(timelines.Auras, &proto.AuraTimeline{
+			Id:     aura.actionID.ToProto(),
+			Uptime: aura.averages(seconds),
+		})
+	}
+	for _, cast := range ut.casts {
+		timelines.Casts = append(timelines.Casts, &proto.CastTimeline{
+			Id:    cast.actionID.ToProto(),
+			Casts: cast.averages(iterations),
+		})
+	}
+	return timelines
+}
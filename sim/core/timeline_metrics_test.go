@@ -0,0 +1,64 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestTimelineAddSpan(t *testing.T) {
+	tl := timeline{bucketSize: time.Second * 2}
+	tl.addSpan(-time.Second, time.Millisecond*4500, 10)
+	tl.addAt(time.Second*5, 1)
+
+	if expected := []float64{20, 20, 6}; !slices.Equal(tl.values, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tl.values)
+	}
+	if expected := []float64{10, 10, 12}; !slices.Equal(tl.averages([]float64{2, 2, 0.5}), expected) {
+		t.Fatalf("Expected averages %v, got %v", expected, tl.averages([]float64{2, 2, 0.5}))
+	}
+}
+
+func TestCombineTimelines(t *testing.T) {
+	castID := ActionID{SpellID: 1}.ToProto()
+	results := []*proto.UnitTimelines{
+		{
+			BucketSeconds: 2,
+			Iterations:    []int32{2, 1},
+			Seconds:       []float64{4, 1},
+			Dps:           []float64{100, 200},
+			Resources:     []*proto.ResourceTimeline{{Type: proto.ResourceType_ResourceTypeMana, Levels: []float64{50, 10}}},
+			Casts:         []*proto.CastTimeline{{Id: castID, Casts: []float64{1, 2}}},
+		},
+		{
+			BucketSeconds: 2,
+			Iterations:    []int32{2},
+			Seconds:       []float64{4},
+			Dps:           []float64{300},
+			Resources:     []*proto.ResourceTimeline{{Type: proto.ResourceType_ResourceTypeMana, Levels: []float64{30}}},
+			Casts:         []*proto.CastTimeline{{Id: castID, Casts: []float64{2}}},
+		},
+	}
+
+	rsrc := &raidSimResultCombiner{}
+	base := &proto.UnitMetrics{}
+	for i, result := range results {
+		rsrc.combineTimelines(base, result, i == len(results)-1)
+	}
+
+	combined := base.Timelines
+	if expected := []int32{4, 1}; !slices.Equal(combined.Iterations, expected) {
+		t.Fatalf("Expected iterations %v, got %v", expected, combined.Iterations)
+	}
+	if expected := []float64{200, 200}; !slices.Equal(combined.Dps, expected) {
+		t.Fatalf("Expected DPS %v, got %v", expected, combined.Dps)
+	}
+	if expected := []float64{40, 10}; !slices.Equal(combined.Resources[0].Levels, expected) {
+		t.Fatalf("Expected mana %v, got %v", expected, combined.Resources[0].Levels)
+	}
+	if expected := []float64{1.5, 2}; !slices.Equal(combined.Casts[0].Casts, expected) {
+		t.Fatalf("Expected casts %v, got %v", expected, combined.Casts[0].Casts)
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"math"
 	"time"
 
@@ -69,11 +70,18 @@ type Unit struct {
 	// for calculating spell travel time for certain spells.
 	StartDistanceFromTarget float64
 	DistanceFromTarget      float64
-	Moving                  bool
-	movementCallbacks       []MovementCallback
-	moveAura                *Aura
-	moveSpell               *Spell
-	movementAction          *MovementAction
+
+	// Position on the ground plane of the encounter, in yards. Only used when
+	// the environment models positions, see Environment.UsePositions().
+	StartPosition    Vector2
+	Position         Vector2
+	hasStartPosition bool
+
+	Moving            bool
+	movementCallbacks []MovementCallback
+	moveAura          *Aura
+	moveSpell         *Spell
+	movementAction    *MovementAction
 
 	// Environment in which this Unit exists. This will be nil until after the
 	// construction phase.
@@ -217,7 +225,7 @@ func (unit *Unit) getAttackPowerValueImpl(spell *Spell) float64 {
 
 // Units can be disabled for several reasons:
 //  1. Downtime for temporary pets (e.g. Water Elemental)
-//  2. Enemy units in various phases (not yet implemented)
+//  2. Enemy units in various phases, see Target.SetActive()
 //  3. Dead units (not yet implemented)
 func (unit *Unit) IsEnabled() bool {
 	return unit.enabled
@@ -244,7 +252,7 @@ func (unit *Unit) LogLabel() string {
 }
 
 func (unit *Unit) Log(sim *Simulation, message string, vals ...interface{}) {
-	sim.Log(unit.LogLabel()+" "+message, vals...)
+	sim.logEvent(unit, &proto.LogEvent{Event: &proto.LogEvent_Message{Message: fmt.Sprintf(message, vals...)}})
 }
 
 func (unit *Unit) GetInitialStat(stat stats.Stat) float64 {
@@ -714,6 +722,7 @@ func (unit *Unit) reset(sim *Simulation, _ Agent) {
 	unit.ChanneledDot = nil
 	unit.QueuedSpell = nil
 	unit.DistanceFromTarget = unit.StartDistanceFromTarget
+	unit.Position = unit.StartPosition
 	unit.Metrics.reset()
 	unit.ResetStatDeps()
 	unit.statsWithoutDeps = unit.initialStatsWithoutDeps
@@ -769,6 +778,10 @@ func (unit *Unit) doneIteration(sim *Simulation) {
 	for _, spell := range unit.Spellbook {
 		spell.doneIteration()
 	}
+
+	if unit.Rotation != nil {
+		unit.Rotation.doneIteration(sim)
+	}
 }
 
 func (unit *Unit) GetSpellsMatchingSchool(school SpellSchool) []*Spell {
@@ -23,6 +23,7 @@ func (dk *DeathKnight) registerBloodBoil() {
 		SpellSchool:    core.SpellSchoolShadow,
 		ProcMask:       core.ProcMaskSpellDamage,
 		ClassSpellMask: DeathKnightSpellBloodBoil,
+		AoeRadius:      10,
 
 		RuneCost: core.RuneCostOptions{
 			BloodRuneCost: 1,
@@ -43,7 +44,8 @@ func (dk *DeathKnight) registerBloodBoil() {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			anyHit := false
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
+			aoeTargets := spell.TargetsInRadius(&dk.Unit)
+			for idx, aoeTarget := range aoeTargets {
 				baseDamage := dk.CalcAndRollDamageRange(sim, 3.09599995613, 0.20000000298) +
 					0.1099999994*spell.MeleeAttackPower()
 				baseDamage *= core.TernaryFloat64(hasGlyphOfFesteringBlood || dk.DiseasesAreActive(aoeTarget), 1.5, 1.0)
@@ -60,7 +62,7 @@ func (dk *DeathKnight) registerBloodBoil() {
 				dk.AddRunicPower(sim, 10, rpMetric)
 			}
 
-			for _, result := range results {
+			for _, result := range results[:len(aoeTargets)] {
 				spell.DealDamage(sim, result)
 			}
 		},
@@ -74,9 +76,11 @@ func (dk *DeathKnight) registerDrwBloodBoil() *core.Spell {
 		SpellSchool: core.SpellSchoolShadow,
 		Flags:       core.SpellFlagAoE,
 		ProcMask:    core.ProcMaskSpellDamage,
+		AoeRadius:   10,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
+			aoeTargets := spell.TargetsInRadius(spell.Unit)
+			for idx, aoeTarget := range aoeTargets {
 				baseDamage := dk.CalcAndRollDamageRange(sim, 3.09599995613, 0.20000000298) +
 					0.1099999994*spell.MeleeAttackPower()
 
@@ -88,7 +92,7 @@ func (dk *DeathKnight) registerDrwBloodBoil() *core.Spell {
 				results[idx] = spell.CalcDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
 
-			for _, result := range results {
+			for _, result := range results[:len(aoeTargets)] {
 				spell.DealDamage(sim, result)
 			}
 		},
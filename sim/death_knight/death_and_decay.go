@@ -18,12 +18,15 @@ and reducing their movement speed by 50%
 for 10 sec.
 */
 func (dk *DeathKnight) registerDeathAndDecay() {
+	var groundPosition core.Vector2
+
 	dk.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 43265},
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL,
 		SpellSchool:    core.SpellSchoolShadow,
 		ProcMask:       core.ProcMaskEmpty, // D&D doesn't seem to proc things in game.
 		ClassSpellMask: DeathKnightSpellDeathAndDecay,
+		AoeRadius:      10,
 
 		RuneCost: core.RuneCostOptions{
 			UnholyRuneCost: 1,
@@ -54,7 +57,7 @@ func (dk *DeathKnight) registerDeathAndDecay() {
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
 				// DnD recalculates everything on each tick
 				baseDamage := 26 + dot.Spell.MeleeAttackPower()*0.06400000304
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range dot.Spell.TargetsInRadiusOfPosition(groundPosition) {
 					dot.Spell.SpellMetrics[aoeTarget.UnitIndex].Casts++
 					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, baseDamage, dot.Spell.OutcomeMagicHitAndCrit)
 				}
@@ -62,6 +65,7 @@ func (dk *DeathKnight) registerDeathAndDecay() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			groundPosition = target.Position
 			dot := spell.AOEDot()
 			dot.Apply(sim)
 			dot.TickOnce(sim)
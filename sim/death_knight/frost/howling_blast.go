@@ -18,7 +18,8 @@ func (fdk *FrostDeathKnight) registerHowlingBlast() {
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL,
 		ClassSpellMask: death_knight.DeathKnightSpellHowlingBlast,
 
-		MaxRange: 30,
+		MaxRange:  30,
+		AoeRadius: 10,
 
 		RuneCost: core.RuneCostOptions{
 			FrostRuneCost:  1,
@@ -36,7 +37,8 @@ func (fdk *FrostDeathKnight) registerHowlingBlast() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
+			aoeTargets := spell.TargetsInRadius(target)
+			for idx, aoeTarget := range aoeTargets {
 				baseDamage := fdk.CalcScalingSpellDmg(0.46000000834) + 0.848*spell.MeleeAttackPower()
 				damageMultiplier := spell.DamageMultiplier
 
@@ -55,7 +57,7 @@ func (fdk *FrostDeathKnight) registerHowlingBlast() {
 				}
 			}
 
-			for _, result := range results {
+			for _, result := range results[:len(aoeTargets)] {
 				spell.DealDamage(sim, result)
 
 				if result.Landed() {
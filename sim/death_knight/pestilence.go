@@ -19,7 +19,8 @@ func (dk *DeathKnight) registerPestilence() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		ClassSpellMask: DeathKnightSpellPestilence,
 
-		MaxRange: maxRange,
+		MaxRange:  maxRange,
+		AoeRadius: 10 + core.TernaryFloat64(dk.HasMajorGlyph(proto.DeathKnightMajorGlyph_GlyphOfPestilence), 5, 0),
 
 		RuneCost: core.RuneCostOptions{
 			BloodRuneCost:  1,
@@ -39,7 +40,7 @@ func (dk *DeathKnight) registerPestilence() {
 			frostFeverActive := dk.FrostFeverSpell.Dot(target).IsActive()
 			bloodPlagueActive := dk.BloodPlagueSpell.Dot(target).IsActive()
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(target) {
 				result := spell.CalcAndDealOutcome(sim, aoeTarget, spell.OutcomeMagicHit)
 
 				if aoeTarget == target {
@@ -72,13 +73,14 @@ func (dk *DeathKnight) registerDrwPestilence() *core.Spell {
 		SpellSchool: core.SpellSchoolShadow,
 		ProcMask:    core.ProcMaskSpellDamage,
 
-		MaxRange: core.MaxMeleeRange,
+		MaxRange:  core.MaxMeleeRange,
+		AoeRadius: 10,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			frostFeverActive := dk.RuneWeapon.FrostFeverSpell.Dot(target).IsActive()
 			bloodPlagueActive := dk.RuneWeapon.BloodPlagueSpell.Dot(target).IsActive()
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(target) {
 				result := spell.CalcAndDealOutcome(sim, aoeTarget, spell.OutcomeMagicHit)
 
 				if result.Landed() {
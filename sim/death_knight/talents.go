@@ -137,7 +137,7 @@ func (dk *DeathKnight) registerUnholyBlight() {
 		Flags:       core.SpellFlagPassiveSpell,
 
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
-			for _, target := range sim.Encounter.TargetUnits {
+			for _, target := range sim.Encounter.ActiveTargetUnits {
 				dk.BloodPlagueSpell.Cast(sim, target)
 				dk.FrostFeverSpell.Cast(sim, target)
 			}
@@ -48,6 +48,8 @@ type Druid struct {
 	Prowl                 *DruidSpell
 	Rebirth               *DruidSpell
 	Rake                  *DruidSpell
+	Regrowth              *DruidSpell
+	Rejuvenation          *DruidSpell
 	Ravage                *DruidSpell
 	Rip                   *DruidSpell
 	SavageRoar            *DruidSpell
@@ -62,6 +64,7 @@ type Druid struct {
 	Wrath                 *DruidSpell
 	WildMushrooms         *DruidSpell
 	WildMushroomsDetonate *DruidSpell
+	WildGrowth            *DruidSpell
 
 	CatForm  *DruidSpell
 	BearForm *DruidSpell
@@ -239,6 +242,20 @@ func (druid *Druid) RegisterBaselineSpells() {
 	// druid.registerInnervateCD()
 }
 
+// Registers the heals used by Restoration, besides the baseline Healing Touch.
+func (druid *Druid) RegisterHealingSpells() {
+	druid.registerRejuvenationSpell()
+	druid.registerRegrowthSpell()
+}
+
+// Heals cast at an enemy land on the druid instead, like in game.
+func (druid *Druid) HealTargetOrSelf(target *core.Unit) *core.Unit {
+	if target.IsOpponent(&druid.Unit) {
+		return &druid.Unit
+	}
+	return target
+}
+
 func (druid *Druid) RegisterFeralCatSpells() {
 	druid.registerBearFormSpell()
 	druid.registerBerserkCD()
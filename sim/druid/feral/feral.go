@@ -24,6 +24,7 @@ func RegisterFeralDruid() {
 			player.Spec = playerSpec
 		},
 	)
+	core.RegisterSimpleRotationCompiler(proto.Spec_SpecFeralDruid, compileSimpleRotation)
 }
 
 func NewFeralDruid(character *core.Character, options *proto.Player) *FeralDruid {
@@ -0,0 +1,85 @@
+package feral
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Trinkets whose healing procs are worth stacking with a prepull Tranquility.
+var healingProcTrinkets = []int32{72898, 77969, 77204, 77989}
+
+// Matches the simple rotation generator of the Feral UI.
+func compileSimpleRotation(player *proto.Player, specRotationJson string, cooldowns *proto.Cooldowns) (*proto.APLRotation, error) {
+	simple := &proto.FeralDruid_Rotation{}
+	if err := core.ParseSimpleRotationOptions(specRotationJson, simple); err != nil {
+		return nil, err
+	}
+
+	prepullActions, actions := core.StandardCooldownDefaults(cooldowns, "", "")
+
+	actionJsons := []string{}
+	if simple.RotationType == proto.FeralDruid_Rotation_SingleTarget {
+		actionJsons = append(actionJsons,
+			// Synapse Springs
+			`{"condition":{"or":{"vals":[{"auraIsActive":{"auraId":{"spellId":5217}}},{"cmp":{"op":"OpLt","lhs":{"remainingTime":{}},"rhs":{"const":{"val":"11s"}}}}]}},"castSpell":{"spellId":{"spellId":82174}}}`,
+			// Potion
+			`{"condition":{"or":{"vals":[{"and":{"vals":[{"auraIsActive":{"auraId":{"spellId":5217}}},{"cmp":{"op":"OpLt","lhs":{"remainingTime":{}},"rhs":{"math":{"op":"OpAdd","lhs":{"spellTimeToReady":{"spellId":{"spellId":50334}}},"rhs":{"const":{"val":"26s"}}}}}}]}},{"cmp":{"op":"OpLt","lhs":{"remainingTime":{}},"rhs":{"const":{"val":"26s"}}}},{"auraIsActive":{"auraId":{"spellId":50334}}}]}},"castSpell":{"spellId":{"itemId":58145}}}`,
+			// Troll racial
+			`{"condition":{"auraIsActive":{"auraId":{"spellId":50334}}},"castSpell":{"spellId":{"spellId":26297}}}`,
+		)
+	}
+	actionJsons = append(actionJsons,
+		// Berserk and Enrage are cast by the rotation itself.
+		`{"condition":{"const":{"val":"false"}},"castSpell":{"spellId":{"spellId":50334}}}`,
+		`{"condition":{"const":{"val":"false"}},"castSpell":{"spellId":{"spellId":5229}}}`,
+		fmt.Sprintf(`{"catOptimalRotationAction":{"rotationType":%d,"manualParams":%t,"maintainFaerieFire":%t,"allowAoeBerserk":%t,"meleeWeave":%t,"bearWeave":%t,"snekWeave":%t,"minRoarOffset":%.2f,"ripLeeway":%d,"useRake":%t,"useBite":%t,"biteDuringExecute":%t,"biteTime":%.2f,"berserkBiteTime":%.2f,"cancelPrimalMadness":%t}}`,
+			simple.RotationType, simple.ManualParams, simple.MaintainFaerieFire, simple.AllowAoeBerserk, simple.MeleeWeave, simple.BearWeave, simple.SnekWeave,
+			simple.MinRoarOffset, simple.RipLeeway, simple.UseRake, simple.UseBite, simple.BiteDuringExecute, simple.BiteTime, simple.BerserkBiteTime, simple.CancelPrimalMadness),
+		`{"autocastOtherCooldowns":{}}`,
+	)
+	for _, actionJson := range actionJsons {
+		action, err := core.APLActionFromJson(actionJson)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+
+	// The UI also enables target dummies for the healing procs, which is up to the caller here.
+	if simple.PrepullTranquility && hasHealingProcTrinketSwap(player) {
+		for _, prepullJson := range []string{
+			`{"action":{"itemSwap":{"swapSet":"Swap1"}},"doAtValue":{"const":{"val":"-125s"}}}`,
+			`{"action":{"channelSpell":{"spellId":{"spellId":740},"interruptIf":{"cmp":{"op":"OpGt","lhs":{"currentTime":{}},"rhs":{"const":{"val":"-2s"}}}}}},"doAtValue":{"const":{"val":"-5.5s"}}}`,
+			`{"action":{"itemSwap":{"swapSet":"Main"}},"doAtValue":{"const":{"val":"-1.5s"}}}`,
+			`{"action":{"castSpell":{"spellId":{"spellId":768}}},"doAtValue":{"const":{"val":"-1.5s"}}}`,
+		} {
+			prepullAction, err := core.APLPrepullActionFromJson(prepullJson)
+			if err != nil {
+				return nil, err
+			}
+			prepullActions = append(prepullActions, prepullAction)
+		}
+	}
+
+	return &proto.APLRotation{
+		PrepullActions: prepullActions,
+		PriorityList: core.MapSlice(actions, func(action *proto.APLAction) *proto.APLListItem {
+			return &proto.APLListItem{Action: action}
+		}),
+	}, nil
+}
+
+func hasHealingProcTrinketSwap(player *proto.Player) bool {
+	if !player.EnableItemSwap || player.ItemSwap == nil {
+		return false
+	}
+	for _, slot := range []proto.ItemSlot{proto.ItemSlot_ItemSlotTrinket1, proto.ItemSlot_ItemSlotTrinket2} {
+		if int(slot) < len(player.ItemSwap.Items) && slices.Contains(healingProcTrinkets, player.ItemSwap.Items[slot].GetId()) {
+			return true
+		}
+	}
+	return false
+}
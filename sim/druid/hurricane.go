@@ -12,12 +12,15 @@ const (
 )
 
 func (druid *Druid) registerHurricaneSpell() {
+	var groundPosition core.Vector2
+
 	druid.HurricaneTickSpell = druid.RegisterSpell(Humanoid|Moonkin, core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 42231},
 		SpellSchool:    core.SpellSchoolNature,
 		ProcMask:       core.ProcMaskSpellProc,
 		Flags:          core.SpellFlagAoE,
 		ClassSpellMask: DruidSpellHurricane,
+		AoeRadius:      8,
 
 		CritMultiplier:   druid.DefaultCritMultiplier(),
 		DamageMultiplier: 1,
@@ -27,7 +30,7 @@ func (druid *Druid) registerHurricaneSpell() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			damage := druid.CalcScalingSpellDmg(HurricaneCoeff)
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadiusOfPosition(groundPosition) {
 				spell.CalcAndDealDamage(sim, aoeTarget, damage, spell.OutcomeMagicHitAndCrit)
 			}
 		},
@@ -61,7 +64,8 @@ func (druid *Druid) registerHurricaneSpell() {
 			},
 		},
 
-		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			groundPosition = target.Position
 			spell.AOEDot().Apply(sim)
 		},
 	})
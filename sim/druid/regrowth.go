@@ -0,0 +1,68 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const (
+	RegrowthBonusCoeff    = 0.958
+	RegrowthCoeff         = 9.81
+	RegrowthVariance      = 0.116
+	RegrowthHotBonusCoeff = 0.073
+	RegrowthHotCoeff      = 0.766
+)
+
+func (druid *Druid) registerRegrowthSpell() {
+	druid.Regrowth = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 8936},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		ClassSpellMask: DruidSpellRegrowth,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 29.7,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		BonusCoefficient: RegrowthBonusCoeff,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Regrowth",
+			},
+			NumberOfTicks:       3,
+			TickLength:          time.Second * 2,
+			AffectedByCastSpeed: true,
+
+			BonusCoefficient: RegrowthHotBonusCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, druid.CalcScalingSpellDmg(RegrowthHotCoeff))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = druid.HealTargetOrSelf(target)
+
+			baseHealing := druid.CalcAndRollDamageRange(sim, RegrowthCoeff, RegrowthVariance)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			spell.Hot(target).Apply(sim)
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const (
+	RejuvenationBonusCoeff = 0.392
+	RejuvenationCoeff      = 3.868
+)
+
+func (druid *Druid) registerRejuvenationSpell() {
+	druid.Rejuvenation = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 774},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		ClassSpellMask: DruidSpellRejuvenation,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 14.5,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Rejuvenation",
+			},
+			NumberOfTicks:       4,
+			TickLength:          time.Second * 3,
+			AffectedByCastSpeed: true,
+
+			BonusCoefficient: RejuvenationBonusCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, druid.CalcScalingSpellDmg(RejuvenationCoeff))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.Hot(druid.HealTargetOrSelf(target)).Apply(sim)
+		},
+	})
+}
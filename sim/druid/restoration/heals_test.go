@@ -0,0 +1,113 @@
+package restoration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+func init() {
+	RegisterRestorationDruid()
+}
+
+// A restoration druid healing eight target dummies across two parties, which
+// all take damage every second.
+func newHealingSim() *core.Simulation {
+	raid := core.SinglePlayerRaidProto(&proto.Player{
+		Race:      proto.Race_RaceTauren,
+		Class:     proto.Class_ClassDruid,
+		Equipment: &proto.EquipmentSpec{},
+		Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		Spec: &proto.Player_RestorationDruid{
+			RestorationDruid: &proto.RestorationDruid{
+				Options: &proto.RestorationDruid_Options{ClassOptions: &proto.DruidOptions{}},
+			},
+		},
+	}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{})
+	raid.Parties = append(raid.Parties, &proto.Party{})
+	raid.TargetDummies = 8
+	raid.IncomingDamage = &proto.IncomingDamageModel{Dtps: 5000, TankDtps: 10000, DamageVariation: 0.5}
+
+	sim := core.NewSim(&proto.RaidSimRequest{
+		Raid: raid,
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{core.NewDefaultTarget()},
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+
+	sim.Step()
+	for sim.CurrentTime < time.Second*5 {
+		sim.Step()
+	}
+	return sim
+}
+
+func getDruid(sim *core.Simulation) *druid.Druid {
+	return sim.Raid.Parties[0].Players[0].(*RestorationDruid).Druid
+}
+
+func TestWildGrowthTargets(t *testing.T) {
+	sim := newHealingSim()
+	resto := getDruid(sim)
+	target := &sim.Raid.GetTargetDummies()[7].Unit
+
+	resto.WildGrowth.SkipCastAndApplyEffects(sim, target)
+
+	// The target and the 5 most injured other players, out of the 9 in the raid.
+	numHealed := 0
+	for _, unit := range sim.Raid.AllPlayerUnits {
+		if resto.WildGrowth.Hot(unit).IsActive() {
+			numHealed++
+		}
+	}
+	if numHealed != WildGrowthNumTargets {
+		t.Fatalf("Expected Wild Growth to heal %d players, healed %d", WildGrowthNumTargets, numHealed)
+	}
+	if !resto.WildGrowth.Hot(target).IsActive() {
+		t.Fatalf("Expected Wild Growth to heal its target")
+	}
+	if resto.WildGrowth.Hot(&resto.Unit).IsActive() {
+		t.Fatalf("Expected Wild Growth to skip the uninjured druid")
+	}
+}
+
+func TestHealsOverTime(t *testing.T) {
+	sim := newHealingSim()
+	resto := getDruid(sim)
+	tank := &sim.Raid.GetTargetDummies()[0].Unit
+
+	resto.Rejuvenation.SkipCastAndApplyEffects(sim, tank)
+	resto.Regrowth.SkipCastAndApplyEffects(sim, tank)
+	if resto.Rejuvenation.SpellMetrics[tank.UnitIndex].TotalHealing != 0 {
+		t.Fatalf("Expected Rejuvenation to only heal over time")
+	}
+	regrowthDirectHealing := resto.Regrowth.SpellMetrics[tank.UnitIndex].TotalHealing
+	if regrowthDirectHealing <= 0 {
+		t.Fatalf("Expected Regrowth to heal the target directly")
+	}
+
+	endTime := sim.CurrentTime + time.Second*7
+	for sim.CurrentTime < endTime {
+		sim.Step()
+	}
+	if resto.Rejuvenation.SpellMetrics[tank.UnitIndex].TotalHealing <= 0 {
+		t.Fatalf("Expected the Rejuvenation heal over time to tick")
+	}
+	if resto.Regrowth.SpellMetrics[tank.UnitIndex].TotalHealing <= regrowthDirectHealing {
+		t.Fatalf("Expected the Regrowth heal over time to tick")
+	}
+
+	// Heals cast at an enemy land on the caster.
+	resto.Rejuvenation.SkipCastAndApplyEffects(sim, sim.GetTargetUnit(0))
+	if !resto.Rejuvenation.Hot(&resto.Unit).IsActive() {
+		t.Fatalf("Expected Rejuvenation cast at the boss to heal the druid")
+	}
+}
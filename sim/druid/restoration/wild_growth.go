@@ -0,0 +1,76 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+const (
+	WildGrowthBonusCoeff = 0.092
+	WildGrowthCoeff      = 0.913
+	WildGrowthNumTargets = 6
+)
+
+// Heals the target and the 5 most injured other players over 7 sec.
+func (resto *RestorationDruid) registerWildGrowthSpell() {
+	resto.WildGrowth = resto.RegisterSpell(druid.Humanoid|druid.Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 48438},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		ClassSpellMask: druid.DruidSpellWildGrowth,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 22.9,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    resto.NewTimer(),
+				Duration: time.Second * 8,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Wild Growth",
+			},
+			NumberOfTicks: 7,
+			TickLength:    time.Second,
+
+			BonusCoefficient: WildGrowthBonusCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, resto.CalcScalingSpellDmg(WildGrowthCoeff))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = resto.HealTargetOrSelf(target)
+			spell.Hot(target).Apply(sim)
+
+			numHealed := 1
+			for _, ally := range resto.Env.Raid.GetSmartHealTargets(WildGrowthNumTargets) {
+				if numHealed == WildGrowthNumTargets {
+					break
+				}
+				if ally != target {
+					spell.Hot(ally).Apply(sim)
+					numHealed++
+				}
+			}
+		},
+	})
+}
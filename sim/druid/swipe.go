@@ -15,6 +15,7 @@ func (druid *Druid) registerSwipeBearSpell() {
 		SpellSchool: core.SpellSchoolPhysical,
 		ProcMask:    core.ProcMaskMeleeMHSpecial,
 		Flags:       core.SpellFlagAoE | core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		AoeRadius:   8,
 
 		RageCost: core.RageCostOptions{
 			Cost: core.TernaryInt32(druid.Spec == proto.Spec_SpecGuardianDruid, 0, 15),
@@ -38,7 +39,7 @@ func (druid *Druid) registerSwipeBearSpell() {
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			baseDamage := flatBaseDamage + 0.225*spell.MeleeAttackPower()
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&druid.Unit) {
 				spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 			}
 		},
@@ -51,6 +52,7 @@ func (druid *Druid) registerSwipeCatSpell() {
 		SpellSchool: core.SpellSchoolPhysical,
 		ProcMask:    core.ProcMaskMeleeMHSpecial,
 		Flags:       core.SpellFlagAoE | core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		AoeRadius:   8,
 
 		EnergyCost: core.EnergyCostOptions{
 			Cost: 45,
@@ -69,7 +71,7 @@ func (druid *Druid) registerSwipeCatSpell() {
 
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			baseDamage := spell.Unit.MHWeaponDamage(sim, spell.MeleeAttackPower())
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&druid.Unit) {
 				result := spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMeleeWeaponSpecialHitAndCrit)
 
 				if result.Landed() && (aoeTarget == druid.CurrentTarget) {
@@ -15,6 +15,7 @@ func (druid *Druid) registerThrashBearSpell() {
 		SpellSchool: core.SpellSchoolPhysical,
 		ProcMask:    core.ProcMaskMeleeMHSpecial,
 		Flags:       core.SpellFlagMeleeMetrics | core.SpellFlagIgnoreArmor | core.SpellFlagAPL | core.SpellFlagAoE,
+		AoeRadius:   8,
 
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
@@ -55,7 +56,7 @@ func (druid *Druid) registerThrashBearSpell() {
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			baseDamage := flatHitDamage + 0.191*spell.MeleeAttackPower()
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&druid.Unit) {
 				result := spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 
 				if result.Landed() {
@@ -82,6 +83,7 @@ func (druid *Druid) registerThrashCatSpell() {
 		SpellSchool: core.SpellSchoolPhysical,
 		ProcMask:    core.ProcMaskMeleeMHSpecial,
 		Flags:       core.SpellFlagMeleeMetrics | core.SpellFlagIgnoreArmor | core.SpellFlagAPL | core.SpellFlagAoE,
+		AoeRadius:   8,
 
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
@@ -122,7 +124,7 @@ func (druid *Druid) registerThrashCatSpell() {
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			baseDamage := flatHitDamage + 0.191*spell.MeleeAttackPower()
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&druid.Unit) {
 				result := spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 
 				if result.Landed() {
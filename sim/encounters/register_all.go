@@ -7,14 +7,17 @@ import (
 	"github.com/wowsims/mop/sim/encounters/bwd"
 	"github.com/wowsims/mop/sim/encounters/dragonsoul"
 	"github.com/wowsims/mop/sim/encounters/firelands"
+	"github.com/wowsims/mop/sim/encounters/throneofthunder"
 )
 
 func init() {
 	AddDefaultPresetEncounter()
 	addMovementAI()
+	core.RegisterEncounterScriptAI(NewScriptedAI)
 	bwd.Register()
 	firelands.Register()
 	dragonsoul.Register()
+	throneofthunder.Register()
 }
 
 func AddSingleTargetBossEncounter(presetTarget *core.PresetTarget) {
@@ -0,0 +1,367 @@
+package encounters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// How often the script checks health-based phase and event triggers.
+const scriptTickInterval = time.Millisecond * 500
+
+// ScriptedAI is a generic TargetAI that executes a declarative
+// proto.EncounterScript timeline instead of hand-written boss logic.
+type ScriptedAI struct {
+	Target *core.Target
+
+	script   *proto.EncounterScript
+	raidSize int32
+	events   [][]*scriptedEvent
+
+	// Adds start despawned until an add_spawn event brings them in.
+	despawnedAuras map[int32]*core.Aura
+
+	// Dynamic state for the current iteration
+	phaseIndex   int
+	phaseStart   time.Duration
+	phaseActions []*core.PendingAction
+}
+
+type scriptedEvent struct {
+	config    *proto.EncounterEvent
+	execute   func(sim *core.Simulation)
+	fireCount int32
+	triggered bool
+}
+
+func NewScriptedAI(script *proto.EncounterScript) core.TargetAI {
+	return &ScriptedAI{
+		script: script,
+	}
+}
+
+func (ai *ScriptedAI) Initialize(target *core.Target, _ *proto.Target) {
+	ai.Target = target
+	ai.raidSize = ai.script.RaidSize
+	ai.despawnedAuras = make(map[int32]*core.Aura)
+
+	ai.events = make([][]*scriptedEvent, len(ai.script.Phases))
+
+	for phaseIdx, phase := range ai.script.Phases {
+		for eventIdx, eventConfig := range phase.Events {
+			ai.events[phaseIdx] = append(ai.events[phaseIdx], &scriptedEvent{
+				config:  eventConfig,
+				execute: ai.buildAction(eventConfig, int32(phaseIdx*100+eventIdx+1)),
+			})
+		}
+	}
+}
+
+func (ai *ScriptedAI) buildAction(eventConfig *proto.EncounterEvent, tag int32) func(*core.Simulation) {
+	switch action := eventConfig.Action.(type) {
+	case *proto.EncounterEvent_DamagePulse:
+		return ai.buildDamagePulse(action.DamagePulse, tag)
+	case *proto.EncounterEvent_MovementWindow:
+		return ai.buildMovementWindow(action.MovementWindow, eventConfig.Name)
+	case *proto.EncounterEvent_AddSpawn:
+		return ai.buildAddSpawn(action.AddSpawn)
+	case *proto.EncounterEvent_TargetSwitch:
+		return ai.buildTargetSwitch(action.TargetSwitch)
+	default:
+		return func(_ *core.Simulation) {}
+	}
+}
+
+func (ai *ScriptedAI) buildDamagePulse(pulse *proto.EncounterDamagePulse, tag int32) func(*core.Simulation) {
+	actionID := core.ActionID{SpellID: pulse.SpellId, Tag: tag}
+
+	if pulse.SpellId == 0 {
+		actionID = core.ActionID{OtherID: proto.OtherAction_OtherActionAttack, Tag: tag}
+	}
+
+	spell := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         actionID,
+		SpellSchool:      core.SpellSchoolFromProto(pulse.SpellSchool),
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			var hitTargets []*core.Unit
+
+			if pulse.TankOnly {
+				hitTargets = []*core.Unit{ai.Target.TankOrFirstPlayer()}
+			} else {
+				hitTargets = sim.Raid.SelectRandomPlayers(sim, pulse.NumTargets, ai.raidSize, fmt.Sprintf("Scripted Pulse %d Target", tag))
+			}
+
+			for _, hitTarget := range hitTargets {
+				spell.CalcAndDealDamage(sim, hitTarget, pulse.Damage, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	return func(sim *core.Simulation) {
+		if pulse.NumTicks <= 1 {
+			spell.Cast(sim, ai.Target.TankOrFirstPlayer())
+			return
+		}
+
+		// Remaining ticks are dropped when the phase changes.
+		ai.phaseActions = append(ai.phaseActions, core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+			Period:          core.DurationFromSeconds(pulse.TickInterval),
+			NumTicks:        int(pulse.NumTicks),
+			TickImmediately: true,
+			Priority:        core.ActionPriorityDOT,
+
+			OnAction: func(sim *core.Simulation) {
+				spell.Cast(sim, ai.Target.TankOrFirstPlayer())
+			},
+		}))
+	}
+}
+
+func (ai *ScriptedAI) buildMovementWindow(window *proto.EncounterMovementWindow, label string) func(*core.Simulation) {
+	moveDuration := core.DurationFromSeconds(window.Duration)
+
+	return func(sim *core.Simulation) {
+		for _, player := range sim.Raid.SelectRandomPlayers(sim, window.NumTargets, ai.raidSize, "Scripted Movement "+label) {
+			player.ForceMovement(moveDuration, sim)
+		}
+	}
+}
+
+func (ai *ScriptedAI) buildAddSpawn(spawn *proto.EncounterAddSpawn) func(*core.Simulation) {
+	addTarget := ai.getTarget(spawn.TargetIndex)
+
+	if addTarget == nil {
+		return func(_ *core.Simulation) {}
+	}
+
+	despawnedAura := ai.despawnedAuras[spawn.TargetIndex]
+
+	if despawnedAura == nil {
+		despawnedAura = ai.registerDespawnedAura(addTarget)
+		ai.despawnedAuras[spawn.TargetIndex] = despawnedAura
+	}
+
+	aliveDuration := core.DurationFromSeconds(spawn.Duration)
+
+	return func(sim *core.Simulation) {
+		despawnedAura.Deactivate(sim)
+
+		if spawn.SwitchTargets {
+			ai.switchTargets(sim, addTarget, aliveDuration)
+		}
+
+		if aliveDuration > 0 {
+			core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt:     sim.CurrentTime + aliveDuration,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					despawnedAura.Activate(sim)
+				},
+			})
+		}
+	}
+}
+
+func (ai *ScriptedAI) buildTargetSwitch(targetSwitch *proto.EncounterTargetSwitch) func(*core.Simulation) {
+	switchTarget := ai.getTarget(targetSwitch.TargetIndex)
+	windowDuration := core.DurationFromSeconds(targetSwitch.Duration)
+
+	return func(sim *core.Simulation) {
+		if switchTarget != nil {
+			ai.switchTargets(sim, switchTarget, windowDuration)
+		}
+	}
+}
+
+// Despawned adds stop attacking and are removed from the encounter's active
+// targets, which also stops them from taking damage.
+func (ai *ScriptedAI) registerDespawnedAura(addTarget *core.Target) *core.Aura {
+	return addTarget.RegisterAura(core.Aura{
+		Label:    "Despawned",
+		Duration: core.NeverExpires,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			addTarget.SetActive(false)
+
+			if aura.Unit.AutoAttacks.MH() != nil {
+				aura.Unit.AutoAttacks.CancelAutoSwing(sim)
+			}
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			addTarget.SetActive(true)
+
+			if aura.Unit.AutoAttacks.MH() != nil {
+				aura.Unit.AutoAttacks.EnableAutoSwing(sim)
+			}
+		},
+	})
+}
+
+// Points every non-tank player and pet at the given target. Units that are
+// still on it once the window ends return to their previous target.
+func (ai *ScriptedAI) switchTargets(sim *core.Simulation, switchTarget *core.Target, windowDuration time.Duration) {
+	for _, unit := range sim.Raid.AllUnits {
+		if ai.isTanking(unit) || (unit.CurrentTarget == &switchTarget.Unit) {
+			continue
+		}
+
+		previousTarget := unit.CurrentTarget
+		unit.CurrentTarget = &switchTarget.Unit
+
+		if windowDuration > 0 {
+			core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt:     sim.CurrentTime + windowDuration,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					if unit.CurrentTarget == &switchTarget.Unit {
+						unit.CurrentTarget = previousTarget
+					}
+				},
+			})
+		}
+	}
+}
+
+func (ai *ScriptedAI) isTanking(unit *core.Unit) bool {
+	for _, target := range ai.Target.Env.Encounter.Targets {
+		if target.CurrentTarget == unit {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ai *ScriptedAI) getTarget(targetIndex int32) *core.Target {
+	targets := ai.Target.Env.Encounter.Targets
+
+	if (targetIndex < 0) || (int(targetIndex) >= len(targets)) {
+		return nil
+	}
+
+	return targets[targetIndex]
+}
+
+func (ai *ScriptedAI) Reset(sim *core.Simulation) {
+	ai.phaseIndex = -1
+	ai.phaseActions = ai.phaseActions[:0]
+
+	// Other targets may reset after this one, so the initial state is applied
+	// once the iteration starts.
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     0,
+		Priority: core.ActionPriorityPrePull,
+
+		OnAction: func(sim *core.Simulation) {
+			for _, despawnedAura := range ai.despawnedAuras {
+				despawnedAura.Activate(sim)
+			}
+
+			if len(ai.script.Phases) > 0 {
+				ai.startPhase(sim, 0)
+			}
+		},
+	})
+}
+
+func (ai *ScriptedAI) startPhase(sim *core.Simulation, phaseIndex int) {
+	for _, pa := range ai.phaseActions {
+		pa.Cancel(sim)
+	}
+
+	ai.phaseActions = ai.phaseActions[:0]
+	ai.phaseIndex = phaseIndex
+	ai.phaseStart = sim.CurrentTime
+
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Starting encounter phase %d (%s)", phaseIndex+1, ai.script.Phases[phaseIndex].Name)
+	}
+
+	for _, event := range ai.events[phaseIndex] {
+		event.fireCount = 0
+		event.triggered = false
+
+		// Health triggers are polled from ExecuteCustomRotation.
+		if _, ok := event.config.Trigger.GetTrigger().(*proto.EncounterTrigger_AtHealthPercent); ok {
+			continue
+		}
+
+		ai.scheduleEvent(sim, event, ai.phaseStart+core.DurationFromSeconds(event.config.Trigger.GetAtTime()))
+	}
+
+	// Time-triggered phases are scheduled exactly rather than polled.
+	nextIndex := phaseIndex + 1
+
+	if nextIndex < len(ai.script.Phases) {
+		if trigger, ok := ai.script.Phases[nextIndex].Start.GetTrigger().(*proto.EncounterTrigger_AtTime); ok {
+			ai.phaseActions = append(ai.phaseActions, core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt:     max(sim.CurrentTime, core.DurationFromSeconds(trigger.AtTime)),
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.startPhase(sim, nextIndex)
+				},
+			}))
+		}
+	}
+}
+
+func (ai *ScriptedAI) scheduleEvent(sim *core.Simulation, event *scriptedEvent, doAt time.Duration) {
+	event.triggered = true
+
+	ai.phaseActions = append(ai.phaseActions, core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     max(sim.CurrentTime, doAt),
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			ai.fireEvent(sim, event)
+		},
+	}))
+}
+
+func (ai *ScriptedAI) fireEvent(sim *core.Simulation, event *scriptedEvent) {
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Encounter event: %s", event.config.Name)
+	}
+
+	event.execute(sim)
+	event.fireCount++
+
+	if (event.config.RepeatInterval > 0) && ((event.config.MaxRepeats == 0) || (event.fireCount < event.config.MaxRepeats)) {
+		ai.scheduleEvent(sim, event, sim.CurrentTime+core.DurationFromSeconds(event.config.RepeatInterval))
+	}
+}
+
+func (ai *ScriptedAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if ai.phaseIndex >= 0 {
+		healthPercent := sim.GetRemainingDurationPercent() * 100
+		nextIndex := ai.phaseIndex + 1
+
+		if nextIndex < len(ai.script.Phases) {
+			if trigger, ok := ai.script.Phases[nextIndex].Start.GetTrigger().(*proto.EncounterTrigger_AtHealthPercent); ok && (healthPercent <= trigger.AtHealthPercent) {
+				ai.startPhase(sim, nextIndex)
+			}
+		}
+
+		for _, event := range ai.events[ai.phaseIndex] {
+			if event.triggered {
+				continue
+			}
+
+			if healthPercent <= event.config.Trigger.GetAtHealthPercent() {
+				ai.scheduleEvent(sim, event, sim.CurrentTime)
+			}
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+scriptTickInterval)
+}
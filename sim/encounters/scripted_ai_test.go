@@ -0,0 +1,179 @@
+package encounters_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/paladin/retribution"
+	"github.com/wowsims/mop/sim/warrior/arms"
+)
+
+func init() {
+	arms.RegisterArmsWarrior()
+	retribution.RegisterRetributionPaladin()
+}
+
+func newScriptedSim(script *proto.EncounterScript) *core.Simulation {
+	return newScriptedSimWithPlayer(&proto.Player{
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassWarrior,
+		Equipment: &proto.EquipmentSpec{},
+		Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		Spec: &proto.Player_ArmsWarrior{
+			ArmsWarrior: &proto.ArmsWarrior{
+				Options: &proto.ArmsWarrior_Options{ClassOptions: &proto.WarriorOptions{}},
+			},
+		},
+	}, script)
+}
+
+func newScriptedSimWithPlayer(player *proto.Player, script *proto.EncounterScript) *core.Simulation {
+	sim := core.NewSim(&proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(
+			player,
+			&proto.PartyBuffs{},
+			&proto.RaidBuffs{},
+			&proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{core.NewDefaultTarget(), core.NewDefaultTarget()},
+			Script:   script,
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+	return sim
+}
+
+// Runs the sim until the given time, or until the iteration ends.
+func runScriptedSimUntil(sim *core.Simulation, until time.Duration) {
+	for sim.CurrentTime < until {
+		if finished := sim.Step(); finished {
+			return
+		}
+	}
+}
+
+func TestScriptedAddSpawn(t *testing.T) {
+	sim := newScriptedSim(&proto.EncounterScript{
+		Phases: []*proto.EncounterPhase{{
+			Name: "Adds",
+			Events: []*proto.EncounterEvent{{
+				Name:    "Add",
+				Trigger: &proto.EncounterTrigger{Trigger: &proto.EncounterTrigger_AtTime{AtTime: 10}},
+				Action: &proto.EncounterEvent_AddSpawn{AddSpawn: &proto.EncounterAddSpawn{
+					TargetIndex:   1,
+					Duration:      10,
+					SwitchTargets: true,
+				}},
+			}},
+		}},
+	})
+	player := sim.Raid.AllPlayerUnits[0]
+	add := sim.Encounter.Targets[1]
+
+	runScriptedSimUntil(sim, time.Second*5)
+	if add.IsActive || add.IsEnabled() || sim.GetNumTargets() != 1 || sim.GetTarget(0) != sim.Encounter.Targets[0] {
+		t.Fatalf("Expected the add to be despawned before its spawn event")
+	}
+
+	// Damage taken multipliers applied while despawned are kept once the add spawns.
+	add.PseudoStats.DamageTakenMultiplier *= 1.1
+	mhAuto := player.AutoAttacks.MHAuto()
+	if result := mhAuto.CalcDamage(sim, &add.Unit, 1000, mhAuto.OutcomeAlwaysHit); result.Damage != 0 {
+		t.Fatalf("Expected a despawned add to take no damage, got %0.2f", result.Damage)
+	}
+
+	runScriptedSimUntil(sim, time.Second*12)
+	if !add.IsActive || sim.GetNumTargets() != 2 || player.CurrentTarget != &add.Unit {
+		t.Fatalf("Expected the add to be active and targeted after spawning")
+	}
+	if add.PseudoStats.DamageTakenMultiplier != 1.1 {
+		t.Fatalf("Expected the add's damage taken multiplier to be kept, got %0.2f", add.PseudoStats.DamageTakenMultiplier)
+	}
+
+	runScriptedSimUntil(sim, time.Second*22)
+	if add.IsActive || sim.GetNumTargets() != 1 || player.CurrentTarget != &sim.Encounter.Targets[0].Unit {
+		t.Fatalf("Expected the add to despawn and the player to switch back after its duration")
+	}
+}
+
+func TestScriptedPhasePulses(t *testing.T) {
+	pulse := &proto.EncounterEvent_DamagePulse{DamagePulse: &proto.EncounterDamagePulse{
+		SpellId:      1,
+		Damage:       1000,
+		NumTicks:     100,
+		TickInterval: 1,
+	}}
+	sim := newScriptedSim(&proto.EncounterScript{
+		Phases: []*proto.EncounterPhase{
+			{
+				Name: "Pulses",
+				Events: []*proto.EncounterEvent{{
+					Name:    "Pulse",
+					Trigger: &proto.EncounterTrigger{Trigger: &proto.EncounterTrigger_AtTime{AtTime: 0}},
+					Action:  pulse,
+				}},
+			},
+			{
+				Name:  "Quiet",
+				Start: &proto.EncounterTrigger{Trigger: &proto.EncounterTrigger_AtTime{AtTime: 10.5}},
+			},
+		},
+	})
+	boss := sim.Encounter.Targets[0]
+	pulseSpell := boss.GetSpell(core.ActionID{SpellID: 1, Tag: 1})
+	if pulseSpell == nil {
+		t.Fatalf("Expected the pulse spell to be registered")
+	}
+
+	runScriptedSimUntil(sim, time.Second*30)
+	if casts := pulseSpell.SpellMetrics[sim.Raid.AllPlayerUnits[0].UnitIndex].Casts; casts != 11 {
+		t.Fatalf("Expected 11 pulses before the phase change, got %d", casts)
+	}
+}
+
+// Spells which size their results by the number of targets at registration keep working
+// while an add is despawned.
+func TestScriptedAddSpawnAoE(t *testing.T) {
+	rotation, err := apltext.Parse(`actions cast_spell(spell_id=spell:53595)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := newScriptedSimWithPlayer(&proto.Player{
+		Race:      proto.Race_RaceHuman,
+		Class:     proto.Class_ClassPaladin,
+		Equipment: &proto.EquipmentSpec{},
+		Rotation:  rotation,
+		Spec: &proto.Player_RetributionPaladin{
+			RetributionPaladin: &proto.RetributionPaladin{
+				Options: &proto.RetributionPaladin_Options{ClassOptions: &proto.PaladinOptions{}},
+			},
+		},
+	}, &proto.EncounterScript{
+		Phases: []*proto.EncounterPhase{{
+			Name: "Adds",
+			Events: []*proto.EncounterEvent{{
+				Name:    "Add",
+				Trigger: &proto.EncounterTrigger{Trigger: &proto.EncounterTrigger_AtTime{AtTime: 10}},
+				Action:  &proto.EncounterEvent_AddSpawn{AddSpawn: &proto.EncounterAddSpawn{TargetIndex: 1, Duration: 10}},
+			}},
+		}},
+	})
+	hammerAoE := sim.Raid.AllPlayerUnits[0].GetSpell(core.ActionID{SpellID: 88263})
+	add := sim.Encounter.Targets[1]
+
+	runScriptedSimUntil(sim, time.Second*9)
+	if hammerAoE.SpellMetrics[add.UnitIndex].Hits != 0 {
+		t.Fatalf("Expected the despawned add not to be hit")
+	}
+	runScriptedSimUntil(sim, time.Second*15)
+	if hammerAoE.SpellMetrics[add.UnitIndex].Hits == 0 {
+		t.Fatalf("Expected the spawned add to be hit")
+	}
+}
@@ -0,0 +1,701 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+type councilMember int32
+
+const (
+	frostKingMalakk councilMember = iota
+	kazrajin
+	sulTheSandcrawler
+	highPriestessMarli
+	numCouncilMembers
+)
+
+var councilMemberIDs = [numCouncilMembers]int32{69131, 69134, 69078, 69132}
+var councilMemberNames = [numCouncilMembers]string{"Frost King Malakk", "Kazra'jin", "Sul the Sandcrawler", "High Priestess Mar'li"}
+
+func addCouncilOfElders(raidPrefix string) {
+	createCouncilOfEldersPreset(raidPrefix, raid10Normal, 32_900_000, 380_000)
+	createCouncilOfEldersPreset(raidPrefix, raid25Normal, 98_600_000, 380_000)
+	createCouncilOfEldersPreset(raidPrefix, raid10Heroic, 46_000_000, 520_000)
+	createCouncilOfEldersPreset(raidPrefix, raid25Heroic, 138_000_000, 520_000)
+}
+
+func createCouncilOfEldersPreset(raidPrefix string, difficulty raidDifficulty, memberHealth float64, memberMinBaseDamage float64) {
+	var targetPathNames []string
+
+	for member := frostKingMalakk; member < numCouncilMembers; member++ {
+		targetName := difficulty.targetName(councilMemberNames[member])
+
+		// Malakk is held by the main tank, while the off-tank collects Kazra'jin
+		// and Sul. Mar'li is a caster and never melees.
+		tankIndex := core.TernaryInt32(member == frostKingMalakk, 0, 1)
+		swingSpeed := 2.0
+
+		if member == highPriestessMarli {
+			tankIndex = -1
+			swingSpeed = 0
+		}
+
+		targetInputs := []*proto.TargetInput{difficultyTargetInput(difficulty)}
+
+		if member == frostKingMalakk {
+			targetInputs = append(targetInputs, councilTargetInputs()...)
+		}
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        councilMemberIDs[member],
+				Name:      targetName,
+				Level:     93,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: tankIndex,
+
+				Stats: stats.Stats{
+					stats.Health:      memberHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:    swingSpeed,
+				MinBaseDamage: memberMinBaseDamage,
+				DamageSpread:  0.4,
+				TargetInputs:  targetInputs,
+			},
+
+			AI: makeCouncilAI(member),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+targetName)
+	}
+
+	core.AddPresetEncounter(difficulty.targetName("Council of Elders"), targetPathNames)
+}
+
+func councilTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Possession damage threshold %",
+			Tooltip:     "% of a council member's maximum health that must be dealt to it before Gara'jal's spirit leaves for another council member.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 25,
+		},
+		{
+			Label:       "Quicksand reposition time",
+			Tooltip:     "How long (in seconds) players spend moving out of each Quicksand pool.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+	}
+}
+
+func makeCouncilAI(member councilMember) core.AIFactory {
+	return func() core.TargetAI {
+		return &CouncilAI{
+			member: member,
+		}
+	}
+}
+
+type CouncilAI struct {
+	Target *core.Target
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+	member   councilMember
+
+	// Malakk acts as the controller for the shared possession mechanic.
+	controller *CouncilAI
+	council    []*CouncilAI
+
+	// Dynamic parameters taken from user inputs (controller only)
+	possessionThreshold float64
+	quicksandMoveTime   time.Duration
+
+	// Possession state
+	possessedAura    *core.Aura
+	darkPower        *core.Spell
+	darkPowerCasts   int32
+	energy           float64
+	possessionDamage float64
+	totalDamageTaken float64
+
+	// Member specific abilities, in priority order
+	abilities        []*core.Spell
+	possessedAbility *core.Spell
+}
+
+func (ai *CouncilAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.Target.AutoAttacks.MHConfig().ActionID.Tag = councilMemberIDs[ai.member]
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	if ai.member == frostKingMalakk {
+		ai.controller = ai
+		ai.possessionThreshold = config.TargetInputs[1].NumberValue / 100
+		ai.quicksandMoveTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+	}
+
+	ai.registerPossession()
+
+	switch ai.member {
+	case frostKingMalakk:
+		ai.registerMalakkAbilities()
+	case kazrajin:
+		ai.registerKazrajinAbilities()
+	case sulTheSandcrawler:
+		ai.registerSulAbilities()
+	case highPriestessMarli:
+		ai.registerMarliAbilities()
+	}
+
+	// Council members look each other up once every target has been constructed.
+	target.Env.RegisterPostFinalizeEffect(func() {
+		for _, encounterTarget := range target.Env.Encounter.Targets {
+			if councilAI, ok := encounterTarget.AI.(*CouncilAI); ok {
+				ai.council = append(ai.council, councilAI)
+
+				if councilAI.member == frostKingMalakk {
+					ai.controller = councilAI
+				}
+			}
+		}
+	})
+}
+
+func (ai *CouncilAI) Reset(sim *core.Simulation) {
+	ai.energy = 0
+	ai.darkPowerCasts = 0
+	ai.possessionDamage = 0
+	ai.totalDamageTaken = 0
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+
+	if ai.Target.AutoAttacks.MH() != nil {
+		ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+	}
+
+	for _, ability := range ai.abilities {
+		randomizeFirstCast(sim, ability, ability.CD.Duration/2, "Council Ability Timing")
+	}
+
+	if ai.controller != ai {
+		return
+	}
+
+	// Gara'jal possesses his first council member shortly after the pull.
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     time.Second * 3,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			firstRoll := int(sim.RandomFloat("Possession Target") * float64(len(ai.council)))
+			ai.council[firstRoll].possessedAura.Activate(sim)
+		},
+	})
+}
+
+// Moves Gara'jal's spirit to the healthiest council member other than the
+// currently possessed one.
+func (ai *CouncilAI) movePossession(sim *core.Simulation, from *CouncilAI) {
+	from.possessedAura.Deactivate(sim)
+
+	var nextHost *CouncilAI
+
+	for _, candidate := range ai.council {
+		if (candidate != from) && ((nextHost == nil) || (candidate.totalDamageTaken < nextHost.totalDamageTaken)) {
+			nextHost = candidate
+		}
+	}
+
+	if nextHost != nil {
+		nextHost.possessedAura.Activate(sim)
+	} else {
+		from.possessedAura.Activate(sim)
+	}
+}
+
+func (ai *CouncilAI) registerPossession() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	darkPowerBase := []float64{55_000, 70_000}[scalingIndex]
+	energyPerSecond := []float64{2.5, 3.3}[scalingIndex]
+
+	core.MakePermanent(ai.Target.RegisterAura(core.Aura{
+		Label: "Council Damage Tracker",
+
+		OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			ai.totalDamageTaken += result.Damage
+		},
+
+		OnPeriodicDamageTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			ai.totalDamageTaken += result.Damage
+		},
+	}))
+
+	ai.darkPower = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136507},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// Every Dark Power cast during the encounter is stronger than the last.
+			controller := ai.controller
+			powerMultiplier := 1.0 + 0.1*float64(controller.darkPowerCasts)
+			controller.darkPowerCasts++
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, darkPowerBase*powerMultiplier, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	var energyAction *core.PendingAction
+
+	trackPossessionDamage := func(aura *core.Aura, sim *core.Simulation, result *core.SpellResult) {
+		ai.possessionDamage += result.Damage
+
+		if (ai.controller != nil) && (ai.possessionDamage >= ai.controller.possessionThreshold*aura.Unit.GetStat(stats.Health)) {
+			ai.controller.movePossession(sim, ai)
+		}
+	}
+
+	ai.possessedAura = ai.Target.RegisterAura(core.Aura{
+		Label:    "Possessed",
+		ActionID: core.ActionID{SpellID: 136442},
+		Duration: core.NeverExpires,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			ai.possessionDamage = 0
+
+			energyAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.energy += energyPerSecond
+
+					if ai.energy >= 100 {
+						ai.energy = 0
+						ai.darkPower.Cast(sim, ai.Target.TankOrFirstPlayer())
+					}
+				},
+			})
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			if energyAction != nil {
+				energyAction.Cancel(sim)
+				energyAction = nil
+			}
+		},
+
+		OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			trackPossessionDamage(aura, sim, result)
+		},
+
+		OnPeriodicDamageTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			trackPossessionDamage(aura, sim, result)
+		},
+	})
+}
+
+func (ai *CouncilAI) registerMalakkAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	bitingColdTick := []float64{30_000, 40_000}[scalingIndex]
+	frostbiteTick := []float64{45_000, 60_000}[scalingIndex]
+
+	bitingCold := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136917},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 45,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Biting Cold",
+			},
+
+			TickLength:    time.Second * 3,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, bitingColdTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Biting Cold Target", ai.Target.CurrentTarget) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+
+	frostbite := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136990},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 45,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Frostbite",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 15,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, frostbiteTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Frostbite Target", ai.Target.CurrentTarget) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+
+	ai.abilities = []*core.Spell{bitingCold}
+	ai.possessedAbility = frostbite
+}
+
+func (ai *CouncilAI) registerKazrajinAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	recklessChargeBase := []float64{140_000, 190_000}[scalingIndex]
+	overloadReflect := 0.4
+
+	recklessCharge := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137122},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 12,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// The charge target and whoever fails to get out of the landing zone take the hit.
+			for _, chargeTarget := range sim.Raid.SelectRandomPlayers(sim, 2, ai.raidSize, "Reckless Charge Target", ai.Target.CurrentTarget) {
+				spell.CalcAndDealDamage(sim, chargeTarget, recklessChargeBase, spell.OutcomeAlwaysHit)
+				chargeTarget.ForceMovement(time.Millisecond*1500, sim)
+			}
+		},
+	})
+
+	reflectSpell := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137151},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskEmpty,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagIgnoreAttackerModifiers | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {},
+	})
+
+	overloadAura := ai.Target.RegisterAura(core.Aura{
+		Label:    "Overload",
+		ActionID: core.ActionID{SpellID: 137149},
+		Duration: time.Second * 10,
+
+		OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if (result.Damage > 0) && (spell.Unit.Type != core.EnemyUnit) {
+				reflectSpell.CalcAndDealDamage(sim, spell.Unit, result.Damage*overloadReflect, reflectSpell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	overload := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 137149},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			overloadAura.Activate(sim)
+		},
+	})
+
+	ai.abilities = []*core.Spell{recklessCharge}
+	ai.possessedAbility = overload
+}
+
+func (ai *CouncilAI) registerSulAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	sandBoltBase := []float64{65_000, 85_000}[scalingIndex]
+	quicksandBase := []float64{40_000, 55_000}[scalingIndex]
+	sandstormTick := []float64{35_000, 45_000}[scalingIndex]
+
+	sandBolt := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136189},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 4,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, boltTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Sand Bolt Target", ai.Target.CurrentTarget) {
+				spell.CalcAndDealDamage(sim, boltTarget, sandBoltBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	quicksand := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136521},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 35,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			moveTime := time.Second * 2
+
+			if ai.controller != nil {
+				moveTime = ai.controller.quicksandMoveTime
+			}
+
+			for _, trappedPlayer := range sim.Raid.SelectRandomPlayers(sim, 5, ai.raidSize, "Quicksand Target", ai.Target.CurrentTarget) {
+				spell.CalcAndDealDamage(sim, trappedPlayer, quicksandBase, spell.OutcomeAlwaysHit)
+				trappedPlayer.ForceMovement(moveTime, sim)
+			}
+		},
+	})
+
+	sandstorm := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136894},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 40,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Sandstorm",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, sandstormTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+
+	ai.abilities = []*core.Spell{quicksand, sandBolt}
+	ai.possessedAbility = sandstorm
+}
+
+func (ai *CouncilAI) registerMarliAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	wrathOfTheLoaBase := []float64{70_000, 95_000}[scalingIndex]
+	twistedFateTick := []float64{25_000, 35_000}[scalingIndex]
+
+	wrathOfTheLoa := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137344},
+		SpellSchool:      core.SpellSchoolHoly,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 3,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, wrathTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Wrath of the Loa Target") {
+				spell.CalcAndDealDamage(sim, wrathTarget, wrathOfTheLoaBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	twistedFate := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137891},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Twisted Fate",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 20,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, twistedFateTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// Twisted Fate links two players until the raid breaks the tether.
+			for _, linkedPlayer := range sim.Raid.SelectRandomPlayers(sim, 2, ai.raidSize, "Twisted Fate Target") {
+				spell.Dot(linkedPlayer).Apply(sim)
+			}
+		},
+	})
+
+	ai.abilities = []*core.Spell{wrathOfTheLoa}
+	ai.possessedAbility = twistedFate
+}
+
+func (ai *CouncilAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.TankOrFirstPlayer()
+
+	if ai.possessedAura.IsActive() && ai.possessedAbility.IsReady(sim) {
+		ai.possessedAbility.Cast(sim, target)
+		return
+	}
+
+	for _, ability := range ai.abilities {
+		if ability.IsReady(sim) {
+			ability.Cast(sim, target)
+			return
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
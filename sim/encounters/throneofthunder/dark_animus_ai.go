@@ -0,0 +1,320 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const darkAnimusID int32 = 69427
+
+func addDarkAnimus(raidPrefix string) {
+	createDarkAnimusPreset(raidPrefix, raid10Normal, 122_900_000, 460_000)
+	createDarkAnimusPreset(raidPrefix, raid25Normal, 368_600_000, 460_000)
+	createDarkAnimusPreset(raidPrefix, raid10Heroic, 172_000_000, 630_000)
+	createDarkAnimusPreset(raidPrefix, raid25Heroic, 516_000_000, 630_000)
+}
+
+func createDarkAnimusPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Dark Animus", darkAnimusID, proto.MobType_MobTypeMechanical, bossHealth, bossMinBaseDamage, darkAnimusTargetInputs(), makeDarkAnimusAI())
+}
+
+func darkAnimusTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Time to Full Power",
+			Tooltip:     "How long (in seconds) it takes Dark Animus to reach 100 Anima as the raid kills its golems. Stronger abilities unlock along the way, and Full Power ends the fight.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 420,
+		},
+		{
+			Label:     "Stop casting for Interrupting Jolt",
+			Tooltip:   "If checked, every player stops casting for the duration of each Interrupting Jolt instead of being interrupted and taking its damage.",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+	}
+}
+
+func makeDarkAnimusAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &DarkAnimusAI{}
+	}
+}
+
+type DarkAnimusAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	timeToFullPower time.Duration
+	stopCastingJolt bool
+
+	// Spell + aura references
+	explosiveSlam     *core.Spell
+	siphonAnima       *core.Spell
+	touchOfTheAnimus  *core.Spell
+	animaFount        *core.Spell
+	interruptingJolt  *core.Spell
+	fullPower         *core.Spell
+	fullPowerActive   bool
+	explosiveSlamAura core.AuraArray
+}
+
+func (ai *DarkAnimusAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.timeToFullPower = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+	ai.stopCastingJolt = config.TargetInputs[2].BoolValue
+
+	ai.registerTankAbilities()
+	ai.registerRaidAbilities()
+}
+
+func (ai *DarkAnimusAI) Reset(sim *core.Simulation) {
+	ai.fullPowerActive = false
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.explosiveSlam, time.Second*8, "Explosive Slam Timing")
+	randomizeFirstCast(sim, ai.siphonAnima, time.Second*20, "Siphon Anima Timing")
+	randomizeFirstCast(sim, ai.touchOfTheAnimus, time.Second*12, "Touch of the Animus Timing")
+	randomizeFirstCast(sim, ai.animaFount, time.Second*20, "Anima Fount Timing")
+	randomizeFirstCast(sim, ai.interruptingJolt, time.Second*18, "Interrupting Jolt Timing")
+}
+
+// Returns the boss's Anima, which grows linearly until Full Power.
+func (ai *DarkAnimusAI) anima(sim *core.Simulation) float64 {
+	if ai.timeToFullPower <= 0 {
+		return 0
+	}
+
+	return min(100*sim.CurrentTime.Seconds()/ai.timeToFullPower.Seconds(), 100)
+}
+
+func (ai *DarkAnimusAI) registerTankAbilities() {
+	// Explosive Slam knocks the tank back, and increases the damage they take
+	// from the next one.
+	ai.explosiveSlamAura = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:     "Explosive Slam",
+			ActionID:  core.ActionID{SpellID: 138569},
+			Duration:  time.Second * 25,
+			MaxStacks: 10,
+		})
+	})
+
+	ai.explosiveSlam = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138569},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 1.5,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*15),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			slam := ai.explosiveSlamAura.Get(tankTarget)
+			stackMultiplier := 1.0
+
+			if slam != nil && slam.IsActive() {
+				stackMultiplier += 0.25 * float64(slam.GetStacks())
+			}
+
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4) * stackMultiplier
+			result := spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+
+			if result.Landed() && (slam != nil) {
+				slam.Activate(sim)
+				slam.AddStack(sim)
+			}
+
+			tankTarget.ForceMovement(time.Second*2, sim)
+
+			if ai.OffTank != nil {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+}
+
+func (ai *DarkAnimusAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	siphonAnimaBase := []float64{20_000, 28_000}[scalingIndex]
+	touchTick := []float64{25_000, 35_000}[scalingIndex]
+	animaFountBase := []float64{90_000, 125_000}[scalingIndex]
+	interruptingJoltBase := []float64{180_000, 250_000}[scalingIndex]
+	fullPowerTick := []float64{300_000, 400_000}[scalingIndex]
+	interruptingJoltCastTime := time.Millisecond * 2200
+
+	// Siphon Anima hits harder the more Anima the boss has gathered.
+	ai.siphonAnima = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138644},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			damage := siphonAnimaBase * (1.0 + ai.anima(sim)/25)
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, damage, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.touchOfTheAnimus = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138659},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*12),
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Touch of the Animus",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, touchTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Touch of the Animus Target", ai.MainTank, ai.OffTank) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+
+	ai.animaFount = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138691},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*25),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 5, 2)
+
+			for _, fountTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Anima Fount Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, fountTarget, animaFountBase, spell.OutcomeAlwaysHit)
+				fountTarget.ForceMovement(time.Second*2, sim)
+			}
+		},
+	})
+
+	// Interrupting Jolt damages and interrupts every player who is casting when
+	// it goes off, so players either stop casting or eat the damage.
+	ai.interruptingJolt = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138763},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*22),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			if ai.stopCastingJolt {
+				for _, raidMember := range sim.Raid.AllPlayerUnits {
+					raidMember.ForceMovement(interruptingJoltCastTime, sim)
+				}
+
+				return
+			}
+
+			core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt:     sim.CurrentTime + interruptingJoltCastTime,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					for _, raidMember := range sim.Raid.AllPlayerUnits {
+						if raidMember.Hardcast.Expires > sim.CurrentTime {
+							raidMember.CancelHardcast(sim)
+							spell.CalcAndDealDamage(sim, raidMember, interruptingJoltBase, spell.OutcomeAlwaysHit)
+						}
+					}
+				},
+			})
+		},
+	})
+
+	ai.fullPower = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138729},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, fullPowerTick, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *DarkAnimusAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.TankOrFirstPlayer()
+	anima := ai.anima(sim)
+
+	// At 100 Anima the boss only casts Full Power until the raid dies.
+	if anima >= 100 {
+		ai.fullPower.Cast(sim, target)
+		return
+	}
+
+	// Abilities unlock as the boss gathers Anima from its golems.
+	abilities := []*core.Spell{ai.explosiveSlam, ai.siphonAnima}
+
+	if anima >= 25 {
+		abilities = append(abilities, ai.touchOfTheAnimus)
+	}
+
+	if anima >= 50 {
+		abilities = append(abilities, ai.animaFount)
+	}
+
+	if anima >= 75 {
+		abilities = append(abilities, ai.interruptingJolt)
+	}
+
+	if castFirstReady(sim, target, abilities...) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,317 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const durumuID int32 = 68036
+
+// Durumu alternates between the Light Spectrum and the Disintegration Beam maze
+// on a fixed cadence.
+const firstLightSpectrumAt = time.Second * 40
+const firstDisintegrationBeamAt = time.Second * 135
+const durumuPhaseCycle = time.Second * 190
+
+func addDurumu(raidPrefix string) {
+	createDurumuPreset(raidPrefix, raid10Normal, 113_600_000, 440_000)
+	createDurumuPreset(raidPrefix, raid25Normal, 340_900_000, 440_000)
+	createDurumuPreset(raidPrefix, raid10Heroic, 159_100_000, 600_000)
+	createDurumuPreset(raidPrefix, raid25Heroic, 477_200_000, 600_000)
+}
+
+func createDurumuPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Durumu the Forgotten", durumuID, proto.MobType_MobTypeUnknown, bossHealth, bossMinBaseDamage, durumuTargetInputs(), makeDurumuAI())
+}
+
+func durumuTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Maze movement %",
+			Tooltip:     "% of the Disintegration Beam maze that each player spends moving to stay ahead of the beam.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 40,
+		},
+		{
+			Label:       "Light Spectrum reposition time",
+			Tooltip:     "How long (in seconds) each player spends moving to their assigned beam and Fog Beast during every Light Spectrum.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 4,
+		},
+	}
+}
+
+func makeDurumuAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &DurumuAI{}
+	}
+}
+
+type DurumuAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	mazeMovementFraction float64
+	spectrumMoveTime     time.Duration
+
+	// Spell + aura references
+	hardStare          *core.Spell
+	forceOfWill        *core.Spell
+	lingeringGaze      *core.Spell
+	lifeDrain          *core.Spell
+	lightSpectrum      *core.Spell
+	disintegrationBeam *core.Spell
+	disintegrationEnd  time.Duration
+}
+
+func (ai *DurumuAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.mazeMovementFraction = max(0, min(config.TargetInputs[1].NumberValue/100, 1))
+	ai.spectrumMoveTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+
+	ai.registerTankAbilities()
+	ai.registerRaidAbilities()
+	ai.registerPhaseAbilities()
+}
+
+func (ai *DurumuAI) Reset(sim *core.Simulation) {
+	ai.disintegrationEnd = 0
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.hardStare, time.Second*5, "Hard Stare Timing")
+	randomizeFirstCast(sim, ai.forceOfWill, time.Second*30, "Force of Will Timing")
+	randomizeFirstCast(sim, ai.lingeringGaze, time.Second*15, "Lingering Gaze Timing")
+	randomizeFirstCast(sim, ai.lifeDrain, time.Second*70, "Life Drain Timing")
+
+	ai.lightSpectrum.CD.Set(firstLightSpectrumAt)
+	ai.disintegrationBeam.CD.Set(firstDisintegrationBeamAt)
+}
+
+func (ai *DurumuAI) registerTankAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	hardStareBase := []float64{150_000, 200_000}[scalingIndex]
+
+	ai.hardStare = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 133765},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*12),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, tankTarget, hardStareBase, spell.OutcomeAlwaysHit)
+		},
+	})
+}
+
+func (ai *DurumuAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	forceOfWillBase := []float64{120_000, 160_000}[scalingIndex]
+	lingeringGazeBase := []float64{70_000, 95_000}[scalingIndex]
+	lifeDrainTick := []float64{500_000, 700_000}[scalingIndex]
+
+	// Force of Will knocks a random player back, who walks back to the boss.
+	ai.forceOfWill = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136413},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, pushedPlayer := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Force of Will Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, pushedPlayer, forceOfWillBase, spell.OutcomeAlwaysHit)
+				pushedPlayer.ForceMovement(time.Second*3, sim)
+			}
+		},
+	})
+
+	ai.lingeringGaze = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138467},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*46),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 3, 1)
+
+			for _, gazeTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Lingering Gaze Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, gazeTarget, lingeringGazeBase, spell.OutcomeAlwaysHit)
+				gazeTarget.ForceMovement(time.Second*2, sim)
+			}
+		},
+	})
+
+	// Life Drain is intercepted by a chain of players taking turns, so its
+	// damage is spread evenly over the raid.
+	ai.lifeDrain = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 133795},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*50),
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Life Drain",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 15,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, lifeDrainTick/float64(ai.raidSize), dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+}
+
+func (ai *DurumuAI) registerPhaseAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	spectrumTick := []float64{20_000, 30_000}[scalingIndex]
+	mazeDuration := time.Second * 60
+	mazeMoveInterval := time.Second * 5
+
+	// During the Light Spectrum the raid finds and burns the Fog Beasts, taking
+	// light damage from the beams along the way.
+	ai.lightSpectrum = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 133737},
+		SpellSchool:      core.SpellSchoolHoly,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: durumuPhaseCycle,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Light Spectrum",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 40,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, spectrumTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.spectrumMoveTime, sim)
+			}
+
+			spell.AOEDot().Apply(sim)
+		},
+	})
+
+	// During the maze the boss stops every other ability, and the raid keeps
+	// shuffling through the gaps in the fog ahead of the beam.
+	ai.disintegrationBeam = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 133776},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: durumuPhaseCycle,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			ai.disintegrationEnd = sim.CurrentTime + mazeDuration
+			ai.Target.AutoAttacks.CancelAutoSwing(sim)
+
+			moveTime := core.DurationFromSeconds(mazeMoveInterval.Seconds() * ai.mazeMovementFraction)
+
+			core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:          mazeMoveInterval,
+				NumTicks:        int(mazeDuration / mazeMoveInterval),
+				TickImmediately: true,
+				Priority:        core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					for _, raidMember := range sim.Raid.AllPlayerUnits {
+						raidMember.ForceMovement(moveTime, sim)
+					}
+				},
+
+				CleanUp: func(sim *core.Simulation) {
+					ai.Target.AutoAttacks.EnableAutoSwing(sim)
+				},
+			})
+		},
+	})
+}
+
+func (ai *DurumuAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if sim.CurrentTime < ai.disintegrationEnd {
+		ai.Target.ExtendGCDUntil(sim, ai.disintegrationEnd)
+		return
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	if ai.disintegrationBeam.IsReady(sim) {
+		ai.disintegrationBeam.Cast(sim, target)
+		ai.Target.ExtendGCDUntil(sim, ai.disintegrationEnd)
+		return
+	}
+
+	if castFirstReady(sim, target, ai.lightSpectrum, ai.hardStare, ai.lingeringGaze, ai.forceOfWill, ai.lifeDrain) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
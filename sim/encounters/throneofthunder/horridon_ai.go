@@ -0,0 +1,669 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const horridonID int32 = 68476
+const jalakID int32 = 69374
+
+// Tribal doors open in a fixed order on a fixed cadence for the whole fight.
+const firstDoorAt = time.Second * 20
+const doorInterval = time.Second * 113
+
+type tribalDoor int32
+
+const (
+	farrakiDoor tribalDoor = iota
+	gurubashiDoor
+	drakkariDoor
+	amaniDoor
+	numTribalDoors
+)
+
+func addHorridon(raidPrefix string) {
+	createHorridonPreset(raidPrefix, raid10Normal, 109_000_000, 500_000, 21_800_000, 310_000)
+	createHorridonPreset(raidPrefix, raid25Normal, 327_100_000, 500_000, 65_400_000, 310_000)
+	createHorridonPreset(raidPrefix, raid10Heroic, 152_700_000, 680_000, 30_500_000, 420_000)
+	createHorridonPreset(raidPrefix, raid25Heroic, 458_000_000, 680_000, 91_600_000, 420_000)
+}
+
+func createHorridonPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64, addHealth float64, addMinBaseDamage float64) {
+	bossName := difficulty.targetName("Horridon")
+	addName := difficulty.targetName("War-God Jalak")
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              horridonID,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeBeast,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			ParryHaste:    false,
+			TargetInputs:  horridonTargetInputs(difficulty),
+		},
+
+		AI: makeHorridonAI(),
+	})
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        jalakID,
+			Name:      addName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      addHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: addMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  jalakTargetInputs(difficulty),
+		},
+
+		AI: makeJalakAI(),
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+	})
+
+	core.AddPresetEncounter(bossName+" + Jalak", []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + addName,
+	})
+}
+
+func horridonTargetInputs(difficulty raidDifficulty) []*proto.TargetInput {
+	return []*proto.TargetInput{
+		difficultyTargetInput(difficulty),
+		{
+			Label:       "Triple Puncture tank swap stacks",
+			Tooltip:     "Number of Triple Puncture stacks at which Tank 2 taunts the boss. Set to 0 to disable tank swaps.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 9,
+		},
+		{
+			Label:       "Door add phase duration",
+			Tooltip:     "How long (in seconds) the raid takes to clear the adds from each Tribal Door. Add abilities hit the raid for this long after every door opens.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+		{
+			Label:     "Stun Horridon with Orb of Control",
+			Tooltip:   "If checked, Horridon is stunned by Headache for 10 seconds whenever a door's adds have been cleared.",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+	}
+}
+
+func jalakTargetInputs(difficulty raidDifficulty) []*proto.TargetInput {
+	return []*proto.TargetInput{
+		difficultyTargetInput(difficulty),
+		{
+			Label:       "Jalak kill time",
+			Tooltip:     "How long (in seconds) after leaping into the arena War-God Jalak survives. Horridon gains Rampage once Jalak dies.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 40,
+		},
+	}
+}
+
+// Returns the time at which the given Tribal Door opens.
+func doorOpensAt(door tribalDoor) time.Duration {
+	return firstDoorAt + time.Duration(door)*doorInterval
+}
+
+func makeHorridonAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &HorridonAI{}
+	}
+}
+
+type HorridonAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	tankSwapStacks    int32
+	doorPhaseDuration time.Duration
+	useOrbOfControl   bool
+
+	// Spell + aura references
+	triplePuncture     *core.Spell
+	triplePunctureAura core.AuraArray
+	doubleSwipe        *core.Spell
+	charge             *core.Spell
+	direCall           *core.Spell
+	headacheAura       *core.Aura
+	doorAbilities      [numTribalDoors]*core.Spell
+}
+
+func (ai *HorridonAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.Target.AutoAttacks.MHConfig().ActionID.Tag = horridonID
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.tankSwapStacks = int32(config.TargetInputs[1].NumberValue)
+	ai.doorPhaseDuration = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+	ai.useOrbOfControl = config.TargetInputs[3].BoolValue
+
+	ai.registerTriplePuncture()
+	ai.registerDoubleSwipe()
+	ai.registerCharge()
+	ai.registerDireCall()
+	ai.registerHeadache()
+	ai.registerDoorAbilities()
+}
+
+func (ai *HorridonAI) Reset(sim *core.Simulation) {
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.triplePuncture, time.Second*10, "Triple Puncture Timing")
+	randomizeFirstCast(sim, ai.doubleSwipe, time.Second*16, "Double Swipe Timing")
+	randomizeFirstCast(sim, ai.charge, time.Second*30, "Charge Timing")
+
+	if ai.direCall != nil {
+		randomizeFirstCast(sim, ai.direCall, time.Second*62, "Dire Call Timing")
+	}
+
+	// Schedule the add phases for each Tribal Door.
+	for door := farrakiDoor; door < numTribalDoors; door++ {
+		doorAbility := ai.doorAbilities[door]
+		openAt := doorOpensAt(door)
+
+		core.StartDelayedAction(sim, core.DelayedActionOptions{
+			DoAt:     openAt,
+			Priority: core.ActionPriorityDOT,
+
+			OnAction: func(sim *core.Simulation) {
+				core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+					Period:   time.Second * 8,
+					NumTicks: int(ai.doorPhaseDuration / (time.Second * 8)),
+					Priority: core.ActionPriorityDOT,
+
+					OnAction: func(sim *core.Simulation) {
+						doorAbility.Cast(sim, ai.Target.TankOrFirstPlayer())
+					},
+				})
+			},
+		})
+
+		if ai.useOrbOfControl {
+			core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt:     openAt + ai.doorPhaseDuration,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.headacheAura.Activate(sim)
+				},
+			})
+		}
+	}
+}
+
+func (ai *HorridonAI) registerTriplePuncture() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	damagePerStack := []float64{0.1, 0.1}[scalingIndex]
+
+	ai.triplePunctureAura = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:     "Triple Puncture",
+			ActionID:  core.ActionID{SpellID: 136767},
+			Duration:  time.Second * 90,
+			MaxStacks: 100,
+
+			OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+				aura.Unit.PseudoStats.DamageTakenMultiplier *= (1.0 + damagePerStack*float64(newStacks)) / (1.0 + damagePerStack*float64(oldStacks))
+			},
+		})
+	})
+
+	ai.triplePuncture = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136767},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 0.9,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 11,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4)
+			result := spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+
+			puncture := ai.triplePunctureAura.Get(tankTarget)
+			if !result.Landed() || (puncture == nil) {
+				return
+			}
+
+			puncture.Activate(sim)
+			puncture.AddStack(sim)
+
+			if (ai.tankSwapStacks > 0) && (puncture.GetStacks() >= ai.tankSwapStacks) && (ai.OffTank != nil) {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+}
+
+func (ai *HorridonAI) registerDoubleSwipe() {
+	ai.doubleSwipe = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136741},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 1.5,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 17,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			// Only the frontal swipe is modeled, since the raid is assumed to avoid the rear arc.
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4)
+			spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+		},
+	})
+}
+
+func (ai *HorridonAI) registerCharge() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	chargeBase := []float64{180_000, 240_000}[scalingIndex]
+
+	ai.charge = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136769},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 50,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// Horridon charges a random player, who then has to step out of the follow-up Double Swipe.
+			for _, chargeTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Charge Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, chargeTarget, chargeBase, spell.OutcomeAlwaysHit)
+				chargeTarget.ForceMovement(time.Second*2, sim)
+			}
+		},
+	})
+}
+
+func (ai *HorridonAI) registerDireCall() {
+	if !ai.isHeroic {
+		return
+	}
+
+	direCallBase := 170_000.0
+	direCallVariance := direCallBase * 0.1
+
+	ai.direCall = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137458},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 62,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := direCallBase + direCallVariance*sim.RandomFloat("Dire Call Damage")
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *HorridonAI) registerHeadache() {
+	ai.headacheAura = ai.Target.RegisterAura(core.Aura{
+		Label:    "Headache",
+		ActionID: core.ActionID{SpellID: 137294},
+		Duration: time.Second * 10,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.AutoAttacks.CancelAutoSwing(sim)
+			ai.Target.ExtendGCDUntil(sim, aura.ExpiresAt())
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.AutoAttacks.EnableAutoSwing(sim)
+		},
+	})
+}
+
+// Each door unleashes a different tribe, whose adds pressure the raid with their
+// own damage and movement pattern until they are cleared.
+func (ai *HorridonAI) registerDoorAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+
+	// Farraki: Sand Traps force players to reposition.
+	sandTrapBase := []float64{60_000, 80_000}[scalingIndex]
+	ai.doorAbilities[farrakiDoor] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136723},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, trappedPlayer := range sim.Raid.SelectRandomPlayers(sim, 5, ai.raidSize, "Sand Trap Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, trappedPlayer, sandTrapBase, spell.OutcomeAlwaysHit)
+				trappedPlayer.ForceMovement(time.Millisecond*1500, sim)
+			}
+		},
+	})
+
+	// Gurubashi: Venom Bolt Volley hits the whole raid.
+	venomBoltBase := []float64{70_000, 95_000}[scalingIndex]
+	ai.doorAbilities[gurubashiDoor] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136587},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := venomBoltBase * (0.9 + 0.2*sim.RandomFloat("Venom Bolt Volley Damage"))
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Drakkari: Frozen Bolts fired at a handful of players.
+	frozenBoltBase := []float64{110_000, 150_000}[scalingIndex]
+	ai.doorAbilities[drakkariDoor] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136573},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, boltTarget := range sim.Raid.SelectRandomPlayers(sim, 8, ai.raidSize, "Frozen Bolt Target") {
+				spell.CalcAndDealDamage(sim, boltTarget, frozenBoltBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Amani: Chain Lightning bounces through clumped players.
+	chainLightningBase := []float64{90_000, 120_000}[scalingIndex]
+	ai.doorAbilities[amaniDoor] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136480},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			bounceMultiplier := 1.0
+			for _, bounceTarget := range sim.Raid.SelectRandomPlayers(sim, 5, ai.raidSize, "Chain Lightning Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, bounceTarget, chainLightningBase*bounceMultiplier, spell.OutcomeAlwaysHit)
+				bounceMultiplier *= 1.2
+			}
+		},
+	})
+}
+
+func (ai *HorridonAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if ai.headacheAura.IsActive() {
+		ai.Target.ExtendGCDUntil(sim, ai.headacheAura.ExpiresAt())
+		return
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	if ai.triplePuncture.IsReady(sim) {
+		ai.triplePuncture.Cast(sim, target)
+		return
+	}
+
+	if ai.doubleSwipe.IsReady(sim) {
+		ai.doubleSwipe.Cast(sim, target)
+		return
+	}
+
+	if (ai.direCall != nil) && ai.direCall.IsReady(sim) {
+		ai.direCall.Cast(sim, target)
+		return
+	}
+
+	if ai.charge.IsReady(sim) {
+		ai.charge.Cast(sim, target)
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
+
+func makeJalakAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &JalakAI{}
+	}
+}
+
+type JalakAI struct {
+	// Unit references
+	Target   *core.Target
+	BossUnit *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	killTime time.Duration
+
+	// Spell + aura references
+	bestialCry   *core.Spell
+	rampageAura  *core.Aura
+	isEngaged    bool
+	bestialCries int32
+}
+
+func (ai *JalakAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.Target.AutoAttacks.MHConfig().ActionID.Tag = jalakID
+	ai.BossUnit = &target.NextTarget().Unit
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+	ai.killTime = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+
+	ai.registerBestialCry()
+	ai.registerRampage()
+}
+
+func (ai *JalakAI) registerBestialCry() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	bestialCryBase := []float64{55_000, 70_000}[scalingIndex]
+
+	ai.bestialCry = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136817},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 10,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// Each successive cry is 50% stronger than the last.
+			cryMultiplier := 1.0 + 0.5*float64(ai.bestialCries)
+			ai.bestialCries++
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, bestialCryBase*cryMultiplier, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *JalakAI) registerRampage() {
+	// The aura lives on Horridon, who is enraged when Jalak dies.
+	ai.rampageAura = ai.BossUnit.RegisterAura(core.Aura{
+		Label:    "Rampage",
+		ActionID: core.ActionID{SpellID: 136821},
+		Duration: core.NeverExpires,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier *= 1.5
+			aura.Unit.MultiplyAttackSpeed(sim, 1.5)
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier /= 1.5
+			aura.Unit.MultiplyAttackSpeed(sim, 1/1.5)
+		},
+	})
+}
+
+func (ai *JalakAI) Reset(sim *core.Simulation) {
+	ai.isEngaged = false
+	ai.bestialCries = 0
+
+	// Jalak watches from his platform until the Amani door opens.
+	ai.Target.AutoAttacks.CancelAutoSwing(sim)
+	ai.bestialCry.CD.Set(core.NeverExpires)
+
+	jumpAt := doorOpensAt(amaniDoor)
+
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     jumpAt,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			ai.isEngaged = true
+			ai.bestialCry.CD.Set(sim.CurrentTime + time.Second*5)
+
+			if ai.Target.CurrentTarget != nil {
+				ai.Target.AutoAttacks.EnableAutoSwing(sim)
+				ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+			}
+		},
+	})
+
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     jumpAt + ai.killTime,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			ai.isEngaged = false
+			ai.Target.AutoAttacks.CancelAutoSwing(sim)
+			ai.bestialCry.CD.Set(core.NeverExpires)
+			ai.rampageAura.Activate(sim)
+		},
+	})
+}
+
+func (ai *JalakAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if ai.isEngaged && ai.bestialCry.IsReady(sim) {
+		ai.bestialCry.Cast(sim, ai.Target.TankOrFirstPlayer())
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
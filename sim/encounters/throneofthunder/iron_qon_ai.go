@@ -0,0 +1,399 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const ironQonID int32 = 68078
+
+type ironQonPhase int32
+
+const (
+	ironQonRoshak ironQonPhase = iota
+	ironQonQuetzal
+	ironQonDamren
+	ironQonOnFoot
+)
+
+func addIronQon(raidPrefix string) {
+	createIronQonPreset(raidPrefix, raid10Normal, 107_400_000, 460_000)
+	createIronQonPreset(raidPrefix, raid25Normal, 322_100_000, 460_000)
+	createIronQonPreset(raidPrefix, raid10Heroic, 150_300_000, 630_000)
+	createIronQonPreset(raidPrefix, raid25Heroic, 451_000_000, 630_000)
+}
+
+func createIronQonPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Iron Qon", ironQonID, proto.MobType_MobTypeHumanoid, bossHealth, bossMinBaseDamage, ironQonTargetInputs(), makeIronQonAI())
+}
+
+func ironQonTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Impale tank swap stacks",
+			Tooltip:     "Number of Impale stacks at which Tank 2 taunts the boss. Set to 0 to disable tank swaps.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+		{
+			Label:       "Phase reposition time",
+			Tooltip:     "How long (in seconds) players spend moving out of Lightning Storm, Windstorm and Dead Zone hazards.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+	}
+}
+
+func makeIronQonAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &IronQonAI{}
+	}
+}
+
+type IronQonAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	tankSwapStacks int32
+	repositionTime time.Duration
+
+	// Phase tracking
+	phase ironQonPhase
+
+	// Spell + aura references
+	impale         *core.Spell
+	impaleAura     core.AuraArray
+	throwSpear     *core.Spell
+	unleashedFlame *core.Spell
+	lightningStorm *core.Spell
+	windstorm      *core.Spell
+	frozenBlood    *core.Spell
+	deadZone       *core.Spell
+	fistSmash      *core.Spell
+	risingAnger    *core.Aura
+}
+
+func (ai *IronQonAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.tankSwapStacks = int32(config.TargetInputs[1].NumberValue)
+	ai.repositionTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+
+	ai.registerSharedAbilities()
+	ai.registerQuilenAbilities()
+	ai.registerOnFootAbilities()
+}
+
+func (ai *IronQonAI) Reset(sim *core.Simulation) {
+	ai.phase = ironQonRoshak
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.impale, time.Second*20, "Impale Timing")
+	randomizeFirstCast(sim, ai.throwSpear, time.Second*30, "Throw Spear Timing")
+	randomizeFirstCast(sim, ai.unleashedFlame, time.Second*6, "Unleashed Flame Timing")
+}
+
+func (ai *IronQonAI) registerSharedAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	throwSpearBase := []float64{150_000, 210_000}[scalingIndex]
+
+	// Each Impale stacks a bleed-like debuff that increases the physical
+	// damage the tank takes.
+	ai.impaleAura = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:     "Impale",
+			ActionID:  core.ActionID{SpellID: 134691},
+			Duration:  time.Second * 40,
+			MaxStacks: 10,
+
+			OnStacksChange: func(aura *core.Aura, _ *core.Simulation, oldStacks int32, newStacks int32) {
+				aura.Unit.PseudoStats.SchoolDamageTakenMultiplier[stats.SchoolIndexPhysical] *= (1 + 0.1*float64(newStacks)) / (1 + 0.1*float64(oldStacks))
+			},
+		})
+	})
+
+	ai.impale = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134691},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 1.5,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4)
+			result := spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+
+			impale := ai.impaleAura.Get(tankTarget)
+
+			if !result.Landed() || (impale == nil) {
+				return
+			}
+
+			impale.Activate(sim)
+			impale.AddStack(sim)
+
+			if (ai.tankSwapStacks > 0) && (impale.GetStacks() >= ai.tankSwapStacks) && (ai.OffTank != nil) {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+
+	// Throw Spear leaves a burning line that the target has to walk out of.
+	ai.throwSpear = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134926},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*33),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, spearTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Throw Spear Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, spearTarget, throwSpearBase, spell.OutcomeAlwaysHit)
+				spearTarget.ForceMovement(time.Second*2, sim)
+			}
+		},
+	})
+}
+
+func (ai *IronQonAI) registerQuilenAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	unleashedFlameBase := []float64{1_200_000, 1_600_000}[scalingIndex]
+	lightningStormBase := []float64{80_000, 110_000}[scalingIndex]
+	frozenBloodBase := []float64{40_000, 55_000}[scalingIndex]
+
+	// Ro'shak's Unleashed Flame is split between the players that soak it,
+	// which is assumed to be the whole raid.
+	ai.unleashedFlame = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134611},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*6),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, unleashedFlameBase/float64(ai.raidSize), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.lightningStorm = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136192},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 5, 2)
+
+			for _, stormTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Lightning Storm Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, stormTarget, lightningStormBase, spell.OutcomeAlwaysHit)
+				stormTarget.ForceMovement(ai.repositionTime, sim)
+			}
+		},
+	})
+
+	// Quet'zal's Windstorm pushes the whole raid around the arena.
+	ai.windstorm = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 136577},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*70),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.repositionTime, sim)
+			}
+		},
+	})
+
+	ai.frozenBlood = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136451},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Frozen Blood",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 8,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, frozenBloodBase, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 5, 2)
+
+			for _, dotTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Frozen Blood Target", ai.MainTank, ai.OffTank) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+
+	// Dam'ren's Dead Zone blocks two sides of him, so the raid repositions.
+	ai.deadZone = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 137226},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*15),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.repositionTime, sim)
+			}
+		},
+	})
+}
+
+func (ai *IronQonAI) registerOnFootAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	fistSmashTick := []float64{65_000, 90_000}[scalingIndex]
+
+	// Once all three Quilen are dead, Iron Qon fights on foot and grows more
+	// angry every few seconds.
+	ai.risingAnger = ai.Target.RegisterAura(core.Aura{
+		Label:     "Rising Anger",
+		ActionID:  core.ActionID{SpellID: 136323},
+		Duration:  core.NeverExpires,
+		MaxStacks: 100,
+
+		OnStacksChange: func(aura *core.Aura, _ *core.Simulation, oldStacks int32, newStacks int32) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier *= (1 + 0.1*float64(newStacks)) / (1 + 0.1*float64(oldStacks))
+		},
+	})
+
+	ai.fistSmash = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136146},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Fist Smash",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 4,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, fistSmashTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+}
+
+// Moves on to the next Quilen, or onto the final phase, at 75%, 50% and 25%
+// remaining health.
+func (ai *IronQonAI) enterPhase(sim *core.Simulation, nextPhase ironQonPhase) {
+	ai.phase = nextPhase
+
+	switch nextPhase {
+	case ironQonQuetzal:
+		randomizeFirstCast(sim, ai.lightningStorm, time.Second*8, "Lightning Storm Timing")
+		randomizeFirstCast(sim, ai.windstorm, time.Second*50, "Windstorm Timing")
+	case ironQonDamren:
+		randomizeFirstCast(sim, ai.frozenBlood, time.Second*8, "Frozen Blood Timing")
+		randomizeFirstCast(sim, ai.deadZone, time.Second*15, "Dead Zone Timing")
+	case ironQonOnFoot:
+		randomizeFirstCast(sim, ai.fistSmash, time.Second*20, "Fist Smash Timing")
+		ai.risingAnger.Activate(sim)
+
+		core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+			Period:   time.Second * 15,
+			Priority: core.ActionPriorityDOT,
+
+			OnAction: func(sim *core.Simulation) {
+				ai.risingAnger.AddStack(sim)
+			},
+		})
+	}
+}
+
+func (ai *IronQonAI) ExecuteCustomRotation(sim *core.Simulation) {
+	healthPercent := sim.GetRemainingDurationPercent()
+
+	if (ai.phase == ironQonRoshak) && (healthPercent <= 0.75) {
+		ai.enterPhase(sim, ironQonQuetzal)
+	} else if (ai.phase == ironQonQuetzal) && (healthPercent <= 0.5) {
+		ai.enterPhase(sim, ironQonDamren)
+	} else if (ai.phase == ironQonDamren) && (healthPercent <= 0.25) {
+		ai.enterPhase(sim, ironQonOnFoot)
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	var phaseAbilities []*core.Spell
+
+	switch ai.phase {
+	case ironQonRoshak:
+		phaseAbilities = []*core.Spell{ai.impale, ai.unleashedFlame, ai.throwSpear}
+	case ironQonQuetzal:
+		phaseAbilities = []*core.Spell{ai.impale, ai.windstorm, ai.lightningStorm, ai.throwSpear}
+	case ironQonDamren:
+		phaseAbilities = []*core.Spell{ai.impale, ai.deadZone, ai.frozenBlood, ai.throwSpear}
+	case ironQonOnFoot:
+		phaseAbilities = []*core.Spell{ai.impale, ai.fistSmash, ai.throwSpear}
+	}
+
+	if castFirstReady(sim, target, phaseAbilities...) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,293 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const jiKunID int32 = 69712
+
+func addJiKun(raidPrefix string) {
+	createJiKunPreset(raidPrefix, raid10Normal, 94_500_000, 470_000)
+	createJiKunPreset(raidPrefix, raid25Normal, 283_400_000, 470_000)
+	createJiKunPreset(raidPrefix, raid10Heroic, 132_300_000, 640_000)
+	createJiKunPreset(raidPrefix, raid25Heroic, 396_800_000, 640_000)
+}
+
+func createJiKunPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Ji-Kun", jiKunID, proto.MobType_MobTypeBeast, bossHealth, bossMinBaseDamage, jiKunTargetInputs(), makeJiKunAI())
+}
+
+func jiKunTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Talon Rake tank swap stacks",
+			Tooltip:     "Number of Talon Rake stacks at which Tank 2 taunts the boss. Set to 0 to disable tank swaps.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+		{
+			Label:       "Down Draft movement time",
+			Tooltip:     "How long (in seconds) each player spends moving against the wind during every Down Draft.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 5,
+		},
+	}
+}
+
+func makeJiKunAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &JiKunAI{}
+	}
+}
+
+type JiKunAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	tankSwapStacks    int32
+	downDraftMoveTime time.Duration
+
+	// Spell + aura references
+	talonRake      *core.Spell
+	talonRakeAura  core.AuraArray
+	infectedTalons *core.Spell
+	caw            *core.Spell
+	quills         *core.Spell
+	downDraft      *core.Spell
+	channelEnd     time.Duration
+}
+
+func (ai *JiKunAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.tankSwapStacks = int32(config.TargetInputs[1].NumberValue)
+	ai.downDraftMoveTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+
+	ai.registerTankAbilities()
+	ai.registerRaidAbilities()
+}
+
+func (ai *JiKunAI) Reset(sim *core.Simulation) {
+	ai.channelEnd = 0
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.talonRake, time.Second*20, "Talon Rake Timing")
+	randomizeFirstCast(sim, ai.infectedTalons, time.Second*12, "Infected Talons Timing")
+	randomizeFirstCast(sim, ai.caw, time.Second*15, "Caw Timing")
+	randomizeFirstCast(sim, ai.quills, time.Second*40, "Quills Timing")
+	randomizeFirstCast(sim, ai.downDraft, time.Second*90, "Down Draft Timing")
+}
+
+func (ai *JiKunAI) registerTankAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	infectedTalonsTick := []float64{25_000, 35_000}[scalingIndex]
+
+	// Each Talon Rake increases the damage the tank takes from the next one.
+	ai.talonRakeAura = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:     "Talon Rake",
+			ActionID:  core.ActionID{SpellID: 134366},
+			Duration:  time.Second * 60,
+			MaxStacks: 10,
+		})
+	})
+
+	ai.talonRake = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134366},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 2,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			rake := ai.talonRakeAura.Get(tankTarget)
+			stackMultiplier := 1.0
+
+			if rake != nil && rake.IsActive() {
+				stackMultiplier += 0.5 * float64(rake.GetStacks())
+			}
+
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4) * stackMultiplier
+			result := spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+
+			if !result.Landed() || (rake == nil) {
+				return
+			}
+
+			rake.Activate(sim)
+			rake.AddStack(sim)
+
+			if (ai.tankSwapStacks > 0) && (rake.GetStacks() >= ai.tankSwapStacks) && (ai.OffTank != nil) {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+
+	ai.infectedTalons = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 140092},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*12),
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Infected Talons",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, infectedTalonsTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.Dot(tankTarget).Apply(sim)
+		},
+	})
+}
+
+func (ai *JiKunAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	cawBase := []float64{110_000, 150_000}[scalingIndex]
+	quillsTick := []float64{45_000, 60_000}[scalingIndex]
+	quillsDuration := time.Second * 10
+	downDraftDuration := time.Second * 8
+
+	ai.caw = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138923},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*18),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 5, 2)
+
+			for _, cawTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Caw Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, cawTarget, cawBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Quills is channeled, so Ji-Kun does nothing else until it ends.
+	ai.quills = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134380},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 62,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Quills",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: int32(quillsDuration / time.Second),
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, quillsTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			ai.channelEnd = sim.CurrentTime + quillsDuration
+			spell.AOEDot().Apply(sim)
+		},
+	})
+
+	// Down Draft blows the raid towards the edge of the nest, so everybody has
+	// to keep walking against the wind.
+	ai.downDraft = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 134370},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 93,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			ai.channelEnd = sim.CurrentTime + downDraftDuration
+
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(min(ai.downDraftMoveTime, downDraftDuration), sim)
+			}
+		},
+	})
+}
+
+func (ai *JiKunAI) ExecuteCustomRotation(sim *core.Simulation) {
+	// Ji-Kun is channeling Quills or Down Draft and cannot use other abilities.
+	if sim.CurrentTime < ai.channelEnd {
+		ai.Target.ExtendGCDUntil(sim, ai.channelEnd)
+		return
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	for _, channel := range []*core.Spell{ai.quills, ai.downDraft} {
+		if channel.IsReady(sim) {
+			channel.Cast(sim, target)
+			ai.Target.ExtendGCDUntil(sim, ai.channelEnd)
+			return
+		}
+	}
+
+	if castFirstReady(sim, target, ai.talonRake, ai.infectedTalons, ai.caw) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,367 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const jinrokhID int32 = 69465
+
+func addJinrokh(raidPrefix string) {
+	createJinrokhPreset(raidPrefix, raid10Normal, 93_400_000, 480_000)
+	createJinrokhPreset(raidPrefix, raid25Normal, 280_300_000, 480_000)
+	createJinrokhPreset(raidPrefix, raid10Heroic, 130_800_000, 650_000)
+	createJinrokhPreset(raidPrefix, raid25Heroic, 392_400_000, 650_000)
+}
+
+func createJinrokhPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Jin'rokh the Breaker", jinrokhID, proto.MobType_MobTypeHumanoid, bossHealth, bossMinBaseDamage, jinrokhTargetInputs(), makeJinrokhAI())
+}
+
+func jinrokhTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:     "Tank swap on Static Burst",
+			Tooltip:   "If checked, Tank 2 will taunt the boss after every Static Burst so that Static Wound stacks can fall off the current tank.",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+		{
+			Label:       "Focused Lightning kite time",
+			Tooltip:     "How long (in seconds) a player targeted by Focused Lightning spends moving before the orb detonates.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 4,
+		},
+		{
+			Label:       "Lightning Storm reposition time",
+			Tooltip:     "How long (in seconds) each player spends moving into position at the start of every Lightning Storm.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+	}
+}
+
+func makeJinrokhAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &JinrokhAI{}
+	}
+}
+
+type JinrokhAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	tankSwap            bool
+	kiteDuration        time.Duration
+	stormRepositionTime time.Duration
+
+	// Spell + aura references
+	staticBurst       *core.Spell
+	focusedLightning  *core.Spell
+	thunderingThrow   *core.Spell
+	lightningStorm    *core.Spell
+	staticWoundSpell  *core.Spell
+	lightningStormEnd time.Duration
+}
+
+func (ai *JinrokhAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.tankSwap = config.TargetInputs[1].BoolValue
+	ai.kiteDuration = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+	ai.stormRepositionTime = core.DurationFromSeconds(config.TargetInputs[3].NumberValue)
+
+	ai.registerStaticBurst()
+	ai.registerFocusedLightning()
+	ai.registerThunderingThrow()
+	ai.registerLightningStorm()
+}
+
+func (ai *JinrokhAI) Reset(sim *core.Simulation) {
+	ai.lightningStormEnd = 0
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.staticBurst, time.Second*13, "Static Burst Timing")
+	randomizeFirstCast(sim, ai.focusedLightning, time.Second*8, "Focused Lightning Timing")
+	randomizeFirstCast(sim, ai.thunderingThrow, time.Second*30, "Thundering Throw Timing")
+	randomizeFirstCast(sim, ai.lightningStorm, time.Second*90, "Lightning Storm Timing")
+}
+
+func (ai *JinrokhAI) registerStaticBurst() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	staticBurstBase := []float64{220_000, 300_000}[scalingIndex]
+	staticWoundTick := []float64{9_000, 12_000}[scalingIndex]
+
+	// Static Wound ticks on the tank for every remaining application, and splashes
+	// a third of that damage onto the rest of the raid. Each boss melee that lands
+	// on the affected tank removes an application.
+	ai.staticWoundSpell = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138349},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label:     "Static Wound",
+				MaxStacks: 10,
+
+				OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+					if (spell == ai.Target.AutoAttacks.MHAuto()) && result.Landed() {
+						aura.RemoveStack(sim)
+					}
+				},
+			},
+
+			TickLength:    time.Second * 3,
+			NumberOfTicks: 8,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				tickDamage := staticWoundTick * float64(dot.GetStacks())
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, tickDamage, dot.Spell.OutcomeAlwaysHit)
+
+				for _, raidMember := range sim.Raid.AllPlayerUnits {
+					if raidMember != target {
+						dot.Spell.CalcAndDealPeriodicDamage(sim, raidMember, tickDamage/3, dot.Spell.OutcomeAlwaysHit)
+					}
+				}
+			},
+		},
+	})
+
+	ai.staticBurst = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137162},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 19,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, tankTarget, staticBurstBase, spell.OutcomeAlwaysHit)
+
+			staticWound := ai.staticWoundSpell.Dot(tankTarget)
+			staticWound.Apply(sim)
+			staticWound.SetStacks(sim, staticWound.MaxStacks)
+
+			if ai.tankSwap && (ai.OffTank != nil) {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+}
+
+func (ai *JinrokhAI) registerFocusedLightning() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	detonationBase := []float64{150_000, 195_000}[scalingIndex]
+	detonationVariance := detonationBase * 0.1
+
+	detonation := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137374},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			damageRoll := detonationBase + detonationVariance*sim.RandomFloat("Focused Lightning Damage")
+			spell.CalcAndDealDamage(sim, target, damageRoll, spell.OutcomeAlwaysHit)
+		},
+	})
+
+	ai.focusedLightning = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 137399},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 10,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// The orb fixates a random non-tank, who kites it until it detonates.
+			for _, kiteTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Focused Lightning Target", ai.MainTank, ai.OffTank) {
+				kiteTarget.ForceMovement(ai.kiteDuration, sim)
+
+				core.StartDelayedAction(sim, core.DelayedActionOptions{
+					DoAt:     sim.CurrentTime + ai.kiteDuration,
+					Priority: core.ActionPriorityDOT,
+
+					OnAction: func(sim *core.Simulation) {
+						detonation.Cast(sim, kiteTarget)
+					},
+				})
+			}
+		},
+	})
+}
+
+func (ai *JinrokhAI) registerThunderingThrow() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	thunderingThrowBase := []float64{350_000, 450_000}[scalingIndex]
+
+	ai.thunderingThrow = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137180},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 90,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, tankTarget, thunderingThrowBase, spell.OutcomeAlwaysHit)
+
+			// The thrown tank is stunned, so the other tank picks up the boss.
+			if ai.OffTank != nil {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+}
+
+func (ai *JinrokhAI) registerLightningStorm() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	lightningStormBase := []float64{55_000, 75_000}[scalingIndex]
+	lightningStormVariance := lightningStormBase * 0.1
+	lightningStormDuration := time.Second * 15
+
+	ai.lightningStorm = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137313},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 90,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Lightning Storm",
+
+				OnGain: func(aura *core.Aura, sim *core.Simulation) {
+					ai.lightningStormEnd = sim.CurrentTime + lightningStormDuration
+					aura.Unit.AutoAttacks.CancelAutoSwing(sim)
+
+					for _, raidMember := range sim.Raid.AllPlayerUnits {
+						raidMember.ForceMovement(ai.stormRepositionTime, sim)
+					}
+				},
+
+				OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+					aura.Unit.AutoAttacks.EnableAutoSwing(sim)
+				},
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: int32(lightningStormDuration / time.Second),
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					damageRoll := lightningStormBase + lightningStormVariance*sim.RandomFloat("Lightning Storm Damage")
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, damageRoll, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+}
+
+func (ai *JinrokhAI) ExecuteCustomRotation(sim *core.Simulation) {
+	// The boss is channeling Lightning Storm and cannot use other abilities.
+	if sim.CurrentTime < ai.lightningStormEnd {
+		ai.Target.ExtendGCDUntil(sim, ai.lightningStormEnd)
+		return
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	if ai.thunderingThrow.IsReady(sim) {
+		ai.thunderingThrow.Cast(sim, target)
+		return
+	}
+
+	if ai.lightningStorm.IsReady(sim) {
+		ai.lightningStorm.Cast(sim, target)
+		ai.Target.ExtendGCDUntil(sim, ai.lightningStormEnd)
+		return
+	}
+
+	if ai.staticBurst.IsReady(sim) {
+		ai.staticBurst.Cast(sim, target)
+		return
+	}
+
+	if ai.focusedLightning.IsReady(sim) {
+		ai.focusedLightning.Cast(sim, target)
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,460 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const leiShenID int32 = 68397
+
+func addLeiShen(raidPrefix string) {
+	createLeiShenPreset(raidPrefix, raid10Normal, 197_600_000, 520_000)
+	createLeiShenPreset(raidPrefix, raid25Normal, 592_900_000, 520_000)
+	createLeiShenPreset(raidPrefix, raid10Heroic, 276_700_000, 700_000)
+	createLeiShenPreset(raidPrefix, raid25Heroic, 830_000_000, 700_000)
+}
+
+func createLeiShenPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Lei Shen", leiShenID, proto.MobType_MobTypeHumanoid, bossHealth, bossMinBaseDamage, leiShenTargetInputs(), makeLeiShenAI())
+}
+
+func leiShenTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Intermission duration",
+			Tooltip:     "How long (in seconds) Lei Shen remains untargetable while supercharging the conduits at 65% and 30% health.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+		{
+			Label:       "Thunderstruck dodge time",
+			Tooltip:     "How long (in seconds) each player spends moving out of the Thunderstruck impact zone.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+		{
+			Label:       "Lightning Whip dodge time",
+			Tooltip:     "How long (in seconds) each player spends moving out of the path of Lightning Whip.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+	}
+}
+
+func makeLeiShenAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &LeiShenAI{}
+	}
+}
+
+type leiShenPhase int32
+
+const (
+	leiShenPhase1 leiShenPhase = iota + 1
+	leiShenPhase2
+	leiShenPhase3
+)
+
+type LeiShenAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	intermissionDuration  time.Duration
+	thunderstruckMoveTime time.Duration
+	lightningWhipMoveTime time.Duration
+
+	// Phase tracking
+	phase              leiShenPhase
+	intermissionActive bool
+
+	// Spell + aura references
+	decapitate          *core.Spell
+	thunderstruck       *core.Spell
+	fusionSlash         *core.Spell
+	lightningWhip       *core.Spell
+	ballLightning       *core.Spell
+	violentGaleWinds    *core.Spell
+	superchargeConduits *core.Aura
+}
+
+func (ai *LeiShenAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.intermissionDuration = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+	ai.thunderstruckMoveTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+	ai.lightningWhipMoveTime = core.DurationFromSeconds(config.TargetInputs[3].NumberValue)
+
+	ai.registerTankAbilities()
+	ai.registerRaidAbilities()
+	ai.registerIntermission()
+}
+
+func (ai *LeiShenAI) Reset(sim *core.Simulation) {
+	ai.phase = leiShenPhase1
+	ai.intermissionActive = false
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.decapitate, time.Second*40, "Decapitate Timing")
+	randomizeFirstCast(sim, ai.thunderstruck, time.Second*25, "Thunderstruck Timing")
+}
+
+func (ai *LeiShenAI) registerTankAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	decapitateBase := []float64{450_000, 600_000}[scalingIndex]
+	fusionSlashBase := []float64{550_000, 750_000}[scalingIndex]
+
+	tankSwapAfter := func(sim *core.Simulation, tankTarget *core.Unit) {
+		if ai.OffTank != nil {
+			swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+		}
+	}
+
+	ai.decapitate = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134912},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 50,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, tankTarget, decapitateBase, spell.OutcomeAlwaysHit)
+			tankSwapAfter(sim, tankTarget)
+		},
+	})
+
+	ai.fusionSlash = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136478},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 42,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, tankTarget, fusionSlashBase, spell.OutcomeAlwaysHit)
+			tankSwapAfter(sim, tankTarget)
+		},
+	})
+}
+
+func (ai *LeiShenAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	thunderstruckBase := []float64{200_000, 260_000}[scalingIndex]
+	lightningWhipBase := []float64{180_000, 240_000}[scalingIndex]
+	ballLightningBase := []float64{60_000, 80_000}[scalingIndex]
+	galeWindsBase := []float64{40_000, 55_000}[scalingIndex]
+
+	ai.thunderstruck = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 135095},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 46,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// Everyone moves away from the impact point, but the players caught
+			// near it still take the full hit.
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.thunderstruckMoveTime, sim)
+			}
+
+			for _, hitTarget := range sim.Raid.SelectRandomPlayers(sim, 5, ai.raidSize, "Thunderstruck Target") {
+				spell.CalcAndDealDamage(sim, hitTarget, thunderstruckBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.lightningWhip = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136850},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 45,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.lightningWhipMoveTime, sim)
+			}
+
+			for _, hitTarget := range sim.Raid.SelectRandomPlayers(sim, 3, ai.raidSize, "Lightning Whip Target", ai.MainTank, ai.OffTank) {
+				spell.CalcAndDealDamage(sim, hitTarget, lightningWhipBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Ball Lightning orbs fixate players and explode on contact, pulsing damage
+	// through the raid for their lifetime.
+	ai.ballLightning = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136543},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 45,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Ball Lightning",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 7,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, ballLightningBase, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+
+	ai.violentGaleWinds = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136889},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.BossGCD,
+			},
+
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// The wind pushes the raid back, forcing everyone to walk into
+			// position again.
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, raidMember, galeWindsBase, spell.OutcomeAlwaysHit)
+				raidMember.ForceMovement(time.Second*3, sim)
+			}
+		},
+	})
+}
+
+func (ai *LeiShenAI) registerIntermission() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	overchargeTick := []float64{45_000, 60_000}[scalingIndex]
+
+	// Overcharged conduits pulse damage through the raid while Lei Shen is away.
+	overcharge := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136295},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, overchargeTick, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	var overchargeAction *core.PendingAction
+	var untargetable untargetableState
+
+	// Lei Shen is untargetable for the duration of each intermission, and stops
+	// meleeing.
+	ai.superchargeConduits = ai.Target.RegisterAura(core.Aura{
+		Label:    "Supercharge Conduits",
+		ActionID: core.ActionID{SpellID: 137045},
+		Duration: core.NeverExpires,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			ai.intermissionActive = true
+			untargetable.apply(sim, ai.Target)
+			aura.Unit.AutoAttacks.CancelAutoSwing(sim)
+
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.thunderstruckMoveTime, sim)
+			}
+
+			overchargeAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second * 3,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					overcharge.Cast(sim, ai.Target.TankOrFirstPlayer())
+				},
+			})
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			ai.intermissionActive = false
+			untargetable.remove(ai.Target)
+			aura.Unit.AutoAttacks.EnableAutoSwing(sim)
+
+			if overchargeAction != nil {
+				overchargeAction.Cancel(sim)
+				overchargeAction = nil
+			}
+		},
+	})
+}
+
+func (ai *LeiShenAI) startIntermission(sim *core.Simulation, nextPhase leiShenPhase) {
+	ai.phase = nextPhase
+	ai.superchargeConduits.Activate(sim)
+
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     sim.CurrentTime + ai.intermissionDuration,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			ai.superchargeConduits.Deactivate(sim)
+
+			// Everybody walks back to Lei Shen when he returns to the platform.
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.thunderstruckMoveTime, sim)
+			}
+
+			// Phase abilities come back on a fresh timer after each intermission.
+			if nextPhase == leiShenPhase2 {
+				randomizeFirstCast(sim, ai.fusionSlash, time.Second*10, "Fusion Slash Timing")
+				randomizeFirstCast(sim, ai.lightningWhip, time.Second*30, "Lightning Whip Timing")
+				randomizeFirstCast(sim, ai.ballLightning, time.Second*15, "Ball Lightning Timing")
+			} else {
+				randomizeFirstCast(sim, ai.violentGaleWinds, time.Second*20, "Violent Gale Winds Timing")
+				randomizeFirstCast(sim, ai.lightningWhip, time.Second*30, "Lightning Whip Timing")
+				randomizeFirstCast(sim, ai.thunderstruck, time.Second*15, "Thunderstruck Timing")
+			}
+		},
+	})
+}
+
+func (ai *LeiShenAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if ai.intermissionActive {
+		ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+		return
+	}
+
+	healthPercent := sim.GetRemainingDurationPercent()
+
+	if (ai.phase == leiShenPhase1) && (healthPercent <= 0.65) {
+		ai.startIntermission(sim, leiShenPhase2)
+		ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+		return
+	}
+
+	if (ai.phase == leiShenPhase2) && (healthPercent <= 0.30) {
+		ai.startIntermission(sim, leiShenPhase3)
+		ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+		return
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	var phaseAbilities []*core.Spell
+
+	switch ai.phase {
+	case leiShenPhase1:
+		phaseAbilities = []*core.Spell{ai.decapitate, ai.thunderstruck}
+	case leiShenPhase2:
+		phaseAbilities = []*core.Spell{ai.fusionSlash, ai.ballLightning, ai.lightningWhip}
+	case leiShenPhase3:
+		phaseAbilities = []*core.Spell{ai.violentGaleWinds, ai.thunderstruck, ai.lightningWhip}
+	}
+
+	if castFirstReady(sim, target, phaseAbilities...) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,337 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const megaeraID int32 = 68065
+const megaeraRampageDuration = time.Second * 20
+
+type megaeraHead int32
+
+const (
+	flamingHead megaeraHead = iota
+	frozenHead
+	venomousHead
+	numMegaeraHeads
+)
+
+func addMegaera(raidPrefix string) {
+	createMegaeraPreset(raidPrefix, raid10Normal, 30_600_000, 430_000)
+	createMegaeraPreset(raidPrefix, raid25Normal, 91_800_000, 430_000)
+	createMegaeraPreset(raidPrefix, raid10Heroic, 45_900_000, 590_000)
+	createMegaeraPreset(raidPrefix, raid25Heroic, 137_700_000, 590_000)
+}
+
+// Every head has its own health pool, so the preset health is that of a
+// single head.
+func createMegaeraPreset(raidPrefix string, difficulty raidDifficulty, headHealth float64, headMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Megaera", megaeraID, proto.MobType_MobTypeDragonkin, headHealth, headMinBaseDamage, megaeraTargetInputs(), makeMegaeraAI())
+}
+
+func megaeraTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Head kill time",
+			Tooltip:     "How long (in seconds) the raid takes to kill a head after each Rampage. Every kill starts a new Rampage, which grows stronger with each head killed.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 40,
+		},
+		{
+			Label:       "Torrent of Ice kite time",
+			Tooltip:     "How long (in seconds) a player targeted by Torrent of Ice spends moving away from it.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 5,
+		},
+	}
+}
+
+func makeMegaeraAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &MegaeraAI{}
+	}
+}
+
+type MegaeraAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	headKillTime    time.Duration
+	torrentKiteTime time.Duration
+
+	// Head tracking. The two heads in front of the raid are tanked separately,
+	// and a killed head is replaced by the next head type in the rotation.
+	activeHeads [2]megaeraHead
+	nextHead    megaeraHead
+	headsKilled int32
+	rampageEnd  time.Duration
+
+	// Spell + aura references
+	breaths       [numMegaeraHeads]*core.Spell
+	raidAbilities [numMegaeraHeads]*core.Spell
+	rampage       *core.Spell
+}
+
+func (ai *MegaeraAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.headKillTime = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+	ai.torrentKiteTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+
+	ai.registerBreaths()
+	ai.registerRaidAbilities()
+	ai.registerRampage()
+}
+
+func (ai *MegaeraAI) Reset(sim *core.Simulation) {
+	ai.activeHeads = [2]megaeraHead{flamingHead, frozenHead}
+	ai.nextHead = venomousHead
+	ai.headsKilled = 0
+	ai.rampageEnd = 0
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	for head := flamingHead; head < numMegaeraHeads; head++ {
+		randomizeFirstCast(sim, ai.breaths[head], time.Second*5, "Breath Timing")
+		randomizeFirstCast(sim, ai.raidAbilities[head], time.Second*13, "Head Ability Timing")
+	}
+
+	ai.scheduleHeadKill(sim, ai.headKillTime)
+}
+
+// Kills one of the two front heads at the given time, alternating sides. Each
+// kill starts a Rampage, and the next kill is scheduled after it ends.
+func (ai *MegaeraAI) scheduleHeadKill(sim *core.Simulation, killAt time.Duration) {
+	if ai.headKillTime <= 0 {
+		return
+	}
+
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     killAt,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			killedSlot := ai.headsKilled % 2
+			ai.headsKilled++
+
+			// Two heads of the killed type return, one of which replaces it at
+			// the front, while the next head in the rotation joins from behind.
+			ai.activeHeads[killedSlot] = ai.nextHead
+			ai.nextHead = (ai.nextHead + 1) % numMegaeraHeads
+
+			ai.rampageEnd = sim.CurrentTime + megaeraRampageDuration
+			ai.rampage.Cast(sim, ai.Target.TankOrFirstPlayer())
+			ai.scheduleHeadKill(sim, ai.rampageEnd+ai.headKillTime)
+		},
+	})
+}
+
+func (ai *MegaeraAI) registerBreaths() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	breathBase := []float64{140_000, 190_000}[scalingIndex]
+	breathDotTick := []float64{20_000, 28_000}[scalingIndex]
+
+	breathActionIDs := [numMegaeraHeads]core.ActionID{{SpellID: 137731}, {SpellID: 139841}, {SpellID: 139839}}
+	breathSchools := [numMegaeraHeads]core.SpellSchool{core.SpellSchoolFire, core.SpellSchoolFrost, core.SpellSchoolNature}
+	breathDebuffLabels := [numMegaeraHeads]string{"Ignite Flesh", "Arctic Freeze", "Rot Armor"}
+
+	for head := flamingHead; head < numMegaeraHeads; head++ {
+		// Each breath leaves a stacking debuff on the head's tank, which ticks
+		// harder with every application until it expires.
+		ai.breaths[head] = ai.Target.RegisterSpell(core.SpellConfig{
+			ActionID:         breathActionIDs[head],
+			SpellSchool:      breathSchools[head],
+			ProcMask:         core.ProcMaskSpellDamage,
+			Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+			DamageMultiplier: 1,
+
+			Cast: bossAbilityCast(ai.Target, time.Second*16),
+
+			Dot: core.DotConfig{
+				Aura: core.Aura{
+					Label:     breathDebuffLabels[head],
+					MaxStacks: 10,
+				},
+
+				TickLength:    time.Second * 2,
+				NumberOfTicks: 15,
+
+				OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, target, breathDotTick*float64(dot.GetStacks()), dot.Spell.OutcomeAlwaysHit)
+				},
+			},
+
+			ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+				spell.CalcAndDealDamage(sim, tankTarget, breathBase, spell.OutcomeAlwaysHit)
+
+				dot := spell.Dot(tankTarget)
+				dot.Apply(sim)
+				dot.AddStack(sim)
+			},
+		})
+	}
+}
+
+func (ai *MegaeraAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	cindersTick := []float64{50_000, 70_000}[scalingIndex]
+	torrentBase := []float64{80_000, 110_000}[scalingIndex]
+	acidRainBase := []float64{60_000, 85_000}[scalingIndex]
+
+	// Cinders burns a random player, who has to carry it out of the raid.
+	ai.raidAbilities[flamingHead] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 139822},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*25),
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Cinders",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 30,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, cindersTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Cinders Target", ai.MainTank, ai.OffTank) {
+				dotTarget.ForceMovement(time.Second*2, sim)
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+
+	// Torrent of Ice chases a random player, who kites it around the room.
+	ai.raidAbilities[frozenHead] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 139857},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*25),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, kiteTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Torrent of Ice Target", ai.MainTank, ai.OffTank) {
+				kiteTarget.ForceMovement(ai.torrentKiteTime, sim)
+				spell.CalcAndDealDamage(sim, kiteTarget, torrentBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.raidAbilities[venomousHead] = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 139850},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, acidRainBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *MegaeraAI) registerRampage() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	rampageTick := []float64{45_000, 60_000}[scalingIndex]
+
+	// Every head killed adds another head to the next Rampage, which hits the
+	// whole raid with the elements of the heads in front.
+	ai.rampage = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 139458},
+		SpellSchool:      core.SpellSchoolFire | core.SpellSchoolFrost | core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Rampage",
+
+				OnGain: func(aura *core.Aura, sim *core.Simulation) {
+					aura.Unit.AutoAttacks.CancelAutoSwing(sim)
+				},
+
+				OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+					aura.Unit.AutoAttacks.EnableAutoSwing(sim)
+				},
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: int32(megaeraRampageDuration / time.Second),
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				tickDamage := rampageTick * (1.0 + 0.25*float64(ai.headsKilled-1))
+
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, tickDamage, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+}
+
+func (ai *MegaeraAI) ExecuteCustomRotation(sim *core.Simulation) {
+	// The heads do nothing but Rampage until it ends.
+	if sim.CurrentTime < ai.rampageEnd {
+		ai.Target.ExtendGCDUntil(sim, ai.rampageEnd)
+		return
+	}
+
+	tanks := [2]*core.Unit{ai.Target.TankOrFirstPlayer(), core.Ternary(ai.OffTank != nil, ai.OffTank, ai.Target.TankOrFirstPlayer())}
+
+	for slot, head := range ai.activeHeads {
+		if ai.breaths[head].IsReady(sim) {
+			ai.breaths[head].Cast(sim, tanks[slot])
+			return
+		}
+	}
+
+	for _, head := range ai.activeHeads {
+		if ai.raidAbilities[head].IsReady(sim) {
+			ai.raidAbilities[head].Cast(sim, tanks[0])
+			return
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
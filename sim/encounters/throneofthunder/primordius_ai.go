@@ -0,0 +1,341 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const primordiusID int32 = 69017
+
+type primordiusEvolution int32
+
+const (
+	metabolicBoost primordiusEvolution = iota
+	acidicSpines
+	pustuleEruption
+	numPrimordiusEvolutions
+)
+
+func addPrimordius(raidPrefix string) {
+	createPrimordiusPreset(raidPrefix, raid10Normal, 105_800_000, 430_000)
+	createPrimordiusPreset(raidPrefix, raid25Normal, 317_500_000, 430_000)
+	createPrimordiusPreset(raidPrefix, raid10Heroic, 148_200_000, 590_000)
+	createPrimordiusPreset(raidPrefix, raid25Heroic, 444_500_000, 590_000)
+}
+
+func createPrimordiusPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Primordius", primordiusID, proto.MobType_MobTypeUnknown, bossHealth, bossMinBaseDamage, primordiusTargetInputs(), makePrimordiusAI())
+}
+
+func primordiusTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Malformed Blood tank swap stacks",
+			Tooltip:     "Number of Malformed Blood stacks at which Tank 2 taunts the boss. Set to 0 to disable tank swaps.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 8,
+		},
+		{
+			Label:       "Living Fluid soak time",
+			Tooltip:     "How long (in seconds) each player spends moving to soak a Living Fluid pool for mutations after every Evolution.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+	}
+}
+
+func makePrimordiusAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &PrimordiusAI{}
+	}
+}
+
+type PrimordiusAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	tankSwapStacks int32
+	fluidSoakTime  time.Duration
+
+	// Spell + aura references
+	primordialStrike  *core.Spell
+	malformedBlood    *core.Spell
+	causticGas        *core.Spell
+	volatilePathogen  *core.Spell
+	evolution         *core.Spell
+	evolutionAuras    [numPrimordiusEvolutions]*core.Aura
+	acidicSpinesSpell *core.Spell
+	pustuleSpell      *core.Spell
+}
+
+func (ai *PrimordiusAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.tankSwapStacks = int32(config.TargetInputs[1].NumberValue)
+	ai.fluidSoakTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+
+	ai.registerTankAbilities()
+	ai.registerRaidAbilities()
+	ai.registerEvolution()
+}
+
+func (ai *PrimordiusAI) Reset(sim *core.Simulation) {
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.primordialStrike, time.Second*20, "Primordial Strike Timing")
+	randomizeFirstCast(sim, ai.causticGas, time.Second*12, "Caustic Gas Timing")
+	randomizeFirstCast(sim, ai.volatilePathogen, time.Second*25, "Volatile Pathogen Timing")
+	randomizeFirstCast(sim, ai.evolution, time.Second*30, "Evolution Timing")
+}
+
+func (ai *PrimordiusAI) registerTankAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	malformedBloodTick := []float64{8_000, 11_000}[scalingIndex]
+
+	// Malformed Blood stacks on the tank with every Primordial Strike, and
+	// ticks for each application until the tanks swap.
+	ai.malformedBlood = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136050},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label:     "Malformed Blood",
+				MaxStacks: 20,
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 30,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, malformedBloodTick*float64(dot.GetStacks()), dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+	})
+
+	ai.primordialStrike = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136037},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 1.5,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4)
+			result := spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+
+			if !result.Landed() {
+				return
+			}
+
+			malformedBlood := ai.malformedBlood.Dot(tankTarget)
+			malformedBlood.Apply(sim)
+			malformedBlood.AddStack(sim)
+
+			if (ai.tankSwapStacks > 0) && (malformedBlood.GetStacks() >= ai.tankSwapStacks) && (ai.OffTank != nil) {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+}
+
+func (ai *PrimordiusAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	causticGasBase := []float64{1_500_000, 2_000_000}[scalingIndex]
+	pathogenTick := []float64{30_000, 40_000}[scalingIndex]
+
+	// Caustic Gas is split between every player in melee range, which is
+	// assumed to be the whole raid.
+	ai.causticGas = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136216},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*14),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, causticGasBase/float64(ai.raidSize), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.volatilePathogen = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136228},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*28),
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Volatile Pathogen",
+			},
+
+			TickLength:    time.Second * 3,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, pathogenTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Volatile Pathogen Target", ai.MainTank, ai.OffTank) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+}
+
+func (ai *PrimordiusAI) registerEvolution() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	acidicSpinesBase := []float64{35_000, 50_000}[scalingIndex]
+	pustuleBase := []float64{80_000, 110_000}[scalingIndex]
+	evolutionDuration := time.Second * 60
+
+	ai.acidicSpinesSpell = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136218},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, acidicSpinesBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.pustuleSpell = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136247},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, splashTarget := range sim.Raid.SelectRandomPlayers(sim, 5, ai.raidSize, "Pustule Eruption Target") {
+				spell.CalcAndDealDamage(sim, splashTarget, pustuleBase, spell.OutcomeAlwaysHit)
+				splashTarget.ForceMovement(time.Second, sim)
+			}
+		},
+	})
+
+	// Each Evolution grants Primordius a random mutation for a minute, and
+	// spawns Living Fluids that the raid steps into for mutations of its own.
+	ai.evolutionAuras[metabolicBoost] = ai.Target.RegisterAura(core.Aura{
+		Label:    "Metabolic Boost",
+		ActionID: core.ActionID{SpellID: 136245},
+		Duration: evolutionDuration,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyAttackSpeed(sim, 1.5)
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyAttackSpeed(sim, 1/1.5)
+		},
+	})
+
+	var acidicSpinesAction *core.PendingAction
+	ai.evolutionAuras[acidicSpines] = ai.Target.RegisterAura(core.Aura{
+		Label:    "Acidic Spines",
+		ActionID: core.ActionID{SpellID: 136218},
+		Duration: evolutionDuration,
+
+		OnGain: func(_ *core.Aura, sim *core.Simulation) {
+			acidicSpinesAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second * 2,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.acidicSpinesSpell.Cast(sim, ai.Target.TankOrFirstPlayer())
+				},
+			})
+		},
+
+		OnExpire: func(_ *core.Aura, sim *core.Simulation) {
+			acidicSpinesAction.Cancel(sim)
+		},
+	})
+
+	var pustuleAction *core.PendingAction
+	ai.evolutionAuras[pustuleEruption] = ai.Target.RegisterAura(core.Aura{
+		Label:    "Pustule Eruption",
+		ActionID: core.ActionID{SpellID: 136246},
+		Duration: evolutionDuration,
+
+		OnGain: func(_ *core.Aura, sim *core.Simulation) {
+			pustuleAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second * 5,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.pustuleSpell.Cast(sim, ai.Target.TankOrFirstPlayer())
+				},
+			})
+		},
+
+		OnExpire: func(_ *core.Aura, sim *core.Simulation) {
+			pustuleAction.Cancel(sim)
+		},
+	})
+
+	ai.evolution = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 139144},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*32),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			evolutionRoll := int(sim.RandomFloat("Evolution") * float64(numPrimordiusEvolutions))
+			ai.evolutionAuras[evolutionRoll].Activate(sim)
+
+			for _, raidMember := range sim.Raid.AllPlayerUnits {
+				raidMember.ForceMovement(ai.fluidSoakTime, sim)
+			}
+		},
+	})
+}
+
+func (ai *PrimordiusAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.TankOrFirstPlayer()
+
+	if castFirstReady(sim, target, ai.evolution, ai.primordialStrike, ai.causticGas, ai.volatilePathogen) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,313 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const raDenID int32 = 69473
+
+type raDenMaterial int32
+
+const (
+	materialVita raDenMaterial = iota
+	materialAnima
+)
+
+// Ra-den can only be fought on heroic difficulty.
+func addRaDen(raidPrefix string) {
+	createRaDenPreset(raidPrefix, raid10Heroic, 220_000_000, 680_000)
+	createRaDenPreset(raidPrefix, raid25Heroic, 660_000_000, 680_000)
+}
+
+func createRaDenPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Ra-den", raDenID, proto.MobType_MobTypeUnknown, bossHealth, bossMinBaseDamage, raDenTargetInputs(), makeRaDenAI())
+}
+
+func raDenTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Fatal Strike tank swap stacks",
+			Tooltip:     "Number of Fatal Strike stacks at which Tank 2 taunts the boss. Set to 0 to disable tank swaps.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+		{
+			Label:       "Unstable Vita movement time",
+			Tooltip:     "How long (in seconds) each Unstable Vita target spends moving to the far side of the raid before it bounces.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 2,
+		},
+	}
+}
+
+func makeRaDenAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &RaDenAI{}
+	}
+}
+
+type RaDenAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	tankSwapStacks int32
+	vitaMoveTime   time.Duration
+
+	// Phase tracking
+	phaseTwo     bool
+	nextMaterial raDenMaterial
+
+	// Spell + aura references
+	fatalStrike         *core.Spell
+	fatalStrikeAura     core.AuraArray
+	materialsOfCreation *core.Spell
+	unstableVita        *core.Spell
+	unstableAnima       *core.Spell
+	ruin                *core.Spell
+	ruinBolt            *core.Spell
+}
+
+func (ai *RaDenAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.tankSwapStacks = int32(config.TargetInputs[1].NumberValue)
+	ai.vitaMoveTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+
+	ai.registerTankAbilities()
+	ai.registerMaterials()
+	ai.registerRuin()
+}
+
+func (ai *RaDenAI) Reset(sim *core.Simulation) {
+	ai.phaseTwo = false
+	ai.nextMaterial = raDenMaterial(core.TernaryInt32(sim.RandomFloat("First Material") < 0.5, int32(materialVita), int32(materialAnima)))
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.fatalStrike, time.Second*10, "Fatal Strike Timing")
+	randomizeFirstCast(sim, ai.materialsOfCreation, time.Second*12, "Materials of Creation Timing")
+}
+
+func (ai *RaDenAI) registerTankAbilities() {
+	// Fatal Strike increases the damage the tank takes from the next one, so
+	// the tanks swap after a couple of stacks.
+	ai.fatalStrikeAura = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:     "Fatal Strike",
+			ActionID:  core.ActionID{SpellID: 138334},
+			Duration:  time.Second * 30,
+			MaxStacks: 10,
+		})
+	})
+
+	ai.fatalStrike = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138334},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 2,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*10),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			fatalStrike := ai.fatalStrikeAura.Get(tankTarget)
+			stackMultiplier := 1.0
+
+			if fatalStrike != nil && fatalStrike.IsActive() {
+				stackMultiplier += 0.5 * float64(fatalStrike.GetStacks())
+			}
+
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4) * stackMultiplier
+			result := spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+
+			if !result.Landed() || (fatalStrike == nil) {
+				return
+			}
+
+			fatalStrike.Activate(sim)
+			fatalStrike.AddStack(sim)
+
+			if (ai.tankSwapStacks > 0) && (fatalStrike.GetStacks() >= ai.tankSwapStacks) && (ai.OffTank != nil) {
+				swapTank(sim, &ai.Target.Unit, core.Ternary(tankTarget == ai.MainTank, ai.OffTank, ai.MainTank))
+			}
+		},
+	})
+}
+
+func (ai *RaDenAI) registerMaterials() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	unstableVitaBase := []float64{300_000, 400_000}[scalingIndex]
+	unstableAnimaBase := []float64{50_000, 65_000}[scalingIndex]
+	vitaBounces := int32(5)
+
+	// Unstable Vita hits its target, then bounces to the furthest player, so
+	// each target runs across the raid before it jumps.
+	ai.unstableVita = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138297},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			var previousTarget *core.Unit
+
+			for bounce := range vitaBounces {
+				core.StartDelayedAction(sim, core.DelayedActionOptions{
+					DoAt:     sim.CurrentTime + time.Duration(bounce)*time.Second*5,
+					Priority: core.ActionPriorityDOT,
+
+					OnAction: func(sim *core.Simulation) {
+						for _, vitaTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Unstable Vita Target", previousTarget) {
+							spell.CalcAndDealDamage(sim, vitaTarget, unstableVitaBase, spell.OutcomeAlwaysHit)
+							vitaTarget.ForceMovement(ai.vitaMoveTime, sim)
+							previousTarget = vitaTarget
+						}
+					},
+				})
+			}
+		},
+	})
+
+	ai.unstableAnima = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 138288},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagNoOnCastComplete,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Unstable Anima",
+			},
+
+			TickLength:    time.Second * 3,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, unstableAnimaBase, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+
+	// Materials of Creation alternates between empowering Ra-den with Vita and
+	// Anima, depending on which add the raid lets reach him.
+	ai.materialsOfCreation = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 138321},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*32),
+
+		ApplyEffects: func(sim *core.Simulation, victim *core.Unit, _ *core.Spell) {
+			if ai.nextMaterial == materialVita {
+				ai.unstableVita.Cast(sim, victim)
+				ai.nextMaterial = materialAnima
+			} else {
+				ai.unstableAnima.Cast(sim, victim)
+				ai.nextMaterial = materialVita
+			}
+		},
+	})
+}
+
+func (ai *RaDenAI) registerRuin() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	ruinTick := []float64{30_000, 40_000}[scalingIndex]
+	ruinBoltBase := []float64{250_000, 330_000}[scalingIndex]
+
+	// Below 40% Ra-den stops creating and pulses Ruin on the raid, which grows
+	// stronger every time it is cast.
+	ruinStacks := 0
+
+	ai.ruin = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 139073},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*5),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			ruinStacks++
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, ruinTick*(1+0.1*float64(ruinStacks)), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.Target.RegisterResetEffect(func(_ *core.Simulation) {
+		ruinStacks = 0
+	})
+
+	ai.ruinBolt = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 139087},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*10),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, tankTarget, ruinBoltBase, spell.OutcomeAlwaysHit)
+		},
+	})
+}
+
+func (ai *RaDenAI) ExecuteCustomRotation(sim *core.Simulation) {
+	if !ai.phaseTwo && (sim.GetRemainingDurationPercent() <= 0.4) {
+		ai.phaseTwo = true
+		randomizeFirstCast(sim, ai.ruin, 0, "Ruin Timing")
+		randomizeFirstCast(sim, ai.ruinBolt, time.Second*5, "Ruin Bolt Timing")
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	var phaseAbilities []*core.Spell
+
+	if ai.phaseTwo {
+		phaseAbilities = []*core.Spell{ai.fatalStrike, ai.ruin, ai.ruinBolt}
+	} else {
+		phaseAbilities = []*core.Spell{ai.fatalStrike, ai.materialsOfCreation}
+	}
+
+	if castFirstReady(sim, target, phaseAbilities...) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
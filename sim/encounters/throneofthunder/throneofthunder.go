@@ -0,0 +1,186 @@
+package throneofthunder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+// Registers every Throne of Thunder boss, in raid order.
+func Register() {
+	addJinrokh("Throne of Thunder")
+	addHorridon("Throne of Thunder")
+	addCouncilOfElders("Throne of Thunder")
+	addTortos("Throne of Thunder")
+	addMegaera("Throne of Thunder")
+	addJiKun("Throne of Thunder")
+	addDurumu("Throne of Thunder")
+	addPrimordius("Throne of Thunder")
+	addDarkAnimus("Throne of Thunder")
+	addIronQon("Throne of Thunder")
+	addTwinConsorts("Throne of Thunder")
+	addLeiShen("Throne of Thunder")
+	addRaDen("Throne of Thunder")
+}
+
+type raidDifficulty int32
+
+const (
+	raid10Normal raidDifficulty = iota
+	raid25Normal
+	raid10Heroic
+	raid25Heroic
+)
+
+var raidDifficultyLabels = []string{"10 Normal", "25 Normal", "10 Heroic", "25 Heroic"}
+
+func (difficulty raidDifficulty) raidSize() int32 {
+	return core.TernaryInt32((difficulty == raid10Normal) || (difficulty == raid10Heroic), 10, 25)
+}
+
+func (difficulty raidDifficulty) isHeroic() bool {
+	return difficulty >= raid10Heroic
+}
+
+// Appends the raid size and difficulty to an NPC name, e.g. "Lei Shen 25 H".
+func (difficulty raidDifficulty) targetName(npcName string) string {
+	targetName := fmt.Sprintf("%s %d", npcName, difficulty.raidSize())
+
+	if difficulty.isHeroic() {
+		targetName += " H"
+	}
+
+	return targetName
+}
+
+// Mists bosses share one NPC id across difficulties, and preset AIs are looked
+// up by NPC id, so every preset of a boss ends up with the same AI. The
+// difficulty is therefore passed to the AI as the first target input instead.
+func difficultyTargetInput(difficulty raidDifficulty) *proto.TargetInput {
+	return &proto.TargetInput{
+		Label:       "Difficulty",
+		Tooltip:     "Raid size and difficulty that boss abilities are scaled to.",
+		InputType:   proto.InputType_Enum,
+		EnumValue:   int32(difficulty),
+		EnumOptions: raidDifficultyLabels,
+	}
+}
+
+func difficultyFromConfig(config *proto.Target) raidDifficulty {
+	return raidDifficulty(max(0, min(config.TargetInputs[0].EnumValue, int32(raid25Heroic))))
+}
+
+// Registers a single boss target for one difficulty, along with a preset
+// encounter containing only that boss.
+func createBossPreset(raidPrefix string, difficulty raidDifficulty, npcName string, npcId int32, mobType proto.MobType, bossHealth float64, bossMinBaseDamage float64, targetInputs []*proto.TargetInput, ai core.AIFactory) {
+	targetName := difficulty.targetName(npcName)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              npcId,
+			Name:            targetName,
+			Level:           93,
+			MobType:         mobType,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  append([]*proto.TargetInput{difficultyTargetInput(difficulty)}, targetInputs...),
+		},
+
+		AI: ai,
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+// Cast settings shared by boss abilities that use the boss GCD.
+func bossAbilityCast(target *core.Target, cooldown time.Duration) core.CastConfig {
+	return core.CastConfig{
+		DefaultCast: core.Cast{
+			GCD: core.BossGCD,
+		},
+
+		CD: core.Cooldown{
+			Timer:    target.NewTimer(),
+			Duration: cooldown,
+		},
+
+		IgnoreHaste: true,
+	}
+}
+
+// Casts the first ready ability in priority order, returning whether one was cast.
+func castFirstReady(sim *core.Simulation, victim *core.Unit, abilities ...*core.Spell) bool {
+	for _, ability := range abilities {
+		if ability.IsReady(sim) {
+			ability.Cast(sim, victim)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Swaps the NPC onto a new tank, mirroring a taunt from the other tank.
+func swapTank(sim *core.Simulation, npc *core.Unit, newTank *core.Unit) {
+	if (newTank == nil) || (npc.CurrentTarget == newTank) {
+		return
+	}
+
+	npc.AutoAttacks.CancelAutoSwing(sim)
+	npc.CurrentTarget = newTank
+	newTank.CurrentTarget = npc
+	npc.AutoAttacks.EnableAutoSwing(sim)
+}
+
+// Tracks how a boss was made untargetable, e.g. while it leaves the platform.
+// It is removed from the active targets, unless it is the last active one,
+// since class code assumes at least one active target. It then stays active
+// but takes no damage instead.
+type untargetableState struct {
+	deactivated           bool
+	damageTakenMultiplier float64
+}
+
+func (state *untargetableState) apply(sim *core.Simulation, target *core.Target) {
+	state.deactivated = len(sim.Encounter.ActiveTargets) > 1
+
+	if state.deactivated {
+		target.SetActive(false)
+	} else {
+		state.damageTakenMultiplier = target.PseudoStats.DamageTakenMultiplier
+		target.PseudoStats.DamageTakenMultiplier = 0
+	}
+}
+
+func (state *untargetableState) remove(target *core.Target) {
+	if state.deactivated {
+		target.SetActive(true)
+	} else {
+		target.PseudoStats.DamageTakenMultiplier = state.damageTakenMultiplier
+	}
+}
+
+// Randomizes the initial cooldown of a boss ability within its first cast window,
+// starting from the current time, to prevent fake APL-Haste couplings with the
+// player's rotation.
+func randomizeFirstCast(sim *core.Simulation, spell *core.Spell, firstCastIn time.Duration, label string) {
+	spell.CD.Set(sim.CurrentTime + firstCastIn + core.DurationFromSeconds(sim.RandomFloat(label)*core.BossGCD.Seconds()))
+}
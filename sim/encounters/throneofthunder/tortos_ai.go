@@ -0,0 +1,315 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const tortosID int32 = 67977
+
+func addTortos(raidPrefix string) {
+	createTortosPreset(raidPrefix, raid10Normal, 109_400_000, 450_000)
+	createTortosPreset(raidPrefix, raid25Normal, 328_200_000, 450_000)
+	createTortosPreset(raidPrefix, raid10Heroic, 153_200_000, 610_000)
+	createTortosPreset(raidPrefix, raid25Heroic, 459_500_000, 610_000)
+}
+
+func createTortosPreset(raidPrefix string, difficulty raidDifficulty, bossHealth float64, bossMinBaseDamage float64) {
+	createBossPreset(raidPrefix, difficulty, "Tortos", tortosID, proto.MobType_MobTypeBeast, bossHealth, bossMinBaseDamage, tortosTargetInputs(), makeTortosAI())
+}
+
+func tortosTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Shell kickers",
+			Tooltip:     "Number of players who leave the boss to kick a Whirl Turtle shell after every Call of Tortos.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+		{
+			Label:       "Shell kick time",
+			Tooltip:     "How long (in seconds) each shell kicker spends moving to their shell.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 4,
+		},
+		{
+			Label:     "Interrupt Furious Stone Breath",
+			Tooltip:   "If checked, every Furious Stone Breath is interrupted by a kicked shell, which applies Shell Concussion to Tortos. Otherwise the raid takes the full channel.",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+	}
+}
+
+func makeTortosAI() core.AIFactory {
+	return func() core.TargetAI {
+		return &TortosAI{}
+	}
+}
+
+type TortosAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	shellKickers    int32
+	shellKickTime   time.Duration
+	interruptBreath bool
+
+	// Spell + aura references
+	snappingBite       *core.Spell
+	quakeStomp         *core.Spell
+	callOfTortos       *core.Spell
+	furiousStoneBreath *core.Spell
+	summonBats         *core.Spell
+	vampiricBats       *core.Spell
+	rockfall           *core.Spell
+	shellConcussion    *core.Aura
+	furiousBreathEnd   time.Duration
+}
+
+func (ai *TortosAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	ai.shellKickers = int32(config.TargetInputs[1].NumberValue)
+	ai.shellKickTime = core.DurationFromSeconds(config.TargetInputs[2].NumberValue)
+	ai.interruptBreath = config.TargetInputs[3].BoolValue
+
+	ai.registerTankAbilities()
+	ai.registerRaidAbilities()
+	ai.registerFuriousStoneBreath()
+}
+
+func (ai *TortosAI) Reset(sim *core.Simulation) {
+	ai.furiousBreathEnd = 0
+
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	randomizeFirstCast(sim, ai.snappingBite, time.Second*8, "Snapping Bite Timing")
+	randomizeFirstCast(sim, ai.quakeStomp, time.Second*27, "Quake Stomp Timing")
+	randomizeFirstCast(sim, ai.callOfTortos, time.Second*21, "Call of Tortos Timing")
+	randomizeFirstCast(sim, ai.furiousStoneBreath, time.Second*45, "Furious Stone Breath Timing")
+	randomizeFirstCast(sim, ai.summonBats, time.Second*45, "Summon Bats Timing")
+
+	// Rocks keep falling on random players for the whole fight, and have to be
+	// sidestepped.
+	core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+		Period:   time.Second * 10,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			ai.rockfall.Cast(sim, ai.Target.TankOrFirstPlayer())
+		},
+	})
+}
+
+func (ai *TortosAI) registerTankAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	batTick := []float64{40_000, 55_000}[scalingIndex]
+
+	ai.snappingBite = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 135251},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskMeleeMHSpecial,
+		Flags:            core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
+		DamageMultiplier: 2.5,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*8),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4)
+			spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeEnemyMeleeWhite)
+		},
+	})
+
+	// The Vampiric Cave Bats are picked up by the off-tank, who takes their
+	// melee for as long as they live.
+	ai.vampiricBats = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136685},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Vampiric Cave Bats",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 15,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, batTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+	})
+
+	ai.summonBats = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 136686},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*45),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, _ *core.Spell) {
+			ai.vampiricBats.Dot(core.Ternary(ai.OffTank != nil, ai.OffTank, tankTarget)).Apply(sim)
+		},
+	})
+}
+
+func (ai *TortosAI) registerRaidAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	quakeStompFraction := []float64{0.6, 0.65}[scalingIndex]
+
+	// Quake Stomp deals a fixed fraction of each player's maximum health.
+	ai.quakeStomp = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 134920},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*47),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, aoeTarget.MaxHealth()*quakeStompFraction, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Falling rocks are assumed to be dodged, so only the movement is modeled.
+	ai.rockfall = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 134476},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagNoOnCastComplete,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			for _, dodgeTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Rockfall Target", ai.MainTank, ai.OffTank) {
+				dodgeTarget.ForceMovement(time.Millisecond*1500, sim)
+			}
+		},
+	})
+
+	ai.callOfTortos = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 136294},
+		ProcMask: core.ProcMaskEmpty,
+		Flags:    core.SpellFlagAPL,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*60),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			// The Whirl Turtles are stunned with AoE and kicked into the boss by
+			// designated players, who have to run out to them.
+			for _, kicker := range sim.Raid.SelectRandomPlayers(sim, ai.shellKickers, ai.raidSize, "Shell Kicker Target", ai.MainTank, ai.OffTank) {
+				kicker.ForceMovement(ai.shellKickTime, sim)
+			}
+		},
+	})
+}
+
+func (ai *TortosAI) registerFuriousStoneBreath() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	breathTick := []float64{65_000, 90_000}[scalingIndex]
+	breathDuration := time.Millisecond * 4500
+
+	ai.shellConcussion = ai.Target.RegisterAura(core.Aura{
+		Label:    "Shell Concussion",
+		ActionID: core.ActionID{SpellID: 136431},
+		Duration: time.Second * 20,
+
+		OnGain: func(aura *core.Aura, _ *core.Simulation) {
+			aura.Unit.PseudoStats.DamageTakenMultiplier *= 1.5
+		},
+
+		OnExpire: func(aura *core.Aura, _ *core.Simulation) {
+			aura.Unit.PseudoStats.DamageTakenMultiplier /= 1.5
+		},
+	})
+
+	ai.furiousStoneBreath = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 133939},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 46,
+			},
+
+			IgnoreHaste: true,
+		},
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Furious Stone Breath",
+			},
+
+			TickLength:    time.Millisecond * 500,
+			NumberOfTicks: int32(breathDuration / (time.Millisecond * 500)),
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, breathTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// A kicked shell interrupts the breath before its first tick.
+			if ai.interruptBreath {
+				ai.shellConcussion.Activate(sim)
+				return
+			}
+
+			ai.furiousBreathEnd = sim.CurrentTime + breathDuration
+			spell.AOEDot().Apply(sim)
+		},
+	})
+}
+
+func (ai *TortosAI) ExecuteCustomRotation(sim *core.Simulation) {
+	// Tortos is channeling Furious Stone Breath and cannot use other abilities.
+	if sim.CurrentTime < ai.furiousBreathEnd {
+		ai.Target.ExtendGCDUntil(sim, ai.furiousBreathEnd)
+		return
+	}
+
+	target := ai.Target.TankOrFirstPlayer()
+
+	if ai.furiousStoneBreath.IsReady(sim) {
+		ai.furiousStoneBreath.Cast(sim, target)
+		ai.Target.ExtendGCDUntil(sim, max(ai.furiousBreathEnd, sim.CurrentTime+core.BossGCD))
+		return
+	}
+
+	if castFirstReady(sim, target, ai.quakeStomp, ai.callOfTortos, ai.summonBats, ai.snappingBite) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,475 @@
+package throneofthunder
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+type twinConsort int32
+
+const (
+	suen twinConsort = iota
+	lulin
+	numTwinConsorts
+)
+
+var twinConsortIDs = [numTwinConsorts]int32{68904, 68905}
+var twinConsortNames = [numTwinConsorts]string{"Suen", "Lu'lin"}
+
+type twinConsortsPhase int32
+
+const (
+	twinConsortsNight twinConsortsPhase = iota
+	twinConsortsDay
+	twinConsortsDusk
+)
+
+func addTwinConsorts(raidPrefix string) {
+	createTwinConsortsPreset(raidPrefix, raid10Normal, 58_900_000, 440_000)
+	createTwinConsortsPreset(raidPrefix, raid25Normal, 176_700_000, 440_000)
+	createTwinConsortsPreset(raidPrefix, raid10Heroic, 82_400_000, 600_000)
+	createTwinConsortsPreset(raidPrefix, raid25Heroic, 247_400_000, 600_000)
+}
+
+func createTwinConsortsPreset(raidPrefix string, difficulty raidDifficulty, consortHealth float64, consortMinBaseDamage float64) {
+	var targetPathNames []string
+
+	for consort := suen; consort < numTwinConsorts; consort++ {
+		targetName := difficulty.targetName(twinConsortNames[consort])
+		targetInputs := []*proto.TargetInput{difficultyTargetInput(difficulty)}
+
+		if consort == suen {
+			targetInputs = append(targetInputs, twinConsortsTargetInputs()...)
+		}
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        twinConsortIDs[consort],
+				Name:      targetName,
+				Level:     93,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: int32(consort),
+
+				Stats: stats.Stats{
+					stats.Health:      consortHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:    2.0,
+				MinBaseDamage: consortMinBaseDamage,
+				DamageSpread:  0.4,
+				TargetInputs:  targetInputs,
+			},
+
+			AI: makeTwinConsortsAI(consort),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+targetName)
+	}
+
+	core.AddPresetEncounter(difficulty.targetName("Twin Consorts"), targetPathNames)
+}
+
+func twinConsortsTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Night phase duration",
+			Tooltip:     "How long (in seconds) the first Night phase lasts before Lu'lin vanishes and Day begins.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 180,
+		},
+		{
+			Label:       "Dusk health threshold %",
+			Tooltip:     "% of the encounter remaining at which Lu'lin returns for Dusk.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+	}
+}
+
+func makeTwinConsortsAI(consort twinConsort) core.AIFactory {
+	return func() core.TargetAI {
+		return &TwinConsortsAI{
+			consort: consort,
+		}
+	}
+}
+
+type TwinConsortsAI struct {
+	Target *core.Target
+
+	// Static parameters taken from the difficulty input
+	raidSize int32
+	isHeroic bool
+	consort  twinConsort
+
+	// Suen acts as the controller for the shared phase transitions.
+	controller *TwinConsortsAI
+	partner    *TwinConsortsAI
+
+	// Dynamic parameters taken from user inputs (controller only)
+	nightDuration time.Duration
+	duskThreshold float64
+
+	// Phase state (controller only)
+	phase        twinConsortsPhase
+	untargetable untargetableState
+
+	// Consort specific abilities, in priority order
+	nightAbilities []*core.Spell
+	dayAbilities   []*core.Spell
+	duskAbilities  []*core.Spell
+}
+
+func (ai *TwinConsortsAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.Target.AutoAttacks.MHConfig().ActionID.Tag = twinConsortIDs[ai.consort]
+
+	difficulty := difficultyFromConfig(config)
+	ai.raidSize = difficulty.raidSize()
+	ai.isHeroic = difficulty.isHeroic()
+
+	// A lone consort controls its own phases, and stays in Night unless it is Suen.
+	ai.controller = ai
+
+	if ai.consort == suen {
+		ai.nightDuration = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+		ai.duskThreshold = config.TargetInputs[2].NumberValue / 100
+		ai.registerSuenAbilities()
+	} else {
+		ai.registerLulinAbilities()
+	}
+
+	// The consorts look each other up once every target has been constructed.
+	target.Env.RegisterPostFinalizeEffect(func() {
+		for _, encounterTarget := range target.Env.Encounter.Targets {
+			if consortAI, ok := encounterTarget.AI.(*TwinConsortsAI); ok && (consortAI != ai) {
+				ai.partner = consortAI
+
+				if consortAI.consort == suen {
+					ai.controller = consortAI
+				}
+			}
+		}
+	})
+}
+
+func (ai *TwinConsortsAI) Reset(sim *core.Simulation) {
+	// Randomize GCD and swing timings to prevent fake APL-Haste couplings.
+	ai.Target.ExtendGCDUntil(sim, core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+	ai.Target.AutoAttacks.RandomizeMeleeTiming(sim)
+
+	for _, ability := range ai.nightAbilities {
+		randomizeFirstCast(sim, ability, ability.CD.Duration/2, "Twin Consorts Ability Timing")
+	}
+
+	ai.phase = twinConsortsNight
+
+	if (ai.controller != ai) || (ai.consort != suen) {
+		return
+	}
+
+	core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     ai.nightDuration,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			if ai.phase == twinConsortsNight {
+				ai.startDay(sim)
+			}
+		},
+	})
+}
+
+// Lu'lin vanishes at sunrise, leaving Suen to fight alone during the Day.
+func (ai *TwinConsortsAI) startDay(sim *core.Simulation) {
+	ai.phase = twinConsortsDay
+
+	for _, ability := range ai.dayAbilities {
+		randomizeFirstCast(sim, ability, ability.CD.Duration/2, "Twin Consorts Ability Timing")
+	}
+
+	if ai.partner == nil {
+		return
+	}
+
+	ai.partner.Target.AutoAttacks.CancelAutoSwing(sim)
+	ai.untargetable.apply(sim, ai.partner.Target)
+}
+
+// Lu'lin returns at Dusk, and both consorts fight together until the end.
+func (ai *TwinConsortsAI) startDusk(sim *core.Simulation) {
+	previousPhase := ai.phase
+	ai.phase = twinConsortsDusk
+
+	for _, consortAI := range []*TwinConsortsAI{ai, ai.partner} {
+		if consortAI == nil {
+			continue
+		}
+
+		for _, ability := range consortAI.duskAbilities {
+			randomizeFirstCast(sim, ability, ability.CD.Duration/2, "Twin Consorts Ability Timing")
+		}
+	}
+
+	if (ai.partner == nil) || (previousPhase != twinConsortsDay) {
+		return
+	}
+
+	ai.untargetable.remove(ai.partner.Target)
+	ai.partner.Target.AutoAttacks.EnableAutoSwing(sim)
+}
+
+func (ai *TwinConsortsAI) registerSuenAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	tearsOfTheSunTick := []float64{35_000, 50_000}[scalingIndex]
+	fanOfFlamesStack := 0.1
+	flamesOfPassionBase := []float64{120_000, 165_000}[scalingIndex]
+	blazingRadianceTick := []float64{15_000, 22_000}[scalingIndex]
+
+	// Tears of the Sun rains fire on the whole raid during Night and Dusk.
+	tearsOfTheSun := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137404},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*40),
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Tears of the Sun",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, tearsOfTheSunTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.AOEDot().Apply(sim)
+		},
+	})
+
+	// Each Fan of Flames increases the damage the tank takes from the next one.
+	fanOfFlamesAura := ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit.Type == core.PetUnit {
+			return nil
+		}
+
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:     "Fan of Flames",
+			ActionID:  core.ActionID{SpellID: 137408},
+			Duration:  time.Second * 30,
+			MaxStacks: 10,
+		})
+	})
+
+	fanOfFlames := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137408},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1.5,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*12),
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			fan := fanOfFlamesAura.Get(tankTarget)
+			stackMultiplier := 1.0
+
+			if fan != nil && fan.IsActive() {
+				stackMultiplier += fanOfFlamesStack * float64(fan.GetStacks())
+			}
+
+			baseDamage := spell.Unit.AutoAttacks.MH().EnemyWeaponDamage(sim, spell.MeleeAttackPower(), 0.4) * stackMultiplier
+			spell.CalcAndDealDamage(sim, tankTarget, baseDamage, spell.OutcomeAlwaysHit)
+
+			if fan != nil {
+				fan.Activate(sim)
+				fan.AddStack(sim)
+			}
+		},
+	})
+
+	flamesOfPassion := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137414},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*30),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, chargeTarget := range sim.Raid.SelectRandomPlayers(sim, 1, ai.raidSize, "Flames of Passion Target", ai.Target.CurrentTarget) {
+				spell.CalcAndDealDamage(sim, chargeTarget, flamesOfPassionBase, spell.OutcomeAlwaysHit)
+				chargeTarget.ForceMovement(time.Second*2, sim)
+			}
+		},
+	})
+
+	// Blazing Radiance pulses on the raid throughout the Day, growing each time.
+	blazingRadianceStacks := 0
+
+	blazingRadiance := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137410},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*10),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			blazingRadianceStacks++
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, blazingRadianceTick*float64(blazingRadianceStacks), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.Target.RegisterResetEffect(func(_ *core.Simulation) {
+		blazingRadianceStacks = 0
+	})
+
+	ai.nightAbilities = []*core.Spell{fanOfFlames, tearsOfTheSun, flamesOfPassion}
+	ai.dayAbilities = []*core.Spell{fanOfFlames, blazingRadiance, flamesOfPassion}
+	ai.duskAbilities = []*core.Spell{fanOfFlames, tearsOfTheSun, flamesOfPassion}
+}
+
+func (ai *TwinConsortsAI) registerLulinAbilities() {
+	// 0 - Normal, 1 - Heroic
+	scalingIndex := core.TernaryInt(ai.isHeroic, 1, 0)
+	cosmicBarrageBase := []float64{60_000, 85_000}[scalingIndex]
+	beastOfNightmaresTick := []float64{20_000, 28_000}[scalingIndex]
+	iceCometBase := []float64{400_000, 550_000}[scalingIndex]
+	tidalForceTick := []float64{90_000, 125_000}[scalingIndex]
+
+	cosmicBarrage := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 136752},
+		SpellSchool:      core.SpellSchoolArcane,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 5, 2)
+
+			for _, barrageTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Cosmic Barrage Target") {
+				spell.CalcAndDealDamage(sim, barrageTarget, cosmicBarrageBase, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Beast of Nightmares haunts the off-tank, who takes shadow damage over time.
+	beastOfNightmares := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137375},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*50),
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Beast of Nightmares",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 25,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, beastOfNightmaresTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, tankTarget *core.Unit, spell *core.Spell) {
+			spell.Dot(tankTarget).Apply(sim)
+		},
+	})
+
+	// Ice Comet is soaked by the whole raid, splitting its damage.
+	iceComet := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137419},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*20),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, iceCometBase/float64(ai.raidSize), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Tidal Force sweeps the arena, so everybody in its path has to move.
+	tidalForce := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 137531},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreArmor | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+
+		Cast: bossAbilityCast(ai.Target, time.Second*70),
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			numTargets := core.TernaryInt32(ai.raidSize == 25, 8, 3)
+
+			for _, waveTarget := range sim.Raid.SelectRandomPlayers(sim, numTargets, ai.raidSize, "Tidal Force Target") {
+				spell.CalcAndDealDamage(sim, waveTarget, tidalForceTick, spell.OutcomeAlwaysHit)
+				waveTarget.ForceMovement(time.Second*3, sim)
+			}
+		},
+	})
+
+	ai.nightAbilities = []*core.Spell{beastOfNightmares, cosmicBarrage}
+	ai.duskAbilities = []*core.Spell{tidalForce, iceComet, cosmicBarrage}
+}
+
+func (ai *TwinConsortsAI) ExecuteCustomRotation(sim *core.Simulation) {
+	controller := ai.controller
+
+	if (ai.consort == suen) && (controller.phase != twinConsortsDusk) && (sim.GetRemainingDurationPercent() <= controller.duskThreshold) {
+		controller.startDusk(sim)
+	}
+
+	var abilities []*core.Spell
+
+	switch controller.phase {
+	case twinConsortsNight:
+		abilities = ai.nightAbilities
+	case twinConsortsDay:
+		abilities = ai.dayAbilities
+	case twinConsortsDusk:
+		abilities = ai.duskAbilities
+	}
+
+	if castFirstReady(sim, ai.Target.TankOrFirstPlayer(), abilities...) {
+		return
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
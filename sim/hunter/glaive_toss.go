@@ -33,7 +33,7 @@ func (hunter *Hunter) registerGlaiveTossSpell() {
 
 				runPass := func(skipPrimary bool) {
 					for i := int32(0); i < numTargets; i++ {
-						unit := hunter.Env.GetTargetUnit(i)
+						unit := hunter.Env.GetActiveTargetUnit(i)
 
 						// skip the main target on return
 						if skipPrimary && unit == target {
@@ -42,7 +42,7 @@ func (hunter *Hunter) registerMultiShotSpell() {
 
 			baseDamageArray := make([]*core.SpellResult, numHits)
 			for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
-				currentTarget := hunter.Env.GetTargetUnit(hitIndex)
+				currentTarget := hunter.Env.GetActiveTargetUnit(hitIndex)
 				baseDamage := sharedDmg
 				baseDamageArray[hitIndex] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeRangedHitAndCrit)
 			}
@@ -241,7 +241,7 @@ func (hp *HunterPet) ExecuteCustomRotation(sim *core.Simulation) {
 
 	target := hp.CurrentTarget
 
-	if hp.frostStormBreath != nil && hp.frostStormBreath.CanCast(sim, target) && len(sim.Encounter.TargetUnits) > 4 {
+	if hp.frostStormBreath != nil && hp.frostStormBreath.CanCast(sim, target) && len(sim.Encounter.ActiveTargetUnits) > 4 {
 		hp.frostStormBreath.Cast(sim, target)
 	}
 
@@ -336,7 +336,7 @@ func (hp *HunterPet) newFrostStormBreath() *core.Spell {
 			TickLength:          time.Second * 2,
 			AffectedByCastSpeed: true,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 					frostStormTickSpell.Cast(sim, aoeTarget)
 				}
 			},
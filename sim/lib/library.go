@@ -27,6 +27,9 @@ var _active_sim = core.NewSim(&_default_rsr, simsignals.Signals{})
 var _active_seed int64 = 1
 var _aura_labels = []string{}
 var _target_aura_labels = []string{}
+var _interactive_sim *core.InteractiveSim
+var _observation = []float64{}
+var _action_mask = []bool{}
 
 //export runSim
 func runSim(json *C.char) *C.char {
@@ -130,6 +133,11 @@ func getDatabase(itemIds *int32, numItems int32, enchantIds *int32, numEnchants
 			EnchantEffect: enchant.EnchantEffect,
 			Name:          enchant.Name,
 			Type:          enchant.Type,
+
+			ExtraTypes:         enchant.ExtraTypes,
+			EnchantType:        enchant.EnchantType,
+			ClassAllowlist:     enchant.ClassAllowlist,
+			RequiredProfession: enchant.RequiredProfession,
 		}
 	}
 	for i, gemId := range gids {
@@ -356,6 +364,93 @@ func cleanup() {
 	_active_sim.Cleanup()
 }
 
+// The functions below drive an InteractiveSim, which works for any spec: the observation
+// vector and the actions are derived from the first player in the raid, and all other
+// players keep running their own rotations.
+
+//export newInteractiveSim
+func newInteractiveSim(json *C.char) bool {
+	input := &proto.RaidSimRequest{}
+	err := protojson.Unmarshal([]byte(C.GoString(json)), input)
+	if err != nil {
+		log.Printf("failed to load input json: %s", err)
+		return false
+	}
+	sim.RegisterAll()
+	_interactive_sim, err = core.NewInteractiveSim(input)
+	if err != nil {
+		log.Printf("failed to create interactive sim: %s", err)
+		return false
+	}
+	return true
+}
+
+//export resetInteractiveSim
+func resetInteractiveSim(seed int64) {
+	_interactive_sim.Reset(seed)
+}
+
+//export stepInteractiveSim
+func stepInteractiveSim(action int, reward *float64) bool {
+	stepReward, done := _interactive_sim.Step(action)
+	*reward = stepReward
+	return done
+}
+
+//export getNumActions
+func getNumActions() int {
+	return _interactive_sim.NumActions()
+}
+
+// Stores the action IDs of actions 1 to n, action 0 is waiting. Only one of the spell, item
+// and other IDs of each action is set, e.g. items such as trinkets only have an item ID.
+//
+//export getActionIDs
+func getActionIDs(spellIDs *int32, itemIDs *int32, otherIDs *int32, tags *int32, n int32) {
+	actions := _interactive_sim.Actions()
+	actions = actions[:min(int(n), len(actions))]
+	spellIDSlice := unsafe.Slice(spellIDs, n)
+	itemIDSlice := unsafe.Slice(itemIDs, n)
+	otherIDSlice := unsafe.Slice(otherIDs, n)
+	tagSlice := unsafe.Slice(tags, n)
+	for i, actionID := range actions {
+		spellIDSlice[i] = actionID.SpellID
+		itemIDSlice[i] = actionID.ItemID
+		otherIDSlice[i] = int32(actionID.OtherID)
+		tagSlice[i] = actionID.Tag
+	}
+}
+
+//export getActionMask
+func getActionMask(storage *int32, n int32) {
+	mask := unsafe.Slice(storage, n)
+	_action_mask = _interactive_sim.ActionMask(_action_mask)
+	for i, valid := range _action_mask[:min(int(n), len(_action_mask))] {
+		mask[i] = 0
+		if valid {
+			mask[i] = 1
+		}
+	}
+}
+
+//export getObservationSize
+func getObservationSize() int {
+	return len(_interactive_sim.ObservationLabels())
+}
+
+// The returned string must be freed with FreeCString.
+//
+//export getObservationLabel
+func getObservationLabel(i int) *C.char {
+	return C.CString(_interactive_sim.ObservationLabels()[i])
+}
+
+//export getObservation
+func getObservation(storage *float64, n int32) {
+	_observation = _interactive_sim.Observation(_observation)
+	copy(unsafe.Slice(storage, n), _observation)
+}
+
 //export FreeCString
 func FreeCString(s *C.char) {
 	C.free(unsafe.Pointer(s))
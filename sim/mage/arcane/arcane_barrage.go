@@ -12,7 +12,6 @@ func (arcane *ArcaneMage) registerArcaneBarrageSpell() {
 	arcaneBarrageVariance := 0.20   // Per https://wago.tools/db2/SpellEffect?build=5.5.0.60802&filter%5BSpellID%5D=exact%253A44425 Field: "Variance"
 	arcaneBarrageScale := 1.0       // Per https://wago.tools/db2/SpellEffect?build=5.5.0.60802&filter%5BSpellID%5D=exact%253A44425 Field: "Coefficient"
 	arcaneBarrageCoefficient := 1.0 // Per https://wago.tools/db2/SpellEffect?build=5.5.0.60802&filter%5BSpellID%5D=exact%253A44425 Field: "BonusCoefficient"
-	numberOfTargets := int32(len(arcane.Env.Encounter.TargetUnits) - 1)
 
 	arcane.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 44425},
@@ -51,7 +50,7 @@ func (arcane *ArcaneMage) registerArcaneBarrageSpell() {
 			spell.DamageMultiplier *= .5
 			currTarget := target
 
-			for range min(arcane.ArcaneChargesAura.GetStacks(), numberOfTargets) {
+			for range min(arcane.ArcaneChargesAura.GetStacks(), sim.GetNumTargets()-1) {
 				currTarget = arcane.Env.NextTargetUnit(currTarget)
 				baseDamage := arcane.CalcAndRollDamageRange(sim, arcaneBarrageScale, arcaneBarrageVariance)
 				result := spell.CalcDamage(sim, currTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
@@ -12,12 +12,15 @@ func (mage *Mage) registerBlizzardSpell() {
 	blizzardCoefficient := 0.367
 	blizzardScaling := 0.323
 	blizzardVariance := 0.0
+	var groundPosition core.Vector2
+
 	blizzardTickSpell := mage.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 42208},
 		SpellSchool:    core.SpellSchoolFrost,
 		ProcMask:       core.ProcMaskSpellDamage,
 		ClassSpellMask: MageSpellBlizzard,
 		Flags:          core.SpellFlagAoE,
+		AoeRadius:      8,
 
 		DamageMultiplier: 1,
 		CritMultiplier:   mage.DefaultCritMultiplier(),
@@ -27,7 +30,7 @@ func (mage *Mage) registerBlizzardSpell() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDamage := mage.CalcAndRollDamageRange(sim, blizzardScaling, blizzardVariance)
 			anyLanded := false
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadiusOfPosition(groundPosition) {
 				result := spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 				if result.Landed() {
 					anyLanded = true
@@ -69,6 +72,7 @@ func (mage *Mage) registerBlizzardSpell() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			groundPosition = target.Position
 			spell.AOEDot().Apply(sim)
 		},
 	})
@@ -16,7 +16,6 @@ func (fire *FireMage) registerInfernoBlastSpell() {
 
 	hasGlyph := fire.HasMajorGlyph(proto.MageMajorGlyph_GlyphOfInfernoBlast)
 	extraTargets := core.Ternary(hasGlyph, 4, 3)
-	numTargets := len(fire.Env.Encounter.TargetUnits) - 1
 
 	fire.InfernoBlast = fire.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 108853},
@@ -60,7 +59,7 @@ func (fire *FireMage) registerInfernoBlastSpell() {
 				}
 			}
 
-			for range min(extraTargets, numTargets) {
+			for range min(extraTargets, int(sim.GetNumTargets())-1) {
 				aoeTarget := fire.Env.NextTargetUnit(target)
 				for _, spellRef := range dotRefs {
 					dot := (*spellRef).Dot(aoeTarget)
@@ -14,12 +14,15 @@ func (mage *Mage) registerFlamestrikeSpell() {
 	flameStrikeDotScaling := .12
 	flameStrikeDotCoefficient := .14
 
+	var groundPosition core.Vector2
+
 	mage.Flamestrike = mage.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 2120},
 		SpellSchool:    core.SpellSchoolFire,
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL,
 		ClassSpellMask: MageSpellFlamestrike,
+		AoeRadius:      8,
 
 		ManaCost: core.ManaCostOptions{
 			BaseCostPercent: 6,
@@ -41,7 +44,8 @@ func (mage *Mage) registerFlamestrikeSpell() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			groundPosition = target.Position
+			for _, aoeTarget := range spell.TargetsInRadiusOfPosition(groundPosition) {
 				baseDamage := mage.CalcAndRollDamageRange(sim, flameStrikeScaling, flameStrikeVariance)
 				spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
@@ -56,6 +60,7 @@ func (mage *Mage) registerFlamestrikeSpell() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		ClassSpellMask: MageSpellFlamestrikeDot,
 		Flags:          core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		AoeRadius:      8,
 
 		DamageMultiplier: 1,
 		CritMultiplier:   mage.DefaultCritMultiplier(),
@@ -73,7 +78,7 @@ func (mage *Mage) registerFlamestrikeSpell() {
 				dot.Snapshot(target, mage.CalcScalingSpellDmg(flameStrikeDotScaling))
 			},
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range dot.Spell.TargetsInRadiusOfPosition(groundPosition) {
 					dot.CalcAndDealPeriodicSnapshotDamage(sim, aoeTarget, dot.OutcomeSnapshotCrit)
 				}
 			},
@@ -24,6 +24,7 @@ func (mage *Mage) registerFrostBomb() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		ClassSpellMask: MageSpellFrostBombExplosion,
 		Flags:          core.SpellFlagAoE,
+		AoeRadius:      10,
 
 		DamageMultiplier: 1,
 		CritMultiplier:   mage.DefaultCritMultiplier(),
@@ -31,13 +32,13 @@ func (mage *Mage) registerFrostBomb() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
-				if idx == 0 {
+			for _, aoeTarget := range spell.TargetsInRadius(target) {
+				if aoeTarget == target {
 					spell.DamageMultiplier *= 2
 				}
 				baseDamage := mage.CalcAndRollDamageRange(sim, frostBombExplosionScaling, frostBombVariance)
 				spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
-				if idx == 0 {
+				if aoeTarget == target {
 					spell.DamageMultiplier /= 2
 				}
 			}
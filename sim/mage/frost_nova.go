@@ -18,6 +18,7 @@ func (mage *Mage) registerfrostNovaSpell() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagAPL | core.SpellFlagAoE,
 		ClassSpellMask: MageSpellFrostNova,
+		AoeRadius:      12,
 
 		ManaCost: core.ManaCostOptions{
 			BaseCostPercent: 2,
@@ -39,7 +40,7 @@ func (mage *Mage) registerfrostNovaSpell() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&mage.Unit) {
 				baseDamage := mage.CalcAndRollDamageRange(sim, frostNovaScaling, frostNovaVariance)
 				spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
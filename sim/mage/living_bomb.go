@@ -41,6 +41,7 @@ func (mage *Mage) registerLivingBomb() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		ClassSpellMask: MageSpellLivingBombExplosion,
 		Flags:          core.SpellFlagAoE | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		AoeRadius:      10,
 
 		DamageMultiplier: 1,
 		CritMultiplier:   mage.DefaultCritMultiplier(),
@@ -51,7 +52,7 @@ func (mage *Mage) registerLivingBomb() {
 			baseDamage := mage.CalcAndRollDamageRange(sim, livingBombExplosionScaling, 0)
 			ticks := max(4, float64(mage.LivingBomb.RelatedDotSpell.Dot(target).Duration)/float64(mage.LivingBomb.RelatedDotSpell.Dot(target).TickPeriod()))
 			spell.DamageMultiplier *= ticks
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(target) {
 				spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
 			spell.DamageMultiplier /= ticks
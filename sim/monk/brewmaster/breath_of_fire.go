@@ -59,7 +59,7 @@ func (bm *BrewmasterMonk) registerBreathOfFire() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for _, enemyTarget := range sim.Encounter.TargetUnits {
+			for _, enemyTarget := range sim.Encounter.ActiveTargetUnits {
 				baseDamage := bm.CalcAndRollDamageRange(sim, 1.475, 0.242) + 0.3626*spell.MeleeAttackPower()
 				result := spell.CalcOutcome(sim, enemyTarget, spell.OutcomeMeleeSpecialNoBlockDodgeParryNoCritNoHitCounter)
 
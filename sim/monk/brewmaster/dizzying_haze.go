@@ -25,6 +25,7 @@ func (bm *BrewmasterMonk) registerDizzyingHaze() {
 		ProcMask:       core.ProcMaskMeleeMHSpecial,
 		ClassSpellMask: monk.MonkSpellDizzyingHazeProjectile,
 		MaxRange:       8,
+		AoeRadius:      8,
 
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
@@ -35,7 +36,7 @@ func (bm *BrewmasterMonk) registerDizzyingHaze() {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			spell.ApplyAOEThreat(spell.MeleeAttackPower() * 1.1)
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(target) {
 				result := spell.CalcOutcome(sim, target, spell.OutcomeMeleeSpecialNoBlockDodgeParryNoCrit)
 				if result.Landed() {
 					bm.DizzyingHazeAuras.Get(aoeTarget).Activate(sim)
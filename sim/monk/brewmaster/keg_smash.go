@@ -19,6 +19,7 @@ func (bm *BrewmasterMonk) registerKegSmash() {
 		Flags:          core.SpellFlagAoE | core.SpellFlagMeleeMetrics | monk.SpellFlagBuilder | core.SpellFlagAPL,
 		ClassSpellMask: monk.MonkSpellKegSmash,
 		MaxRange:       core.MaxMeleeRange,
+		AoeRadius:      8,
 		MissileSpeed:   30,
 
 		EnergyCost: core.EnergyCostOptions{
@@ -47,7 +48,9 @@ func (bm *BrewmasterMonk) registerKegSmash() {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			missedTargets := 0
-			for i, enemyTarget := range sim.Encounter.TargetUnits {
+			aoeTargets := spell.TargetsInRadius(target)
+			numTargets := len(aoeTargets)
+			for i, enemyTarget := range aoeTargets {
 				baseDamage := bm.CalculateMonkStrikeDamage(sim, spell)
 				result := spell.CalcDamage(sim, enemyTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 				results[i] = result
@@ -56,13 +59,13 @@ func (bm *BrewmasterMonk) registerKegSmash() {
 				}
 			}
 			spell.WaitTravelTime(sim, func(s *core.Simulation) {
-				for _, result := range results {
+				for _, result := range results[:numTargets] {
 					spell.DealOutcome(sim, result)
 					if result.Landed() {
 						bm.DizzyingHazeAuras.Get(result.Target).Activate(sim)
 					}
 				}
-				if missedTargets > 0 && missedTargets == len(sim.Encounter.TargetUnits) {
+				if missedTargets > 0 && missedTargets == numTargets {
 					spell.IssueRefund(sim)
 				} else {
 					bm.AddChi(sim, spell, 2, chiMetrics)
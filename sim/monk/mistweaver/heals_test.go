@@ -0,0 +1,108 @@
+package mistweaver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func init() {
+	RegisterMistweaverMonk()
+}
+
+// A mistweaver healing four target dummies which all take damage every second.
+func newHealingSim() *core.Simulation {
+	raid := core.SinglePlayerRaidProto(&proto.Player{
+		Race:      proto.Race_RaceHordePandaren,
+		Class:     proto.Class_ClassMonk,
+		Equipment: &proto.EquipmentSpec{},
+		Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		Spec: &proto.Player_MistweaverMonk{
+			MistweaverMonk: &proto.MistweaverMonk{
+				Options: &proto.MistweaverMonk_Options{ClassOptions: &proto.MonkOptions{}},
+			},
+		},
+	}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{})
+	raid.TargetDummies = 4
+	raid.IncomingDamage = &proto.IncomingDamageModel{Dtps: 5000, TankDtps: 10000, DamageVariation: 0.5}
+
+	sim := core.NewSim(&proto.RaidSimRequest{
+		Raid: raid,
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{core.NewDefaultTarget()},
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+
+	sim.Step()
+	for sim.CurrentTime < time.Second*5 {
+		sim.Step()
+	}
+	return sim
+}
+
+func getMistweaver(sim *core.Simulation) *MistweaverMonk {
+	return sim.Raid.Parties[0].Players[0].(*MistweaverMonk)
+}
+
+func TestUpliftHealsRenewingMistTargets(t *testing.T) {
+	sim := newHealingSim()
+	mw := getMistweaver(sim)
+	dummies := sim.Raid.GetTargetDummies()
+	surgingMist := mw.GetSpell(core.ActionID{SpellID: 116694})
+	uplift := mw.GetSpell(core.ActionID{SpellID: 116670})
+
+	if uplift.CanCast(sim, &dummies[0].Unit) {
+		t.Fatalf("Expected Uplift to need chi and a target with Renewing Mist")
+	}
+
+	// Both heals generate the chi Uplift spends.
+	mw.RenewingMist.SkipCastAndApplyEffects(sim, &dummies[0].Unit)
+	surgingMist.SkipCastAndApplyEffects(sim, &dummies[1].Unit)
+	if mw.GetChi() != 2 {
+		t.Fatalf("Expected Renewing Mist and Surging Mist to generate 1 chi each, got %d", mw.GetChi())
+	}
+	if surgingMist.SpellMetrics[dummies[1].UnitIndex].TotalHealing <= 0 {
+		t.Fatalf("Expected Surging Mist to heal its target")
+	}
+
+	uplift.SkipCastAndApplyEffects(sim, &dummies[1].Unit)
+	if uplift.SpellMetrics[dummies[0].UnitIndex].Hits != 1 {
+		t.Fatalf("Expected Uplift to heal the target with Renewing Mist")
+	}
+	for _, dummy := range dummies[1:] {
+		if uplift.SpellMetrics[dummy.UnitIndex].Hits != 0 {
+			t.Fatalf("Expected Uplift to skip %s, which has no Renewing Mist", dummy.Label)
+		}
+	}
+	if mw.GetChi() != 0 {
+		t.Fatalf("Expected Uplift to spend 2 chi, %d left", mw.GetChi())
+	}
+}
+
+func TestRenewingMistHot(t *testing.T) {
+	sim := newHealingSim()
+	mw := getMistweaver(sim)
+	tank := &sim.Raid.GetTargetDummies()[0].Unit
+
+	mw.RenewingMist.SkipCastAndApplyEffects(sim, tank)
+	endTime := sim.CurrentTime + time.Second*5
+	for sim.CurrentTime < endTime {
+		sim.Step()
+	}
+	if mw.RenewingMist.SpellMetrics[tank.UnitIndex].TotalHealing <= 0 {
+		t.Fatalf("Expected the Renewing Mist heal over time to tick")
+	}
+
+	// Heals cast at an enemy land on the caster.
+	mw.RenewingMist.SkipCastAndApplyEffects(sim, sim.GetTargetUnit(0))
+	if !mw.RenewingMist.Hot(&mw.Unit).IsActive() {
+		t.Fatalf("Expected Renewing Mist cast at the boss to heal the monk")
+	}
+}
@@ -50,6 +50,8 @@ func NewMistweaverMonk(character *core.Character, options *proto.Player) *Mistwe
 
 type MistweaverMonk struct {
 	*monk.Monk
+
+	RenewingMist *core.Spell
 }
 
 func (mw *MistweaverMonk) GetMonk() *monk.Monk {
@@ -60,6 +62,17 @@ func (mw *MistweaverMonk) Initialize() {
 	mw.Monk.Initialize()
 
 	mw.RegisterSpecializationEffects()
+	mw.registerSurgingMist()
+	mw.registerRenewingMist()
+	mw.registerUplift()
+}
+
+// Heals cast at an enemy land on the monk instead, like in game.
+func (mw *MistweaverMonk) healTargetOrSelf(target *core.Unit) *core.Unit {
+	if target.IsOpponent(&mw.Unit) {
+		return &mw.Unit
+	}
+	return target
 }
 
 func (mw *MistweaverMonk) ApplyTalents() {
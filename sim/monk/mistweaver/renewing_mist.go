@@ -0,0 +1,63 @@
+package mistweaver
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/monk"
+)
+
+// Heals the target over 18 sec. Uplift heals every target with Renewing Mist.
+func (mw *MistweaverMonk) registerRenewingMist() {
+	actionID := core.ActionID{SpellID: 115151}
+	chiMetrics := mw.NewChiMetrics(actionID)
+
+	mw.RenewingMist = mw.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | monk.SpellFlagBuilder | core.SpellFlagAPL,
+		ClassSpellMask: monk.MonkSpellRenewingMist,
+		MaxRange:       40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 5.85,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    mw.NewTimer(),
+				Duration: time.Second * 8,
+			},
+		},
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   mw.DefaultCritMultiplier(),
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Renewing Mist",
+			},
+			NumberOfTicks:       9,
+			TickLength:          time.Second * 2,
+			AffectedByCastSpeed: true,
+
+			BonusCoefficient: 0.107,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, mw.CalcScalingSpellDmg(1.056))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.Hot(mw.healTargetOrSelf(target)).Apply(sim)
+			mw.AddChi(sim, spell, 1, chiMetrics)
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package mistweaver
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/monk"
+)
+
+func (mw *MistweaverMonk) registerSurgingMist() {
+	actionID := core.ActionID{SpellID: 116694}
+	chiMetrics := mw.NewChiMetrics(actionID)
+
+	mw.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | monk.SpellFlagBuilder | core.SpellFlagAPL,
+		ClassSpellMask: monk.MonkSpellSurgingMist,
+		MaxRange:       40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 8.8,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   mw.DefaultCritMultiplier(),
+		BonusCoefficient: 1.8,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := mw.CalcAndRollDamageRange(sim, 17.4, 0.1)
+			spell.CalcAndDealHealing(sim, mw.healTargetOrSelf(target), baseHealing, spell.OutcomeHealingCrit)
+			mw.AddChi(sim, spell, 1, chiMetrics)
+		},
+	})
+}
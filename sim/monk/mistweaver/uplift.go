@@ -0,0 +1,55 @@
+package mistweaver
+
+import (
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/monk"
+)
+
+// Heals every player with Renewing Mist active.
+func (mw *MistweaverMonk) registerUplift() {
+	actionID := core.ActionID{SpellID: 116670}
+	chiMetrics := mw.NewChiMetrics(actionID)
+	chiCost := int32(2)
+
+	mw.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | monk.SpellFlagSpender | core.SpellFlagAPL,
+		ClassSpellMask: monk.MonkSpellUplift,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   mw.DefaultCritMultiplier(),
+		BonusCoefficient: 0.68,
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return mw.GetChi() >= chiCost && mw.hasRenewingMistTarget()
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, ally := range mw.Env.Raid.AllPlayerUnits {
+				if mw.RenewingMist.Hot(ally).IsActive() {
+					baseHealing := mw.CalcAndRollDamageRange(sim, 6.708, 0.1)
+					spell.CalcAndDealHealing(sim, ally, baseHealing, spell.OutcomeHealingCrit)
+				}
+			}
+			mw.SpendChi(sim, chiCost, chiMetrics)
+		},
+	})
+}
+
+func (mw *MistweaverMonk) hasRenewingMistTarget() bool {
+	for _, ally := range mw.Env.Raid.AllPlayerUnits {
+		if mw.RenewingMist.Hot(ally).IsActive() {
+			return true
+		}
+	}
+	return false
+}
@@ -342,6 +342,11 @@ const (
 	MonkSpellPurifyingBrew
 	MonkSpellGiftOfTheOx
 
+	// Mistweaver
+	MonkSpellSurgingMist
+	MonkSpellRenewingMist
+	MonkSpellUplift
+
 	MonkSpellLast
 	MonkSpellsAll = MonkSpellLast<<1 - 1
 )
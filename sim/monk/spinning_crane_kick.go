@@ -37,12 +37,13 @@ func spinningCraneKickTickSpellConfig(monk *Monk, isSEFClone bool) core.SpellCon
 		ClassSpellMask: MonkSpellSpinningCraneKick,
 		Flags:          core.SpellFlagAoE | core.SpellFlagMeleeMetrics | core.SpellFlagPassiveSpell,
 		MaxRange:       8,
+		AoeRadius:      8,
 
 		DamageMultiplier: 1.75, // 1.59 * (1.75 / 1.59),
 		ThreatMultiplier: 1,
 		CritMultiplier:   monk.DefaultCritMultiplier(),
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for _, target := range sim.Encounter.TargetUnits {
+			for _, target := range spell.TargetsInRadius(spell.Unit) {
 				baseDamage := monk.CalculateMonkStrikeDamage(sim, spell)
 				spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 			}
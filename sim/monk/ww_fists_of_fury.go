@@ -19,8 +19,7 @@ var fofActionID = core.ActionID{SpellID: 113656}
 var fofDebuffActionID = core.ActionID{SpellID: 117418}
 
 func fistsOfFuryTickSpellConfig(monk *Monk, pet *StormEarthAndFirePet) core.SpellConfig {
-	numTargets := monk.Env.GetNumTargets()
-	results := make([]*core.SpellResult, numTargets)
+	results := make([]*core.SpellResult, monk.Env.GetNumTargets())
 
 	config := core.SpellConfig{
 		ActionID:       fofDebuffActionID,
@@ -37,14 +36,14 @@ func fistsOfFuryTickSpellConfig(monk *Monk, pet *StormEarthAndFirePet) core.Spel
 			baseDamage := monk.CalculateMonkStrikeDamage(sim, spell)
 
 			// Damage is split between all mobs, each hit rolls for hit/crit separately
-			baseDamage /= float64(numTargets)
+			baseDamage /= float64(sim.GetNumTargets())
 
-			for i, enemyTarget := range sim.Encounter.TargetUnits {
+			for i, enemyTarget := range sim.Encounter.ActiveTargetUnits {
 				result := spell.CalcDamage(sim, enemyTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 				results[i] = result
 			}
 
-			for _, result := range results {
+			for _, result := range results[:sim.GetNumTargets()] {
 				spell.DealDamage(sim, result)
 			}
 		},
@@ -90,7 +90,7 @@ func (monk *Monk) registerRisingSunKick() {
 
 			if result.Landed() {
 				monk.SpendChi(sim, 2, chiMetrics)
-				for _, target := range sim.Encounter.TargetUnits {
+				for _, target := range sim.Encounter.ActiveTargetUnits {
 					risingSunKickDebuff.Get(target).Activate(sim)
 				}
 			}
@@ -123,7 +123,7 @@ func (pet *StormEarthAndFirePet) registerSEFRisingSunKick() {
 			result := spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 
 			if result.Landed() {
-				for _, target := range sim.Encounter.TargetUnits {
+				for _, target := range sim.Encounter.ActiveTargetUnits {
 					risingSunKickDebuff.Get(target).Activate(sim)
 				}
 			}
@@ -74,7 +74,7 @@ func (monk *Monk) NewXuen() *Xuen {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDamage := monk.CalcScalingSpellDmg(0.293) + xuen.GetStat(stats.AttackPower)*0.505
-			for index, target := range sim.Encounter.TargetUnits {
+			for index, target := range sim.Encounter.ActiveTargetUnits {
 				if index > 3 {
 					break
 				}
@@ -172,8 +172,6 @@ func (paladin *Paladin) registerRetributionGuardian(duration time.Duration) {
 		},
 	})
 
-	numTargets := paladin.Env.GetNumTargets()
-
 	ancientFury := paladin.RegisterSpell(core.SpellConfig{
 		ActionID:    core.ActionID{SpellID: 86704},
 		SpellSchool: core.SpellSchoolHoly,
@@ -193,16 +191,15 @@ func (paladin *Paladin) registerRetributionGuardian(duration time.Duration) {
 			// Deals X Holy damage per application of Ancient Power,
 			// divided evenly among all targets within 10 yards.
 			baseDamage *= float64(paladin.AncientPowerAura.GetStacks())
-			baseDamage /= float64(numTargets)
+			baseDamage /= float64(sim.GetNumTargets())
 
-			results := make([]*core.SpellResult, numTargets)
-			for idx := range numTargets {
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+			results := make([]*core.SpellResult, sim.GetNumTargets())
+			for idx, currentTarget := range sim.Encounter.ActiveTargetUnits {
 				results[idx] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
 
-			for idx := range numTargets {
-				spell.DealDamage(sim, results[idx])
+			for _, result := range results {
+				spell.DealDamage(sim, result)
 			}
 		},
 	})
@@ -15,7 +15,6 @@ Weakened Blows
 Demoralizes the target, reducing their physical damage dealt by 10% for 30 sec.
 */
 func (paladin *Paladin) registerHammerOfTheRighteous() {
-	numTargets := paladin.Env.GetNumTargets()
 	actionID := core.ActionID{SpellID: 53595}
 	paladin.CanTriggerHolyAvengerHpGain(actionID)
 	auraArray := paladin.NewEnemyAuraArray(core.WeakenedBlowsAura)
@@ -28,25 +27,26 @@ func (paladin *Paladin) registerHammerOfTheRighteous() {
 		Flags:          core.SpellFlagMeleeMetrics | core.SpellFlagPassiveSpell | core.SpellFlagAoE,
 		ClassSpellMask: SpellMaskHammerOfTheRighteousAoe,
 
-		MaxRange: 8,
+		MaxRange:  8,
+		AoeRadius: 8,
 
 		DamageMultiplier: 0.35,
 		CritMultiplier:   paladin.DefaultCritMultiplier(),
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			results := make([]*core.SpellResult, numTargets)
+			aoeTargets := spell.TargetsInRadius(target)
+			results := make([]*core.SpellResult, len(aoeTargets))
 
-			for idx := range numTargets {
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+			for idx, currentTarget := range aoeTargets {
 				baseDamage := paladin.MHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
 				results[idx] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMagicCrit)
 			}
 
 			spell.WaitTravelTime(sim, func(sim *core.Simulation) {
-				for idx := range numTargets {
-					spell.DealDamage(sim, results[idx])
-					aura := auraArray.Get(results[idx].Target)
+				for _, result := range results {
+					spell.DealDamage(sim, result)
+					aura := auraArray.Get(result.Target)
 					if hasGlyphOfHammerOfTheRighteous && aura.Duration != core.NeverExpires {
 						aura.Duration = core.DurationFromSeconds(core.WeakenedBlowsDuration.Seconds() * 1.5)
 					}
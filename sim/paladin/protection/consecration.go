@@ -9,7 +9,8 @@ import (
 
 // Consecrates the land beneath you, causing 8222 Holy damage over 9 sec to enemies who enter the area.
 func (prot *ProtectionPaladin) registerConsecrationSpell() {
-	numTargets := prot.Env.GetNumTargets()
+	var groundPosition core.Vector2
+
 	prot.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 26573},
 		SpellSchool:    core.SpellSchoolHoly,
@@ -17,7 +18,8 @@ func (prot *ProtectionPaladin) registerConsecrationSpell() {
 		Flags:          core.SpellFlagAPL | core.SpellFlagAoE,
 		ClassSpellMask: paladin.SpellMaskConsecration,
 
-		MaxRange: 8,
+		MaxRange:  8,
+		AoeRadius: 8,
 
 		ManaCost: core.ManaCostOptions{
 			BaseCostPercent: 7,
@@ -46,23 +48,25 @@ func (prot *ProtectionPaladin) registerConsecrationSpell() {
 			TickLength:    time.Second * 1,
 
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				results := make([]*core.SpellResult, numTargets)
+				aoeTargets := dot.Spell.TargetsInRadiusOfPosition(groundPosition)
+				results := make([]*core.SpellResult, len(aoeTargets))
 
 				// Consecration recalculates everything on each tick
 				baseDamage := prot.CalcScalingSpellDmg(0.80000001192) + 0.07999999821*dot.Spell.MeleeAttackPower()
 
-				for idx := range numTargets {
-					currentTarget := sim.Environment.GetTargetUnit(idx)
+				for idx, currentTarget := range aoeTargets {
 					results[idx] = dot.Spell.CalcPeriodicDamage(sim, currentTarget, baseDamage, dot.Spell.OutcomeMagicHitAndCrit)
 				}
 
-				for idx := range numTargets {
-					dot.Spell.DealPeriodicDamage(sim, results[idx])
+				for _, result := range results {
+					dot.Spell.DealPeriodicDamage(sim, result)
 				}
 			},
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			// Consecration is placed beneath the paladin.
+			groundPosition = prot.Position
 			spell.AOEDot().Apply(sim)
 		},
 	})
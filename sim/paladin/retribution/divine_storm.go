@@ -13,7 +13,6 @@ Using Divine Storm will also heal you for 5% of your maximum health.
 -- /Glyph of Divine Storm --
 */
 func (ret *RetributionPaladin) registerDivineStorm() {
-	numTargets := ret.Env.GetNumTargets()
 	actionID := core.ActionID{SpellID: 53385}
 
 	ret.RegisterSpell(core.SpellConfig{
@@ -23,7 +22,8 @@ func (ret *RetributionPaladin) registerDivineStorm() {
 		Flags:          core.SpellFlagMeleeMetrics | core.SpellFlagAPL | core.SpellFlagAoE,
 		ClassSpellMask: paladin.SpellMaskDivineStorm,
 
-		MaxRange: 8,
+		MaxRange:  8,
+		AoeRadius: 8,
 
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
@@ -40,10 +40,10 @@ func (ret *RetributionPaladin) registerDivineStorm() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			results := make([]*core.SpellResult, numTargets)
+			aoeTargets := spell.TargetsInRadius(&ret.Unit)
+			results := make([]*core.SpellResult, len(aoeTargets))
 
-			for idx := range numTargets {
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+			for idx, currentTarget := range aoeTargets {
 				baseDamage := ret.MHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
 				results[idx] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 			}
@@ -52,8 +52,8 @@ func (ret *RetributionPaladin) registerDivineStorm() {
 				ret.HolyPower.Spend(sim, 3, actionID)
 			}
 
-			for idx := range numTargets {
-				spell.DealDamage(sim, results[idx])
+			for _, result := range results {
+				spell.DealDamage(sim, result)
 			}
 		},
 	})
@@ -68,7 +68,7 @@ func (discPriest *DisciplinePriest) GetMainTarget() *core.Unit {
 func (discPriest *DisciplinePriest) Initialize() {
 	discPriest.CurrentTarget = discPriest.GetMainTarget()
 	discPriest.Priest.Initialize()
-	// discPriest.Priest.RegisterHealingSpells()
+	discPriest.Priest.RegisterHealingSpells()
 
 	// // discPriest.ApplyRapture(discPriest.Options.RapturesPerMinute)
 	// discPriest.RegisterHymnOfHopeCD()
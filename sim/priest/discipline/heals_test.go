@@ -0,0 +1,116 @@
+package discipline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+func newDiscPlayer() *proto.Player {
+	return &proto.Player{
+		Race:      proto.Race_RaceUndead,
+		Class:     proto.Class_ClassPriest,
+		Equipment: &proto.EquipmentSpec{},
+		Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		Spec: &proto.Player_DisciplinePriest{
+			DisciplinePriest: &proto.DisciplinePriest{
+				Options: &proto.DisciplinePriest_Options{ClassOptions: &proto.PriestOptions{}},
+			},
+		},
+	}
+}
+
+// Two discipline priests healing two target dummies, the first of which takes
+// tank damage every second.
+func newHealingSim(tankDtps float64) *core.Simulation {
+	raid := core.SinglePlayerRaidProto(newDiscPlayer(), &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{})
+	raid.Parties[0].Players = append(raid.Parties[0].Players, newDiscPlayer())
+	raid.TargetDummies = 2
+	raid.IncomingDamage = &proto.IncomingDamageModel{TankDtps: tankDtps}
+
+	sim := core.NewSim(&proto.RaidSimRequest{
+		Raid: raid,
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{core.NewDefaultTarget()},
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+	return sim
+}
+
+func getPriest(sim *core.Simulation, index int) *priest.Priest {
+	return sim.Raid.Parties[0].Players[index].(*DisciplinePriest).Priest
+}
+
+func TestHealTargeting(t *testing.T) {
+	sim := newHealingSim(10000)
+	disc := getPriest(sim, 0)
+	dummies := sim.Raid.GetTargetDummies()
+
+	if disc.CurrentTarget != &dummies[0].Unit {
+		t.Fatalf("Expected the priest to target the first target dummy")
+	}
+
+	sim.Step()
+	for sim.CurrentTime < time.Second*3 {
+		sim.Step()
+	}
+	if dummies[0].CurrentHealthPercent() >= 1 || dummies[1].CurrentHealthPercent() != 1 {
+		t.Fatalf("Expected only the tank dummy to take damage")
+	}
+	if smartTargets := sim.Raid.GetSmartHealTargets(1); smartTargets[0] != &dummies[0].Unit {
+		t.Fatalf("Expected smart heals to pick the most injured dummy")
+	}
+
+	// Heals cast at an enemy land on the caster.
+	disc.FlashHeal.SkipCastAndApplyEffects(sim, sim.GetTargetUnit(0))
+	if metrics := disc.FlashHeal.SpellMetrics[disc.UnitIndex]; metrics.Hits != 1 {
+		t.Fatalf("Expected Flash Heal cast at the boss to heal the priest")
+	}
+}
+
+func TestPowerWordShieldAbsorbs(t *testing.T) {
+	sim := newHealingSim(0)
+	disc := getPriest(sim, 0)
+	otherDisc := getPriest(sim, 1)
+	tank := &sim.Raid.GetTargetDummies()[0].Unit
+
+	disc.PowerWordShield.SkipCastAndApplyEffects(sim, tank)
+	shielding := disc.PowerWordShield.SpellMetrics[tank.UnitIndex].TotalShielding
+	if shielding <= 0 {
+		t.Fatalf("Expected Power Word: Shield to record shielding")
+	}
+	if otherDisc.PowerWordShield.CanCast(sim, tank) {
+		t.Fatalf("Expected Weakened Soul to stop another priest from shielding the same target")
+	}
+
+	incomingDamage := sim.Encounter.TargetUnits[0].GetSpell(core.ActionID{OtherID: proto.OtherAction_OtherActionIncomingDamage})
+	incomingDamage.CalcAndDealDamage(sim, tank, shielding/2, incomingDamage.OutcomeAlwaysHit)
+	if tank.CurrentHealthPercent() != 1 {
+		t.Fatalf("Expected the shield to absorb damage below its strength")
+	}
+
+	incomingDamage.CalcAndDealDamage(sim, tank, shielding*10, incomingDamage.OutcomeAlwaysHit)
+	if tank.CurrentHealthPercent() == 1 || tank.GetAura("Power Word: Shield"+disc.Label).IsActive() {
+		t.Fatalf("Expected damage past the shield strength to break the shield")
+	}
+}
+
+func TestIncomingPeriodicDamage(t *testing.T) {
+	sim := newHealingSim(0)
+	tank := &sim.Raid.GetTargetDummies()[0].Unit
+
+	incomingDamage := sim.Encounter.TargetUnits[0].GetSpell(core.ActionID{OtherID: proto.OtherAction_OtherActionIncomingDamage})
+	result := incomingDamage.CalcDamage(sim, tank, 1000, incomingDamage.OutcomeAlwaysHit)
+	incomingDamage.DealPeriodicDamage(sim, result)
+	if missing := tank.MaxHealth() - tank.CurrentHealth(); missing <= 0 || !core.WithinToleranceFloat64(result.Damage, missing, 0.01) {
+		t.Fatalf("Expected periodic damage to reduce dummy health by %0.2f, got %0.2f", result.Damage, missing)
+	}
+}
@@ -0,0 +1,44 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+// Heals the 5 most injured friendly targets.
+func (holyPriest *HolyPriest) registerCircleOfHealingSpell() {
+	holyPriest.CircleOfHealing = holyPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 34861},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellCircleOfHealing,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 32,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holyPriest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holyPriest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.467,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, target := range holyPriest.Env.Raid.GetSmartHealTargets(5) {
+				baseHealing := holyPriest.CalcAndRollDamageRange(sim, 4.631, 0.1)
+				spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			}
+		},
+	})
+}
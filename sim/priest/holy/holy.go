@@ -49,6 +49,8 @@ func (holyPriest *HolyPriest) GetPriest() *priest.Priest {
 
 func (holyPriest *HolyPriest) Initialize() {
 	holyPriest.Priest.Initialize()
+	holyPriest.Priest.RegisterHealingSpells()
+	holyPriest.registerCircleOfHealingSpell()
 
 	// holyPriest.RegisterHolyFireSpell()
 	// holyPriest.RegisterSmiteSpell()
@@ -27,9 +27,10 @@ func (priest *Priest) getMindSearTickSpell() *core.Spell {
 	config := priest.getMindSearBaseConfig()
 	config.Flags = core.SpellFlagNoOnDamageDealt | core.SpellFlagAoE
 	config.ActionID = core.ActionID{SpellID: 48045}
+	config.AoeRadius = 10
 	config.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 		damage := priest.CalcAndRollDamageRange(sim, SearScale, SearVariance)
-		for _, aoeTarget := range sim.Encounter.TargetUnits {
+		for _, aoeTarget := range spell.TargetsInRadius(target) {
 
 			// Calc spell damage but deal as periodic for metric purposes
 			result := spell.CalcDamage(sim, aoeTarget, damage, spell.OutcomeMagicHitAndCritNoHitCounter)
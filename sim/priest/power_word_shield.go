@@ -0,0 +1,74 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+/*
+Draws on the soul of the friendly target to shield them, absorbing damage for 15 sec.
+You cannot shield the target again for 15 sec while they are afflicted by Weakened Soul.
+*/
+func (priest *Priest) registerPowerWordShieldSpell() {
+	var shieldAmount float64
+
+	absorbAuras := priest.NewAllyDamageAbsorptionAuraArray(func(unit *core.Unit) *core.DamageAbsorptionAura {
+		return unit.NewDamageAbsorptionAura(core.AbsorptionAuraConfig{
+			Aura: core.Aura{
+				Label:    "Power Word: Shield" + priest.Label,
+				ActionID: core.ActionID{SpellID: 17},
+				Duration: time.Second * 15,
+			},
+			ShieldStrengthCalculator: func(_ *core.Unit) float64 {
+				return shieldAmount
+			},
+		})
+	})
+
+	// Weakened Soul is shared by all priests, so a target can only hold one shield at a time.
+	priest.WeakenedSouls = priest.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		return unit.GetOrRegisterAura(core.Aura{
+			Label:    "Weakened Soul",
+			ActionID: core.ActionID{SpellID: 6788},
+			Duration: time.Second * 15,
+		})
+	})
+
+	priest.PowerWordShield = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 17},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPowerWordShield,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 24.5,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return !priest.WeakenedSouls.Get(priest.healTargetOrSelf(target)).IsActive()
+		},
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = priest.healTargetOrSelf(target)
+
+			// Shields are not affected by healing pseudostats the same way heals are.
+			shieldAmount = (priest.CalcScalingSpellDmg(18.515) + 1.871*spell.HealingPower(target)) * spell.DamageMultiplier
+			absorbAuras.Get(target).Activate(sim)
+			spell.SpellMetrics[target.UnitIndex].TotalShielding += shieldAmount
+			spell.SpellMetrics[target.UnitIndex].Hits++
+
+			priest.WeakenedSouls.Get(target).Activate(sim)
+		},
+	})
+}
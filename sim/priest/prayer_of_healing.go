@@ -0,0 +1,52 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+// Heals the target and the rest of their party.
+func (priest *Priest) registerPrayerOfHealingSpell() {
+	priest.PrayerOfHealing = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 596},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPrayerOfHealing,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 26.3,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.838,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = priest.healTargetOrSelf(target)
+			agent := priest.Env.Raid.GetPlayerFromUnit(target)
+			if agent == nil {
+				return
+			}
+
+			for _, partyMember := range agent.GetCharacter().Party.PlayersAndPets {
+				unit := &partyMember.GetCharacter().Unit
+				if !unit.HasHealthBar() || !unit.IsActive() {
+					continue
+				}
+				baseHealing := priest.CalcAndRollDamageRange(sim, 8.296, 0.055)
+				spell.CalcAndDealHealing(sim, unit, baseHealing, spell.OutcomeHealingCrit)
+			}
+		},
+	})
+}
@@ -35,6 +35,7 @@ type Priest struct {
 	CircleOfHealing   *core.Spell
 	FlashHeal         *core.Spell
 	GreaterHeal       *core.Spell
+	Heal              *core.Spell
 	Penance           *core.Spell
 	PenanceHeal       *core.Spell
 	PowerWordShield   *core.Spell
@@ -93,6 +94,24 @@ func (priest *Priest) Initialize() {
 	priest.ApplyGlyphs()
 }
 
+// Registers the heals shared by the healing specs.
+func (priest *Priest) RegisterHealingSpells() {
+	priest.registerFlashHealSpell()
+	priest.registerHealSpell()
+	priest.registerGreaterHealSpell()
+	priest.registerRenewSpell()
+	priest.registerPowerWordShieldSpell()
+	priest.registerPrayerOfHealingSpell()
+}
+
+// Heals cast at an enemy land on the priest instead, like in game.
+func (priest *Priest) healTargetOrSelf(target *core.Unit) *core.Unit {
+	if target.IsOpponent(&priest.Unit) {
+		return &priest.Unit
+	}
+	return target
+}
+
 func (priest *Priest) AddHolyEvanglismStack(sim *core.Simulation) {
 	if priest.HolyEvangelismProcAura != nil {
 		priest.HolyEvangelismProcAura.Activate(sim)
@@ -164,6 +183,7 @@ const (
 	PriestSpellGreaterHeal
 	PriestSpellGuardianSpirit
 	PriestSpellHalo
+	PriestSpellHeal
 	PriestSpellHolyFire
 	PriestSpellHolyNova
 	PriestSpellHolyWordChastise
@@ -45,7 +45,7 @@ func (shadow *ShadowPriest) registerDivineStar() {
 			core.StartDelayedAction(sim, core.DelayedActionOptions{
 				DoAt: sim.CurrentTime + time.Second*time.Duration(hit1),
 				OnAction: func(s *core.Simulation) {
-					for _, unit := range sim.Encounter.TargetUnits {
+					for _, unit := range sim.Encounter.ActiveTargetUnits {
 						spell.CalcAndDealDamage(
 							sim,
 							unit,
@@ -59,7 +59,7 @@ func (shadow *ShadowPriest) registerDivineStar() {
 			core.StartDelayedAction(sim, core.DelayedActionOptions{
 				DoAt: sim.CurrentTime + time.Second*time.Duration(hit2),
 				OnAction: func(s *core.Simulation) {
-					for _, unit := range sim.Encounter.TargetUnits {
+					for _, unit := range sim.Encounter.ActiveTargetUnits {
 						spell.CalcAndDealDamage(
 							sim,
 							unit,
@@ -54,7 +54,7 @@ func (comRogue *CombatRogue) registerBladeFlurry() {
 			numHits := 0
 
 			for enemyIndex := 0; enemyIndex < int(comRogue.Env.GetNumTargets()) && numHits < 4; enemyIndex++ {
-				bfTarget := comRogue.Env.GetTargetUnit(int32(enemyIndex))
+				bfTarget := comRogue.Env.GetActiveTargetUnit(int32(enemyIndex))
 				if bfTarget != comRogue.CurrentTarget {
 					numHits++
 					bfHit.Cast(sim, bfTarget)
@@ -67,7 +67,7 @@ func (comRogue *CombatRogue) registerKillingSpreeCD() {
 					target := comRogue.CurrentTarget
 					if targetCount > 1 && comRogue.HasActiveAura("Blade Flurry") {
 						newUnitIndex := int32(math.Ceil(float64(targetCount)*sim.RandomFloat("Killing Spree"))) - 1
-						target = sim.GetTargetUnit(newUnitIndex)
+						target = sim.GetActiveTargetUnit(newUnitIndex)
 					}
 					mhWeaponSwing.Cast(sim, target)
 					ohWeaponSwing.Cast(sim, target)
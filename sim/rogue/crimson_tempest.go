@@ -56,6 +56,7 @@ func (rogue *Rogue) registerCrimsonTempest() {
 		Flags:          core.SpellFlagMeleeMetrics | SpellFlagFinisher | core.SpellFlagAPL,
 		MetricSplits:   6,
 		ClassSpellMask: RogueSpellCrimsonTempest,
+		AoeRadius:      8,
 
 		DamageMultiplier: 1,
 		CritMultiplier:   rogue.CritMultiplier(false),
@@ -82,7 +83,7 @@ func (rogue *Rogue) registerCrimsonTempest() {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			lastCTDamage = make([]float64, sim.GetNumTargets())
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&rogue.Unit) {
 				damage := hit_minDamage +
 					sim.RandomFloat("Crimson Tempest")*hit_baseDamage +
 					hit_cpScaling*float64(rogue.ComboPoints()) +
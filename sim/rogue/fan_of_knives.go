@@ -34,6 +34,7 @@ func (rogue *Rogue) registerFanOfKnives() {
 		ActionID:    core.ActionID{SpellID: 51723},
 		SpellSchool: core.SpellSchoolPhysical,
 		Flags:       core.SpellFlagMeleeMetrics | core.SpellFlagAPL | core.SpellFlagAoE,
+		AoeRadius:   10,
 
 		EnergyCost: core.EnergyCostOptions{
 			Cost: 35,
@@ -48,7 +49,7 @@ func (rogue *Rogue) registerFanOfKnives() {
 
 		ApplyEffects: func(sim *core.Simulation, unit *core.Unit, spell *core.Spell) {
 			rogue.BreakStealth(sim)
-			for i, aoeTarget := range sim.Encounter.TargetUnits {
+			for i, aoeTarget := range spell.TargetsInRadius(&rogue.Unit) {
 				damage := minDamage +
 					sim.RandomFloat("Fan of Knives")*damageSpread +
 					spell.MeleeAttackPower()*apScaling
@@ -8,6 +8,7 @@ import (
 )
 
 func (elemental *ElementalShaman) registerEarthquakeSpell() {
+	var groundPosition core.Vector2
 
 	earthquakePulse := elemental.RegisterSpell(core.SpellConfig{
 		ActionID:         core.ActionID{SpellID: 77478},
@@ -15,6 +16,7 @@ func (elemental *ElementalShaman) registerEarthquakeSpell() {
 		SpellSchool:      core.SpellSchoolPhysical,
 		ClassSpellMask:   shaman.SpellMaskEarthquake,
 		ProcMask:         core.ProcMaskSpellProc,
+		AoeRadius:        8,
 		DamageMultiplier: 1,
 		ThreatMultiplier: 1,
 		CritMultiplier:   elemental.DefaultCritMultiplier(),
@@ -22,7 +24,7 @@ func (elemental *ElementalShaman) registerEarthquakeSpell() {
 			spell.SpellMetrics[target.UnitIndex].Casts-- // Do not count pulses as casts
 			// Coefficient damage calculated manually because it's a Nature spell but deals Physical damage
 			baseDamage := elemental.CalcScalingSpellDmg(0.32400000095) + 0.1099999994*spell.SpellPower()
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadiusOfPosition(groundPosition) {
 				spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
 		},
@@ -60,6 +62,7 @@ func (elemental *ElementalShaman) registerEarthquakeSpell() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			groundPosition = target.Position
 			dot := spell.Dot(target)
 			dot.Apply(sim)
 		},
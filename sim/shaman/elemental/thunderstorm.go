@@ -43,11 +43,11 @@ func (elemental *ElementalShaman) registerThunderstormSpell() {
 			elemental.AddMana(sim, elemental.MaxMana()*manaRestore, manaMetrics)
 
 			if elemental.Shaman.ThunderstormInRange {
-				for i, aoeTarget := range sim.Encounter.TargetUnits {
+				for i, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 					baseDamage := elemental.GetShaman().CalcAndRollDamageRange(sim, 1.62999999523, 0.13300000131)
 					results[i] = spell.CalcDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 				}
-				for i := range sim.Encounter.TargetUnits {
+				for i := range sim.Encounter.ActiveTargetUnits {
 					spell.DealDamage(sim, results[i])
 				}
 			}
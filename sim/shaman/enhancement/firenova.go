@@ -23,7 +23,7 @@ func (enh *EnhancementShaman) registerFireNovaSpell() {
 			ClassSpellMask: shaman.SpellMaskFireNova,
 
 			ApplyEffects: func(sim *core.Simulation, mainTarget *core.Unit, spell *core.Spell) {
-				for j, target := range sim.Encounter.TargetUnits {
+				for j, target := range sim.Encounter.ActiveTargetUnits {
 					if target != mainTarget {
 						spell.DealDamage(sim, results[mainTarget.Index][j])
 					}
@@ -58,23 +58,23 @@ func (enh *EnhancementShaman) registerFireNovaSpell() {
 		BonusCoefficient: 0.30000001192,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for i, mainTarget := range sim.Encounter.TargetUnits {
+			for _, mainTarget := range sim.Encounter.ActiveTargetUnits {
 				//need to calculate damage even from non flame shocked target in case echo procs from it
-				for j, target := range sim.Encounter.TargetUnits {
+				for j, target := range sim.Encounter.ActiveTargetUnits {
 					if mainTarget != target {
 						baseDamage := enh.CalcAndRollDamageRange(sim, 1.43599998951, 0.15000000596)
-						results[i][j] = spell.CalcDamage(sim, target, baseDamage, spell.OutcomeMagicHitAndCrit)
+						results[mainTarget.Index][j] = spell.CalcDamage(sim, target, baseDamage, spell.OutcomeMagicHitAndCrit)
 					}
 				}
 			}
-			for i, mainTarget := range sim.Encounter.TargetUnits {
+			for _, mainTarget := range sim.Encounter.ActiveTargetUnits {
 				if enh.FlameShock.Dot(mainTarget).IsActive() {
-					enh.FireNovas[i].Cast(sim, mainTarget)
+					enh.FireNovas[mainTarget.Index].Cast(sim, mainTarget)
 				}
 			}
 		},
 		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 				if enh.FlameShock.Dot(aoeTarget).IsActive() {
 					return true
 				}
@@ -99,10 +99,10 @@ func (shaman *Shaman) registerMagmaTotemSpell() {
 
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
 				baseDamage := shaman.CalcScalingSpellDmg(0.26699998975)
-				for i, aoeTarget := range sim.Encounter.TargetUnits {
+				for i, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 					results[i] = dot.Spell.CalcPeriodicDamage(sim, aoeTarget, baseDamage, dot.Spell.OutcomeMagicHitAndCrit)
 				}
-				for i := range sim.Encounter.TargetUnits {
+				for i := range sim.Encounter.ActiveTargetUnits {
 					dot.Spell.DealPeriodicDamage(sim, results[i])
 				}
 			},
@@ -0,0 +1,213 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+// Each bounce of Chain Heal heals for less than the previous one.
+const chainHealBounceMultiplier = 0.9
+
+// Heals cast at an enemy land on the shaman instead, like in game.
+func (shaman *Shaman) healTargetOrSelf(target *core.Unit) *core.Unit {
+	if target.IsOpponent(&shaman.Unit) {
+		return &shaman.Unit
+	}
+	return target
+}
+
+func (shaman *Shaman) registerHealingSurgeSpell() {
+	shaman.HealingSurge = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 8004},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskHealingSurge,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 20.7,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 1.135,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := shaman.CalcAndRollDamageRange(sim, 11.264, 0.15)
+			spell.CalcAndDealHealing(sim, shaman.healTargetOrSelf(target), baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
+
+func (shaman *Shaman) registerHealingWaveSpell() {
+	shaman.HealingWave = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 331},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskHealingWave,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 9.9,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.756,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := shaman.CalcAndRollDamageRange(sim, 7.502, 0.15)
+			spell.CalcAndDealHealing(sim, shaman.healTargetOrSelf(target), baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
+
+func (shaman *Shaman) registerGreaterHealingWaveSpell() {
+	shaman.GreaterHealingWave = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 77472},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskGreaterHealingWave,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 27.1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 1.377,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := shaman.CalcAndRollDamageRange(sim, 13.651, 0.15)
+			spell.CalcAndDealHealing(sim, shaman.healTargetOrSelf(target), baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
+
+// Heals the target instantly and then over 18 sec.
+func (shaman *Shaman) registerRiptideSpell() {
+	shaman.Riptide = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 61295},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskRiptide,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 10,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    shaman.NewTimer(),
+				Duration: time.Second * 6,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.39,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Riptide",
+			},
+			NumberOfTicks:       6,
+			TickLength:          time.Second * 3,
+			AffectedByCastSpeed: true,
+
+			BonusCoefficient: 0.185,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, shaman.CalcScalingSpellDmg(1.833))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = shaman.healTargetOrSelf(target)
+			spell.CalcAndDealHealing(sim, target, shaman.CalcScalingSpellDmg(3.867), spell.OutcomeHealingCrit)
+			spell.Hot(target).Apply(sim)
+		},
+	})
+}
+
+// Heals the target, then bounces to the 3 most injured other players.
+func (shaman *Shaman) registerChainHealSpell() {
+	shaman.ChainHeal = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 1064},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskChainHeal,
+
+		MaxRange: 40,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 26.1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.687,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = shaman.healTargetOrSelf(target)
+			targets := append([]*core.Unit{target}, core.FilterSlice(shaman.Env.Raid.GetSmartHealTargets(4), func(unit *core.Unit) bool {
+				return unit != target
+			})...)
+
+			bounceMultiplier := 1.0
+			for _, bounceTarget := range targets[:min(4, len(targets))] {
+				baseHealing := shaman.CalcAndRollDamageRange(sim, 6.808, 0.15)
+				spell.DamageMultiplier *= bounceMultiplier
+				spell.CalcAndDealHealing(sim, bounceTarget, baseHealing, spell.OutcomeHealingCrit)
+				spell.DamageMultiplier /= bounceMultiplier
+				bounceMultiplier *= chainHealBounceMultiplier
+			}
+		},
+	})
+}
@@ -47,11 +47,11 @@ var ItemSetRegaliaOfTheWitchDoctor = core.NewItemSet(core.ItemSet{
 					baseDamage := sim.RollWithLabel(32375, 37625, "Lighting Strike 2pT14")
 					nTargets := shaman.Env.GetNumTargets()
 					results := make([]*core.SpellResult, nTargets)
-					for i, aoeTarget := range sim.Encounter.TargetUnits {
+					for i, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 						results[i] = spell.CalcDamage(sim, aoeTarget, baseDamage/float64(nTargets), spell.OutcomeMagicHitAndCrit)
 					}
 					spell.WaitTravelTime(sim, func(sim *core.Simulation) {
-						for i, _ := range sim.Encounter.TargetUnits {
+						for i, _ := range sim.Encounter.ActiveTargetUnits {
 							spell.DealDamage(sim, results[i])
 						}
 					})
@@ -0,0 +1,107 @@
+package restoration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/shaman"
+)
+
+// A restoration shaman healing four target dummies which all take damage every second.
+func newHealingSim() *core.Simulation {
+	raid := core.SinglePlayerRaidProto(&proto.Player{
+		Race:      proto.Race_RaceTroll,
+		Class:     proto.Class_ClassShaman,
+		Equipment: &proto.EquipmentSpec{},
+		Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		Spec: &proto.Player_RestorationShaman{
+			RestorationShaman: &proto.RestorationShaman{
+				Options: &proto.RestorationShaman_Options{ClassOptions: &proto.ShamanOptions{}},
+			},
+		},
+	}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{})
+	raid.TargetDummies = 4
+	raid.IncomingDamage = &proto.IncomingDamageModel{Dtps: 5000, TankDtps: 10000, DamageVariation: 0.5}
+
+	sim := core.NewSim(&proto.RaidSimRequest{
+		Raid: raid,
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets:  []*proto.Target{core.NewDefaultTarget()},
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 1},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+
+	sim.Step()
+	for sim.CurrentTime < time.Second*5 {
+		sim.Step()
+	}
+	return sim
+}
+
+func getShaman(sim *core.Simulation) *shaman.Shaman {
+	return sim.Raid.Parties[0].Players[0].(*RestorationShaman).Shaman
+}
+
+func TestChainHealBounces(t *testing.T) {
+	sim := newHealingSim()
+	resto := getShaman(sim)
+	dummies := sim.Raid.GetTargetDummies()
+	target := &dummies[3].Unit
+
+	mostInjured := sim.Raid.GetSmartHealTargets(4)
+	resto.ChainHeal.SkipCastAndApplyEffects(sim, target)
+
+	// The target, then the 3 most injured other players.
+	expectedTargets := []*core.Unit{target}
+	for _, unit := range mostInjured {
+		if unit != target && len(expectedTargets) < 4 {
+			expectedTargets = append(expectedTargets, unit)
+		}
+	}
+	numHealed := 0
+	for _, unit := range sim.Raid.AllPlayerUnits {
+		if resto.ChainHeal.SpellMetrics[unit.UnitIndex].Hits > 0 {
+			numHealed++
+		}
+	}
+	if numHealed != 4 {
+		t.Fatalf("Expected Chain Heal to heal 4 players, healed %d", numHealed)
+	}
+	for _, unit := range expectedTargets {
+		if resto.ChainHeal.SpellMetrics[unit.UnitIndex].Hits != 1 {
+			t.Fatalf("Expected Chain Heal to heal %s", unit.Label)
+		}
+	}
+}
+
+func TestRiptideHot(t *testing.T) {
+	sim := newHealingSim()
+	resto := getShaman(sim)
+	tank := &sim.Raid.GetTargetDummies()[0].Unit
+
+	resto.Riptide.SkipCastAndApplyEffects(sim, tank)
+	if !resto.Riptide.Hot(tank).IsActive() {
+		t.Fatalf("Expected Riptide to leave a heal over time on the target")
+	}
+	directHealing := resto.Riptide.SpellMetrics[tank.UnitIndex].TotalHealing
+
+	endTime := sim.CurrentTime + time.Second*7
+	for sim.CurrentTime < endTime {
+		sim.Step()
+	}
+	if resto.Riptide.SpellMetrics[tank.UnitIndex].TotalHealing <= directHealing {
+		t.Fatalf("Expected the Riptide heal over time to tick")
+	}
+
+	// Heals cast at an enemy land on the caster.
+	resto.Riptide.SkipCastAndApplyEffects(sim, sim.GetTargetUnit(0))
+	if !resto.Riptide.Hot(&resto.Unit).IsActive() {
+		t.Fatalf("Expected Riptide cast at the boss to heal the shaman")
+	}
+}
@@ -230,33 +230,13 @@ func (shaman *Shaman) Initialize() {
 	shaman.registerStormlashCD()
 }
 
+// Registers the heals used by Restoration.
 func (shaman *Shaman) RegisterHealingSpells() {
-	// shaman.registerAncestralHealingSpell()
-	// shaman.registerHealingSurgeSpell()
-	// shaman.registerHealingWaveSpell()
-	// shaman.registerRiptideSpell()
-	// shaman.registerEarthShieldSpell()
-	// shaman.registerChainHealSpell()
-
-	// if shaman.Talents.TidalWaves > 0 {
-	// 	shaman.tidalWaveProc = shaman.GetOrRegisterAura(core.Aura{
-	// 		Label:    "Tidal Wave Proc",
-	// 		ActionID: core.ActionID{SpellID: 53390},
-	// 		Duration: core.NeverExpires,
-	// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-	// 			aura.Deactivate(sim)
-	// 		},
-	// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-	// 			shaman.HealingWave.CastTimeMultiplier *= 0.7
-	// 			shaman.HealingSurge.BonusCritRating += core.CritRatingPerCritChance * 25
-	// 		},
-	// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-	// 			shaman.HealingWave.CastTimeMultiplier /= 0.7
-	// 			shaman.HealingSurge.BonusCritRating -= core.CritRatingPerCritChance * 25
-	// 		},
-	// 		MaxStacks: 2,
-	// 	})
-	// }
+	shaman.registerHealingSurgeSpell()
+	shaman.registerHealingWaveSpell()
+	shaman.registerGreaterHealingWaveSpell()
+	shaman.registerRiptideSpell()
+	shaman.registerChainHealSpell()
 }
 
 func (shaman *Shaman) Reset(sim *core.Simulation) {
@@ -331,6 +311,11 @@ const (
 	SpellMaskElementalBlastOverload
 	SpellMaskStormlashTotem
 	SpellMaskBloodlust
+	SpellMaskHealingSurge
+	SpellMaskHealingWave
+	SpellMaskGreaterHealingWave
+	SpellMaskRiptide
+	SpellMaskChainHeal
 
 	SpellMaskStormstrike  = SpellMaskStormstrikeCast | SpellMaskStormstrikeDamage
 	SpellMaskFlameShock   = SpellMaskFlameShockDirect | SpellMaskFlameShockDot
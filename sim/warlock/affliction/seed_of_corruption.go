@@ -1,6 +1,7 @@
 package affliction
 
 import (
+	"slices"
 	"time"
 
 	"github.com/wowsims/mop/sim/core"
@@ -27,6 +28,7 @@ func (affliction *AfflictionWarlock) registerSeed() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagAoE | core.SpellFlagPassiveSpell,
 		ClassSpellMask: warlock.WarlockSpellSeedOfCorruptionExposion,
+		AoeRadius:      15,
 
 		DamageMultiplierAdditive: 1,
 		CritMultiplier:           affliction.DefaultCritMultiplier(),
@@ -36,7 +38,8 @@ func (affliction *AfflictionWarlock) registerSeed() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDmg := affliction.CalcAndRollDamageRange(sim, seedExploScale, seedExploVariance)
 			isSoulBurn := seedPropertyTracker[target.UnitIndex].isSoulBurn
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			// Explosions can pop the seeds of the targets they hit, which reuses the target buffer.
+			for _, aoeTarget := range slices.Clone(spell.TargetsInRadius(target)) {
 				result := spell.CalcAndDealDamage(sim, aoeTarget, baseDmg, spell.OutcomeMagicHitAndCrit)
 				if isSoulBurn && result.Landed() {
 					affliction.Corruption.Proc(sim, aoeTarget)
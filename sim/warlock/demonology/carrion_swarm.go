@@ -39,7 +39,7 @@ func (demonology *DemonologyWarlock) registerCarrionSwarm() {
 		},
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			demonology.DemonicFury.Spend(sim, core.TernaryInt32(demonology.T15_2pc.IsActive(), 35, 50), spell.ActionID)
-			for _, enemy := range sim.Encounter.TargetUnits {
+			for _, enemy := range sim.Encounter.ActiveTargetUnits {
 				spell.CalcAndDealDamage(
 					sim,
 					enemy,
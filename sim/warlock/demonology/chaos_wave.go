@@ -44,7 +44,7 @@ func (demonology *DemonologyWarlock) registerChaosWave() {
 			pa.Priority = core.ActionPriorityAuto
 
 			pa.OnAction = func(sim *core.Simulation) {
-				for _, enemy := range sim.Encounter.TargetUnits {
+				for _, enemy := range sim.Encounter.ActiveTargetUnits {
 					spell.CalcAndDealDamage(
 						sim,
 						enemy,
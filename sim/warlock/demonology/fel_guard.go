@@ -77,7 +77,7 @@ func registerLegionStrikeSpell(pet *warlock.WarlockPet, demo *DemonologyWarlock)
 			baseDmg := spell.Unit.MHWeaponDamage(sim, spell.MeleeAttackPower()) * 1.3
 			baseDmg /= float64(sim.Environment.GetNumTargets())
 
-			for _, target := range sim.Encounter.TargetUnits {
+			for _, target := range sim.Encounter.ActiveTargetUnits {
 				spell.CalcAndDealDamage(sim, target, baseDmg, spell.OutcomeMeleeWeaponSpecialHitAndCrit)
 			}
 
@@ -114,7 +114,7 @@ func registerFelstorm(pet *warlock.WarlockPet, _ *DemonologyWarlock, autoCast bo
 			TickLength:    time.Second,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
 				baseDamage := dot.Spell.Unit.MHWeaponDamage(sim, dot.Spell.MeleeAttackPower()) + dot.Spell.Unit.OHWeaponDamage(sim, dot.Spell.MeleeAttackPower())
-				for _, enemy := range sim.Encounter.TargetUnits {
+				for _, enemy := range sim.Encounter.ActiveTargetUnits {
 					dot.Spell.CalcAndDealDamage(sim, enemy, baseDamage, dot.Spell.OutcomeMeleeSpecialBlockAndCritNoHitCounter)
 				}
 			},
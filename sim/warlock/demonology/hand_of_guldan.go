@@ -85,7 +85,7 @@ func (demonology *DemonologyWarlock) registerHandOfGuldan() {
 			pa.Priority = core.ActionPriorityAuto
 
 			pa.OnAction = func(sim *core.Simulation) {
-				for _, enemy := range sim.Encounter.TargetUnits {
+				for _, enemy := range sim.Encounter.ActiveTargetUnits {
 					result := spell.CalcAndDealDamage(
 						sim,
 						enemy,
@@ -19,6 +19,7 @@ func (demonology *DemonologyWarlock) registerImmolationAura() {
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL | core.SpellFlagNoMetrics,
 		ClassSpellMask: warlock.WarlockSpellImmolationAura,
+		AoeRadius:      10,
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
 				GCD: time.Second,
@@ -48,7 +49,7 @@ func (demonology *DemonologyWarlock) registerImmolationAura() {
 
 				demonology.DemonicFury.Spend(sim, core.TernaryInt32(demonology.T15_2pc.IsActive(), 18, 25), dot.Spell.ActionID)
 
-				for _, unit := range sim.Encounter.TargetUnits {
+				for _, unit := range dot.Spell.TargetsInRadius(&demonology.Unit) {
 					dot.Spell.CalcAndDealPeriodicDamage(sim, unit, baseDamage, dot.OutcomeTick)
 				}
 			},
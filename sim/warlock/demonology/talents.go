@@ -54,7 +54,7 @@ func (demonlogy *DemonologyWarlock) registerGrimoireOfSupremacy() {
 			baseDmg := spell.Unit.MHWeaponDamage(sim, spell.MeleeAttackPower()) * 1.95
 			baseDmg /= float64(sim.Environment.GetNumTargets())
 
-			for _, target := range sim.Encounter.TargetUnits {
+			for _, target := range sim.Encounter.ActiveTargetUnits {
 				spell.CalcAndDealDamage(sim, target, baseDmg, spell.OutcomeMeleeWeaponSpecialHitAndCrit)
 			}
 
@@ -32,7 +32,7 @@ func (demonology *DemonologyWarlock) registerVoidRay() {
 		},
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			demonology.DemonicFury.Spend(sim, core.TernaryInt32(demonology.T15_2pc.IsActive(), 56, 80), spell.ActionID)
-			for _, enemy := range sim.Encounter.TargetUnits {
+			for _, enemy := range sim.Encounter.ActiveTargetUnits {
 				baseDamage := demonology.CalcAndRollDamageRange(sim, voidRayScale, voidRayVariance)
 				spell.CalcAndDealDamage(sim, enemy, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
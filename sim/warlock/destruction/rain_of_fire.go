@@ -18,12 +18,15 @@ var rofCoeff = 0.15
 
 func (destruction DestructionWarlock) registerRainOfFire() {
 	baseDamage := destruction.CalcScalingSpellDmg(rofScale)
+	var groundPosition core.Vector2
+
 	destruction.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 104232},
 		SpellSchool:    core.SpellSchoolFire,
 		ProcMask:       core.ProcMaskSpellDamage,
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL,
 		ClassSpellMask: warlock.WarlockSpellRainOfFire,
+		AoeRadius:      8,
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
 				GCD: core.GCDDefault,
@@ -51,7 +54,7 @@ func (destruction DestructionWarlock) registerRainOfFire() {
 			IsAOE:                true,
 			BonusCoefficient:     rofCoeff,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range dot.Spell.TargetsInRadiusOfPosition(groundPosition) {
 					result := dot.Spell.CalcAndDealPeriodicDamage(sim, aoeTarget, baseDamage, dot.OutcomeTickMagicCrit)
 					if result.Landed() && sim.Proc(0.125, "RoF - Ember Proc") {
 						destruction.BurningEmbers.Gain(sim, 2, dot.ActionID)
@@ -61,6 +64,7 @@ func (destruction DestructionWarlock) registerRainOfFire() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			groundPosition = target.Position
 			spell.AOEDot().Apply(sim)
 		},
 	})
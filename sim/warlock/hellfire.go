@@ -21,6 +21,7 @@ func (warlock *Warlock) RegisterHellfire(callback WarlockSpellCastedCallback) *c
 		Flags:            core.SpellFlagAoE | core.SpellFlagChanneled | core.SpellFlagAPL,
 		ProcMask:         core.ProcMaskSpellDamage,
 		ClassSpellMask:   WarlockSpellHellfire,
+		AoeRadius:        10,
 		ThreatMultiplier: 1,
 		DamageMultiplier: 1,
 
@@ -44,13 +45,14 @@ func (warlock *Warlock) RegisterHellfire(callback WarlockSpellCastedCallback) *c
 			BonusCoefficient:     hellFireCoeff,
 
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for idx, unit := range sim.Encounter.TargetUnits {
+				aoeTargets := dot.Spell.TargetsInRadius(&warlock.Unit)
+				for idx, unit := range aoeTargets {
 					results[idx] = *dot.Spell.CalcAndDealPeriodicDamage(sim, unit, baseDamage, dot.Spell.OutcomeMagicHit)
 				}
 
 				warlock.SpendMana(sim, warlock.MaxMana()*0.02, manaMetric)
 				if callback != nil {
-					callback(results, dot.Spell, sim)
+					callback(results[:len(aoeTargets)], dot.Spell, sim)
 				}
 			},
 		},
@@ -57,7 +57,7 @@ func (war *ArmsWarrior) registerSlam() {
 
 			if war.SweepingStrikesAura.IsActive() {
 				sweepingStrikesSlamDamage = result.Damage
-				for _, otherTarget := range sim.Encounter.TargetUnits {
+				for _, otherTarget := range sim.Encounter.ActiveTargetUnits {
 					if otherTarget != target {
 						sweepingStrikesSlam.Cast(sim, otherTarget)
 					}
@@ -34,11 +34,11 @@ func (warrior *Warrior) registerHeroicLeap() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDamage := 1 + 0.5*spell.MeleeAttackPower()
 
-			for i, enemyTarget := range sim.Encounter.TargetUnits {
+			for i, enemyTarget := range sim.Encounter.ActiveTargetUnits {
 				results[i] = spell.CalcDamage(sim, enemyTarget, baseDamage, spell.OutcomeMeleeWeaponSpecialHitAndCrit)
 			}
 
-			for _, result := range results {
+			for _, result := range results[:sim.GetNumTargets()] {
 				spell.DealDamage(sim, result)
 			}
 		},
@@ -112,14 +112,14 @@ func (war *Warrior) registerCleaveSpell() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDamage := spell.Unit.MHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
 
-			for idx, target := range sim.Encounter.TargetUnits {
-				if idx > maxTargets {
+			for idx, target := range sim.Encounter.ActiveTargetUnits {
+				if idx >= maxTargets {
 					break
 				}
 				results[idx] = spell.CalcDamage(sim, target, baseDamage, spell.OutcomeMeleeWeaponSpecialHitAndCrit)
 			}
 
-			for _, result := range results {
+			for _, result := range results[:min(maxTargets, int(sim.GetNumTargets()))] {
 				spell.DealDamage(sim, result)
 			}
 		},
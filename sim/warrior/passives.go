@@ -120,7 +120,7 @@ func (war *Warrior) registerBloodAndThunder() {
 		Outcome:        core.OutcomeLanded,
 		ClassSpellMask: SpellMaskThunderClap,
 		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-			for _, target := range sim.Encounter.TargetUnits {
+			for _, target := range sim.Encounter.ActiveTargetUnits {
 				dot := war.DeepWounds.Dot(target)
 				dot.Apply(sim)
 			}
@@ -22,6 +22,7 @@ func (war *ProtectionWarrior) registerDemoralizingShout() {
 		ProcMask:       core.ProcMaskEmpty,
 		Flags:          core.SpellFlagAPL | core.SpellFlagReadinessTrinket,
 		ClassSpellMask: warrior.SpellMaskDemoralizingShout,
+		AoeRadius:      10,
 
 		Cast: core.CastConfig{
 			DefaultCast: core.Cast{
@@ -35,7 +36,7 @@ func (war *ProtectionWarrior) registerDemoralizingShout() {
 		},
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&war.Unit) {
 				result := spell.CalcAndDealOutcome(sim, aoeTarget, spell.OutcomeMagicHit)
 				if result.Landed() {
 					war.DemoralizingShoutAuras.Get(aoeTarget).Activate(sim)
@@ -17,6 +17,7 @@ func (war *Warrior) registerThunderClap() {
 		ProcMask:       core.ProcMaskMeleeMHSpecial,
 		Flags:          core.SpellFlagAoE | core.SpellFlagAPL,
 		ClassSpellMask: SpellMaskThunderClap,
+		AoeRadius:      8,
 
 		RageCost: core.RageCostOptions{
 			Cost: 20,
@@ -39,7 +40,7 @@ func (war *Warrior) registerThunderClap() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDamage := war.CalcScalingSpellDmg(0.25) + spell.MeleeAttackPower()*0.44999998808
 
-			for _, aoeTarget := range sim.Encounter.TargetUnits {
+			for _, aoeTarget := range spell.TargetsInRadius(&war.Unit) {
 				result := spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMeleeSpecialNoBlockDodgeParry)
 				if result.Landed() {
 					war.ThunderClapAuras.Get(aoeTarget).Activate(sim)
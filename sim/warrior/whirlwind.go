@@ -21,6 +21,7 @@ func (war *Warrior) registerWhirlwind() {
 			ProcMask:       core.ProcMaskMeleeOHSpecial,
 			ClassSpellMask: SpellMaskWhirlwindOh,
 			Flags:          core.SpellFlagAoE | core.SpellFlagMeleeMetrics | core.SpellFlagNoOnCastComplete,
+			AoeRadius:      8,
 
 			DamageMultiplier: 0.85,
 			ThreatMultiplier: 1,
@@ -29,12 +30,13 @@ func (war *Warrior) registerWhirlwind() {
 			BonusCoefficient: 1,
 
 			ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-				for i, enemyTarget := range sim.Encounter.TargetUnits {
+				aoeTargets := spell.TargetsInRadius(&war.Unit)
+				for i, enemyTarget := range aoeTargets {
 					baseDamage := spell.Unit.OHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
 					results[i] = whirlwindOH.CalcDamage(sim, enemyTarget, baseDamage, whirlwindOH.OutcomeMeleeWeaponSpecialHitAndCrit)
 				}
 
-				for _, result := range results {
+				for _, result := range results[:len(aoeTargets)] {
 					whirlwindOH.DealDamage(sim, result)
 				}
 			},
@@ -47,6 +49,7 @@ func (war *Warrior) registerWhirlwind() {
 		ProcMask:       core.ProcMaskMeleeMHSpecial,
 		Flags:          core.SpellFlagAoE | core.SpellFlagMeleeMetrics | core.SpellFlagAPL,
 		ClassSpellMask: SpellMaskWhirlwind,
+		AoeRadius:      8,
 
 		RageCost: core.RageCostOptions{
 			Cost: core.TernaryInt32(war.Spec == proto.Spec_SpecFuryWarrior, 30, 20),
@@ -65,12 +68,13 @@ func (war *Warrior) registerWhirlwind() {
 		BonusCoefficient: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for i, enemyTarget := range sim.Encounter.TargetUnits {
+			aoeTargets := spell.TargetsInRadius(&war.Unit)
+			for i, enemyTarget := range aoeTargets {
 				baseDamage := spell.Unit.MHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
 				results[i] = spell.CalcDamage(sim, enemyTarget, baseDamage, spell.OutcomeMeleeWeaponSpecialHitAndCrit)
 			}
 
-			for _, result := range results {
+			for _, result := range results[:len(aoeTargets)] {
 				spell.DealDamage(sim, result)
 			}
 
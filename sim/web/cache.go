@@ -0,0 +1,39 @@
+package main
+
+import (
+	proto "github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Returns the cached result of a raid sim request, or nil if the cache is disabled, the
+// message is not a raid sim request or there is no result for it.
+func (s *server) cachedResult(msg googleProto.Message) *proto.RaidSimResult {
+	rsr, ok := msg.(*proto.RaidSimRequest)
+	if !ok || s.cache == nil {
+		return nil
+	}
+	return s.cache.Get(rsr)
+}
+
+// Stores the result of a raid sim request in the cache, if it is enabled.
+func (s *server) cacheResult(msg googleProto.Message, result *proto.RaidSimResult) {
+	rsr, ok := msg.(*proto.RaidSimRequest)
+	if !ok || s.cache == nil {
+		return
+	}
+	s.cache.Put(rsr, result)
+}
+
+// Creates the final progress message of an async sim with a cached result.
+func cachedProgress(result *proto.RaidSimResult) *proto.ProgressMetrics {
+	progress := &proto.ProgressMetrics{
+		CompletedIterations: result.IterationsDone,
+		TotalIterations:     result.IterationsDone,
+		FinalRaidResult:     result,
+	}
+	if raidMetrics := result.RaidMetrics; raidMetrics != nil {
+		progress.Dps = raidMetrics.GetDps().GetAvg()
+		progress.Hps = raidMetrics.GetHps().GetAvg()
+	}
+	return progress
+}
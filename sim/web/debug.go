@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/wowsims/mop/sim/core"
+	proto "github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Debug sessions which aren't used for this long are dropped.
+const debugSessionTimeout = time.Minute * 30
+
+// Each debug session keeps a whole sim in memory, so only this many are kept at once and
+// starting another drops the least recently used one.
+const maxDebugSessions = 16
+
+// debugSession steps through a single iteration of a raid sim, see core.DebugSim.
+type debugSession struct {
+	mu       sync.Mutex
+	debugSim *core.DebugSim
+	lastUsed time.Time
+}
+
+// debugSessions holds the debug sessions of the server by their id.
+type debugSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*debugSession
+}
+
+func newDebugSessions() *debugSessions {
+	return &debugSessions{sessions: map[string]*debugSession{}}
+}
+
+func (ds *debugSessions) add(debugSim *core.DebugSim) string {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	ds.pruneLocked()
+	for len(ds.sessions) >= maxDebugSessions {
+		ds.evictLeastRecentlyUsedLocked()
+	}
+	id := uuid.NewString()
+	ds.sessions[id] = &debugSession{debugSim: debugSim, lastUsed: time.Now()}
+	return id
+}
+
+func (ds *debugSessions) get(id string) (*debugSession, bool) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	ds.pruneLocked()
+	session, ok := ds.sessions[id]
+	if ok {
+		session.lastUsed = time.Now()
+	}
+	return session, ok
+}
+
+func (ds *debugSessions) remove(id string) {
+	ds.mu.Lock()
+	delete(ds.sessions, id)
+	ds.mu.Unlock()
+}
+
+func (ds *debugSessions) pruneLocked() {
+	for id, session := range ds.sessions {
+		if time.Since(session.lastUsed) > debugSessionTimeout {
+			delete(ds.sessions, id)
+		}
+	}
+}
+
+func (ds *debugSessions) evictLeastRecentlyUsedLocked() {
+	var lruID string
+	var lruSession *debugSession
+	for id, session := range ds.sessions {
+		if lruSession == nil || session.lastUsed.Before(lruSession.lastUsed) {
+			lruID, lruSession = id, session
+		}
+	}
+	delete(ds.sessions, lruID)
+}
+
+func (s *server) setupDebugServer() {
+	http.Handle("/debugStart", corsMiddleware(debugHandler(s.debugStart)))
+	http.Handle("/debugStep", corsMiddleware(debugHandler(s.debugStep)))
+	http.Handle("/debugSetBreakpoints", corsMiddleware(debugHandler(s.debugSetBreakpoints)))
+	http.Handle("/debugEnd", corsMiddleware(debugHandler(s.debugEnd)))
+}
+
+// debugHandler decodes the request of a debug endpoint, and returns its DebugResult. Panics
+// of the sim, e.g. from invalid rotations, are returned as errors.
+func debugHandler[T googleProto.Message](handle func(T) *proto.DebugResult) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		var msg T
+		msg = msg.ProtoReflect().New().Interface().(T)
+		if err := googleProto.Unmarshal(body, msg); err != nil {
+			log.Printf("Failed to parse request: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		result := func() (result *proto.DebugResult) {
+			defer func() {
+				if err := recover(); err != nil {
+					result = &proto.DebugResult{Error: &proto.ErrorOutcome{Message: fmt.Sprint(err)}}
+				}
+			}()
+			return handle(msg)
+		}()
+		writeProto(w, result)
+	}
+}
+
+func debugError(message string, vals ...interface{}) *proto.DebugResult {
+	return &proto.DebugResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf(message, vals...)}}
+}
+
+func (s *server) debugStart(request *proto.DebugStartRequest) *proto.DebugResult {
+	if request.Request == nil {
+		return debugError("no sim to debug")
+	}
+	debugSim, err := core.NewDebugSim(request.Request, request.Seed, request.PlayerIndex)
+	if err != nil {
+		return debugError("failed to start debugging: %s", err)
+	}
+	debugSim.Breakpoints = request.Breakpoints
+
+	return &proto.DebugResult{
+		SessionId: s.debugSessions.add(debugSim),
+		State:     debugSim.State(),
+	}
+}
+
+// Runs fn on the session, which is dropped if the sim panics.
+func (s *server) withDebugSession(id string, fn func(debugSim *core.DebugSim) error) *proto.DebugResult {
+	session, ok := s.debugSessions.get(id)
+	if !ok {
+		return debugError("unknown debug session %q", id)
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	defer func() {
+		if err := recover(); err != nil {
+			s.debugSessions.remove(id)
+			panic(err)
+		}
+	}()
+	result := &proto.DebugResult{SessionId: id}
+	if err := fn(session.debugSim); err != nil {
+		result.Error = &proto.ErrorOutcome{Message: err.Error()}
+	}
+	result.State = session.debugSim.State()
+	return result
+}
+
+func (s *server) debugStep(request *proto.DebugStepRequest) *proto.DebugResult {
+	return s.withDebugSession(request.SessionId, func(debugSim *core.DebugSim) error {
+		return debugSim.Step(request.Mode, time.Duration(request.Time*float64(time.Second)))
+	})
+}
+
+func (s *server) debugSetBreakpoints(request *proto.DebugSetBreakpointsRequest) *proto.DebugResult {
+	return s.withDebugSession(request.SessionId, func(debugSim *core.DebugSim) error {
+		debugSim.Breakpoints = request.Breakpoints
+		return nil
+	})
+}
+
+func (s *server) debugEnd(request *proto.DebugEndRequest) *proto.DebugResult {
+	if _, ok := s.debugSessions.get(request.SessionId); !ok {
+		return debugError("unknown debug session %q", request.SessionId)
+	}
+	s.debugSessions.remove(request.SessionId)
+	return &proto.DebugResult{SessionId: request.SessionId}
+}
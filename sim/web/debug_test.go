@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebugSessionLimit(t *testing.T) {
+	ds := newDebugSessions()
+	var ids []string
+	for i := 0; i < maxDebugSessions; i++ {
+		ids = append(ids, ds.add(nil))
+	}
+	// Age all sessions, the second one the most. Using the first one keeps it from being evicted.
+	for _, session := range ds.sessions {
+		session.lastUsed = session.lastUsed.Add(-time.Minute)
+	}
+	ds.sessions[ids[1]].lastUsed = ds.sessions[ids[1]].lastUsed.Add(-time.Minute)
+	ds.get(ids[0])
+
+	newID := ds.add(nil)
+	if len(ds.sessions) != maxDebugSessions {
+		t.Fatalf("Expected %d sessions, got %d", maxDebugSessions, len(ds.sessions))
+	}
+	if _, ok := ds.sessions[ids[1]]; ok {
+		t.Fatalf("Expected the least recently used session to be evicted")
+	}
+	for _, id := range []string{ids[0], ids[2], newID} {
+		if _, ok := ds.sessions[id]; !ok {
+			t.Fatalf("Expected session %s to be kept", id)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"container/heap"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	proto "github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// Each sim already runs on all CPUs, so running several at once only slows all of them down.
+	defaultWorkers   = 1
+	defaultQueueSize = 100
+)
+
+type queuedJob struct {
+	progress *asyncProgress
+	handler  asyncAPIHandler
+	msg      googleProto.Message
+
+	seq   uint64 // Orders jobs of the same priority by arrival.
+	index int    // Index in the heap.
+}
+
+// jobHeap implements heap.Interface, with the job to run next at the top.
+type jobHeap []*queuedJob
+
+func (h jobHeap) Len() int { return len(h) }
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].progress.priority != h[j].progress.priority {
+		return h[i].progress.priority > h[j].progress.priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *jobHeap) Push(x any) {
+	job := x.(*queuedJob)
+	job.index = len(*h)
+	*h = append(*h, job)
+}
+func (h *jobHeap) Pop() any {
+	old := *h
+	job := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	job.index = -1
+	return job
+}
+
+// jobQueue is a bounded priority queue of async sims waiting for a worker.
+type jobQueue struct {
+	mu       sync.Mutex
+	nonEmpty *sync.Cond
+	jobs     jobHeap
+	byId     map[string]*queuedJob
+	capacity int
+	nextSeq  uint64
+}
+
+func newJobQueue(capacity int) *jobQueue {
+	q := &jobQueue{
+		byId:     map[string]*queuedJob{},
+		capacity: capacity,
+	}
+	q.nonEmpty = sync.NewCond(&q.mu)
+	return q
+}
+
+// Adds a job to the queue, returning false if the queue is full.
+func (q *jobQueue) push(job *queuedJob, ignoreCapacity bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !ignoreCapacity && q.capacity > 0 && len(q.jobs) >= q.capacity {
+		return false
+	}
+	job.seq = q.nextSeq
+	q.nextSeq++
+	heap.Push(&q.jobs, job)
+	q.byId[job.progress.id] = job
+	q.nonEmpty.Signal()
+	return true
+}
+
+// Removes and returns the job to run next, waiting until there is one.
+func (q *jobQueue) pop() *queuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.jobs) == 0 {
+		q.nonEmpty.Wait()
+	}
+	job := heap.Pop(&q.jobs).(*queuedJob)
+	delete(q.byId, job.progress.id)
+	return job
+}
+
+// Removes a job from the queue, returning it if it was still queued.
+func (q *jobQueue) remove(progressId string) *queuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.byId[progressId]
+	if !ok {
+		return nil
+	}
+	heap.Remove(&q.jobs, job.index)
+	delete(q.byId, progressId)
+	return job
+}
+
+// Returns the number of jobs that will run before the given one, or -1 if it is not queued.
+func (q *jobQueue) position(progressId string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.byId[progressId]
+	if !ok {
+		return -1
+	}
+	position := 0
+	for i := range q.jobs {
+		if q.jobs.Less(i, job.index) {
+			position++
+		}
+	}
+	return position
+}
+
+func (q *jobQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+func (s *server) startWorkers() {
+	s.requeueStoredJobs()
+	for i := 0; i < s.workers; i++ {
+		go func() {
+			for {
+				s.runJob(s.queue.pop())
+			}
+		}()
+	}
+}
+
+// Runs a job until it has sent its final progress message.
+func (s *server) runJob(job *queuedJob) {
+	simProgress := job.progress
+	simProgress.setRunning()
+	s.storeJob(simProgress)
+
+	// reporter channel is handed into the core simulation.
+	//  as the simulation advances it will push changes to the channel
+	//  these changes are consumed here so the asyncProgress endpoint can fetch the results.
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	if job.handler.distribute != nil && s.pool.Capacity() > 0 {
+		job.handler.distribute(s.pool, job.msg, reporter, simProgress.requestId)
+	} else {
+		job.handler.handle(job.msg, reporter, simProgress.requestId)
+	}
+	// The handler registers the abort signal before returning, so an abort which came in
+	// after the job left the queue but before then is passed on here.
+	if simProgress.isAborted() {
+		simsignals.AbortById(simProgress.requestId)
+	}
+
+	for progMetric := range reporter {
+		if progMetric == nil {
+			break
+		}
+		if progMetric.FinalRaidResult != nil {
+			s.cacheResult(job.msg, progMetric.FinalRaidResult)
+		}
+		if simProgress.push(progMetric) {
+			break
+		}
+	}
+	s.finishJob(job)
+}
+
+// Called once a job has stopped sending progress messages. Jobs which stopped without
+// a final result, e.g. because their sim closed the reporter early, are marked failed.
+func (s *server) finishJob(job *queuedJob) {
+	simProgress := job.progress
+	if !simProgress.isFinished() {
+		simProgress.push(job.handler.final(&proto.ErrorOutcome{Message: "sim ended without a result"}))
+	}
+	s.storeJob(simProgress)
+
+	// Keep finished sims around for a while, so their results can still be streamed.
+	time.AfterFunc(finishedSimRetention, func() {
+		s.removeSim(simProgress.id)
+	})
+}
+
+func (s *server) storeJob(simProgress *asyncProgress) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.update(simProgress); err != nil {
+		log.Printf("[ERROR] Failed to store job %s: %s", simProgress.id, err.Error())
+	}
+}
+
+// Aborts a sim, whether it is queued or running.
+func (s *server) abortSim(simProgress *asyncProgress) bool {
+	if !simProgress.setAborted() {
+		return false
+	}
+	if job := s.queue.remove(simProgress.id); job != nil {
+		job.progress.push(job.handler.final(&proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}))
+		s.finishJob(job)
+		return true
+	}
+	// A job which a worker has just taken may not have registered its abort signal yet,
+	// in which case runJob aborts it once it has.
+	simsignals.AbortById(simProgress.requestId)
+	return true
+}
+
+// Queues the jobs which were queued or running when the server was last stopped.
+func (s *server) requeueStoredJobs() {
+	if s.store == nil {
+		return
+	}
+	jobs, err := s.store.unfinished()
+	if err != nil {
+		log.Printf("[ERROR] Failed to load unfinished jobs: %s", err.Error())
+		return
+	}
+
+	for _, stored := range jobs {
+		handler, ok := asyncAPIHandlers[stored.info.Endpoint]
+		if !ok {
+			continue
+		}
+		msg := handler.msg()
+		if err := googleProto.Unmarshal(stored.request, msg); err != nil {
+			log.Printf("[ERROR] Failed to parse stored job %s: %s", stored.info.ProgressId, err.Error())
+			continue
+		}
+
+		simProgress := s.addNewSim(stored.info.ProgressId, stored.info.RequestId, stored.info.Endpoint, stored.info.Priority)
+		simProgress.createdAt = time.UnixMilli(stored.info.CreatedAt)
+		s.storeJob(simProgress)
+		// The queue limit only applies to new jobs.
+		s.queue.push(&queuedJob{progress: simProgress, handler: handler, msg: msg}, true)
+	}
+	if len(jobs) > 0 {
+		log.Printf("Re-queued %d unfinished jobs", len(jobs))
+	}
+}
+
+func (p *asyncProgress) setRunning() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = proto.AsyncJobStatus_AsyncJobStatusRunning
+	p.startedAt = time.Now()
+	close(p.updated)
+	p.updated = make(chan struct{})
+}
+
+// Marks the sim as aborted, returning false if it had already finished.
+func (p *asyncProgress) setAborted() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return false
+	}
+	p.aborted = true
+	return true
+}
+
+func (p *asyncProgress) isAborted() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.aborted
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func (s *server) jobInfo(p *asyncProgress) *proto.JobInfo {
+	p.mu.Lock()
+	info := &proto.JobInfo{
+		ProgressId: p.id,
+		RequestId:  p.requestId,
+		Endpoint:   p.endpoint,
+		Priority:   p.priority,
+		Status:     p.status,
+		CreatedAt:  unixMilli(p.createdAt),
+		StartedAt:  unixMilli(p.startedAt),
+		FinishedAt: unixMilli(p.finishedAt),
+	}
+	if p.status == proto.AsyncJobStatus_AsyncJobStatusFailed {
+		info.Error = p.err
+	}
+	p.mu.Unlock()
+
+	if info.Status == proto.AsyncJobStatus_AsyncJobStatusQueued {
+		info.QueuePosition = int32(max(s.queue.position(p.id), 0))
+	}
+	return info
+}
+
+// handleListJobs lists the sims in memory and in the store, most recent first.
+func (s *server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	msg := &proto.ListJobsRequest{}
+	if err := googleProto.Unmarshal(body, msg); err != nil {
+		log.Printf("Failed to parse request: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	jobs := map[string]*proto.JobInfo{}
+	if s.store != nil {
+		stored, err := s.store.list(int(msg.Limit))
+		if err != nil {
+			log.Printf("[ERROR] Failed to list jobs: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for _, info := range stored {
+			jobs[info.ProgressId] = info
+		}
+	}
+	s.progMut.RLock()
+	progresses := make([]*asyncProgress, 0, len(s.asyncProgresses))
+	for _, progress := range s.asyncProgresses {
+		progresses = append(progresses, progress)
+	}
+	s.progMut.RUnlock()
+	// Jobs in memory are more up to date than the store.
+	for _, progress := range progresses {
+		jobs[progress.id] = s.jobInfo(progress)
+	}
+
+	result := &proto.ListJobsResult{}
+	for _, info := range jobs {
+		result.Jobs = append(result.Jobs, info)
+	}
+	sort.Slice(result.Jobs, func(i, j int) bool {
+		if result.Jobs[i].CreatedAt != result.Jobs[j].CreatedAt {
+			return result.Jobs[i].CreatedAt > result.Jobs[j].CreatedAt
+		}
+		return result.Jobs[i].ProgressId < result.Jobs[j].ProgressId
+	})
+	if msg.Limit > 0 && len(result.Jobs) > int(msg.Limit) {
+		result.Jobs = result.Jobs[:msg.Limit]
+	}
+	writeProto(w, result)
+}
+
+// handleGetJob returns the status and latest progress of a sim, from memory or the store.
+func (s *server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	msg, ok := readAsyncAPIResult(w, r)
+	if !ok {
+		return
+	}
+
+	if progress, ok := s.getSim(msg.ProgressId); ok {
+		writeProto(w, &proto.GetJobResult{
+			Job:            s.jobInfo(progress),
+			LatestProgress: progress.latestProgress.Load().(*proto.ProgressMetrics),
+		})
+		return
+	}
+
+	result := &proto.GetJobResult{}
+	if s.store != nil {
+		info, latest, err := s.store.get(msg.ProgressId)
+		if err != nil {
+			log.Printf("[ERROR] Failed to get job: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		result.Job = info
+		result.LatestProgress = latest
+	}
+	writeProto(w, result)
+}
+
+// handleDeleteJob deletes a sim from memory and the store, aborting it if it has not finished.
+func (s *server) handleDeleteJob(w http.ResponseWriter, r *http.Request) {
+	msg, ok := readAsyncAPIResult(w, r)
+	if !ok {
+		return
+	}
+
+	result := &proto.DeleteJobResult{}
+	if progress, ok := s.getSim(msg.ProgressId); ok {
+		s.abortSim(progress)
+		s.removeSim(progress.id)
+		result.Deleted = true
+	}
+	if s.store != nil {
+		deleted, err := s.store.delete(msg.ProgressId)
+		if err != nil {
+			log.Printf("[ERROR] Failed to delete job: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		result.Deleted = result.Deleted || deleted
+	}
+	writeProto(w, result)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestJobQueueOrder(t *testing.T) {
+	q := newJobQueue(0)
+	for i, priority := range []int32{0, 5, 0, 10, 5} {
+		q.push(&queuedJob{progress: &asyncProgress{id: string(rune('a' + i)), priority: priority}}, false)
+	}
+
+	if pos := q.position("c"); pos != 4 {
+		t.Fatalf("Expected job c to be at position 4, got %d", pos)
+	}
+	if q.remove("e") == nil {
+		t.Fatalf("Expected job e to be removed")
+	}
+
+	var order string
+	for q.len() > 0 {
+		order += q.pop().progress.id
+	}
+	// Highest priority first, then in order of arrival.
+	if order != "dbac" {
+		t.Fatalf("Unexpected job order %q", order)
+	}
+}
+
+func startQueuedSim(t *testing.T, s *server, priority string) (*http.Response, string) {
+	msgBytes, err := googleProto.Marshal(newStreamTestRequest(100))
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+	recorder := httptest.NewRecorder()
+	s.handleAsyncAPI(recorder, httptest.NewRequest("POST", "/raidSimAsync?priority="+priority, bytes.NewReader(msgBytes)))
+
+	result := recorder.Result()
+	asyncResult := &proto.AsyncAPIResult{}
+	if result.StatusCode == http.StatusOK {
+		if err := googleProto.Unmarshal(recorder.Body.Bytes(), asyncResult); err != nil {
+			t.Fatalf("Failed to parse result: %s", err.Error())
+		}
+	}
+	return result, asyncResult.ProgressId
+}
+
+func TestQueuedJobs(t *testing.T) {
+	store, err := openJobStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("Failed to open job store: %s", err)
+	}
+
+	// No workers are started, so jobs stay queued.
+	s := newServer(1, 2, store)
+	_, kept := startQueuedSim(t, s, "0")
+	_, aborted := startQueuedSim(t, s, "1")
+	if result, _ := startQueuedSim(t, s, "0"); result.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a full queue to reject the job, got status %d", result.StatusCode)
+	}
+
+	abortedProgress, _ := s.getSim(aborted)
+	if info := s.jobInfo(abortedProgress); info.Status != proto.AsyncJobStatus_AsyncJobStatusQueued || info.QueuePosition != 0 || info.Priority != 1 {
+		t.Fatalf("Unexpected info for queued job: %v", info)
+	}
+	if !s.abortSim(abortedProgress) {
+		t.Fatalf("Expected queued job to be aborted")
+	}
+	latest := abortedProgress.latestProgress.Load().(*proto.ProgressMetrics)
+	if latest.GetFinalRaidResult().GetError().GetType() != proto.ErrorOutcomeType_ErrorOutcomeAborted {
+		t.Fatalf("Expected an aborted final result, got %v", latest)
+	}
+	if info, _, _ := store.get(aborted); info.Status != proto.AsyncJobStatus_AsyncJobStatusAborted {
+		t.Fatalf("Expected aborted job to be stored as aborted, got %v", info)
+	}
+
+	// A restarted server picks up the job which was still queued.
+	restarted := newServer(1, 2, store)
+	restarted.requeueStoredJobs()
+	if restarted.queue.len() != 1 || restarted.queue.position(kept) != 0 {
+		t.Fatalf("Expected job %s to be re-queued", kept)
+	}
+	restarted.runJob(restarted.queue.pop())
+	if info, latest, _ := store.get(kept); info.Status != proto.AsyncJobStatus_AsyncJobStatusDone || latest.GetFinalRaidResult().GetRaidMetrics() == nil {
+		t.Fatalf("Expected re-queued job to finish, got %v", info)
+	}
+}
+
+func TestJobWithoutFinalResult(t *testing.T) {
+	handler := asyncAPIHandlers["/raidSimAsync"]
+	handler.handle = func(_ googleProto.Message, reporter chan *proto.ProgressMetrics, _ string) {
+		reporter <- &proto.ProgressMetrics{CompletedIterations: 10, TotalIterations: 100}
+		close(reporter)
+	}
+
+	s := newServer(1, 1, nil)
+	simProgress := s.addNewSim("a", "", "/raidSimAsync", 0)
+	s.runJob(&queuedJob{progress: simProgress, handler: handler, msg: newStreamTestRequest(100)})
+
+	status := simProgress.getStatus()
+	if status.Status != proto.AsyncJobStatus_AsyncJobStatusFailed || status.Error == nil || status.NumProgressMessages != 2 {
+		t.Fatalf("Expected a job whose reporter closed early to fail, got %v", status)
+	}
+	latest := simProgress.latestProgress.Load().(*proto.ProgressMetrics)
+	if latest.GetFinalRaidResult().GetError() == nil {
+		t.Fatalf("Expected a final result with an error, got %v", latest)
+	}
+}
+
+func TestAbortJobBeforeSimStarts(t *testing.T) {
+	s := newServer(1, 1, nil)
+	simProgress := s.addNewSim("a", "abort-before-start", "/raidSimAsync", 0)
+	s.queue.push(&queuedJob{progress: simProgress, handler: asyncAPIHandlers["/raidSimAsync"], msg: newStreamTestRequest(10000)}, false)
+
+	// The worker has taken the job, but its sim has not registered an abort signal yet.
+	job := s.queue.pop()
+	if !s.abortSim(simProgress) {
+		t.Fatalf("Expected the abort to be accepted")
+	}
+	s.runJob(job)
+
+	latest := simProgress.latestProgress.Load().(*proto.ProgressMetrics)
+	if latest.GetFinalRaidResult().GetError().GetType() != proto.ErrorOutcomeType_ErrorOutcomeAborted {
+		t.Fatalf("Expected an aborted final result, got %v", latest)
+	}
+	if s.abortSim(simProgress) {
+		t.Fatalf("Expected a finished job not to be aborted again")
+	}
+}
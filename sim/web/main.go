@@ -11,7 +11,9 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"runtime"
 	"runtime/pprof"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -48,6 +50,14 @@ func main() {
 	var host = flag.String("host", "localhost:3333", "URL to host the interface on.")
 	var launch = flag.Bool("launch", true, "auto launch browser")
 	var skipVersionCheck = flag.Bool("nvc", false, "set true to skip version check")
+	var workers = flag.Int("workers", defaultWorkers, "Maximum number of async sims to run at the same time. Each sim already uses all CPUs.")
+	var queueSize = flag.Int("queue", defaultQueueSize, "Maximum number of async sims waiting to run. Further sims are rejected.")
+	var dbPath = flag.String("db", "", "Path of an sqlite database to store async sims and their results in, so they can be fetched later and survive restarts.")
+	var coordinate = flag.Bool("coordinate", false, "Accept workers registering with this server, and run shards of async sims on them.")
+	var coordinator = flag.String("coordinator", "", "URL of a coordinator server (ex: http://localhost:3333). If set, this server registers as a worker and runs shards of the coordinator's async sims.")
+	var slots = flag.Int("slots", runtime.NumCPU(), "Number of shards to run at the same time when running as a worker.")
+	var cacheSize = flag.Int("cache", 0, "Number of raid sim results to keep in memory, so identical requests with a fixed random seed return them instead of simulating again.")
+	var cacheDir = flag.String("cachedir", "", "Directory to also store cached raid sim results in, so they survive restarts.")
 
 	flag.Parse()
 
@@ -82,9 +92,31 @@ func main() {
 		}()
 	}
 
-	s := &server{
-		progMut:         sync.RWMutex{},
-		asyncProgresses: map[string]*asyncProgress{},
+	var store *jobStore
+	if *dbPath != "" {
+		var err error
+		store, err = openJobStore(*dbPath)
+		if err != nil {
+			log.Fatalf("Failed to open job store: %s", err)
+		}
+	}
+
+	s := newServer(*workers, *queueSize, store)
+	if *cacheSize > 0 || *cacheDir != "" {
+		cache, err := core.NewSimResultCache(*cacheSize, *cacheDir)
+		if err != nil {
+			log.Fatalf("Failed to create result cache: %s", err)
+		}
+		s.cache = cache
+	}
+	s.coordinate = *coordinate
+	if *coordinator != "" {
+		s.isWorker = true
+		address := *host
+		if strings.HasPrefix(address, ":") {
+			address = "localhost" + address
+		}
+		go registerWithCoordinator(*coordinator, "http://"+address, *slots)
 	}
 	s.runServer(*useFS, *host, *launch, *simName, *wasm, bufio.NewReader(os.Stdin))
 }
@@ -114,23 +146,70 @@ var handlers = map[string]apiHandler{
 	"/bulkSimCombos": {msg: func() googleProto.Message { return &proto.BulkSimCombosRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.RunBulkCombos(msg.(*proto.BulkSimCombosRequest))
 	}},
+	"/optimizeGear": {msg: func() googleProto.Message { return &proto.OptimizeGearRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.OptimizeGear(msg.(*proto.OptimizeGearRequest))
+	}},
+	"/tuneAPL": {msg: func() googleProto.Message { return &proto.TuneAPLRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.TuneAPL(msg.(*proto.TuneAPLRequest))
+	}},
+	"/compileSimpleRotation": {msg: func() googleProto.Message { return &proto.CompileSimpleRotationRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.CompileSimpleRotation(msg.(*proto.CompileSimpleRotationRequest))
+	}},
 }
 
 var asyncAPIHandlers = map[string]asyncAPIHandler{
 	"/raidSimAsync": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunRaidSimConcurrentAsync(msg.(*proto.RaidSimRequest), reporter, requestId)
+	}, distribute: func(runner core.ShardRunner, msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunRaidSimDistributedAsync(runner, msg.(*proto.RaidSimRequest), reporter, requestId)
+	}, final: func(err *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalRaidResult: &proto.RaidSimResult{Error: err}}
 	}},
 	"/statWeightsAsync": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.StatWeightsAsync(msg.(*proto.StatWeightsRequest), reporter, requestId)
+	}, distribute: func(runner core.ShardRunner, msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.StatWeightsDistributedAsync(runner, msg.(*proto.StatWeightsRequest), reporter, requestId)
+	}, final: func(err *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalWeightResult: &proto.StatWeightsResult{Error: err}}
 	}},
 	"/bulkSimAsync": {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
+	}, distribute: func(runner core.ShardRunner, msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunBulkSimDistributedAsync(runner, msg.(*proto.BulkSimRequest), reporter, requestId)
+	}, final: func(err *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalBulkResult: &proto.BulkSimResult{Error: err}}
 	}},
 }
 
 type server struct {
 	progMut         sync.RWMutex
 	asyncProgresses map[string]*asyncProgress
+
+	queue   *jobQueue
+	workers int
+	store   *jobStore // Optional.
+
+	// Worker processes which registered with this server, to run shards of async sims on.
+	pool *workerPool
+
+	coordinate bool // Whether workers may register with this server.
+	isWorker   bool // Whether this server runs shards for a coordinator.
+
+	cache *core.SimResultCache // Optional.
+
+	debugSessions *debugSessions
+}
+
+func newServer(workers int, queueSize int, store *jobStore) *server {
+	return &server{
+		progMut:         sync.RWMutex{},
+		asyncProgresses: map[string]*asyncProgress{},
+		queue:           newJobQueue(queueSize),
+		workers:         max(workers, 1),
+		store:           store,
+		pool:            newWorkerPool(),
+		debugSessions:   newDebugSessions(),
+	}
 }
 
 type apiHandler struct {
@@ -140,22 +219,52 @@ type apiHandler struct {
 type asyncAPIHandler struct {
 	msg    func() googleProto.Message
 	handle func(googleProto.Message, chan *proto.ProgressMetrics, string)
+	// Runs the sim on worker processes instead, used when any are registered.
+	distribute func(core.ShardRunner, googleProto.Message, chan *proto.ProgressMetrics, string)
+	// Creates the final progress message for a sim which ended without sending one, e.g. because
+	// it was aborted before it was started.
+	final func(*proto.ErrorOutcome) *proto.ProgressMetrics
 }
 
 type asyncProgress struct {
 	id             string
+	requestId      string
+	endpoint       string
+	priority       int32
 	latestProgress atomic.Value
+
+	createdAt  time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+
+	// Every progress message of the sim, for streaming.
+	mu       sync.Mutex
+	status   proto.AsyncJobStatus
+	err      *proto.ErrorOutcome
+	history  []*proto.ProgressMetrics
+	finished bool
+	aborted  bool          // Set once an abort was requested, even if the sim had not started yet.
+	updated  chan struct{} // Closed and replaced whenever a message is added.
 }
 
-func (s *server) addNewSim() *asyncProgress {
-	newID := uuid.NewString()
+func (s *server) addNewSim(id string, requestId string, endpoint string, priority int32) *asyncProgress {
+	if requestId == "" {
+		// Sims can only be aborted by their request id, so make sure there is one.
+		requestId = id
+	}
 	simProgress := &asyncProgress{
-		id: newID,
+		id:        id,
+		requestId: requestId,
+		endpoint:  endpoint,
+		priority:  priority,
+		createdAt: time.Now(),
+		status:    proto.AsyncJobStatus_AsyncJobStatusQueued,
+		updated:   make(chan struct{}),
 	}
 	simProgress.latestProgress.Store(&proto.ProgressMetrics{})
 
 	s.progMut.Lock()
-	s.asyncProgresses[newID] = simProgress
+	s.asyncProgresses[id] = simProgress
 	s.progMut.Unlock()
 
 	return simProgress
@@ -181,37 +290,39 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// reporter channel is handed into the core simulation.
-	//  as the simulation advances it will push changes to the channel
-	//  these changes will be consumed by the goroutine below so the asyncProgress endpoint can fetch the results.
-	reporter := make(chan *proto.ProgressMetrics, 100)
-	handler.handle(msg, reporter, r.URL.Query().Get("requestId"))
-
-	// Generate a new async simulation
-	simProgress := s.addNewSim()
+	var priority int32
+	if priorityStr := r.URL.Query().Get("priority"); priorityStr != "" {
+		p, err := strconv.ParseInt(priorityStr, 10, 32)
+		if err != nil {
+			log.Printf("Invalid priority: %s", priorityStr)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		priority = int32(p)
+	}
 
-	// Now launch a background process that pulls progress reports off the reporter channel
-	// and pushes it into the async progress cache.
-	go func() {
-		for {
-			select {
-			case <-time.After(time.Minute * 10):
-				// if we get no progress after 10 minutes, delete the pending sim and exit.
-				s.progMut.Lock()
-				delete(s.asyncProgresses, simProgress.id)
-				s.progMut.Unlock()
-				return
-			case progMetric := <-reporter:
-				if progMetric == nil {
-					return
-				}
-				simProgress.latestProgress.Store(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil {
-					return
-				}
-			}
+	// Generate a new async simulation, which is started by a worker once its turn comes.
+	simProgress := s.addNewSim(uuid.NewString(), r.URL.Query().Get("requestId"), endpoint, priority)
+	if s.store != nil {
+		if err := s.store.insert(simProgress, body); err != nil {
+			log.Printf("[ERROR] Failed to store job: %s", err.Error())
 		}
-	}()
+	}
+	job := &queuedJob{progress: simProgress, handler: handler, msg: msg}
+	if cached := s.cachedResult(msg); cached != nil {
+		// Cached results skip the queue.
+		simProgress.setRunning()
+		simProgress.push(cachedProgress(cached))
+		s.finishJob(job)
+	} else if !s.queue.push(job, false) {
+		s.removeSim(simProgress.id)
+		if s.store != nil {
+			s.store.delete(simProgress.id)
+		}
+		log.Printf("Rejected async sim, the queue is full")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
 
 	protoResult := &proto.AsyncAPIResult{
 		ProgressId: simProgress.id,
@@ -229,6 +340,8 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *server) setupAsyncServer() {
+	s.startWorkers()
+
 	// All async handlers here will call the addNewSim, generating a new UUID and cached progress state.
 	for route := range asyncAPIHandlers {
 		http.Handle(route, corsMiddleware(http.HandlerFunc(s.handleAsyncAPI)))
@@ -264,14 +377,29 @@ func (s *server) setupAsyncServer() {
 		}
 
 		// If this was the last result, delete the cache for this simulation.
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil {
-			s.progMut.Lock()
-			delete(s.asyncProgresses, msg.ProgressId)
-			s.progMut.Unlock()
+		if isFinalProgress(latest) {
+			s.removeSim(msg.ProgressId)
 		}
 		w.Header().Add("Content-Type", "application/x-protobuf")
 		w.Write(outbytes)
 	})))
+
+	http.Handle("/asyncStatus", corsMiddleware(http.HandlerFunc(s.handleAsyncStatus)))
+	http.Handle("/asyncCancel", corsMiddleware(http.HandlerFunc(s.handleAsyncCancel)))
+	http.Handle("/asyncStream", corsMiddleware(http.HandlerFunc(s.handleAsyncStream)))
+
+	http.Handle("/listJobs", corsMiddleware(http.HandlerFunc(s.handleListJobs)))
+	http.Handle("/getJob", corsMiddleware(http.HandlerFunc(s.handleGetJob)))
+	http.Handle("/deleteJob", corsMiddleware(http.HandlerFunc(s.handleDeleteJob)))
+
+	// Workers register with a coordinator, which then sends them shards to run.
+	if s.coordinate {
+		go s.pool.pruneLoop()
+		http.Handle("/registerWorker", http.HandlerFunc(s.handleRegisterWorker))
+	}
+	if s.isWorker {
+		http.Handle("/runShard", http.HandlerFunc(handleRunShard))
+	}
 }
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -287,6 +415,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 }
 func (s *server) runServer(useFS bool, host string, launchBrowser bool, simName string, wasm bool, inputReader *bufio.Reader) {
 	s.setupAsyncServer()
+	s.setupDebugServer()
 
 	var fs http.Handler
 	if useFS {
@@ -298,7 +427,7 @@ func (s *server) runServer(useFS bool, host string, launchBrowser bool, simName
 	}
 
 	for route := range handlers {
-		http.Handle(route, corsMiddleware(http.HandlerFunc(handleAPI)))
+		http.Handle(route, corsMiddleware(http.HandlerFunc(s.handleAPI)))
 	}
 
 	http.HandleFunc("/version", func(resp http.ResponseWriter, req *http.Request) {
@@ -406,16 +535,22 @@ func (s *server) runServer(useFS bool, host string, launchBrowser bool, simName
 			}()
 		case "sims":
 			s.progMut.RLock()
-			fmt.Printf("Total Sims Running: %d\n", len(s.asyncProgresses))
+			fmt.Printf("Total Sims Running: %d (%d queued)\n", len(s.asyncProgresses), s.queue.len())
 			for _, v := range s.asyncProgresses {
 				latest := (v.latestProgress.Load()).(*proto.ProgressMetrics)
-				fmt.Printf("Process: %s (%d sims)\n\t  Progress: %d/%d\n", v.id, latest.TotalSims, latest.CompletedIterations, latest.TotalIterations)
+				fmt.Printf("Process: %s (%d sims, %s)\n\t  Progress: %d/%d\n", v.id, latest.TotalSims, v.getStatus().Status, latest.CompletedIterations, latest.TotalIterations)
 			}
 			s.progMut.RUnlock()
+		case "workers":
+			workers := s.pool.describe()
+			fmt.Printf("Total Workers: %d (%d slots)\n", len(workers), s.pool.Capacity())
+			for _, line := range workers {
+				fmt.Printf("%s\n", line)
+			}
 		case "quit":
 			os.Exit(1)
 		case "?":
-			fmt.Printf("Commands:\n\tsims - Lists all active async sims running currently.\n\tprofile - start a CPU profile for debugging performance\n\theap_profile - capture a memory snapshot for debugging performance\n\tquit - exits\n\n")
+			fmt.Printf("Commands:\n\tsims - Lists all active async sims running currently.\n\tworkers - Lists all worker processes registered with this server.\n\tprofile - start a CPU profile for debugging performance\n\theap_profile - capture a memory snapshot for debugging performance\n\tquit - exits\n\n")
 		case "":
 			// nothing.
 		default:
@@ -425,7 +560,7 @@ func (s *server) runServer(useFS bool, host string, launchBrowser bool, simName
 }
 
 // handleAPI is generic handler for any api function using protos.
-func handleAPI(w http.ResponseWriter, r *http.Request) {
+func (s *server) handleAPI(w http.ResponseWriter, r *http.Request) {
 	endpoint := r.URL.Path
 
 	body, err := io.ReadAll(r.Body)
@@ -452,7 +587,15 @@ func handleAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := handler.handle(msg)
+	var result googleProto.Message
+	if cached := s.cachedResult(msg); cached != nil {
+		result = cached
+	} else {
+		result = handler.handle(msg)
+		if raidResult, ok := result.(*proto.RaidSimResult); ok {
+			s.cacheResult(msg, raidResult)
+		}
+	}
 
 	outbytes, err := googleProto.Marshal(result)
 	if err != nil {
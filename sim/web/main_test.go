@@ -3,16 +3,21 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"sync"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	_ "github.com/wowsims/mop/sim/common"
 	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/apltext"
 	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
 	googleProto "google.golang.org/protobuf/proto"
 )
 
@@ -54,13 +59,20 @@ var p1Equip = &proto.EquipmentSpec{
 	},
 }
 
+var testServer *server
+
 func init() {
-	s := &server{
-		progMut:         sync.RWMutex{},
-		asyncProgresses: map[string]*asyncProgress{},
+	dir, err := os.MkdirTemp("", "wowsimweb")
+	if err != nil {
+		log.Fatalf("Failed to create temp dir: %s", err)
+	}
+	store, err := openJobStore(filepath.Join(dir, "jobs.db"))
+	if err != nil {
+		log.Fatalf("Failed to open job store: %s", err)
 	}
+	testServer = newServer(defaultWorkers, defaultQueueSize, store)
 	go func() {
-		s.runServer(true, "localhost:3339", false, "", false, bufio.NewReader(bytes.NewBuffer([]byte{})))
+		testServer.runServer(true, "localhost:3339", false, "", false, bufio.NewReader(bytes.NewBuffer([]byte{})))
 	}()
 
 	time.Sleep(time.Second) // hack so we have time for server to startup. Probably could repeatedly curl the endpoint until it responds.
@@ -118,3 +130,283 @@ func TestIndividualSim(t *testing.T) {
 
 	log.Printf("RESULT: %#v", rsr)
 }
+
+func newStreamTestRequest(iterations int32) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(
+			&proto.Player{
+				Race:      proto.Race_RaceHuman,
+				Class:     proto.Class_ClassWarrior,
+				Equipment: &proto.EquipmentSpec{},
+				Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+				Spec: &proto.Player_ArmsWarrior{
+					ArmsWarrior: &proto.ArmsWarrior{
+						Options: &proto.ArmsWarrior_Options{ClassOptions: &proto.WarriorOptions{}},
+					},
+				},
+			},
+			&proto.PartyBuffs{},
+			&proto.RaidBuffs{},
+			&proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 120,
+			Targets: []*proto.Target{
+				core.NewDefaultTarget(),
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: iterations,
+			RandomSeed: 1,
+		},
+	}
+}
+
+func postProto(t *testing.T, endpoint string, msg googleProto.Message, result googleProto.Message) {
+	msgBytes, err := googleProto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+	r, err := http.Post("http://localhost:3339"+endpoint, "application/x-protobuf", bytes.NewReader(msgBytes))
+	if err != nil {
+		t.Fatalf("Failed to POST request: %s", err.Error())
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read result body: %s", err.Error())
+	}
+	if err := googleProto.Unmarshal(body, result); err != nil {
+		t.Fatalf("Failed to parse result: %s", err.Error())
+	}
+}
+
+type streamEvent struct {
+	event string
+	id    string
+	data  string
+}
+
+func readStream(t *testing.T, progressId string) []streamEvent {
+	r, err := http.Get(fmt.Sprintf("http://localhost:3339/asyncStream?progressId=%s", progressId))
+	if err != nil {
+		t.Fatalf("Failed to GET stream: %s", err.Error())
+	}
+	defer r.Body.Close()
+	if r.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected content type: %s", r.Header.Get("Content-Type"))
+	}
+
+	var events []streamEvent
+	var current streamEvent
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(nil, 1<<26)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			events = append(events, current)
+			current = streamEvent{}
+		case strings.HasPrefix(line, "event: "):
+			current.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read stream: %s", err.Error())
+	}
+	return events
+}
+
+func finalStreamStatus(t *testing.T, events []streamEvent) *proto.AsyncJobStatusResult {
+	if len(events) == 0 || events[len(events)-1].event != "status" {
+		t.Fatalf("Stream should end with a status event, got %v", events)
+	}
+	status := &proto.AsyncJobStatusResult{}
+	if err := protojson.Unmarshal([]byte(events[len(events)-1].data), status); err != nil {
+		t.Fatalf("Failed to parse status: %s", err.Error())
+	}
+	return status
+}
+
+func TestAsyncStream(t *testing.T) {
+	asyncResult := &proto.AsyncAPIResult{}
+	postProto(t, "/raidSimAsync", newStreamTestRequest(1000), asyncResult)
+
+	events := readStream(t, asyncResult.ProgressId)
+	if status := finalStreamStatus(t, events); status.Status != proto.AsyncJobStatus_AsyncJobStatusDone {
+		t.Fatalf("Expected sim to be done, got %s: %v", status.Status, status.Error)
+	}
+
+	var progress []*proto.ProgressMetrics
+	for _, event := range events {
+		if event.event != "progress" {
+			continue
+		}
+		if event.id != fmt.Sprint(len(progress)) {
+			t.Fatalf("Expected progress event %d, got id %s", len(progress), event.id)
+		}
+		msg := &proto.ProgressMetrics{}
+		if err := protojson.Unmarshal([]byte(event.data), msg); err != nil {
+			t.Fatalf("Failed to parse progress: %s", err.Error())
+		}
+		progress = append(progress, msg)
+	}
+	if len(progress) == 0 || progress[len(progress)-1].FinalRaidResult == nil {
+		t.Fatalf("Expected the last progress message to have the final result")
+	}
+
+	// The finished sim is kept, so its status can still be fetched.
+	status := &proto.AsyncJobStatusResult{}
+	postProto(t, "/asyncStatus", asyncResult, status)
+	if status.Status != proto.AsyncJobStatus_AsyncJobStatusDone || int(status.NumProgressMessages) != len(progress) {
+		t.Fatalf("Unexpected status after the stream ended: %v", status)
+	}
+
+	jobs := &proto.ListJobsResult{}
+	postProto(t, "/listJobs", &proto.ListJobsRequest{}, jobs)
+	found := false
+	for _, job := range jobs.Jobs {
+		if job.ProgressId == asyncResult.ProgressId {
+			found = true
+			if job.Status != proto.AsyncJobStatus_AsyncJobStatusDone || job.Endpoint != "/raidSimAsync" || job.FinishedAt < job.StartedAt || job.StartedAt < job.CreatedAt {
+				t.Fatalf("Unexpected job info: %v", job)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("Job %s not listed", asyncResult.ProgressId)
+	}
+
+	// Once removed from memory, the job is fetched from the store.
+	testServer.removeSim(asyncResult.ProgressId)
+	job := &proto.GetJobResult{}
+	postProto(t, "/getJob", asyncResult, job)
+	if job.Job.GetStatus() != proto.AsyncJobStatus_AsyncJobStatusDone || job.LatestProgress.GetFinalRaidResult().GetRaidMetrics() == nil {
+		t.Fatalf("Unexpected stored job: %v", job.Job)
+	}
+
+	deleted := &proto.DeleteJobResult{}
+	postProto(t, "/deleteJob", asyncResult, deleted)
+	if !deleted.Deleted {
+		t.Fatalf("Expected job to be deleted")
+	}
+	job = &proto.GetJobResult{}
+	postProto(t, "/getJob", asyncResult, job)
+	if job.Job != nil {
+		t.Fatalf("Expected job to be gone after deleting it, got %v", job.Job)
+	}
+}
+
+func TestAsyncCancel(t *testing.T) {
+	asyncResult := &proto.AsyncAPIResult{}
+	postProto(t, "/raidSimAsync", newStreamTestRequest(1000000), asyncResult)
+
+	abortResponse := &proto.AbortResponse{}
+	postProto(t, "/asyncCancel", asyncResult, abortResponse)
+	if !abortResponse.WasTriggered {
+		t.Fatalf("Expected the abort to be triggered")
+	}
+
+	events := readStream(t, asyncResult.ProgressId)
+	if status := finalStreamStatus(t, events); status.Status != proto.AsyncJobStatus_AsyncJobStatusAborted {
+		t.Fatalf("Expected sim to be aborted, got %s", status.Status)
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	cache, err := core.NewSimResultCache(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testServer.cache = cache
+	defer func() { testServer.cache = nil }()
+
+	request := newStreamTestRequest(100)
+	first := &proto.RaidSimResult{}
+	postProto(t, "/raidSim", request, first)
+	if first.CacheInfo == nil || first.CacheInfo.Hit || first.CacheInfo.RequestHash == "" {
+		t.Fatalf("Expected an uncached result with a request hash, got %v", first.CacheInfo)
+	}
+
+	second := &proto.RaidSimResult{}
+	postProto(t, "/raidSim", request, second)
+	if !second.CacheInfo.GetHit() || second.CacheInfo.RequestHash != first.CacheInfo.RequestHash {
+		t.Fatalf("Expected a cache hit for the same request, got %v", second.CacheInfo)
+	}
+	if second.RaidMetrics.Dps.Avg != first.RaidMetrics.Dps.Avg {
+		t.Fatalf("Expected the cached dps %0.3f, got %0.3f", first.RaidMetrics.Dps.Avg, second.RaidMetrics.Dps.Avg)
+	}
+
+	asyncResult := &proto.AsyncAPIResult{}
+	postProto(t, "/raidSimAsync", request, asyncResult)
+	job := &proto.GetJobResult{}
+	postProto(t, "/getJob", asyncResult, job)
+	if job.Job.Status != proto.AsyncJobStatus_AsyncJobStatusDone || !job.LatestProgress.GetFinalRaidResult().GetCacheInfo().GetHit() {
+		t.Fatalf("Expected an async sim of the same request to finish from the cache, got %s", job.Job.Status)
+	}
+}
+
+func TestDebugSession(t *testing.T) {
+	request := newStreamTestRequest(1)
+	rotation, err := apltext.Parse(`
+		actions cast_spell(spell_id=spell:6673)
+		actions cast_spell(spell_id=spell:12294)
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Raid.Parties[0].Players[0].Rotation = rotation
+	battleShout := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 6673}}
+
+	start := &proto.DebugResult{}
+	postProto(t, "/debugStart", &proto.DebugStartRequest{
+		Request:     request,
+		Breakpoints: []*proto.DebugBreakpoint{{Breakpoint: &proto.DebugBreakpoint_Cast{Cast: battleShout}}},
+	}, start)
+	if start.Error != nil || start.SessionId == "" {
+		t.Fatalf("Failed to start debugging: %v", start.Error)
+	}
+	if next := start.State.NextAction; next.GetIndex() != 0 || next.GetText() != "cast_spell(spell_id=spell:6673)" {
+		t.Fatalf("Expected Battle Shout to be next, got %v", next)
+	}
+
+	result := &proto.DebugResult{}
+	postProto(t, "/debugStep", &proto.DebugStepRequest{SessionId: start.SessionId, Mode: proto.DebugStepRequest_Resume}, result)
+	state := result.State
+	if state.BreakpointHit != 0 || state.Player.GcdRemaining == 0 {
+		t.Fatalf("Expected to stop at the Battle Shout cast, got breakpoint %d with %0.2fs GCD", state.BreakpointHit, state.Player.GcdRemaining)
+	}
+	castAt := state.CurrentTime
+
+	result = &proto.DebugResult{}
+	postProto(t, "/debugStep", &proto.DebugStepRequest{SessionId: start.SessionId, Mode: proto.DebugStepRequest_GCD}, result)
+	state = result.State
+	if state.Done || state.CurrentTime <= castAt || state.Player.GcdRemaining != 0 {
+		t.Fatalf("Expected to stop at the end of the GCD, got %0.2fs with %0.2fs GCD", state.CurrentTime, state.Player.GcdRemaining)
+	}
+	if next := state.NextAction; next.GetIndex() != 1 {
+		t.Fatalf("Expected Mortal Strike to be next while Battle Shout is on cooldown, got %v", next)
+	}
+
+	result = &proto.DebugResult{}
+	postProto(t, "/debugStep", &proto.DebugStepRequest{SessionId: start.SessionId, Mode: proto.DebugStepRequest_Time, Time: 60}, result)
+	if state = result.State; state.CurrentTime > 60 || len(state.Events) == 0 {
+		t.Fatalf("Expected to run until 60s, got %0.2fs", state.CurrentTime)
+	}
+
+	postProto(t, "/debugEnd", &proto.DebugEndRequest{SessionId: start.SessionId}, &proto.DebugResult{})
+	result = &proto.DebugResult{}
+	postProto(t, "/debugStep", &proto.DebugStepRequest{SessionId: start.SessionId}, result)
+	if result.Error == nil {
+		t.Fatalf("Expected an error for an ended session")
+	}
+
+	result = &proto.DebugResult{}
+	postProto(t, "/debugStart", &proto.DebugStartRequest{Request: request, PlayerIndex: 1}, result)
+	if result.Error == nil {
+		t.Fatalf("Expected an error for a player which doesn't exist")
+	}
+}
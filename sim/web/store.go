@@ -0,0 +1,189 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	proto "github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+
+	_ "modernc.org/sqlite"
+)
+
+// jobStore keeps async sims and their results in an sqlite database, so they can be
+// fetched after they have been removed from memory and unfinished ones survive restarts.
+type jobStore struct {
+	db *sql.DB
+}
+
+const createJobsTable = `
+CREATE TABLE IF NOT EXISTS jobs (
+	progress_id TEXT PRIMARY KEY,
+	request_id TEXT NOT NULL,
+	endpoint TEXT NOT NULL,
+	priority INTEGER NOT NULL,
+	status INTEGER NOT NULL,
+	created_at INTEGER NOT NULL,
+	started_at INTEGER NOT NULL DEFAULT 0,
+	finished_at INTEGER NOT NULL DEFAULT 0,
+	error BLOB,
+	request BLOB NOT NULL,
+	latest_progress BLOB
+);
+CREATE INDEX IF NOT EXISTS jobs_created_at ON jobs (created_at);
+`
+
+const jobInfoColumns = "progress_id, request_id, endpoint, priority, status, created_at, started_at, finished_at, error"
+
+func openJobStore(path string) (*jobStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %v", err)
+	}
+	// sqlite only allows a single writer, so serialize all access instead of failing with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(createJobsTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating jobs table: %v", err)
+	}
+	return &jobStore{db: db}, nil
+}
+
+func (js *jobStore) insert(p *asyncProgress, request []byte) error {
+	_, err := js.db.Exec(
+		"INSERT INTO jobs (progress_id, request_id, endpoint, priority, status, created_at, request) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		p.id, p.requestId, p.endpoint, p.priority, int32(proto.AsyncJobStatus_AsyncJobStatusQueued), unixMilli(p.createdAt), request,
+	)
+	return err
+}
+
+// Writes the current status of a job, and its result once it has finished.
+// Does nothing if the job is not in the store.
+func (js *jobStore) update(p *asyncProgress) error {
+	p.mu.Lock()
+	status := p.status
+	startedAt := unixMilli(p.startedAt)
+	finishedAt := unixMilli(p.finishedAt)
+	errorOutcome := p.err
+	finished := p.finished
+	p.mu.Unlock()
+
+	var errorBytes []byte
+	if errorOutcome != nil {
+		var err error
+		if errorBytes, err = googleProto.Marshal(errorOutcome); err != nil {
+			return err
+		}
+	}
+
+	if !finished {
+		_, err := js.db.Exec("UPDATE jobs SET status = ?, started_at = ? WHERE progress_id = ?", int32(status), startedAt, p.id)
+		return err
+	}
+
+	latest, err := googleProto.Marshal(p.latestProgress.Load().(*proto.ProgressMetrics))
+	if err != nil {
+		return err
+	}
+	_, err = js.db.Exec(
+		"UPDATE jobs SET status = ?, started_at = ?, finished_at = ?, error = ?, latest_progress = ? WHERE progress_id = ?",
+		int32(status), startedAt, finishedAt, errorBytes, latest, p.id,
+	)
+	return err
+}
+
+func scanJobInfo(scanner interface{ Scan(...any) error }, extra ...any) (*proto.JobInfo, error) {
+	info := &proto.JobInfo{}
+	var status int32
+	var errorBytes []byte
+	dest := append([]any{&info.ProgressId, &info.RequestId, &info.Endpoint, &info.Priority, &status, &info.CreatedAt, &info.StartedAt, &info.FinishedAt, &errorBytes}, extra...)
+	if err := scanner.Scan(dest...); err != nil {
+		return nil, err
+	}
+	info.Status = proto.AsyncJobStatus(status)
+	if len(errorBytes) > 0 && info.Status == proto.AsyncJobStatus_AsyncJobStatusFailed {
+		info.Error = &proto.ErrorOutcome{}
+		if err := googleProto.Unmarshal(errorBytes, info.Error); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// Returns the most recent jobs, or all of them if limit is 0.
+func (js *jobStore) list(limit int) ([]*proto.JobInfo, error) {
+	query := "SELECT " + jobInfoColumns + " FROM jobs ORDER BY created_at DESC"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	rows, err := js.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*proto.JobInfo
+	for rows.Next() {
+		info, err := scanJobInfo(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, info)
+	}
+	return jobs, rows.Err()
+}
+
+// Returns a job and its latest progress, or nil if it is not in the store.
+func (js *jobStore) get(progressId string) (*proto.JobInfo, *proto.ProgressMetrics, error) {
+	var latestBytes []byte
+	row := js.db.QueryRow("SELECT "+jobInfoColumns+", latest_progress FROM jobs WHERE progress_id = ?", progressId)
+	info, err := scanJobInfo(row, &latestBytes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	latest := &proto.ProgressMetrics{}
+	if err := googleProto.Unmarshal(latestBytes, latest); err != nil {
+		return nil, nil, err
+	}
+	return info, latest, nil
+}
+
+func (js *jobStore) delete(progressId string) (bool, error) {
+	result, err := js.db.Exec("DELETE FROM jobs WHERE progress_id = ?", progressId)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+type storedJob struct {
+	info    *proto.JobInfo
+	request []byte
+}
+
+// Returns the jobs which have not finished, oldest first.
+func (js *jobStore) unfinished() ([]storedJob, error) {
+	rows, err := js.db.Query(
+		"SELECT "+jobInfoColumns+", request FROM jobs WHERE status IN (?, ?) ORDER BY created_at",
+		int32(proto.AsyncJobStatus_AsyncJobStatusQueued), int32(proto.AsyncJobStatus_AsyncJobStatusRunning),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []storedJob
+	for rows.Next() {
+		var job storedJob
+		if job.info, err = scanJobInfo(rows, &job.request); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
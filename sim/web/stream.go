@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	proto "github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// How long finished sims are kept, so that their results can still be fetched.
+const finishedSimRetention = time.Minute * 10
+
+func isFinalProgress(progMetric *proto.ProgressMetrics) bool {
+	return progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil
+}
+
+func finalProgressError(progMetric *proto.ProgressMetrics) *proto.ErrorOutcome {
+	switch {
+	case progMetric.FinalRaidResult != nil:
+		return progMetric.FinalRaidResult.Error
+	case progMetric.FinalWeightResult != nil:
+		return progMetric.FinalWeightResult.Error
+	case progMetric.FinalBulkResult != nil:
+		return progMetric.FinalBulkResult.Error
+	}
+	return nil
+}
+
+// Records a progress message, returning whether it was the final one.
+func (p *asyncProgress) push(progMetric *proto.ProgressMetrics) bool {
+	p.latestProgress.Store(progMetric)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.history = append(p.history, progMetric)
+	if isFinalProgress(progMetric) {
+		p.finished = true
+		p.finishedAt = time.Now()
+		p.err = finalProgressError(progMetric)
+		if p.err == nil {
+			p.status = proto.AsyncJobStatus_AsyncJobStatusDone
+		} else if p.err.Type == proto.ErrorOutcomeType_ErrorOutcomeAborted {
+			p.status = proto.AsyncJobStatus_AsyncJobStatusAborted
+		} else {
+			p.status = proto.AsyncJobStatus_AsyncJobStatusFailed
+		}
+	}
+
+	close(p.updated)
+	p.updated = make(chan struct{})
+	return p.finished
+}
+
+func (p *asyncProgress) isFinished() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.finished
+}
+
+func (p *asyncProgress) getStatus() *proto.AsyncJobStatusResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.statusLocked()
+}
+
+func (p *asyncProgress) statusLocked() *proto.AsyncJobStatusResult {
+	result := &proto.AsyncJobStatusResult{
+		ProgressId:          p.id,
+		RequestId:           p.requestId,
+		Status:              p.status,
+		NumProgressMessages: int32(len(p.history)),
+	}
+	if p.status == proto.AsyncJobStatus_AsyncJobStatusFailed {
+		result.Error = p.err
+	}
+	return result
+}
+
+// Returns the progress messages from index start onwards, the current status, and a
+// channel which is closed when there is a new message.
+func (p *asyncProgress) messagesSince(start int) ([]*proto.ProgressMetrics, *proto.AsyncJobStatusResult, chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var messages []*proto.ProgressMetrics
+	if start < len(p.history) {
+		messages = p.history[start:]
+	}
+	return messages, p.statusLocked(), p.updated
+}
+
+func (s *server) getSim(progressId string) (*asyncProgress, bool) {
+	s.progMut.RLock()
+	defer s.progMut.RUnlock()
+	progress, ok := s.asyncProgresses[progressId]
+	return progress, ok
+}
+
+func (s *server) removeSim(progressId string) {
+	s.progMut.Lock()
+	delete(s.asyncProgresses, progressId)
+	s.progMut.Unlock()
+}
+
+func readAsyncAPIResult(w http.ResponseWriter, r *http.Request) (*proto.AsyncAPIResult, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, false
+	}
+	msg := &proto.AsyncAPIResult{}
+	if err := googleProto.Unmarshal(body, msg); err != nil {
+		log.Printf("Failed to parse request: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	return msg, true
+}
+
+// Reads an AsyncAPIResult from the request body and looks up its sim.
+func (s *server) readAsyncSim(w http.ResponseWriter, r *http.Request) (*asyncProgress, bool) {
+	msg, ok := readAsyncAPIResult(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	progress, ok := s.getSim(msg.ProgressId)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return nil, false
+	}
+	return progress, true
+}
+
+func writeProto(w http.ResponseWriter, msg googleProto.Message) {
+	outbytes, err := googleProto.Marshal(msg)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/x-protobuf")
+	w.Write(outbytes)
+}
+
+// handleAsyncStatus returns the status of an async sim.
+func (s *server) handleAsyncStatus(w http.ResponseWriter, r *http.Request) {
+	progress, ok := s.readAsyncSim(w, r)
+	if !ok {
+		return
+	}
+	writeProto(w, progress.getStatus())
+}
+
+// handleAsyncCancel aborts an async sim, whether it is queued or running.
+func (s *server) handleAsyncCancel(w http.ResponseWriter, r *http.Request) {
+	progress, ok := s.readAsyncSim(w, r)
+	if !ok {
+		return
+	}
+	triggered := s.abortSim(progress)
+	writeProto(w, &proto.AbortResponse{RequestId: progress.requestId, WasTriggered: triggered})
+}
+
+// handleAsyncStream streams every progress message of an async sim as server-sent events,
+// with the sim given by the progressId query parameter. Messages are sent as "progress"
+// events with their index as the event id, so clients can resume from Last-Event-ID after
+// reconnecting, and status changes are sent as "status" events. Both are encoded as protojson.
+// The stream ends after the final result.
+func (s *server) handleAsyncStream(w http.ResponseWriter, r *http.Request) {
+	progress, ok := s.getSim(r.URL.Query().Get("progressId"))
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	next := 0
+	if lastEventId := r.Header.Get("Last-Event-ID"); lastEventId != "" {
+		if idx, err := strconv.Atoi(lastEventId); err == nil {
+			next = idx + 1
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	lastStatus := proto.AsyncJobStatus(-1)
+	for {
+		messages, status, updated := progress.messagesSince(next)
+		for _, message := range messages {
+			if err := writeEvent(w, "progress", strconv.Itoa(next), message); err != nil {
+				return
+			}
+			next++
+		}
+		if status.Status != lastStatus {
+			if err := writeEvent(w, "status", "", status); err != nil {
+				return
+			}
+			lastStatus = status.Status
+		}
+		flusher.Flush()
+
+		if status.Status >= proto.AsyncJobStatus_AsyncJobStatusDone {
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event string, id string, msg googleProto.Message) error {
+	// protojson output never contains newlines unless multiline is set, so it fits in one data line.
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
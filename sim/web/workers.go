@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	proto "github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// How often workers register with the coordinator. Workers which miss a few
+// heartbeats in a row are dropped, and their shards re-issued to other workers.
+const (
+	workerHeartbeatInterval = time.Second * 5
+	workerMissedHeartbeats  = 3
+)
+
+// Maximum number of workers a shard is sent to before giving up on it, so a shard which
+// fails on every worker doesn't take down the whole pool.
+const maxShardAttempts = 3
+
+type remoteWorker struct {
+	address  string
+	slots    int
+	busy     int
+	lastSeen time.Time
+	lost     chan struct{} // Closed when the worker is dropped, cancelling its shards.
+}
+
+// workerPool keeps track of the worker processes registered with a coordinator, and runs
+// shards of async sims on them. It implements core.ShardRunner.
+type workerPool struct {
+	mu      sync.Mutex
+	workers map[string]*remoteWorker
+	changed chan struct{} // Closed and replaced whenever a slot is freed or a worker is added.
+	client  *http.Client
+}
+
+func newWorkerPool() *workerPool {
+	return &workerPool{
+		workers: map[string]*remoteWorker{},
+		changed: make(chan struct{}),
+		client:  &http.Client{},
+	}
+}
+
+func (wp *workerPool) notifyLocked() {
+	close(wp.changed)
+	wp.changed = make(chan struct{})
+}
+
+func (wp *workerPool) register(address string, slots int) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if worker, ok := wp.workers[address]; ok {
+		worker.lastSeen = time.Now()
+		if worker.slots != slots {
+			worker.slots = slots
+			wp.notifyLocked()
+		}
+		return
+	}
+	wp.workers[address] = &remoteWorker{
+		address:  address,
+		slots:    slots,
+		lastSeen: time.Now(),
+		lost:     make(chan struct{}),
+	}
+	log.Printf("Worker %s registered with %d slots", address, slots)
+	wp.notifyLocked()
+}
+
+func (wp *workerPool) dropLocked(worker *remoteWorker, reason string) {
+	if wp.workers[worker.address] != worker {
+		return
+	}
+	delete(wp.workers, worker.address)
+	close(worker.lost)
+	log.Printf("Dropped worker %s: %s", worker.address, reason)
+	wp.notifyLocked()
+}
+
+func (wp *workerPool) pruneLocked() {
+	deadline := time.Now().Add(-workerHeartbeatInterval * workerMissedHeartbeats)
+	for _, worker := range wp.workers {
+		if worker.lastSeen.Before(deadline) {
+			wp.dropLocked(worker, "missed heartbeats")
+		}
+	}
+}
+
+// Drops workers which stopped sending heartbeats, so shards stuck on them are re-issued.
+func (wp *workerPool) pruneLoop() {
+	for range time.Tick(workerHeartbeatInterval) {
+		wp.mu.Lock()
+		wp.pruneLocked()
+		wp.mu.Unlock()
+	}
+}
+
+func (wp *workerPool) Capacity() int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.pruneLocked()
+	capacity := 0
+	for _, worker := range wp.workers {
+		capacity += worker.slots
+	}
+	return capacity
+}
+
+// Reserves a slot on the least busy worker, waiting until one is free. Returns nil if there
+// are no workers left or the sim was aborted.
+func (wp *workerPool) acquire(signals simsignals.Signals) *remoteWorker {
+	for {
+		wp.mu.Lock()
+		wp.pruneLocked()
+		if len(wp.workers) == 0 {
+			wp.mu.Unlock()
+			return nil
+		}
+
+		var best *remoteWorker
+		for _, worker := range wp.workers {
+			if worker.busy < worker.slots && (best == nil || worker.slots-worker.busy > best.slots-best.busy) {
+				best = worker
+			}
+		}
+		if best != nil {
+			best.busy++
+			wp.mu.Unlock()
+			return best
+		}
+		changed := wp.changed
+		wp.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-signals.Abort.Done():
+			return nil
+		case <-time.After(workerHeartbeatInterval):
+		}
+	}
+}
+
+func (wp *workerPool) release(worker *remoteWorker, err error) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	worker.busy--
+	if err != nil {
+		wp.dropLocked(worker, err.Error())
+	} else {
+		wp.notifyLocked()
+	}
+}
+
+// Runs a shard on a worker, re-issuing it to another worker if the worker is lost. If there
+// are no workers left, the shard is run in this process instead. The shard fails once it has
+// failed on maxShardAttempts workers.
+func (wp *workerPool) RunShard(request *proto.RaidSimRequest, signals simsignals.Signals) *proto.RaidSimResult {
+	aborted := &proto.RaidSimResult{Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}}
+	for attempt := 1; ; attempt++ {
+		worker := wp.acquire(signals)
+		if signals.Abort.IsTriggered() {
+			if worker != nil {
+				wp.release(worker, nil)
+			}
+			return aborted
+		}
+		if worker == nil {
+			return core.RunSim(request, nil, signals)
+		}
+
+		result, err := wp.send(worker, request, signals)
+		if signals.Abort.IsTriggered() {
+			wp.release(worker, nil)
+			return aborted
+		}
+		wp.release(worker, err)
+		if err == nil {
+			return result
+		}
+		if attempt == maxShardAttempts {
+			return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf("Shard failed on %d workers: %s", attempt, err)}}
+		}
+		log.Printf("Re-issuing shard of %d iterations", request.SimOptions.Iterations)
+	}
+}
+
+func (wp *workerPool) send(worker *remoteWorker, request *proto.RaidSimRequest, signals simsignals.Signals) (*proto.RaidSimResult, error) {
+	body, err := googleProto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-signals.Abort.Done():
+		case <-worker.lost:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", worker.address+"/runShard", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := wp.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("shard failed with status %s", resp.Status)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &proto.RaidSimResult{}
+	if err := googleProto.Unmarshal(respBody, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Returns a description of each worker, for the console.
+func (wp *workerPool) describe() []string {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	var lines []string
+	for _, worker := range wp.workers {
+		lines = append(lines, fmt.Sprintf("Worker: %s (%d/%d slots busy, last seen %s ago)", worker.address, worker.busy, worker.slots, time.Since(worker.lastSeen).Round(time.Second)))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// handleRegisterWorker adds a worker process to the pool, or records its heartbeat.
+func (s *server) handleRegisterWorker(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	msg := &proto.RegisterWorkerRequest{}
+	if err := googleProto.Unmarshal(body, msg); err != nil {
+		log.Printf("Failed to parse request: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if msg.Address == "" || msg.Slots <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.pool.register(strings.TrimSuffix(msg.Address, "/"), int(msg.Slots))
+	writeProto(w, &proto.RegisterWorkerResult{HeartbeatIntervalMs: workerHeartbeatInterval.Milliseconds()})
+}
+
+// handleRunShard runs a shard for a coordinator. The shard is aborted if the coordinator
+// cancels the request.
+func handleRunShard(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	msg := &proto.RaidSimRequest{}
+	if err := googleProto.Unmarshal(body, msg); err != nil {
+		log.Printf("Failed to parse request: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	signals := simsignals.CreateSignals()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-r.Context().Done():
+			signals.Abort.Trigger()
+		case <-done:
+		}
+	}()
+
+	writeProto(w, core.RunSim(msg, nil, signals))
+}
+
+// Registers this server as a worker with the coordinator, and keeps sending heartbeats.
+func registerWithCoordinator(coordinator string, address string, slots int) {
+	coordinator = strings.TrimSuffix(coordinator, "/")
+	body, err := googleProto.Marshal(&proto.RegisterWorkerRequest{Address: address, Slots: int32(slots)})
+	if err != nil {
+		log.Fatalf("Failed to marshal worker registration: %s", err)
+	}
+
+	interval := workerHeartbeatInterval
+	registered := false
+	for {
+		result, err := postRegistration(coordinator, body)
+		if err != nil {
+			if registered {
+				log.Printf("Lost connection to coordinator %s: %s", coordinator, err)
+			}
+			registered = false
+		} else {
+			if !registered {
+				log.Printf("Registered with coordinator %s as %s with %d slots", coordinator, address, slots)
+			}
+			registered = true
+			if result.HeartbeatIntervalMs > 0 {
+				interval = time.Duration(result.HeartbeatIntervalMs) * time.Millisecond
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+func postRegistration(coordinator string, body []byte) (*proto.RegisterWorkerResult, error) {
+	resp, err := http.Post(coordinator+"/registerWorker", "application/x-protobuf", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registration failed with status %s", resp.Status)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &proto.RegisterWorkerResult{}
+	if err := googleProto.Unmarshal(respBody, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
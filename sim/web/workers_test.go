@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestRegisterWorker(t *testing.T) {
+	s := newServer(1, 1, nil)
+	register := func(msg *proto.RegisterWorkerRequest) *httptest.ResponseRecorder {
+		body, _ := googleProto.Marshal(msg)
+		recorder := httptest.NewRecorder()
+		s.handleRegisterWorker(recorder, httptest.NewRequest("POST", "/registerWorker", bytes.NewReader(body)))
+		return recorder
+	}
+
+	if recorder := register(&proto.RegisterWorkerRequest{Address: "http://localhost:4000/", Slots: 2}); recorder.Code != http.StatusOK {
+		t.Fatalf("Expected registration to succeed, got status %d", recorder.Code)
+	}
+	// Heartbeats update the existing worker.
+	register(&proto.RegisterWorkerRequest{Address: "http://localhost:4000", Slots: 3})
+	if recorder := register(&proto.RegisterWorkerRequest{Address: "http://localhost:4001"}); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected a worker without slots to be rejected, got status %d", recorder.Code)
+	}
+
+	if capacity := s.pool.Capacity(); capacity != 3 {
+		t.Fatalf("Expected a capacity of 3, got %d", capacity)
+	}
+}
+
+func TestDistributedRaidSim(t *testing.T) {
+	worker := httptest.NewServer(http.HandlerFunc(handleRunShard))
+	defer worker.Close()
+	lostWorker := httptest.NewServer(http.HandlerFunc(handleRunShard))
+	lostWorker.Close()
+
+	pool := newWorkerPool()
+	pool.register(worker.URL, 1)
+	pool.register(lostWorker.URL, 1)
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	core.RunRaidSimDistributedAsync(pool, newStreamTestRequest(200), reporter, "distributed-test")
+
+	var final *proto.RaidSimResult
+	for msg := range reporter {
+		if msg.FinalRaidResult != nil {
+			final = msg.FinalRaidResult
+		}
+	}
+	if final == nil || final.Error != nil {
+		t.Fatalf("Expected a result, got %v", final)
+	}
+	// The shard sent to the lost worker is re-issued to the other one.
+	if final.IterationsDone != 200 {
+		t.Fatalf("Expected 200 iterations, got %d", final.IterationsDone)
+	}
+	if workers := pool.describe(); len(workers) != 1 {
+		t.Fatalf("Expected the lost worker to be dropped, got %v", workers)
+	}
+}
+
+func TestShardAttemptLimit(t *testing.T) {
+	pool := newWorkerPool()
+	for i := 0; i < maxShardAttempts+1; i++ {
+		worker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer worker.Close()
+		pool.register(worker.URL, 1)
+	}
+
+	result := pool.RunShard(newStreamTestRequest(10), simsignals.CreateSignals())
+	if result.Error == nil || result.Error.Type != proto.ErrorOutcomeType_ErrorOutcomeError {
+		t.Fatalf("Expected the shard to fail, got %v", result)
+	}
+	if workers := pool.describe(); len(workers) != 1 {
+		t.Fatalf("Expected the shard to stop after %d workers, got %d workers left", maxShardAttempts, len(workers))
+	}
+}
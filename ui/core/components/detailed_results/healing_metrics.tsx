@@ -250,6 +250,11 @@ export class HealingMetricsTable extends MetricsTable<ActionMetrics> {
 				getValue: (metric: ActionMetrics) => metric.critPercent || metric.critTickPercent,
 				getDisplayString: (metric: ActionMetrics) => formatToPercent(metric.critPercent || metric.critTickPercent, { fallbackString: '-' }),
 			},
+			{
+				name: 'Overheal %',
+				getValue: (metric: ActionMetrics) => metric.overhealingPercent,
+				getDisplayString: (metric: ActionMetrics) => formatToPercent(metric.overhealingPercent, { fallbackString: '-' }),
+			},
 			{
 				name: 'HPET',
 				getValue: (metric: ActionMetrics) => metric.healingThroughput,
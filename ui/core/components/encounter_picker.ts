@@ -136,6 +136,31 @@ export class EncounterPicker extends Component {
 				});
 			}
 
+			if (simUI.isIndividualSim() && (simUI as IndividualSimUI<any>).player.getPlayerSpec().isHealingSpec) {
+				new NumberPicker(this.rootElem, simUI.sim.raid, {
+					id: 'encounter-incoming-dtps',
+					label: 'Incoming DTPS',
+					labelTooltip: 'Damage taken per second by each allied player, so there is missing health to heal.',
+					changedEvent: (raid: Raid) => TypedEvent.onAny([raid.incomingDamageChangeEmitter, raid.targetDummiesChangeEmitter]),
+					getValue: (raid: Raid) => raid.getIncomingDamage().dtps,
+					setValue: (eventID: EventID, raid: Raid, newValue: number) => {
+						raid.setIncomingDamage(eventID, { ...raid.getIncomingDamage(), dtps: newValue });
+					},
+					showWhen: (raid: Raid) => raid.getTargetDummies() > 0,
+				});
+				new NumberPicker(this.rootElem, simUI.sim.raid, {
+					id: 'encounter-incoming-tank-dtps',
+					label: 'Additional Tank DTPS',
+					labelTooltip: 'Additional damage taken per second by the first allied player, who stands in for the tank.',
+					changedEvent: (raid: Raid) => TypedEvent.onAny([raid.incomingDamageChangeEmitter, raid.targetDummiesChangeEmitter]),
+					getValue: (raid: Raid) => raid.getIncomingDamage().tankDtps,
+					setValue: (eventID: EventID, raid: Raid, newValue: number) => {
+						raid.setIncomingDamage(eventID, { ...raid.getIncomingDamage(), tankDtps: newValue });
+					},
+					showWhen: (raid: Raid) => raid.getTargetDummies() > 0,
+				});
+			}
+
 			if (simUI.isIndividualSim() && (simUI as IndividualSimUI<any>).player.getPlayerSpec().isTankSpec) {
 				new NumberPicker(this.rootElem, modEncounter, {
 					id: 'encounter-min-base-damage',
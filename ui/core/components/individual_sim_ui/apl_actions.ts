@@ -6,6 +6,7 @@ import {
 	APLActionActivateAura,
 	APLActionActivateAuraWithStacks,
 	APLActionAutocastOtherCooldowns,
+	APLActionCallActionList,
 	APLActionCancelAura,
 	APLActionCastAllStatBuffCooldowns,
 	APLActionCastFriendlySpell,
@@ -20,9 +21,12 @@ import {
 	APLActionMoveDuration,
 	APLActionMultidot,
 	APLActionMultishield,
+	APLActionPoolResource,
 	APLActionResetSequence,
+	APLActionRunActionList,
 	APLActionSchedule,
 	APLActionSequence,
+	APLActionSetVariable,
 	APLActionStrictMultidot,
 	APLActionStrictSequence,
 	APLActionTriggerICD,
@@ -537,6 +541,23 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		newValue: () => APLActionWaitUntil.create(),
 		fields: [AplValues.valueFieldConfig('condition')],
 	}),
+	['poolResource']: inputBuilder({
+		label: 'Pool Resource',
+		submenu: ['Timing'],
+		shortDescription: 'Pauses all APL actions until the resource reaches the specified amount.',
+		fullDescription: `
+			<p>If no amount is specified, pools for the cost of the next action, which must be a <b>Cast</b> action, and also waits for its cooldown and the GCD.</p>
+			<p>Skipped if the amount is already reached, cannot be reached from regeneration or auto attack Rage alone, or would take longer than the <b>Max Wait</b>.</p>
+			<p>Resources which are only generated by abilities, such as Runic Power, Combo Points, Chi and Holy Power, cannot be pooled and show a warning.</p>
+		`,
+		includeIf: (player: Player<any>, isPrepull: boolean) => !isPrepull,
+		newValue: () => APLActionPoolResource.create(),
+		fields: [
+			AplHelpers.resourceTypeFieldConfig('resourceType'),
+			AplValues.valueFieldConfig('amount', { label: 'Amount' }),
+			AplValues.valueFieldConfig('maxWait', { label: 'Max Wait' }),
+		],
+	}),
 	['schedule']: inputBuilder({
 		label: 'Scheduled Action',
 		submenu: ['Timing'],
@@ -592,6 +613,43 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		newValue: APLActionStrictSequence.create,
 		fields: [actionListFieldConfig('actions')],
 	}),
+	['setVariable']: inputBuilder({
+		label: 'Set Variable',
+		submenu: ['Variables'],
+		shortDescription: 'Sets a variable to the given value.',
+		fullDescription: `
+			<p>Use the <b>name</b> field to refer to a variable declared in the <b>Variables</b> list. Variables are reset to their initial value at the start of each iteration.</p>
+			<p>This action is skipped when the variable already has the given value.</p>
+		`,
+		newValue: APLActionSetVariable.create,
+		fields: [
+			AplHelpers.stringFieldConfig('name'),
+			AplValues.valueFieldConfig('value', {
+				label: 'Value',
+			}),
+		],
+	}),
+	['callActionList']: inputBuilder({
+		label: 'Call Action List',
+		submenu: ['Action Lists'],
+		shortDescription: 'Performs the first ready action from another action list.',
+		fullDescription: `
+			<p>Use the <b>list name</b> field to refer to an action list from the <b>Action Lists</b> section. If no action in that list is ready, the next action in this list is considered.</p>
+		`,
+		newValue: APLActionCallActionList.create,
+		fields: [AplHelpers.stringFieldConfig('listName')],
+	}),
+	['runActionList']: inputBuilder({
+		label: 'Run Action List',
+		submenu: ['Action Lists'],
+		shortDescription: 'Performs the first ready action from another action list, without returning to this list.',
+		fullDescription: `
+			<p>Use the <b>list name</b> field to refer to an action list from the <b>Action Lists</b> section. If no action in that list is ready, no further actions in this list are considered.</p>
+		`,
+		includeIf: (_, isPrepull: boolean) => !isPrepull,
+		newValue: APLActionRunActionList.create,
+		fields: [AplHelpers.stringFieldConfig('listName')],
+	}),
 	['changeTarget']: inputBuilder({
 		label: 'Change Target',
 		submenu: ['Misc'],
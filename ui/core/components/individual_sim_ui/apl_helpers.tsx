@@ -5,8 +5,9 @@ import { Player, UnitMetadata } from '../../player.js';
 import { APLValueEclipsePhase, APLValueRuneSlot, APLValueRuneType } from '../../proto/apl.js';
 import { ActionID, OtherAction, Stat, UnitReference, UnitReference_Type as UnitType } from '../../proto/common.js';
 import { FeralDruid_Rotation_AplType } from '../../proto/druid.js';
+import { ResourceType } from '../../proto/spell.js';
 import { ActionId, defaultTargetIcon, getPetIconFromName } from '../../proto_utils/action_id.js';
-import { getStatName } from '../../proto_utils/names.js';
+import { getStatName, resourceNames } from '../../proto_utils/names.js';
 import { EventID } from '../../typed_event.js';
 import { bucket, getEnumValues, randomUUID } from '../../utils.js';
 import { Input, InputConfig } from '../input.jsx';
@@ -409,6 +410,7 @@ const unitSets: Record<
 		getUnits: player => {
 			return [
 				undefined,
+				UnitReference.create({ type: UnitType.LowestHealthAlly }),
 				player.sim.raid.getActivePlayers().map(player => UnitReference.create({ type: UnitType.Player, index: player.getRaidIndex() })),
 			].flat();
 		},
@@ -460,6 +462,12 @@ export class APLUnitPicker extends UnitPicker<Player<any>> {
 				iconUrl: 'fa-bullseye',
 				text: 'Current Target',
 			};
+		} else if (ref.type == UnitType.LowestHealthAlly) {
+			return {
+				value: ref,
+				iconUrl: 'fa-heart-pulse',
+				text: 'Lowest Health Ally',
+			};
 		} else if (ref.type == UnitType.Player) {
 			const player = thisPlayer.sim.raid.getPlayer(ref.index);
 			if (player) {
@@ -752,6 +760,38 @@ export function runeTypeFieldConfig(field: string, includeDeath: boolean): APLPi
 	};
 }
 
+export function resourceTypeFieldConfig(field: string): APLPickerBuilderFieldConfig<any, any> {
+	// Resources which are only generated by abilities can't be pooled or predicted, and report a warning.
+	const values = [
+		ResourceType.ResourceTypeMana,
+		ResourceType.ResourceTypeEnergy,
+		ResourceType.ResourceTypeRage,
+		ResourceType.ResourceTypeFocus,
+		ResourceType.ResourceTypeComboPoints,
+		ResourceType.ResourceTypeChi,
+		ResourceType.ResourceTypeRunicPower,
+		ResourceType.ResourceTypeBloodRune,
+		ResourceType.ResourceTypeFrostRune,
+		ResourceType.ResourceTypeUnholyRune,
+		ResourceType.ResourceTypeDeathRune,
+		ResourceType.ResourceTypeGenericResource,
+	].map(resourceType => ({ value: resourceType, label: resourceNames.get(resourceType)! }));
+
+	return {
+		field: field,
+		label: 'Resource',
+		newValue: () => ResourceType.ResourceTypeMana,
+		factory: (parent, player, config) =>
+			new TextDropdownPicker(parent, player, {
+				id: randomUUID(),
+				...config,
+				defaultLabel: 'None',
+				equals: (a, b) => a == b,
+				values: values,
+			}),
+	};
+}
+
 export function runeSlotFieldConfig(field: string): APLPickerBuilderFieldConfig<any, any> {
 	return {
 		field: field,
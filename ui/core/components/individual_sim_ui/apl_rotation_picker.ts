@@ -1,7 +1,8 @@
 import tippy, { Instance as TippyInstance } from 'tippy.js';
 
 import { Player } from '../../player';
-import { APLAction, APLListItem, APLPrepullAction, APLValue } from '../../proto/apl';
+import { APLValidation } from '../../proto/api';
+import { APLAction, APLActionList, APLListItem, APLPrepullAction, APLValue, APLVariable } from '../../proto/apl';
 import { SimUI } from '../../sim_ui';
 import { EventID, TypedEvent } from '../../typed_event';
 import { randomUUID } from '../../utils';
@@ -10,7 +11,7 @@ import { Input, InputConfig } from '../input';
 import { ListItemPickerConfig, ListPicker } from '../pickers/list_picker';
 import { AdaptiveStringPicker } from '../pickers/string_picker';
 import { APLActionPicker } from './apl_actions';
-import { APLValueImplStruct } from './apl_values';
+import { APLValueImplStruct, APLValuePicker } from './apl_values';
 
 export class APLRotationPicker extends Component {
 	constructor(parent: HTMLElement, simUI: SimUI, modPlayer: Player<any>) {
@@ -65,7 +66,58 @@ export class APLRotationPicker extends Component {
 				listPicker: ListPicker<Player<any>, APLListItem>,
 				index: number,
 				config: ListItemPickerConfig<Player<any>, APLListItem>,
-			) => new APLListItemPicker(parent, modPlayer, config, index),
+			) =>
+				new APLListItemPicker(parent, modPlayer, config, player => player.getCurrentStats().rotationStats?.priorityList[index]?.validations || []),
+			inlineMenuBar: true,
+		});
+
+		new ListPicker<Player<any>, APLVariable>(this.rootElem, modPlayer, {
+			extraCssClasses: ['apl-variable-picker'],
+			title: 'Variables',
+			titleTooltip: 'Named values which can be changed with Set Variable actions. Variables are reset to their initial value at the start of each iteration.',
+			itemLabel: 'Variable',
+			changedEvent: (player: Player<any>) => player.rotationChangeEmitter,
+			getValue: (player: Player<any>) => player.aplRotation.variables,
+			setValue: (eventID: EventID, player: Player<any>, newValue: Array<APLVariable>) => {
+				player.aplRotation.variables = newValue;
+				player.rotationChangeEmitter.emit(eventID);
+			},
+			newItem: () =>
+				APLVariable.create({
+					value: {
+						value: { oneofKind: 'const', const: { val: '0' } },
+						uuid: { value: randomUUID() },
+					},
+				}),
+			copyItem: (oldItem: APLVariable) => APLVariable.clone(oldItem),
+			newItemPicker: (
+				parent: HTMLElement,
+				listPicker: ListPicker<Player<any>, APLVariable>,
+				index: number,
+				config: ListItemPickerConfig<Player<any>, APLVariable>,
+			) => new APLVariablePicker(parent, modPlayer, config, index),
+			inlineMenuBar: true,
+		});
+
+		new ListPicker<Player<any>, APLActionList>(this.rootElem, modPlayer, {
+			extraCssClasses: ['apl-action-list-picker'],
+			title: 'Action Lists',
+			titleTooltip: 'Named lists of actions, which can be invoked from other lists with the Call Action List and Run Action List actions.',
+			itemLabel: 'Action List',
+			changedEvent: (player: Player<any>) => player.rotationChangeEmitter,
+			getValue: (player: Player<any>) => player.aplRotation.actionLists,
+			setValue: (eventID: EventID, player: Player<any>, newValue: Array<APLActionList>) => {
+				player.aplRotation.actionLists = newValue;
+				player.rotationChangeEmitter.emit(eventID);
+			},
+			newItem: () => APLActionList.create(),
+			copyItem: (oldItem: APLActionList) => APLActionList.clone(oldItem),
+			newItemPicker: (
+				parent: HTMLElement,
+				listPicker: ListPicker<Player<any>, APLActionList>,
+				index: number,
+				config: ListItemPickerConfig<Player<any>, APLActionList>,
+			) => new APLActionListPicker(parent, modPlayer, config, index),
 			inlineMenuBar: true,
 		});
 
@@ -190,13 +242,18 @@ class APLListItemPicker extends Input<Player<any>, APLListItem> {
 		);
 	}
 
-	constructor(parent: HTMLElement, player: Player<any>, config: ListItemPickerConfig<Player<any>, APLListItem>, index: number) {
+	constructor(
+		parent: HTMLElement,
+		player: Player<any>,
+		config: ListItemPickerConfig<Player<any>, APLListItem>,
+		getValidations: (player: Player<any>) => Array<APLValidation>,
+	) {
 		config.enableWhen = () => !this.getItem().hide;
 		super(parent, 'apl-list-item-picker-root', player, config);
 		this.player = player;
 
 		const itemHeaderElem = ListPicker.getItemHeaderElem(this);
-		ListPicker.makeListItemValidations(itemHeaderElem, player, player => player.getCurrentStats().rotationStats?.priorityList[index]?.validations || []);
+		ListPicker.makeListItemValidations(itemHeaderElem, player, getValidations);
 
 		this.hidePicker = new HidePicker(itemHeaderElem, player, {
 			changedEvent: () => this.player.rotationChangeEmitter,
@@ -239,6 +296,150 @@ class APLListItemPicker extends Input<Player<any>, APLListItem> {
 	}
 }
 
+class APLVariablePicker extends Input<Player<any>, APLVariable> {
+	private readonly player: Player<any>;
+
+	private readonly namePicker: Input<Player<any>, string>;
+	private readonly valuePicker: APLValuePicker;
+
+	private getItem(): APLVariable {
+		return this.getSourceValue() || APLVariable.create();
+	}
+
+	constructor(parent: HTMLElement, player: Player<any>, config: ListItemPickerConfig<Player<any>, APLVariable>, index: number) {
+		super(parent, 'apl-list-item-picker-root', player, config);
+		this.player = player;
+
+		const itemHeaderElem = ListPicker.getItemHeaderElem(this);
+		ListPicker.makeListItemValidations(itemHeaderElem, player, player => player.getCurrentStats().rotationStats?.variables[index]?.validations || []);
+
+		this.namePicker = new AdaptiveStringPicker(this.rootElem, this.player, {
+			id: randomUUID(),
+			label: 'Name',
+			extraCssClasses: ['input-inline'],
+			changedEvent: () => this.player.rotationChangeEmitter,
+			getValue: () => this.getItem().name,
+			setValue: (eventID: EventID, player: Player<any>, newValue: string) => {
+				this.getItem().name = newValue;
+				this.player.rotationChangeEmitter.emit(eventID);
+			},
+			inline: true,
+		});
+
+		this.valuePicker = new APLValuePicker(this.rootElem, this.player, {
+			label: 'Initial Value',
+			labelTooltip: 'Value of the variable at the start of each iteration. Also determines whether the variable holds a boolean, number or duration.',
+			changedEvent: () => this.player.rotationChangeEmitter,
+			getValue: () => this.getItem().value,
+			setValue: (eventID: EventID, player: Player<any>, newValue: APLValue | undefined) => {
+				this.getItem().value = newValue;
+				this.player.rotationChangeEmitter.emit(eventID);
+			},
+		});
+		this.init();
+	}
+
+	getInputElem(): HTMLElement | null {
+		return this.rootElem;
+	}
+
+	getInputValue(): APLVariable {
+		return APLVariable.create({
+			name: this.namePicker.getInputValue(),
+			value: this.valuePicker.getInputValue(),
+		});
+	}
+
+	setInputValue(newValue: APLVariable) {
+		if (!newValue) {
+			return;
+		}
+		this.namePicker.setInputValue(newValue.name);
+		this.valuePicker.setInputValue(newValue.value);
+	}
+}
+
+class APLActionListPicker extends Input<Player<any>, APLActionList> {
+	private readonly player: Player<any>;
+
+	private readonly namePicker: Input<Player<any>, string>;
+	private readonly itemsPicker: ListPicker<Player<any>, APLListItem>;
+
+	private getItem(): APLActionList {
+		return this.getSourceValue() || APLActionList.create();
+	}
+
+	constructor(parent: HTMLElement, player: Player<any>, config: ListItemPickerConfig<Player<any>, APLActionList>, index: number) {
+		super(parent, 'apl-list-item-picker-root', player, config);
+		this.player = player;
+
+		const itemHeaderElem = ListPicker.getItemHeaderElem(this);
+		ListPicker.makeListItemValidations(itemHeaderElem, player, player => player.getCurrentStats().rotationStats?.actionLists[index]?.validations || []);
+
+		this.namePicker = new AdaptiveStringPicker(this.rootElem, this.player, {
+			id: randomUUID(),
+			label: 'Name',
+			extraCssClasses: ['input-inline'],
+			changedEvent: () => this.player.rotationChangeEmitter,
+			getValue: () => this.getItem().name,
+			setValue: (eventID: EventID, player: Player<any>, newValue: string) => {
+				this.getItem().name = newValue;
+				this.player.rotationChangeEmitter.emit(eventID);
+			},
+			inline: true,
+		});
+
+		this.itemsPicker = new ListPicker<Player<any>, APLListItem>(this.rootElem, this.player, {
+			extraCssClasses: ['apl-list-item-picker'],
+			itemLabel: 'Action',
+			changedEvent: () => this.player.rotationChangeEmitter,
+			getValue: () => this.getItem().items,
+			setValue: (eventID: EventID, player: Player<any>, newValue: Array<APLListItem>) => {
+				this.getItem().items = newValue;
+				this.player.rotationChangeEmitter.emit(eventID);
+			},
+			newItem: () =>
+				APLListItem.create({
+					action: {},
+				}),
+			copyItem: (oldItem: APLListItem) => APLListItem.clone(oldItem),
+			newItemPicker: (
+				parent: HTMLElement,
+				listPicker: ListPicker<Player<any>, APLListItem>,
+				itemIndex: number,
+				config: ListItemPickerConfig<Player<any>, APLListItem>,
+			) =>
+				new APLListItemPicker(
+					parent,
+					this.player,
+					config,
+					player => player.getCurrentStats().rotationStats?.actionLists[index]?.items[itemIndex]?.validations || [],
+				),
+			inlineMenuBar: true,
+		});
+		this.init();
+	}
+
+	getInputElem(): HTMLElement | null {
+		return this.rootElem;
+	}
+
+	getInputValue(): APLActionList {
+		return APLActionList.create({
+			name: this.namePicker.getInputValue(),
+			items: this.itemsPicker.getInputValue(),
+		});
+	}
+
+	setInputValue(newValue: APLActionList) {
+		if (!newValue) {
+			return;
+		}
+		this.namePicker.setInputValue(newValue.name);
+		this.itemsPicker.setInputValue(newValue.items);
+	}
+}
+
 class HidePicker extends Input<Player<any>, boolean> {
 	private readonly inputElem: HTMLElement;
 	private readonly iconElem: HTMLElement;
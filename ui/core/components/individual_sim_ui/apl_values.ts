@@ -41,6 +41,7 @@ import {
 	APLValueCurrentSolarEnergy,
 	APLValueCurrentTime,
 	APLValueCurrentTimePercent,
+	APLValueDistanceToTarget,
 	APLValueDotIsActive,
 	APLValueDotPercentIncrease,
 	APLValueDotRemainingTime,
@@ -71,12 +72,14 @@ import {
 	APLValueNextRuneCooldown,
 	APLValueNot,
 	APLValueNumberTargets,
+	APLValueNumberTargetsInRange,
 	APLValueNumEquippedStatProcTrinkets,
 	APLValueNumStatBuffCooldowns,
 	APLValueOr,
 	APLValueProtectionPaladinDamageTakenLastGlobal,
 	APLValueRemainingTime,
 	APLValueRemainingTimePercent,
+	APLValueResourceTimeToTarget,
 	APLValueRuneCooldown,
 	APLValueRuneSlotCooldown,
 	APLValueSequenceIsComplete,
@@ -99,6 +102,7 @@ import {
 	APLValueTrinketProcsMaxRemainingICD,
 	APLValueTrinketProcsMinRemainingTime,
 	APLValueUnitIsMoving,
+	APLValueVariable,
 	APLValueWarlockHandOfGuldanInFlight,
 	APLValueWarlockHauntInFlight,
 } from '../../proto/apl.js';
@@ -644,6 +648,27 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		newValue: APLValueFrontOfTarget.create,
 		fields: [],
 	}),
+	distanceToTarget: inputBuilder({
+		label: 'Distance to Target',
+		submenu: ['Encounter'],
+		shortDescription: 'Distance in yards between the player and the target.',
+		fullDescription: `
+		<p>Only differs between targets when unit positions are configured for the encounter. Otherwise, this is the player's distance from target setting.</p>
+		`,
+		newValue: APLValueDistanceToTarget.create,
+		fields: [AplHelpers.unitFieldConfig('targetUnit', 'targets')],
+	}),
+	numberTargetsInRange: inputBuilder({
+		label: 'Number of Targets in Range',
+		submenu: ['Encounter'],
+		shortDescription: 'Number of active targets within the given distance, in yards, of the player.',
+		newValue: APLValueNumberTargetsInRange.create,
+		fields: [
+			AplHelpers.numberFieldConfig('range', true, {
+				label: 'Range',
+			}),
+		],
+	}),
 
 	// Boss
 	bossSpellIsCasting: inputBuilder({
@@ -821,6 +846,17 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		},
 		fields: [valueFieldConfig('targetEnergy')],
 	}),
+	resourceTimeToTarget: inputBuilder({
+		label: 'Estimated Time To Target Resource',
+		submenu: ['Resources'],
+		shortDescription: 'Estimated time until the target amount of the resource is reached from regeneration, will return 0 if at or above target.',
+		fullDescription: `
+			<p>Rage is estimated from upcoming auto attacks, assuming they all land. Death runes count towards every rune type.</p>
+			<p>Resources which are only generated by abilities, such as Runic Power, Combo Points, Chi and Holy Power, cannot be predicted and show a warning.</p>
+		`,
+		newValue: APLValueResourceTimeToTarget.create,
+		fields: [AplHelpers.resourceTypeFieldConfig('resourceType'), valueFieldConfig('targetAmount')],
+	}),
 	currentComboPoints: inputBuilder({
 		label: 'Current Combo Points',
 		submenu: ['Resources', 'Combo Points'],
@@ -1358,6 +1394,18 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		fields: [AplHelpers.stringFieldConfig('sequenceName')],
 	}),
 
+	// Variables
+	variable: inputBuilder({
+		label: 'Variable',
+		submenu: ['Variables'],
+		shortDescription: 'Returns the current value of a variable.',
+		fullDescription: `
+			<p>Use the <b>name</b> field to refer to a variable declared in the <b>Variables</b> list.</p>
+		`,
+		newValue: APLValueVariable.create,
+		fields: [AplHelpers.stringFieldConfig('name')],
+	}),
+
 	// Class/spec specific values
 	totemRemainingTime: inputBuilder({
 		label: 'Totem Remaining Time',
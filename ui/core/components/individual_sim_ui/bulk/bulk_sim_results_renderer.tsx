@@ -23,6 +23,8 @@ export default class BulkSimResultRenderer extends Component {
 		}
 
 		const dpsDelta = result.unitMetrics!.dps!.avg! - baseResult.unitMetrics!.dps!.avg;
+		const dpsMargin = result.dpsInterval ? (result.dpsInterval.upper - result.dpsInterval.lower) / 2 : 0;
+		const dpsDeltaInsignificant = !!result.dpsDeltaInterval && !result.dpsDeltaSignificant;
 
 		const equipButtonRef = ref<HTMLButtonElement>();
 		const dpsDeltaRef = ref<HTMLDivElement>();
@@ -32,8 +34,18 @@ export default class BulkSimResultRenderer extends Component {
 				<div className="results-sim">
 					<div className="results-sim-dps damage-metrics">
 						<span className="topline-result-avg">{this.formatDps(result.unitMetrics!.dps!.avg)}</span>
+						{dpsMargin > 0 && (
+							<span className="topline-result-stdev">
+								(<i className="fas fa-plus-minus fa-xs"></i>
+								{this.formatDps(dpsMargin)})
+							</span>
+						)}
 						<div className="results-reference">
-							<span ref={dpsDeltaRef} className={clsx('results-reference-diff', dpsDelta >= 0 ? 'positive' : 'negative')} />
+							<span
+								ref={dpsDeltaRef}
+								className={clsx('results-reference-diff', dpsDelta >= 0 ? 'positive' : 'negative', dpsDeltaInsignificant && 'opacity-50')}
+								title={dpsDeltaInsignificant ? 'Within the margin of error of the equipped gear' : undefined}
+							/>
 						</div>
 					</div>
 				</div>
@@ -66,6 +66,8 @@ export class BulkTab extends SimTab {
 	// TODO: Make a real options probably
 	doCombos: boolean;
 	fastMode: boolean;
+	adaptiveIterations: boolean;
+	commonRandomNumbers: boolean;
 	autoGem: boolean;
 	simTalents: boolean;
 	autoEnchant: boolean;
@@ -174,6 +176,8 @@ export class BulkTab extends SimTab {
 
 		this.doCombos = true;
 		this.fastMode = true;
+		this.adaptiveIterations = false;
+		this.commonRandomNumbers = false;
 		this.autoGem = true;
 		this.autoEnchant = true;
 		this.savedTalents = [];
@@ -233,6 +237,8 @@ export class BulkTab extends SimTab {
 
 			this.doCombos = settings.combinations;
 			this.fastMode = settings.fastMode;
+			this.adaptiveIterations = settings.adaptiveIterations;
+			this.commonRandomNumbers = settings.commonRandomNumbers;
 			this.autoEnchant = settings.autoEnchant;
 			this.savedTalents = settings.talentsToSim;
 			this.autoGem = settings.autoGem;
@@ -270,6 +276,8 @@ export class BulkTab extends SimTab {
 			// For now, it's always constant iteration combinations mode for "sim my bags".
 			combinations: this.doCombos,
 			fastMode: this.fastMode,
+			adaptiveIterations: this.adaptiveIterations,
+			commonRandomNumbers: this.commonRandomNumbers,
 			autoEnchant: this.autoEnchant,
 			autoGem: this.autoGem,
 			simTalents: this.simTalents,
@@ -458,6 +466,16 @@ export class BulkTab extends SimTab {
 		this.settingsChangedEmitter.emit(TypedEvent.nextEventID());
 	}
 
+	private setAdaptiveIterations(adaptiveIterations: boolean) {
+		this.adaptiveIterations = adaptiveIterations;
+		this.settingsChangedEmitter.emit(TypedEvent.nextEventID());
+	}
+
+	private setCommonRandomNumbers(commonRandomNumbers: boolean) {
+		this.commonRandomNumbers = commonRandomNumbers;
+		this.settingsChangedEmitter.emit(TypedEvent.nextEventID());
+	}
+
 	protected async runBulkSim(onProgress: WorkerProgressCallback) {
 		this.pendingResults.setPending();
 
@@ -658,6 +676,28 @@ export class BulkTab extends SimTab {
 					this.setFastMode(newValue);
 				},
 			});
+			new BooleanPicker<BulkTab>(this.booleanSettingsContainer, this, {
+				id: 'bulk-adaptive-iterations',
+				label: 'Adaptive Iterations',
+				labelTooltip:
+					'Keeps re-simming the top results with more iterations until their ranking is statistically significant. Slower, but avoids upgrades which are within the margin of error.',
+				changedEvent: _modObj => this.settingsChangedEmitter,
+				getValue: _modObj => this.adaptiveIterations,
+				setValue: (_, _modObj, newValue: boolean) => {
+					this.setAdaptiveIterations(newValue);
+				},
+			});
+			new BooleanPicker<BulkTab>(this.booleanSettingsContainer, this, {
+				id: 'bulk-common-random-numbers',
+				label: 'Paired RNG',
+				labelTooltip:
+					'Sims every combination with the same random numbers, so that the differences between them are measured with much less noise. Uses more memory.',
+				changedEvent: _modObj => this.settingsChangedEmitter,
+				getValue: _modObj => this.commonRandomNumbers,
+				setValue: (_, _modObj, newValue: boolean) => {
+					this.setCommonRandomNumbers(newValue);
+				},
+			});
 		}
 		new BooleanPicker<BulkTab>(this.booleanSettingsContainer, this, {
 			id: 'bulk-combinations',
@@ -32,10 +32,14 @@ export class IndividualJsonExporter<SpecType extends Spec> extends IndividualExp
 				return path.length > 3;
 			}
 
-			if (path[0] == 'player' && path[1] == 'rotation' && ['prepullActions', 'priorityList'].includes(path[2])) {
+			if (path[0] == 'player' && path[1] == 'rotation' && ['prepullActions', 'priorityList', 'variables'].includes(path[2])) {
 				return path.length > 3;
 			}
 
+			if (path[0] == 'player' && path[1] == 'rotation' && path[2] == 'actionLists' && path[4] == 'items') {
+				return path.length > 5;
+			}
+
 			return false;
 		});
 	}
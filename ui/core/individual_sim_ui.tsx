@@ -46,6 +46,7 @@ import {
 	Faction,
 	Glyphs,
 	HandType,
+	IncomingDamageModel,
 	IndividualBuffs,
 	ItemSlot,
 	ItemSwap,
@@ -642,6 +643,7 @@ export abstract class IndividualSimUI<SpecType extends Spec> extends SimUI {
 				raidBuffs: this.sim.raid.getBuffs(),
 				debuffs: this.sim.raid.getDebuffs(),
 				targetDummies: this.sim.raid.getTargetDummies(),
+				incomingDamage: this.sim.raid.getIncomingDamage(),
 			});
 		}
 		if (exportCategory(SimSettingCategories.UISettings)) {
@@ -688,6 +690,7 @@ export abstract class IndividualSimUI<SpecType extends Spec> extends SimUI {
 					party.setBuffs(eventID, settings.partyBuffs || PartyBuffs.create());
 				}
 				this.sim.raid.setTargetDummies(eventID, settings.targetDummies);
+				this.sim.raid.setIncomingDamage(eventID, settings.incomingDamage || IncomingDamageModel.create());
 			}
 			if (loadCategory(SimSettingCategories.Encounter)) {
 				this.sim.encounter.fromProto(eventID, settings.encounter || EncounterProto.create());
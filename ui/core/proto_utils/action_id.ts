@@ -143,6 +143,10 @@ export class ActionId {
 				baseName = 'Prepull';
 				iconUrl = 'https://wow.zamimg.com/images/wow/icons/medium/inv_misc_pocketwatch_02.jpg';
 				break;
+			case OtherAction.OtherActionIncomingDamage:
+				baseName = 'Incoming Damage';
+				iconUrl = 'https://wow.zamimg.com/images/wow/icons/large/inv_sword_04.jpg';
+				break;
 		}
 		this.baseName = baseName ?? '';
 		this.name = (name || baseName) ?? '';
@@ -927,6 +927,10 @@ export class ActionMetrics {
 		return this.combinedMetrics.critHealing / this.iterations;
 	}
 
+	get overhealingPercent() {
+		return this.combinedMetrics.overhealingPercent;
+	}
+
 	get hps() {
 		return this.combinedMetrics.hps;
 	}
@@ -1370,6 +1374,11 @@ export class TargetedActionMetrics {
 		return this.data.critHealing / this.iterations;
 	}
 
+	get overhealingPercent() {
+		if (!this.data.healing) return 0;
+		return (this.data.overhealing / this.data.healing) * 100;
+	}
+
 	get shielding() {
 		return this.data.shielding;
 	}
@@ -1588,6 +1597,7 @@ export class TargetedActionMetrics {
 				healing: sum(actions.map(a => a.data.healing)),
 				critHealing: sum(actions.map(a => a.data.critHealing)),
 				shielding: sum(actions.map(a => a.data.shielding)),
+				overhealing: sum(actions.map(a => a.data.overhealing)),
 				castTimeMs: sum(actions.map(a => a.data.castTimeMs)),
 			}),
 			{
@@ -4,6 +4,7 @@ import { Raid as RaidProto } from './proto/api.js';
 import {
 	Class,
 	Debuffs,
+	IncomingDamageModel,
 	RaidBuffs,
 	UnitReference,
 	UnitReference_Type as UnitType,
@@ -20,6 +21,7 @@ export class Raid {
 	private debuffs: Debuffs = Debuffs.create();
 	private tanks: Array<UnitReference> = [];
 	private targetDummies = 0;
+	private incomingDamage: IncomingDamageModel = IncomingDamageModel.create();
 	private numActiveParties = 5;
 
 	// Emits when a raid member is added/removed/moved.
@@ -29,6 +31,7 @@ export class Raid {
 	readonly debuffsChangeEmitter = new TypedEvent<void>();
 	readonly tanksChangeEmitter = new TypedEvent<void>();
 	readonly targetDummiesChangeEmitter = new TypedEvent<void>();
+	readonly incomingDamageChangeEmitter = new TypedEvent<void>();
 	readonly numActivePartiesChangeEmitter = new TypedEvent<void>();
 
 	// Emits when anything in the raid changes.
@@ -61,6 +64,7 @@ export class Raid {
 			this.debuffsChangeEmitter,
 			this.tanksChangeEmitter,
 			this.targetDummiesChangeEmitter,
+			this.incomingDamageChangeEmitter,
 		], 'RaidChange');
 
 		this.changeEmitter.on(() => {
@@ -171,6 +175,20 @@ export class Raid {
 		this.targetDummiesChangeEmitter.emit(eventID);
 	}
 
+	getIncomingDamage(): IncomingDamageModel {
+		// Make a defensive copy
+		return IncomingDamageModel.clone(this.incomingDamage);
+	}
+
+	setIncomingDamage(eventID: EventID, newIncomingDamage: IncomingDamageModel) {
+		if (IncomingDamageModel.equals(this.incomingDamage, newIncomingDamage))
+			return;
+
+		// Make a defensive copy
+		this.incomingDamage = IncomingDamageModel.clone(newIncomingDamage);
+		this.incomingDamageChangeEmitter.emit(eventID);
+	}
+
 	getNumActiveParties(): number {
 		return this.numActiveParties;
 	}
@@ -198,6 +216,7 @@ export class Raid {
 			debuffs: this.getDebuffs(),
 			tanks: this.getTanks(),
 			targetDummies: this.getTargetDummies(),
+			incomingDamage: this.getIncomingDamage(),
 			numActiveParties: this.getNumActiveParties(),
 		});
 	}
@@ -208,6 +227,7 @@ export class Raid {
 			this.setDebuffs(eventID, proto.debuffs || Debuffs.create());
 			this.setTanks(eventID, proto.tanks);
 			this.setTargetDummies(eventID, proto.targetDummies);
+			this.setIncomingDamage(eventID, proto.incomingDamage || IncomingDamageModel.create());
 			this.setNumActiveParties(eventID, proto.numActiveParties || 5);
 
 			for (let i = 0; i < MAX_NUM_PARTIES; i++) {